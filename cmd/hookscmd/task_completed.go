@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/tag"
 )
 
 // TaskCompletedCmd represents the task-completed hook command
@@ -23,6 +24,8 @@ Features:
 - Parse acceptance criteria from spec.md
 - Reject if unchecked criteria remain (exit code 2)
 - Accept if all criteria checked or no SPEC referenced
+- On accept, name other SPECs affected by the uncommitted changes
+  (see jikime tag graph --impact) whose acceptance criteria need re-verifying
 
 Only active in Agent Teams mode (CLAUDE_HOOK_EVENT_TEAM_NAME is set).
 Based on moai-adk task_completed hook pattern.`,
@@ -120,13 +123,55 @@ func runTaskCompleted(cmd *cobra.Command, args []string) error {
 		os.Exit(2)
 	}
 
-	// All acceptance criteria checked - accept completion
+	// All acceptance criteria checked - accept completion, pointing out
+	// other SPECs the changes affect
+	if msg := impactMessage(projectRoot, specID); msg != "" {
+		return outputTaskCompleted(taskCompletedOutput{
+			Continue:      true,
+			SystemMessage: msg,
+		})
+	}
 	return outputTaskCompleted(taskCompletedOutput{
 		Continue:       true,
 		SuppressOutput: true,
 	})
 }
 
+// impactMessage describes the SPECs other than specID affected by the
+// uncommitted changes in projectRoot, or returns "" when there are none
+// or the impact cannot be determined.
+func impactMessage(projectRoot, specID string) string {
+	changed, err := tag.GitChangedFiles(projectRoot, "", false)
+	if err != nil || len(changed) == 0 {
+		return ""
+	}
+	graph, err := tag.LoadProjectGraph(projectRoot, changed)
+	if err != nil {
+		return ""
+	}
+
+	var lines []string
+	for _, s := range graph.Impact(changed).Specs {
+		if s.SpecID == specID {
+			continue
+		}
+		why := "changed " + strings.Join(s.Reasons, ", ")
+		if !s.Direct() {
+			why = "depends on " + strings.Join(s.Via, " → ")
+		}
+		line := fmt.Sprintf("  %s (%s): re-verify %d acceptance criteria", s.SpecID, why, len(s.Acceptance))
+		if len(s.Tests) > 0 {
+			line += fmt.Sprintf(", %d test location(s)", len(s.Tests))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[Hook] %s changes also affect %d other SPEC(s):\n%s\nRun `jikime tag graph --impact` for the tests to re-run.",
+		specID, len(lines), strings.Join(lines, "\n"))
+}
+
 func outputTaskCompleted(out taskCompletedOutput) error {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetEscapeHTML(false)
@@ -65,6 +65,19 @@ func runTeamAgentStart(cmd *cobra.Command, args []string) error {
 		Status:        team.AgentStatusActive,
 		PID:           os.Getpid(),
 		LastHeartbeat: time.Now(),
+		WorktreePath:  os.Getenv("JIKIME_WORKTREE_PATH"),
+	}
+	// Keep spawn-time details recorded by `jikime team spawn` so that
+	// stop and session restore can still find the tmux session or container.
+	if prev, _ := reg.Get(agentID); prev != nil {
+		info.TmuxSession = prev.TmuxSession
+		info.ContainerID = prev.ContainerID
+		info.ContainerRuntime = prev.ContainerRuntime
+		info.Backend = prev.Backend
+		info.JoinedAt = prev.JoinedAt
+		if info.WorktreePath == "" {
+			info.WorktreePath = prev.WorktreePath
+		}
 	}
 	if err := reg.Register(info); err != nil {
 		fmt.Fprintf(os.Stderr, "[jikime/team] register failed: %v\n", err)
@@ -75,6 +88,10 @@ func runTeamAgentStart(cmd *cobra.Command, args []string) error {
 
 	// Send join message to team inbox so the leader knows we started.
 	ti := team.NewTeamInbox(td)
+	// Messages parked while this agent was away are delivered again.
+	if n, err := ti.RedriveAgent(agentID); err == nil && n > 0 {
+		fmt.Fprintf(os.Stderr, "[jikime/team] redelivered %d dead-lettered message(s)\n", n)
+	}
 	joinMsg := &team.Message{
 		TeamName: teamName,
 		Kind:     team.MessageKindDirect,
@@ -68,8 +68,14 @@ func runTeamAgentStop(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "[jikime/team] agent %s marked offline\n", agentID)
 	}
 
-	// Notify leader.
+	// Unread messages would never be consumed; park them in the dead-letter
+	// queue. They are redriven automatically if the agent rejoins.
 	ti := team.NewTeamInbox(td)
+	if n, err := ti.DeadLetterAgent(agentID, "recipient "+agentID+" left the team"); err == nil && n > 0 {
+		fmt.Fprintf(os.Stderr, "[jikime/team] moved %d unread message(s) to dead-letter queue\n", n)
+	}
+
+	// Notify leader.
 	leaveMsg := &team.Message{
 		TeamName: teamName,
 		Kind:     team.MessageKindDirect,
@@ -16,8 +16,12 @@ var TeamCostTrackCmd = &cobra.Command{
 	Use:   "team-cost-track",
 	Short: "Track token costs per PostToolUse and enforce budget",
 	Long: `Team-aware PostToolUse hook that accumulates token usage into the cost store.
-If JIKIME_TEAM_BUDGET is set and total tokens exceed it, the hook returns
-Continue:false to stop the agent gracefully.
+If the team token budget is exceeded, the hook returns Continue:false to stop
+the agent gracefully.
+
+Dollar budgets (config.json "budgets") are checked at team, agent, and task
+scope. Crossing a soft limit sends a one-time warning to the leader's inbox;
+crossing a hard limit stops the agent.
 Only activates when JIKIME_TEAM_NAME is set.`,
 	RunE: runTeamCostTrack,
 }
@@ -28,9 +32,12 @@ type postToolUseInput struct {
 	ToolName   string `json:"tool_name"`
 	ToolInput  any    `json:"tool_input"`
 	ToolResult any    `json:"tool_result"`
+	Model      string `json:"model"`
 	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 	} `json:"usage"`
 }
 
@@ -54,37 +61,58 @@ func runTeamCostTrack(cmd *cobra.Command, args []string) error {
 	decoder := json.NewDecoder(os.Stdin)
 	_ = decoder.Decode(&input)
 
-	inputTokens := input.Usage.InputTokens
-	outputTokens := input.Usage.OutputTokens
-	if inputTokens == 0 && outputTokens == 0 {
+	usage := input.Usage
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 &&
+		usage.CacheCreationInputTokens == 0 && usage.CacheReadInputTokens == 0 {
 		// No token info in this event — skip silently.
 		return writeResponse(HookResponse{Continue: true})
 	}
 
 	td := filepath.Join(dataDir, "teams", teamName)
 
-	// Determine budget from team config.
-	budget := 0
+	// Determine budgets from team config.
 	cfgData, _ := os.ReadFile(filepath.Join(td, "config.json"))
-	var cfg struct {
-		Budget int `json:"budget"`
-	}
+	var cfg team.TeamConfig
 	_ = json.Unmarshal(cfgData, &cfg)
-	budget = cfg.Budget
+	budget := cfg.Budget
 
 	costStore, err := team.NewCostStore(filepath.Join(td, "costs"), budget)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[jikime/team] cost store: %v\n", err)
 		return writeResponse(HookResponse{Continue: true})
 	}
+	if cfg.Budgets != nil {
+		costStore.SetPrices(cfg.Budgets.Prices)
+	}
 
 	taskID := os.Getenv("JIKIME_TASK_ID") // optional: current task ID
-	_, err = costStore.Record(agentID, taskID, input.ToolName, "", inputTokens, outputTokens)
+	if taskID == "" {
+		if reg, err := team.NewRegistry(filepath.Join(td, "registry")); err == nil {
+			if info, _ := reg.Get(agentID); info != nil {
+				taskID = info.CurrentTaskID
+			}
+		}
+	}
+	model := input.Model
+	if model == "" {
+		model = os.Getenv("ANTHROPIC_MODEL")
+	}
+	_, err = costStore.RecordEvent(&team.CostEvent{
+		TeamName:            teamName,
+		AgentID:             agentID,
+		TaskID:              taskID,
+		ToolName:            input.ToolName,
+		Model:               model,
+		InputTokens:         usage.InputTokens,
+		OutputTokens:        usage.OutputTokens,
+		CacheCreationTokens: usage.CacheCreationInputTokens,
+		CacheReadTokens:     usage.CacheReadInputTokens,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[jikime/team] record cost: %v\n", err)
 	}
 
-	// Enforce budget.
+	// Enforce token budget.
 	if budget > 0 {
 		exceeded, _ := costStore.BudgetExceeded()
 		if exceeded {
@@ -103,5 +131,54 @@ func runTeamCostTrack(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Enforce dollar budgets.
+	alerts, err := costStore.CheckBudgets(cfg.Budgets, agentID, taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[jikime/team] check budgets: %v\n", err)
+	}
+	ti := team.NewTeamInbox(td)
+	for _, a := range alerts {
+		desc := formatBudgetAlert(a)
+		if a.Level == team.BudgetLevelHard {
+			msg := fmt.Sprintf("⛔ Hard budget limit reached: %s. Stopping agent %s in team %s.",
+				desc, agentID, teamName)
+			fmt.Fprintf(os.Stderr, "[jikime/team] %s\n", msg)
+			if first, _ := costStore.MarkAlerted(a); first {
+				_ = ti.Send(&team.Message{
+					TeamName: teamName,
+					Kind:     team.MessageKindSystem,
+					From:     agentID,
+					To:       "leader",
+					Subject:  "budget_hard_limit",
+					Body:     msg,
+				})
+			}
+			return writeResponse(HookResponse{
+				Continue:      false,
+				SystemMessage: msg,
+			})
+		}
+		if first, _ := costStore.MarkAlerted(a); first {
+			_ = ti.Send(&team.Message{
+				TeamName: teamName,
+				Kind:     team.MessageKindSystem,
+				From:     agentID,
+				To:       "leader",
+				Subject:  "budget_soft_limit",
+				Body: fmt.Sprintf("⚠️ Soft budget limit reached: %s (agent %s, team %s).",
+					desc, agentID, teamName),
+			})
+		}
+	}
+
 	return writeResponse(HookResponse{Continue: true})
 }
+
+// formatBudgetAlert renders an alert as e.g. "agent worker-1 $4.12 / $4.00".
+func formatBudgetAlert(a team.BudgetAlert) string {
+	scope := string(a.Scope)
+	if a.Key != "" {
+		scope += " " + a.Key
+	}
+	return fmt.Sprintf("%s $%.2f / $%.2f", scope, a.SpentUSD, a.LimitUSD)
+}
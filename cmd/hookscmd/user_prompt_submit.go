@@ -4,88 +4,28 @@ import (
 	"encoding/json"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/skill"
 )
 
 // UserPromptSubmitCmd represents the user-prompt-submit hook command
 var UserPromptSubmitCmd = &cobra.Command{
 	Use:   "user-prompt-submit",
-	Short: "Analyze user prompt and provide agent hints",
+	Short: "Analyze user prompt and inject matching skills",
 	Long: `UserPromptSubmit hook that analyzes user input before execution:
-- Suggests appropriate agents based on prompt keywords
+- Ranks the project's skills by their triggers (keywords, phase, agent,
+  language) and injects the best ones as additional context
 - Warns about dangerous command patterns
-- Provides helpful hints for better results`,
-	RunE: runUserPromptSubmit,
-}
 
-// Agent hint patterns - keyword to agent mapping
-var agentHintPatterns = []struct {
-	keywords []string
-	agent    string
-	hint     string
-}{
-	{
-		keywords: []string{"security", "vulnerability", "audit", "owasp", "cve", "exploit", "injection", "xss", "csrf"},
-		agent:    "security-auditor",
-		hint:     "Security analysis detected - security-auditor agent recommended",
-	},
-	{
-		keywords: []string{"debug", "error", "bug", "fix", "crash", "exception", "stacktrace", "traceback"},
-		agent:    "debugger",
-		hint:     "Debugging task detected - debugger agent recommended",
-	},
-	{
-		keywords: []string{"refactor", "cleanup", "simplify", "optimize code", "code smell", "technical debt"},
-		agent:    "refactorer",
-		hint:     "Refactoring task detected - refactorer agent recommended",
-	},
-	{
-		keywords: []string{"test", "coverage", "unit test", "integration test", "e2e", "spec", "jest", "vitest", "pytest"},
-		agent:    "test-guide",
-		hint:     "Testing task detected - test-guide agent recommended",
-	},
-	{
-		keywords: []string{"architecture", "design", "system design", "scalability", "microservice", "monolith"},
-		agent:    "architect",
-		hint:     "Architecture task detected - architect agent recommended",
-	},
-	{
-		keywords: []string{"document", "readme", "api doc", "jsdoc", "docstring", "changelog"},
-		agent:    "documenter",
-		hint:     "Documentation task detected - documenter agent recommended",
-	},
-	{
-		keywords: []string{"review", "code review", "pr review", "pull request"},
-		agent:    "reviewer",
-		hint:     "Code review detected - reviewer agent recommended",
-	},
-	{
-		keywords: []string{"build", "compile", "webpack", "vite", "rollup", "esbuild", "bundle"},
-		agent:    "build-fixer",
-		hint:     "Build task detected - build-fixer agent may help with errors",
-	},
-	{
-		keywords: []string{"deploy", "ci/cd", "pipeline", "github actions", "docker", "kubernetes", "k8s"},
-		agent:    "devops",
-		hint:     "DevOps task detected - devops agent recommended",
-	},
-	{
-		keywords: []string{"performance", "slow", "optimize", "profiling", "bottleneck", "memory leak", "latency"},
-		agent:    "optimizer",
-		hint:     "Performance task detected - optimizer agent recommended",
-	},
-	{
-		keywords: []string{"react", "vue", "angular", "component", "ui", "frontend", "css", "tailwind", "styled"},
-		agent:    "frontend",
-		hint:     "Frontend task detected - frontend agent recommended",
-	},
-	{
-		keywords: []string{"api", "endpoint", "rest", "graphql", "database", "sql", "backend", "server"},
-		agent:    "backend",
-		hint:     "Backend task detected - backend agent recommended",
-	},
+The phase comes from /jikime:1-plan, 2-run and 3-sync, the language from
+project files (go.mod, package.json, ...), and the agent from the prompt
+("@agent-backend", "backend agent") or JIKIME_ROLE. Skill bodies are
+inlined while they fit the token budget (JIKIME_SKILL_TOKEN_BUDGET,
+default 2000); the rest are listed as pointers to their SKILL.md.`,
+	RunE: runUserPromptSubmit,
 }
 
 // Dangerous patterns that should trigger warnings
@@ -135,14 +75,24 @@ var dangerousPatterns = []struct {
 	},
 }
 
+// maxInjectedSkills caps how many ranked skills are considered for
+// injection before the token budget applies.
+const maxInjectedSkills = 5
+
 type userPromptInput struct {
 	Prompt string `json:"prompt"`
 }
 
 type userPromptOutput struct {
-	Continue      bool              `json:"continue"`
-	SystemMessage string            `json:"systemMessage,omitempty"`
-	Performance   map[string]bool   `json:"performance,omitempty"`
+	Continue           bool                  `json:"continue"`
+	SystemMessage      string                `json:"systemMessage,omitempty"`
+	HookSpecificOutput *userPromptHookOutput `json:"hookSpecificOutput,omitempty"`
+	Performance        map[string]bool       `json:"performance,omitempty"`
+}
+
+type userPromptHookOutput struct {
+	HookEventName     string `json:"hookEventName"`
+	AdditionalContext string `json:"additionalContext,omitempty"`
 }
 
 func runUserPromptSubmit(cmd *cobra.Command, args []string) error {
@@ -151,10 +101,9 @@ func runUserPromptSubmit(cmd *cobra.Command, args []string) error {
 	decoder := json.NewDecoder(os.Stdin)
 	if err := decoder.Decode(&input); err != nil {
 		// Invalid JSON - continue without hints
-		return outputPromptResult("", true)
+		return outputPromptResult("", "")
 	}
 
-	prompt := strings.ToLower(input.Prompt)
 	var messages []string
 
 	// Check for dangerous patterns first (highest priority)
@@ -164,37 +113,71 @@ func runUserPromptSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Check for agent hints
-	agentSuggested := make(map[string]bool)
-	for _, hint := range agentHintPatterns {
-		for _, keyword := range hint.keywords {
-			if strings.Contains(prompt, keyword) {
-				if !agentSuggested[hint.agent] {
-					messages = append(messages, "💡 "+hint.hint)
-					agentSuggested[hint.agent] = true
-				}
-				break
-			}
-		}
+	// Inject the skills whose triggers match the prompt
+	additionalContext, names := suggestSkills(input.Prompt)
+	if len(names) > 0 {
+		messages = append(messages, "💡 Skills loaded: "+strings.Join(names, ", "))
+	}
+
+	return outputPromptResult(strings.Join(messages, "\n"), additionalContext)
+}
+
+// suggestSkills ranks the project's skills for the prompt by their trigger
+// metadata and renders the best ones within the token budget. It returns
+// the context and the names of the skills it includes.
+func suggestSkills(prompt string) (string, []string) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return "", nil
+	}
+	registry := skill.NewRegistry()
+	if err := registry.LoadFromProjectRoot(projectRoot); err != nil || registry.Count() == 0 {
+		return "", nil
 	}
 
-	// Build system message
-	systemMessage := ""
-	if len(messages) > 0 {
-		systemMessage = strings.Join(messages, "\n")
+	pc := skill.PromptContext{
+		Prompt:    prompt,
+		Agents:    registry.DetectAgents(prompt),
+		Languages: skill.DetectLanguages(projectRoot),
+	}
+	if phase := skill.DetectPhase(prompt); phase != "" {
+		pc.Phases = []string{phase}
+	}
+	// Team agents run with their role in the environment
+	if role := os.Getenv("JIKIME_ROLE"); role != "" {
+		pc.Agents = append(pc.Agents, role)
 	}
 
-	return outputPromptResult(systemMessage, true)
+	// Composed skills bring what they require or extend along
+	suggestions := registry.Compose(registry.Suggest(pc, maxInjectedSkills))
+	return skill.SuggestionContext(suggestions, skillTokenBudget())
 }
 
-func outputPromptResult(systemMessage string, continueExec bool) error {
+// skillTokenBudget returns JIKIME_SKILL_TOKEN_BUDGET, or the default
+// budget when it is unset or invalid.
+func skillTokenBudget() int {
+	if v := os.Getenv("JIKIME_SKILL_TOKEN_BUDGET"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return skill.DefaultTokenBudget
+}
+
+func outputPromptResult(systemMessage, additionalContext string) error {
 	output := userPromptOutput{
-		Continue:      continueExec,
+		Continue:      true,
 		SystemMessage: systemMessage,
 		Performance: map[string]bool{
 			"user_prompt_hook": true,
 		},
 	}
+	if additionalContext != "" {
+		output.HookSpecificOutput = &userPromptHookOutput{
+			HookEventName:     "UserPromptSubmit",
+			AdditionalContext: additionalContext,
+		}
+	}
 
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetEscapeHTML(false)
@@ -11,7 +11,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var infoShowBody bool
+var (
+	infoShowBody bool
+	infoResolve  bool
+)
 
 func newInfoCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -19,14 +22,22 @@ func newInfoCmd() *cobra.Command {
 		Short: "Show detailed information about a skill",
 		Long: `Show detailed information about a specific skill.
 
+With --resolve, prints the composed skill set to load: the skill with
+everything it extends and requires (front-matter "extends" and "requires"),
+transitively, in load order. Unknown skills, cycles and "conflicts"
+between members of the set are errors.
+
 Examples:
   jikime-adk skill info jikime-lang-typescript
-  jikime-adk skill info jikime-platform-vercel --body  # Show markdown body`,
+  jikime-adk skill info jikime-platform-vercel --body  # Show markdown body
+  jikime-adk skill info nextjs-migration --resolve     # Composed skill set
+  jikime-adk skill info nextjs-migration --resolve --body`,
 		Args: cobra.ExactArgs(1),
 		RunE: runInfo,
 	}
 
 	cmd.Flags().BoolVar(&infoShowBody, "body", false, "Show full markdown body content")
+	cmd.Flags().BoolVar(&infoResolve, "resolve", false, "Show the composed skill set (extends and requires)")
 
 	return cmd
 }
@@ -52,6 +63,10 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("skill not found: %s", skillName)
 	}
 
+	if infoResolve {
+		return showResolved(registry, skillName)
+	}
+
 	// If body is requested, reload with full content
 	if infoShowBody && s.FilePath != "" {
 		fullSkill, err := skill.LoadFromFile(s.FilePath)
@@ -81,6 +96,11 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		fmt.Println(s.FilePath)
 	}
 
+	if s.Pack != "" {
+		cyan.Printf("Pack: ")
+		fmt.Println(s.Pack)
+	}
+
 	// Tags
 	if len(s.Tags) > 0 {
 		cyan.Printf("\nTags: ")
@@ -140,6 +160,27 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Composition
+	if len(s.Requires) > 0 || len(s.Extends) > 0 || len(s.Conflicts) > 0 {
+		bold.Println("\nComposition:")
+		fmt.Println(strings.Repeat("-", 40))
+
+		if len(s.Extends) > 0 {
+			fmt.Printf("  Extends: %s\n", strings.Join(s.Extends, ", "))
+		}
+		if len(s.Requires) > 0 {
+			fmt.Printf("  Requires: %s\n", strings.Join(s.Requires, ", "))
+		}
+		if len(s.Conflicts) > 0 {
+			fmt.Printf("  Conflicts: %s\n", strings.Join(s.Conflicts, ", "))
+		}
+		for _, e := range registry.ValidateDependencies() {
+			if e.Skill == s.Name || containsString(e.Cycle, s.Name) {
+				color.Red("  ✗ %s", e.Message)
+			}
+		}
+	}
+
 	// Allowed tools
 	if len(s.AllowedTools) > 0 {
 		bold.Println("\nAllowed Tools:")
@@ -158,3 +199,50 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// showResolved prints the composed skill set for skillName in load order,
+// with each skill's body when --body is set.
+func showResolved(registry *skill.Registry, skillName string) error {
+	set, err := registry.Resolve(skillName)
+	if err != nil {
+		return err
+	}
+
+	bold := color.New(color.Bold)
+	cyan := color.New(color.FgCyan)
+
+	bold.Printf("Resolved skill set for '%s' (%d skills, load order):\n", skillName, len(set))
+	fmt.Println(strings.Repeat("-", 80))
+	for i, rs := range set {
+		cyan.Printf("%d. %s", i+1, rs.Skill.Name)
+		fmt.Printf("  (%s)\n", rs.Reason)
+		if rs.Skill.Description != "" {
+			fmt.Printf("   %s\n", truncate(rs.Skill.Description, 70))
+		}
+	}
+
+	if !infoShowBody {
+		return nil
+	}
+	for _, rs := range set {
+		s := rs.Skill
+		if s.FilePath != "" {
+			if full, err := skill.LoadFromFile(s.FilePath); err == nil {
+				s = full
+			}
+		}
+		bold.Printf("\n# %s\n", s.Name)
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println(s.Body)
+	}
+	return nil
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Package skillcmd provides the lint command for skills.
+package skillcmd
+
+import (
+	"fmt"
+	"os"
+
+	"jikime-adk/internal/skill"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFormat    string
+	lintMaxTokens int
+	lintStrict    bool
+)
+
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [path...]",
+		Short: "Validate SKILL.md files",
+		Long: `Validate SKILL.md files against the skill schema and the project.
+
+Checks:
+  front-matter  YAML front-matter is present and parses
+  schema        Fields match the SKILL.md JSON schema (unknown fields warn)
+  phase         triggers.phases are valid phases (plan, run, sync)
+  agent         agent and triggers.agents exist in .claude/agents
+  tool          allowed-tools names are real tools (or mcp__ tools)
+  link          Relative links in the body resolve
+  dependency    requires, extends and conflicts are consistent
+  size          Warns when the body exceeds the token budget
+  keyword       Warns on trigger keywords shared with unrelated skills
+
+With no paths, every skill in the project is linted. Issues are reported
+with file and line; --format json or github produces output for CI.
+
+Examples:
+  jikime-adk skill lint
+  jikime-adk skill lint .claude/skills/jikime-lang-go
+  jikime-adk skill lint --format github --strict`,
+		RunE:         runLint,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&lintFormat, "format", "text", "Output format: text, json, github")
+	cmd.Flags().IntVar(&lintMaxTokens, "max-tokens", skill.DefaultMaxBodyTokens, "Body size (estimated tokens) above which to warn")
+	cmd.Flags().BoolVar(&lintStrict, "strict", false, "Fail on warnings as well as errors")
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	format, err := skill.ParseLintFormat(lintFormat)
+	if err != nil {
+		return err
+	}
+
+	// Find project root
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("could not find project root: %w", err)
+	}
+
+	report, err := skill.Lint(projectRoot, args, skill.LintOptions{MaxBodyTokens: lintMaxTokens})
+	if err != nil {
+		return fmt.Errorf("failed to lint skills: %w", err)
+	}
+	if err := report.Render(os.Stdout, format); err != nil {
+		return err
+	}
+
+	if report.Errors() > 0 || (lintStrict && report.Warnings() > 0) {
+		return fmt.Errorf("skill lint failed")
+	}
+	return nil
+}
@@ -101,6 +101,10 @@ func outputTable(skills []*skill.Skill) error {
 		cyan.Printf("%-40s", s.Name)
 		fmt.Printf("  %s\n", truncate(s.Description, 35))
 
+		if s.Pack != "" {
+			fmt.Printf("  Pack: %s\n", s.Pack)
+		}
+
 		// Show tags
 		if len(s.Tags) > 0 {
 			fmt.Printf("  Tags: ")
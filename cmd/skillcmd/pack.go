@@ -0,0 +1,209 @@
+// Package skillcmd provides the install, update and remove commands for
+// skill packs.
+package skillcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"jikime-adk/internal/skill"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installName     string
+	installChecksum string
+	installVerify   bool
+	updateVersion   string
+)
+
+func newInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install [git-url|archive][@version]",
+		Short: "Install a skill pack from git or an archive",
+		Long: `Install a skill pack: a git repository or tar archive (.tar.gz, .tgz,
+.tar; URL or local path) containing SKILL.md files.
+
+The pack is fetched into a cache shared by all projects
+(~/.jikime/cache/skill-packs, or $JIKIME_SKILL_CACHE) and recorded in
+.jikime/skill-packs.lock with its resolved commit and checksum. Commit the
+lockfile; running install without arguments fetches the locked packs and
+verifies them against their checksums.
+
+Pack skills are loaded alongside project skills. A project skill with the
+same name takes precedence.
+
+Examples:
+  jikime-adk skill install https://github.com/acme/legacy-skills.git@v1.2.0
+  jikime-adk skill install git@github.com:acme/legacy-skills.git --verify-signature
+  jikime-adk skill install https://example.com/skills-{version}.tar.gz@2.0 --checksum sha256:9f86d0...
+  jikime-adk skill install`,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         runInstall,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&installName, "name", "", "Pack name (default: derived from the source)")
+	cmd.Flags().StringVar(&installChecksum, "checksum", "", "Expected sha256 of the archive or of the pack files")
+	cmd.Flags().BoolVar(&installVerify, "verify-signature", false, "Require a valid signature on the git tag or commit")
+
+	return cmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [pack...]",
+		Short: "Update installed skill packs",
+		Long: `Re-fetch installed skill packs from their recorded source and update the
+lockfile. A pack that follows a branch moves to its latest commit; use
+--version with a pack name to switch that pack to another tag or branch.
+
+Examples:
+  jikime-adk skill update
+  jikime-adk skill update legacy-skills --version v1.3.0`,
+		RunE:         runUpdate,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&updateVersion, "version", "", "Switch the named packs to this tag, branch or commit")
+
+	return cmd
+}
+
+func newRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove [pack]",
+		Short: "Remove an installed skill pack",
+		Long: `Remove a skill pack from the project's lockfile. The cached copy is kept
+for other projects that use it.
+
+Examples:
+  jikime-adk skill remove legacy-skills`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         runRemove,
+		SilenceUsage: true,
+	}
+}
+
+func runInstall(cmd *cobra.Command, args []string) error {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("could not find project root: %w", err)
+	}
+
+	green := color.New(color.FgGreen)
+	dim := color.New(color.Faint)
+
+	if len(args) == 0 {
+		restored, err := skill.RestorePacks(projectRoot)
+		for _, p := range restored {
+			green.Printf("✓ ")
+			fmt.Printf("%s restored (%s)\n", p.Name, shortResolved(p))
+		}
+		if err != nil {
+			return err
+		}
+		if len(restored) == 0 {
+			fmt.Println("All locked skill packs are installed.")
+		}
+		return nil
+	}
+
+	pack, err := skill.InstallPack(projectRoot, args[0], skill.InstallOptions{
+		Name:            installName,
+		Checksum:        installChecksum,
+		VerifySignature: installVerify,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %w", args[0], err)
+	}
+
+	green.Printf("✓ ")
+	fmt.Printf("Installed %s (%s)\n", pack.Name, shortResolved(pack))
+	dim.Printf("  %s\n", pack.Checksum)
+	fmt.Printf("  Skills: %s\n", strings.Join(pack.Skills, ", "))
+	warnShadowed(projectRoot, pack)
+	return nil
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("could not find project root: %w", err)
+	}
+
+	if updateVersion != "" && len(args) == 0 {
+		return fmt.Errorf("--version needs a pack name: jikime-adk skill update <pack> --version %s", updateVersion)
+	}
+
+	updates, err := skill.UpdatePacks(projectRoot, args, updateVersion)
+	for _, u := range updates {
+		if !u.Changed() {
+			fmt.Printf("  %s is up to date (%s)\n", u.New.Name, shortResolved(u.New))
+			continue
+		}
+		color.New(color.FgGreen).Printf("✓ ")
+		fmt.Printf("%s: %s → %s\n", u.New.Name, shortResolved(u.Old), shortResolved(u.New))
+		warnShadowed(projectRoot, u.New)
+	}
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		fmt.Println("No skill packs installed.")
+	}
+	return nil
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("could not find project root: %w", err)
+	}
+
+	pack, err := skill.RemovePack(projectRoot, args[0])
+	if err != nil {
+		return err
+	}
+	color.New(color.FgGreen).Printf("✓ ")
+	fmt.Printf("Removed %s (%d skills)\n", pack.Name, len(pack.Skills))
+	return nil
+}
+
+// shortResolved describes what a pack resolved to: version and short
+// commit for git, the archive otherwise.
+func shortResolved(p *skill.LockedPack) string {
+	resolved := p.Resolved
+	if p.Kind == skill.PackKindGit && len(resolved) > 12 {
+		resolved = resolved[:12]
+	}
+	if p.Version != "" {
+		return p.Version + ", " + resolved
+	}
+	return resolved
+}
+
+// warnShadowed notes pack skills hidden by a project skill or an earlier
+// pack of the same name.
+func warnShadowed(projectRoot string, pack *skill.LockedPack) {
+	registry := skill.NewRegistry()
+	if err := registry.LoadFromProjectRoot(projectRoot); err != nil {
+		return
+	}
+	var shadowed []string
+	for _, name := range pack.Skills {
+		s := registry.Get(name)
+		switch {
+		case s == nil || s.Pack == pack.Name:
+		case s.Pack == "":
+			shadowed = append(shadowed, name+" (project skill)")
+		default:
+			shadowed = append(shadowed, name+" (pack "+s.Pack+")")
+		}
+	}
+	if len(shadowed) > 0 {
+		color.New(color.FgYellow).Printf("  Not loaded, name taken by: %s\n", strings.Join(shadowed, ", "))
+	}
+}
@@ -3,6 +3,7 @@ package skillcmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"jikime-adk/internal/skill"
@@ -25,12 +26,20 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search skills by text, tags, or triggers",
 		Long: `Search skills by text, tags, or trigger conditions.
 
+A text query is ranked with BM25 over skill names, descriptions, keywords
+and SKILL.md bodies, and each result shows the best matching section with
+its heading and a snippet. English words are stemmed ("hydrated" finds
+"hydration"); Korean, Japanese and Chinese text is matched by character
+bigrams. The index is cached in .jikime/cache/skill-index.json and rebuilt
+when a SKILL.md changes. Tag and trigger flags filter the results.
+
 Examples:
   jikime-adk skill search nextjs           # Search by text
   jikime-adk skill search --tags framework,nextjs  # Filter by tags
   jikime-adk skill search --phases run     # Filter by phases
   jikime-adk skill search --agents frontend  # Filter by agents
-  jikime-adk skill search "react components" --limit 5  # Text search with limit`,
+  jikime-adk skill search "react components" --limit 5  # Text search with limit
+  jikime-adk skill search "hydration mismatch"  # Find skills discussing it`,
 		RunE: runSearch,
 	}
 
@@ -56,6 +65,10 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load skills: %w", err)
 	}
 
+	if len(args) > 0 {
+		return runTextSearch(registry, projectRoot, strings.Join(args, " "))
+	}
+
 	// Build search query
 	query := skill.SearchQuery{
 		Tags:      searchTags,
@@ -65,11 +78,6 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		Limit:     searchLimit,
 	}
 
-	// Add text query if provided
-	if len(args) > 0 {
-		query.Text = strings.Join(args, " ")
-	}
-
 	// Search
 	results := registry.Search(query)
 
@@ -112,6 +120,88 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runTextSearch prints full-text results with the matching section of each
+// skill, applying the tag and trigger flags as filters.
+func runTextSearch(registry *skill.Registry, projectRoot, query string) error {
+	var results []*skill.TextResult
+	for _, r := range registry.SearchText(query, 0) {
+		if matchesSearchFilters(r.Skill) {
+			results = append(results, r)
+		}
+	}
+	if searchLimit > 0 && len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No skills found matching the criteria.")
+		return nil
+	}
+
+	bold := color.New(color.Bold)
+	cyan := color.New(color.FgCyan)
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+
+	bold.Printf("Search Results (%d found):\n", len(results))
+	fmt.Println(strings.Repeat("-", 80))
+
+	for i, result := range results {
+		cyan.Printf("%d. %s", i+1, result.Skill.Name)
+		green.Printf(" (score: %.2f)\n", result.Score)
+		fmt.Printf("   %s\n", truncate(result.Skill.Description, 70))
+
+		if result.Heading != "" {
+			location := ""
+			if result.Skill.FilePath != "" && result.Line > 0 {
+				path := result.Skill.FilePath
+				if rel, err := filepath.Rel(projectRoot, path); err == nil {
+					path = rel
+				}
+				location = fmt.Sprintf("  (%s:%d)", path, result.Line)
+			}
+			yellow.Printf("   § %s", result.Heading)
+			fmt.Println(location)
+		}
+		if result.Snippet != "" {
+			fmt.Printf("   %s\n", result.Snippet)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// matchesSearchFilters applies the --tags, --phases, --agents and
+// --languages flags: a skill must match at least one value of each.
+func matchesSearchFilters(s *skill.Skill) bool {
+	for _, filter := range []struct {
+		values []string
+		has    func(string) bool
+	}{
+		{searchTags, s.HasTag},
+		{searchPhases, s.HasPhase},
+		{searchAgents, s.HasAgent},
+		{searchLanguages, s.HasLanguage},
+	} {
+		if len(filter.values) == 0 {
+			continue
+		}
+		matched := false
+		for _, v := range filter.values {
+			if filter.has(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
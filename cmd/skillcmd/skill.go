@@ -16,7 +16,11 @@ Commands:
   list      List all available skills
   search    Search skills by text, tags, or triggers
   related   Find skills related to a given skill
-  info      Show detailed information about a skill`,
+  info      Show detailed information about a skill
+  lint      Validate SKILL.md files
+  install   Install a skill pack from git or an archive
+  update    Update installed skill packs
+  remove    Remove an installed skill pack`,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
@@ -26,6 +30,10 @@ Commands:
 	cmd.AddCommand(newSearchCmd())
 	cmd.AddCommand(newRelatedCmd())
 	cmd.AddCommand(newInfoCmd())
+	cmd.AddCommand(newLintCmd())
+	cmd.AddCommand(newInstallCmd())
+	cmd.AddCommand(newUpdateCmd())
+	cmd.AddCommand(newRemoveCmd())
 
 	return cmd
 }
@@ -0,0 +1,178 @@
+package tagcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/tag"
+)
+
+func newGraphCmd() *cobra.Command {
+	var (
+		format    string
+		withFiles bool
+		impact    bool
+		since     string
+		staged    bool
+		testsOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "graph [SPEC-ID | files...]",
+		Short: "SPEC relationship graph and change impact analysis",
+		Long: `Show how SPECs relate to each other and to files.
+
+SPEC-to-SPEC edges come from two places:
+  - spec.md front-matter:  depends: [SPEC-AUTH-001]  /  related: SPEC-USER-002
+  - TAGs: a "depends" or "related" TAG in a file that implements or
+    verifies SPEC X links X to the referenced SPEC.
+
+TAGs come from the linkage database, refreshed in memory for files that
+changed since it was written. The database itself is only updated by
+'jikime tag scan'.
+
+With --impact, the graph answers "which SPECs and tests are affected by
+changing these files?". Pass files as arguments, or use --since/--staged
+to take them from git. A SPEC is affected when a file with its impl or
+verify TAG or its SPEC document changed (including a deleted file, or a
+removed TAG the database still records), and when it depends on an
+affected SPEC. Only the affected SPECs' tests and acceptance criteria
+need verifying again. An impact query re-reads just the changed files.
+
+Formats: text (default), json, dot (Graphviz; graph only).
+
+Examples:
+  jikime tag graph
+  jikime tag graph SPEC-AUTH-001 --files
+  jikime tag graph --format dot | dot -Tsvg > specs.svg
+  jikime tag graph --impact src/auth/login.go
+  jikime tag graph --impact --since origin/main --format json
+  jikime tag graph --impact --staged --tests`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmtKind, err := tag.ParseGraphFormat(format)
+			if err != nil {
+				return err
+			}
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if !impact {
+				if since != "" || staged || testsOnly {
+					return fmt.Errorf("--since, --staged and --tests need --impact")
+				}
+				if len(args) > 1 {
+					return fmt.Errorf("expected at most one SPEC-ID (use --impact for files)")
+				}
+				graph, err := tag.LoadProjectGraph(root, nil)
+				if err != nil {
+					return err
+				}
+				if len(args) == 1 {
+					return showSpecNeighborhood(graph, args[0], fmtKind, withFiles)
+				}
+				return graph.Render(os.Stdout, fmtKind, withFiles)
+			}
+
+			var changed []string
+			switch {
+			case since != "" || staged:
+				if len(args) > 0 {
+					return fmt.Errorf("pass files or --since/--staged, not both")
+				}
+				if changed, err = tag.GitChangedFiles(root, since, staged); err != nil {
+					return err
+				}
+			case len(args) > 0:
+				for _, a := range args {
+					abs, err := filepath.Abs(a)
+					if err != nil {
+						return err
+					}
+					changed = append(changed, abs)
+				}
+			default:
+				if changed, err = tag.GitChangedFiles(root, "", false); err != nil {
+					return err
+				}
+			}
+
+			// Only the changed files are re-read, so the TAGs they had
+			// before the change are still known to the impact query.
+			graph, err := tag.LoadProjectGraph(root, changed)
+			if err != nil {
+				return err
+			}
+			result := graph.Impact(changed)
+			if testsOnly {
+				for _, t := range result.Tests {
+					fmt.Println(t)
+				}
+				return nil
+			}
+			return result.Render(os.Stdout, fmtKind)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json or dot")
+	cmd.Flags().BoolVar(&withFiles, "files", false, "Include file locations")
+	cmd.Flags().BoolVarP(&impact, "impact", "i", false, "Show SPECs and tests affected by changed files")
+	cmd.Flags().StringVar(&since, "since", "", "Impact of changes since a git ref")
+	cmd.Flags().BoolVar(&staged, "staged", false, "Impact of staged changes")
+	cmd.Flags().BoolVar(&testsOnly, "tests", false, "Print only the affected test files, one per line")
+	cmd.MarkFlagsMutuallyExclusive("since", "staged")
+
+	return cmd
+}
+
+// showSpecNeighborhood prints one SPEC with its direct relations.
+func showSpecNeighborhood(graph *tag.Graph, specID string, format tag.GraphFormat, withFiles bool) error {
+	n := graph.Node(specID)
+	if n == nil {
+		return fmt.Errorf("%s: no SPEC document or TAGs", specID)
+	}
+	if format == tag.GraphJSON {
+		return (&tag.Graph{Root: graph.Root, Specs: []*tag.SpecNode{n}}).Render(os.Stdout, format, withFiles)
+	}
+	if format != tag.GraphText {
+		return fmt.Errorf("--format %s is only available for the whole graph", format)
+	}
+
+	color.Cyan("%s  %s", n.SpecID, n.Title)
+	if n.Status != "" {
+		fmt.Printf("  status:     %s\n", n.Status)
+	}
+	for _, edge := range []struct {
+		label string
+		ids   []string
+	}{{"depends on", n.Depends}, {"needed by", n.Dependents}, {"related", n.Related}} {
+		if len(edge.ids) == 0 {
+			continue
+		}
+		fmt.Printf("  %s:\n", edge.label)
+		for _, id := range edge.ids {
+			title := ""
+			if other := graph.Node(id); other != nil && other.Title != "" {
+				title = "  " + other.Title
+			}
+			fmt.Printf("    %s%s\n", id, title)
+		}
+	}
+	fmt.Printf("  impl:       %d location(s)\n", len(n.Impl))
+	fmt.Printf("  verify:     %d location(s)\n", len(n.Verify))
+	if withFiles {
+		for _, locs := range [][]tag.Location{n.Impl, n.Verify} {
+			for _, l := range locs {
+				fmt.Printf("    %s\n", l)
+			}
+		}
+	}
+	if len(n.Acceptance) > 0 {
+		fmt.Printf("  acceptance: %s\n", strings.Join(n.Acceptance, "; "))
+	}
+	return nil
+}
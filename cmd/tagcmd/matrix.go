@@ -0,0 +1,109 @@
+package tagcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/tag"
+)
+
+func newMatrixCmd() *cobra.Command {
+	var (
+		format   string
+		output   string
+		specsDir string
+		scan     bool
+		strict   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "SPEC traceability matrix and coverage report",
+		Long: `List every SPEC in .jikime/specs with the files that implement it
+(@SPEC ... impl), the tests that verify it (@SPEC ... verify), and its
+acceptance criteria from acceptance.md. SPECs without an implementation
+or without tests are flagged, and TAGs that reference unknown SPECs are
+listed at the end.
+
+TAGs come from the linkage database (see 'tag linkage rebuild'); with
+--scan, or when the database is empty, the project is scanned instead.
+
+The format defaults to the extension of --output (csv, html, json), or a
+table on stdout.
+
+Examples:
+  jikime tag matrix
+  jikime tag matrix --output traceability.html
+  jikime tag matrix --format csv --output traceability.csv
+  jikime tag matrix --strict   # exit 1 if any SPEC lacks impl or tests`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = strings.TrimPrefix(filepath.Ext(output), ".")
+			}
+			fmtKind, err := tag.ParseMatrixFormat(format)
+			if err != nil {
+				return err
+			}
+
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if specsDir == "" {
+				specsDir = filepath.Join(root, ".jikime", "specs")
+			}
+			specs, err := tag.LoadSpecDocuments(specsDir)
+			if err != nil {
+				return fmt.Errorf("read SPECs: %w", err)
+			}
+
+			var tags []*tag.TAG
+			if !scan {
+				lm, err := getLinkageManager()
+				if err != nil {
+					return err
+				}
+				tags = lm.GetAllTags()
+			}
+			if len(tags) == 0 {
+				tags = tag.ExtractTagsFromSupportedFiles(root, true)
+			}
+
+			matrix := tag.BuildMatrix(root, specs, tags)
+			if output == "" {
+				if err := matrix.Render(os.Stdout, fmtKind); err != nil {
+					return err
+				}
+			} else {
+				var buf bytes.Buffer
+				if err := matrix.Render(&buf, fmtKind); err != nil {
+					return err
+				}
+				if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil {
+					return fmt.Errorf("write matrix: %w", err)
+				}
+				color.Green("Traceability matrix written to %s (%d SPEC(s), %.0f%% covered)",
+					output, matrix.Summary.Specs, matrix.CoveragePercent())
+			}
+
+			if gaps := matrix.Gaps(); strict && len(gaps) > 0 {
+				return fmt.Errorf("%d SPEC(s) lack an implementation or tests", len(gaps))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Output format: table, csv, html or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the matrix to a file instead of stdout")
+	cmd.Flags().StringVar(&specsDir, "specs-dir", "", "SPEC documents directory (default: .jikime/specs)")
+	cmd.Flags().BoolVar(&scan, "scan", false, "Scan the project for TAGs instead of reading the linkage database")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error if any SPEC lacks an implementation or tests")
+
+	return cmd
+}
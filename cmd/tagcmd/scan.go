@@ -1,9 +1,13 @@
 package tagcmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -14,6 +18,10 @@ func newScanCmd() *cobra.Command {
 	var (
 		recursive bool
 		pattern   string
+		update    bool
+		since     string
+		staged    bool
+		watch     bool
 	)
 
 	cmd := &cobra.Command{
@@ -21,10 +29,35 @@ func newScanCmd() *cobra.Command {
 		Short: "Scan files for TAGs",
 		Long: `Scan files or directories for @SPEC TAGs.
 
+TAGs are read from comments in the file's own syntax: # (Python, shell,
+YAML, Ruby), // and /* */ (Go, Java, C#, JavaScript/TypeScript, PHP),
+-- (SQL, Lua), <!-- --> or <%-- --%> (HTML, XML, JSP), and both markup
+and // /* */ comments in Vue single-file components. Text inside
+string literals is ignored. Files with other extensions use # comments.
+
+  // @SPEC SPEC-AUTH-001 impl
+  /** @SPEC SPEC-AUTH-001 verify */
+  -- @SPEC SPEC-DB-002
+  <%-- @SPEC SPEC-WEB-003 --%>
+
+With --update the linkage database (.jikime/tag_linkage.json) is brought
+up to date incrementally instead of listing TAGs. Each file's mtime, size
+and content hash are stored, so only files that actually changed are
+parsed again and deleted files lose their TAGs. --since and --staged limit
+the update to the files git reports as changed, which keeps a pre-commit
+hook fast on large repositories. The database indexes the work tree, so
+--staged reads the current copy of each staged file, including edits not
+yet staged. --watch keeps the database live by
+re-indexing files as they change, until interrupted.
+
 Examples:
   jikime tag scan .
   jikime tag scan src/ --recursive
-  jikime tag scan --pattern "*.py"`,
+  jikime tag scan --pattern "*.py"
+  jikime tag scan --update
+  jikime tag scan --staged
+  jikime tag scan --since origin/main
+  jikime tag scan --watch`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			path := "."
@@ -42,6 +75,13 @@ Examples:
 				return fmt.Errorf("path not found: %s", path)
 			}
 
+			if update || since != "" || staged || watch {
+				if !info.IsDir() && watch {
+					return fmt.Errorf("--watch needs a directory: %s", path)
+				}
+				return runIndexScan(cmd.Context(), absPath, since, staged, watch)
+			}
+
 			var tags []*tag.TAG
 
 			if info.IsDir() {
@@ -73,6 +113,66 @@ Examples:
 
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Scan directories recursively")
 	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "File pattern to match (e.g., *.py)")
+	cmd.Flags().BoolVarP(&update, "update", "u", false, "Update the linkage database incrementally")
+	cmd.Flags().StringVar(&since, "since", "", "Only index files changed since a git ref (implies --update)")
+	cmd.Flags().BoolVar(&staged, "staged", false, "Only index staged files (implies --update)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep the linkage database updated as files change")
+	cmd.MarkFlagsMutuallyExclusive("since", "staged")
 
 	return cmd
 }
+
+// runIndexScan updates the linkage database for path: the files git
+// reports as changed with since/staged, otherwise everything under path
+// (or path itself). With watch it then follows changes until interrupted.
+func runIndexScan(ctx context.Context, path, since string, staged, watch bool) error {
+	lm, err := getLinkageManager()
+	if err != nil {
+		return err
+	}
+
+	var stats *tag.IndexStats
+	if since != "" || staged {
+		files, err := tag.GitChangedFiles(path, since, staged)
+		if err != nil {
+			return err
+		}
+		stats, err = lm.UpdateFiles(files)
+		if err != nil {
+			return err
+		}
+	} else {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			stats, err = lm.Sync(path)
+		} else {
+			stats, err = lm.UpdateFiles([]string{path})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	color.Green("Linkage database updated: %s", stats)
+
+	if !watch {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	color.Cyan("Watching %s for TAG changes (Ctrl+C to stop)", path)
+	return lm.Watch(ctx, path, func(stats *tag.IndexStats, err error) {
+		stamp := time.Now().Format("15:04:05")
+		if err != nil {
+			color.Yellow("%s  Warning: %v", stamp, err)
+			return
+		}
+		fmt.Printf("%s  %s\n", stamp, stats)
+	})
+}
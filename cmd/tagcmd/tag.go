@@ -15,7 +15,9 @@ func NewTag() *cobra.Command {
 Commands:
   validate  Validate TAGs in staged files (pre-commit)
   scan      Scan files for TAGs
-  linkage   Manage TAG↔CODE linkage database`,
+  linkage   Manage TAG↔CODE linkage database
+  matrix    SPEC traceability matrix and coverage report
+  graph     SPEC relationship graph and change impact analysis`,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
@@ -24,6 +26,8 @@ Commands:
 	cmd.AddCommand(newValidateCmd())
 	cmd.AddCommand(newScanCmd())
 	cmd.AddCommand(newLinkageCmd())
+	cmd.AddCommand(newMatrixCmd())
+	cmd.AddCommand(newGraphCmd())
 
 	return cmd
 }
@@ -20,9 +20,11 @@ type ValidationConfig struct {
 
 func newValidateCmd() *cobra.Command {
 	var (
-		mode    string
+		mode     string
 		specsDir string
-		files   []string
+		files    []string
+		fix      bool
+		all      bool
 	)
 
 	cmd := &cobra.Command{
@@ -35,17 +37,70 @@ Modes:
   enforce  Block commit on validation errors
   off      Skip validation
 
-If no files specified, validates staged git files.`,
+If no files specified, validates staged git files. With --all, every
+supported file in the project is validated.
+
+Project rules are read from .jikime/config/tag-policy.yaml:
+
+  tag_policy:
+    mode: enforce                 # default for --mode
+    format:                       # malformed TAGs (@spec, spec-auth-1, Impl)
+      level: error
+    require_impl:                 # files that must carry an impl TAG
+      level: error
+      paths: ["src/**"]
+      exclude: ["**/*_test.*", "**/testdata/**"]
+    require_verify:               # SPECs that need a verify TAG
+      level: warning
+    domains:                      # allowed SPEC-ID domains
+      level: error
+      allowed: [AUTH, USER, API]
+    deprecated:                   # no references to deprecated SPECs
+      level: error
+      specs: [SPEC-LEGACY-001]    # plus SPECs with status: deprecated
+
+Levels are error, warning, hint or off. Without a policy file only
+malformed TAGs and references to deprecated SPECs are reported, as
+warnings. require_verify checks the SPECs
+the validated files implement (every SPEC with --all), looking up verify
+TAGs in the index kept by 'jikime tag scan' plus the validated files.
+
+--fix rewrites malformed TAGs in place before validating: the marker
+becomes @SPEC, SPEC-IDs are normalized (spec_auth_1 -> SPEC-AUTH-001) and
+verbs lowercased (Impl -> impl, tests -> verify). When validating staged
+files the fixed files are re-staged; a fixed file that also has unstaged
+changes is reported as an error instead, to be reviewed and added by hand.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			policy, err := tag.LoadPolicy(cwd)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("mode") {
+				mode = policy.Mode
+			}
+
 			// Get files to validate
-			filesToValidate := args
-			if len(filesToValidate) == 0 {
+			filesToValidate := append(args, files...)
+			staged := false
+			if all {
+				filesToValidate = nil
+				for _, file := range tag.SupportedFiles(cwd) {
+					if rel, err := filepath.Rel(cwd, file); err == nil {
+						file = rel
+					}
+					filesToValidate = append(filesToValidate, file)
+				}
+			} else if len(filesToValidate) == 0 {
 				// Get staged files from git
-				staged, err := getStagedFiles()
+				stagedFiles, err := getStagedFiles()
 				if err != nil {
 					return nil // Not in git repo or no staged files
 				}
-				filesToValidate = staged
+				filesToValidate, staged = stagedFiles, true
 			}
 
 			if len(filesToValidate) == 0 {
@@ -55,22 +110,120 @@ If no files specified, validates staged git files.`,
 
 			// Find specs directory
 			if specsDir == "" {
-				cwd, _ := os.Getwd()
 				specsDir = filepath.Join(cwd, ".jikime", "specs")
 			}
 
+			var pending []ValidationResult
+			if fix {
+				var unstaged map[string]bool
+				if staged {
+					// Files with unstaged edits cannot be re-staged without
+					// committing those edits too.
+					unstaged = getUnstagedFiles()
+				}
+				fixed := fixFiles(filesToValidate)
+				if staged && len(fixed) > 0 {
+					pending = restageFixed(fixed, unstaged)
+				}
+			}
+
 			// Validate files
-			return validateFiles(filesToValidate, specsDir, mode)
+			return validateFiles(filesToValidate, specsDir, mode, policy, cwd, all, pending)
 		},
 	}
 
-	cmd.Flags().StringVar(&mode, "mode", "warn", "Validation mode: warn, enforce, off")
+	cmd.Flags().StringVar(&mode, "mode", "warn", "Validation mode: warn, enforce, off (default from the policy file)")
 	cmd.Flags().StringVar(&specsDir, "specs-dir", "", "SPEC documents directory")
 	cmd.Flags().StringSliceVarP(&files, "files", "f", nil, "Files to validate")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Rewrite malformed TAGs before validating")
+	cmd.Flags().BoolVar(&all, "all", false, "Validate every supported file and SPEC in the project")
 
 	return cmd
 }
 
+// fixFiles rewrites malformed TAGs, reports what changed and returns the
+// files that were rewritten.
+func fixFiles(files []string) []string {
+	fixed := 0
+	var changed []string
+	for _, file := range files {
+		if !fileExists(file) {
+			continue
+		}
+		fixes, err := tag.FixTagsInFile(file)
+		if err != nil {
+			color.Yellow("Warning: could not fix %s: %v", file, err)
+			continue
+		}
+		n := 0
+		for _, f := range fixes {
+			if f.Fixable() {
+				fmt.Printf("  %s %s:%d: %s -> %s\n", color.GreenString("FIXED"), file, f.Line, f.Original, f.Fixed)
+				n++
+			}
+		}
+		if n > 0 {
+			changed = append(changed, file)
+			fixed += n
+		}
+	}
+	if fixed > 0 {
+		color.Green("Fixed %d TAG(s) in %d file(s)", fixed, len(changed))
+	}
+	return changed
+}
+
+// restageFixed stages the files fixFiles rewrote, so the commit contains
+// the fixed TAGs rather than the malformed ones still in the index. Files
+// that also have unstaged edits are left alone and reported as errors:
+// staging them would commit those edits as well.
+func restageFixed(files []string, unstaged map[string]bool) []ValidationResult {
+	var add []string
+	var results []ValidationResult
+	for _, file := range files {
+		if unstaged[filepath.ToSlash(filepath.Clean(file))] {
+			results = append(results, ValidationResult{
+				File:    file,
+				Level:   "error",
+				Message: "TAGs fixed in the working tree only (the file has unstaged changes); review and git add it",
+			})
+			continue
+		}
+		add = append(add, file)
+	}
+	if len(add) > 0 {
+		out, err := exec.Command("git", append([]string{"add", "--"}, add...)...).CombinedOutput()
+		if err != nil {
+			for _, file := range add {
+				results = append(results, ValidationResult{
+					File:    file,
+					Level:   "error",
+					Message: fmt.Sprintf("could not re-stage fixed file: %s", strings.TrimSpace(string(out))),
+				})
+			}
+		} else {
+			color.Green("Re-staged %d fixed file(s)", len(add))
+		}
+	}
+	return results
+}
+
+// getUnstagedFiles returns the files with changes not yet staged, as
+// getStagedFiles names them.
+func getUnstagedFiles() map[string]bool {
+	output, err := exec.Command("git", "diff", "--name-only").Output()
+	if err != nil {
+		return nil
+	}
+	files := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files[line] = true
+		}
+	}
+	return files
+}
+
 // getStagedFiles returns a list of staged files from git.
 func getStagedFiles() ([]string, error) {
 	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACM")
@@ -82,7 +235,7 @@ func getStagedFiles() ([]string, error) {
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	var files []string
 	for _, line := range lines {
-		if line != "" && isSupportedFile(line) {
+		if line != "" && tag.IsSupportedFile(line) {
 			files = append(files, line)
 		}
 	}
@@ -90,42 +243,26 @@ func getStagedFiles() ([]string, error) {
 	return files, nil
 }
 
-// isSupportedFile checks if a file supports TAG comments.
-func isSupportedFile(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	supportedExts := map[string]bool{
-		".py":     true,
-		".sh":     true,
-		".bash":   true,
-		".yaml":   true,
-		".yml":    true,
-		".toml":   true,
-		".rb":     true,
-		".pl":     true,
-		".r":      true,
-		".coffee": true,
-	}
-	return supportedExts[ext]
-}
-
 // ValidationResult holds the result of TAG validation.
 type ValidationResult struct {
-	File     string
-	Line     int
-	Level    string // error, warning, hint
-	Message  string
-	TAG      *tag.TAG
+	File    string
+	Line    int
+	Level   string // error, warning, hint
+	Message string
+	TAG     *tag.TAG
 }
 
-// validateFiles validates TAGs in the given files.
-func validateFiles(files []string, specsDir, mode string) error {
+// validateFiles validates TAGs in the given files, then applies the
+// project policy. pending holds results found before validation, such as
+// fixed files that could not be re-staged.
+func validateFiles(files []string, specsDir, mode string, policy *tag.Policy, root string, all bool, pending []ValidationResult) error {
 	if mode == "off" {
 		fmt.Println("TAG validation mode: off")
 		return nil
 	}
 
-	var results []ValidationResult
-	hasErrors := false
+	results := pending
+	hasErrors := len(pending) > 0
 
 	for _, file := range files {
 		if !fileExists(file) {
@@ -196,6 +333,14 @@ func validateFiles(files []string, specsDir, mode string) error {
 		}
 	}
 
+	policyResults := checkPolicy(files, specsDir, policy, root, all)
+	for _, r := range policyResults {
+		if r.Level == "error" {
+			hasErrors = true
+		}
+	}
+	results = append(results, policyResults...)
+
 	// Display results
 	if len(results) > 0 {
 		fmt.Println()
@@ -212,7 +357,11 @@ func validateFiles(files []string, specsDir, mode string) error {
 			case "hint":
 				prefix = color.HiBlackString("HINT")
 			}
-			fmt.Printf("  [%s] %s:%d: %s\n", prefix, r.File, r.Line, r.Message)
+			if r.Line > 0 {
+				fmt.Printf("  [%s] %s:%d: %s\n", prefix, r.File, r.Line, r.Message)
+			} else {
+				fmt.Printf("  [%s] %s: %s\n", prefix, r.File, r.Message)
+			}
 		}
 
 		fmt.Println(strings.Repeat("=", 60))
@@ -232,6 +381,62 @@ func validateFiles(files []string, specsDir, mode string) error {
 	return nil
 }
 
+// checkPolicy applies the project's TAG policy to files. require-verify
+// covers the documented SPECs the files implement, or every SPEC with all.
+func checkPolicy(files []string, specsDir string, policy *tag.Policy, root string, all bool) []ValidationResult {
+	specs, _ := tag.LoadSpecDocuments(specsDir)
+	checker := policy.NewChecker(root, specs)
+
+	var violations []tag.Violation
+	implemented := map[string]bool{}
+	for _, file := range files {
+		if !fileExists(file) {
+			continue
+		}
+		violations = append(violations, checker.CheckFile(file)...)
+		for _, t := range tag.ExtractTagsFromFile(file) {
+			if t.Verb == "impl" {
+				implemented[t.SpecID] = true
+			}
+		}
+	}
+
+	if policy.RequireVerify.Enabled() {
+		var ids []string
+		if all {
+			for _, doc := range specs {
+				ids = append(ids, doc.ID)
+			}
+		} else {
+			for id := range implemented {
+				if tag.SpecDocumentExists(id, specsDir) {
+					ids = append(ids, id)
+				}
+			}
+		}
+		// Only the validated files are re-read on top of the TAG index;
+		// a full sync of the tree is left to --all.
+		changed := files
+		if all {
+			changed = nil
+		}
+		if graph, err := tag.LoadProjectGraph(root, changed); err == nil {
+			violations = append(violations, checker.CheckVerify(ids, graph)...)
+		}
+	}
+
+	results := make([]ValidationResult, 0, len(violations))
+	for _, v := range violations {
+		results = append(results, ValidationResult{
+			File:    v.File,
+			Line:    v.Line,
+			Level:   v.Level,
+			Message: fmt.Sprintf("%s (%s)", v.Message, v.Rule),
+		})
+	}
+	return results
+}
+
 // getValidationLevel determines the validation level based on context.
 func getValidationLevel(mode string) string {
 	if mode == "enforce" {
@@ -0,0 +1,336 @@
+package teamcmd
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"jikime-adk/internal/team"
+)
+
+// boardActor is the sender / reviewer recorded for actions taken in the web UI.
+const boardActor = "board"
+
+// boardCSRFHeader carries the per-server CSRF token on every write request.
+const boardCSRFHeader = "X-CSRF-Token"
+
+// newBoardCSRFToken returns a random token embedded in the served SPA.
+// Write endpoints require it in boardCSRFHeader, so a page on another
+// origin cannot drive the board even though the server binds to localhost.
+func newBoardCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate csrf token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// requireCSRF wraps a write handler with CSRF checks:
+//   - the X-CSRF-Token header must match the server token;
+//   - the body must be JSON (rules out cross-site HTML form posts);
+//   - Origin, when sent, must be the board's own origin.
+func requireCSRF(token string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get(boardCSRFHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeBoardError(w, http.StatusForbidden, errors.New("missing or invalid CSRF token"))
+			return
+		}
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+			writeBoardError(w, http.StatusUnsupportedMediaType, errors.New("content type must be application/json"))
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				writeBoardError(w, http.StatusForbidden, fmt.Errorf("cross-origin request from %s rejected", origin))
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// registerBoardActions adds the write endpoints to mux. All are POST and
+// CSRF-protected; they reply with the updated object or {"error": ...}.
+//
+//	POST /api/team/{name}/tasks                    create task
+//	POST /api/team/{name}/tasks/{id}/assign        reassign task owner
+//	POST /api/team/{name}/tasks/{id}/priority      change priority
+//	POST /api/team/{name}/tasks/{id}/fail          mark task failed
+//	POST /api/team/{name}/plans/{id}/approve       approve plan
+//	POST /api/team/{name}/plans/{id}/reject        reject plan
+//	POST /api/team/{name}/inbox/send               send (or broadcast) a message
+//	POST /api/team/{name}/agents/{id}/stop         stop an agent
+func registerBoardActions(mux *http.ServeMux, token string) {
+	handle := func(pattern string, h func(td string, r *http.Request) (any, error)) {
+		mux.HandleFunc("POST "+pattern, requireCSRF(token, func(w http.ResponseWriter, r *http.Request) {
+			td := teamDir(r.PathValue("name"))
+			if _, err := os.Stat(td); err != nil {
+				writeBoardError(w, http.StatusNotFound, fmt.Errorf("team %q not found", r.PathValue("name")))
+				return
+			}
+			v, err := h(td, r)
+			if err != nil {
+				status := http.StatusBadRequest
+				var nf *team.ErrTaskNotFound
+				if errors.As(err, &nf) {
+					status = http.StatusNotFound
+				}
+				writeBoardError(w, status, err)
+				return
+			}
+			writeJSON2(w, v)
+		}))
+	}
+
+	handle("/api/team/{name}/tasks", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			Title       string   `json:"title"`
+			Description string   `json:"description"`
+			DoD         string   `json:"dod"`
+			Priority    int      `json:"priority"`
+			Owner       string   `json:"owner"`
+			DependsOn   []string `json:"depends_on"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		if req.Title == "" {
+			return nil, errors.New("title is required")
+		}
+		store, err := team.NewStore(filepath.Join(td, "tasks"))
+		if err != nil {
+			return nil, err
+		}
+		t, err := store.Create(req.Title, req.Description, req.DoD, req.DependsOn, req.Priority, nil, req.Owner)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"task": t}, nil
+	})
+
+	handle("/api/team/{name}/tasks/{id}/assign", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			Owner string `json:"owner"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		store, err := team.NewStore(filepath.Join(td, "tasks"))
+		if err != nil {
+			return nil, err
+		}
+		t, err := store.Assign(r.PathValue("id"), req.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if req.Owner != "" {
+			_ = team.NewTeamInbox(td).Send(&team.Message{
+				TeamName: filepath.Base(td),
+				Kind:     team.MessageKindSystem,
+				From:     boardActor,
+				To:       req.Owner,
+				Subject:  "task_assigned",
+				Body:     fmt.Sprintf("Task %.8s %q was assigned to you. Claim it with: jikime team tasks claim %s %s", t.ID, t.Title, filepath.Base(td), t.ID),
+			})
+		}
+		return map[string]any{"task": t}, nil
+	})
+
+	handle("/api/team/{name}/tasks/{id}/priority", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			Priority int `json:"priority"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		if req.Priority < 0 {
+			return nil, errors.New("priority must be 0 (default) or a positive integer")
+		}
+		store, err := team.NewStore(filepath.Join(td, "tasks"))
+		if err != nil {
+			return nil, err
+		}
+		t, err := store.SetPriority(r.PathValue("id"), req.Priority)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"task": t}, nil
+	})
+
+	handle("/api/team/{name}/tasks/{id}/fail", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		if req.Reason == "" {
+			req.Reason = "failed from board"
+		}
+		store, err := team.NewStore(filepath.Join(td, "tasks"))
+		if err != nil {
+			return nil, err
+		}
+		t, err := store.Fail(r.PathValue("id"), boardActor, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"task": t}, nil
+	})
+
+	handle("/api/team/{name}/plans/{id}/approve", func(td string, r *http.Request) (any, error) {
+		ps, err := boardPlanStore(td, r.PathValue("id"))
+		if err != nil {
+			return nil, err
+		}
+		plan, err := ps.Approve(r.PathValue("id"), boardActor)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"plan": plan}, nil
+	})
+
+	handle("/api/team/{name}/plans/{id}/reject", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		ps, err := boardPlanStore(td, r.PathValue("id"))
+		if err != nil {
+			return nil, err
+		}
+		plan, err := ps.Reject(r.PathValue("id"), boardActor, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"plan": plan}, nil
+	})
+
+	handle("/api/team/{name}/inbox/send", func(td string, r *http.Request) (any, error) {
+		var req struct {
+			To      string `json:"to"`
+			Subject string `json:"subject"`
+			Body    string `json:"body"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			return nil, err
+		}
+		if req.To == "" || req.Body == "" {
+			return nil, errors.New("to and body are required")
+		}
+		name := filepath.Base(td)
+		ti := team.NewTeamInbox(td)
+		msg := &team.Message{
+			TeamName: name,
+			Kind:     team.MessageKindDirect,
+			From:     boardActor,
+			To:       req.To,
+			Subject:  req.Subject,
+			Body:     req.Body,
+			SentAt:   time.Now(),
+		}
+		if req.To == "broadcast" {
+			reg, err := team.NewRegistry(filepath.Join(td, "registry"))
+			if err != nil {
+				return nil, err
+			}
+			agents, _ := reg.List()
+			ids := make([]string, 0, len(agents))
+			for _, a := range agents {
+				ids = append(ids, a.ID)
+			}
+			msg.Kind = team.MessageKindBroadcast
+			msg.To = ""
+			if err := ti.Broadcast(msg, ids); err != nil {
+				return nil, err
+			}
+			return map[string]any{"message": msg}, nil
+		}
+		if err := ti.Send(msg); err != nil {
+			return nil, err
+		}
+		return map[string]any{"message": msg}, nil
+	})
+
+	handle("/api/team/{name}/agents/{id}/stop", func(td string, r *http.Request) (any, error) {
+		agentID := r.PathValue("id")
+		reg, err := team.NewRegistry(filepath.Join(td, "registry"))
+		if err != nil {
+			return nil, err
+		}
+		a, err := reg.Get(agentID)
+		if err != nil {
+			return nil, err
+		}
+		if a == nil {
+			return nil, fmt.Errorf("agent %q not found", agentID)
+		}
+		killErr := killAgent(team.NewSpawner(), reg, a)
+
+		// Same cleanup as an agent exiting on its own.
+		var released []string
+		if store, err := team.NewStore(filepath.Join(td, "tasks")); err == nil {
+			tasks, _ := store.List(team.TaskStatusInProgress, agentID)
+			for _, t := range tasks {
+				if _, err := store.Release(t.ID); err == nil {
+					released = append(released, t.ID)
+				}
+			}
+		}
+		_, _ = team.NewTeamInbox(td).DeadLetterAgent(agentID, "recipient "+agentID+" was stopped from the board")
+
+		resp := map[string]any{"agent": agentID, "releasedTasks": released}
+		if killErr != nil {
+			resp["warning"] = killErr.Error()
+		}
+		return resp, nil
+	})
+}
+
+// boardPlanStore returns the plan store after checking the plan belongs to the team.
+func boardPlanStore(td, planID string) (*team.PlanStore, error) {
+	ps, err := team.NewPlanStore(filepath.Join(dataDir(), "plans"))
+	if err != nil {
+		return nil, err
+	}
+	plan, err := ps.Get(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || plan.TeamName != filepath.Base(td) {
+		return nil, fmt.Errorf("plan %s not found in team %s", planID, filepath.Base(td))
+	}
+	return ps, nil
+}
+
+// decodeBoardJSON decodes a request body, rejecting unknown fields. An empty
+// body is allowed for actions without parameters.
+func decodeBoardJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// writeBoardError replies with {"error": msg} and the given status.
+func writeBoardError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
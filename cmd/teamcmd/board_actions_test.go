@@ -0,0 +1,271 @@
+package teamcmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"jikime-adk/internal/team"
+)
+
+const testBoardToken = "test-token"
+
+// newBoardTestTeam points the data dir at a temp dir and creates an empty team.
+func newBoardTestTeam(t *testing.T, name string) string {
+	t.Helper()
+	t.Setenv("JIKIME_DATA_DIR", t.TempDir())
+	td := teamDir(name)
+	for _, sub := range []string{"tasks", "inbox", "registry"} {
+		if err := mkdirAll(filepath.Join(td, sub)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return td
+}
+
+// boardPost sends a CSRF-valid JSON POST through the board mux.
+func boardPost(t *testing.T, mux http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(boardCSRFHeader, testBoardToken)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireCSRF(t *testing.T) {
+	h := requireCSRF(testBoardToken, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	cases := []struct {
+		name   string
+		token  string
+		ctype  string
+		origin string
+		want   int
+	}{
+		{"valid", testBoardToken, "application/json", "", http.StatusNoContent},
+		{"valid with charset and same origin", testBoardToken, "application/json; charset=utf-8", "http://127.0.0.1:8080", http.StatusNoContent},
+		{"missing token", "", "application/json", "", http.StatusForbidden},
+		{"wrong token", "nope", "application/json", "", http.StatusForbidden},
+		{"form post", testBoardToken, "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"cross origin", testBoardToken, "application/json", "http://evil.example", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080/api/team/x/tasks", strings.NewReader("{}"))
+			if c.token != "" {
+				req.Header.Set(boardCSRFHeader, c.token)
+			}
+			req.Header.Set("Content-Type", c.ctype)
+			if c.origin != "" {
+				req.Header.Set("Origin", c.origin)
+			}
+			rec := httptest.NewRecorder()
+			h(rec, req)
+			if rec.Code != c.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, c.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBoardHostAllowed(t *testing.T) {
+	cases := []struct {
+		host, bind string
+		want       bool
+	}{
+		{"127.0.0.1:8080", "127.0.0.1", true},
+		{"localhost:8080", "127.0.0.1", true},
+		{"[::1]:8080", "127.0.0.1", true},
+		{"attacker.example:8080", "127.0.0.1", false},
+		{"attacker.example", "0.0.0.0", false},
+		{"192.168.1.5:8080", "0.0.0.0", false},
+		{"192.168.1.5:8080", "192.168.1.5", true},
+		{"", "127.0.0.1", false},
+	}
+	for _, c := range cases {
+		if got := boardHostAllowed(c.host, c.bind); got != c.want {
+			t.Errorf("boardHostAllowed(%q, %q) = %v, want %v", c.host, c.bind, got, c.want)
+		}
+	}
+
+	h := requireBoardHost("127.0.0.1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "rebind.attacker.example:8080"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("rebinding host status = %d, want 403", rec.Code)
+	}
+}
+
+func TestDiffKeyed(t *testing.T) {
+	key := func(m boardMember) string { return m.Name }
+	prev := []boardMember{{Name: "a", Status: "active"}, {Name: "b", Status: "active"}, {Name: "c"}}
+	next := []boardMember{{Name: "a", Status: "active"}, {Name: "b", Status: "idle"}, {Name: "d"}}
+
+	d := diffKeyed(prev, next, key)
+	if d == nil {
+		t.Fatal("diffKeyed = nil, want changes")
+	}
+	var upserted []string
+	for _, m := range d.Upsert {
+		upserted = append(upserted, m.Name)
+	}
+	sort.Strings(upserted)
+	if strings.Join(upserted, ",") != "b,d" {
+		t.Errorf("Upsert = %v, want [b d]", upserted)
+	}
+	if len(d.Remove) != 1 || d.Remove[0] != "c" {
+		t.Errorf("Remove = %v, want [c]", d.Remove)
+	}
+	if d := diffKeyed(prev, prev, key); d != nil {
+		t.Errorf("diffKeyed(same) = %+v, want nil", d)
+	}
+}
+
+func TestDiffBoard(t *testing.T) {
+	prev := &boardSnapshot{
+		Team:        boardTeam{Name: "t", LeaderName: "leader"},
+		TaskSummary: map[string]int{"pending": 1},
+		Tasks:       map[string][]boardTaskItem{"pending": {{ID: "1", Subject: "x", Status: "pending"}}},
+		Messages:    []boardMessage{{ID: "m1"}},
+	}
+	same := *prev
+	if d := diffBoard(prev, &same); !d.empty() {
+		t.Errorf("diffBoard(same) = %+v, want empty", d)
+	}
+
+	next := &boardSnapshot{
+		Team:        prev.Team,
+		TaskSummary: map[string]int{"in_progress": 1},
+		Tasks:       map[string][]boardTaskItem{"in_progress": {{ID: "1", Subject: "x", Status: "in_progress"}}},
+		Messages:    []boardMessage{{ID: "m1"}, {ID: "m2"}},
+	}
+	d := diffBoard(prev, next)
+	if d.Team != nil {
+		t.Errorf("Team = %+v, want nil (unchanged)", d.Team)
+	}
+	if d.TaskSummary["in_progress"] != 1 {
+		t.Errorf("TaskSummary = %v", d.TaskSummary)
+	}
+	if d.Tasks == nil || len(d.Tasks.Upsert) != 1 || d.Tasks.Upsert[0].Status != "in_progress" || len(d.Tasks.Remove) != 0 {
+		t.Errorf("Tasks = %+v, want task 1 moved to in_progress", d.Tasks)
+	}
+	if len(d.Messages) != 1 || d.Messages[0].ID != "m2" {
+		t.Errorf("Messages = %+v, want only m2", d.Messages)
+	}
+}
+
+func TestBoardActions_Tasks(t *testing.T) {
+	td := newBoardTestTeam(t, "web")
+	mux := http.NewServeMux()
+	registerBoardActions(mux, testBoardToken)
+
+	rec := boardPost(t, mux, "/api/team/web/tasks", `{"title":"Build API","priority":3}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Task team.Task `json:"task"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.Task.ID
+	if created.Task.Priority != 3 {
+		t.Errorf("created priority = %d, want 3", created.Task.Priority)
+	}
+
+	if rec := boardPost(t, mux, "/api/team/web/tasks", `{"description":"no title"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("create without title status = %d, want 400", rec.Code)
+	}
+	if rec := boardPost(t, mux, "/api/team/web/tasks", `{"title":"x","bogus":1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("create with unknown field status = %d, want 400", rec.Code)
+	}
+	if rec := boardPost(t, mux, "/api/team/missing/tasks", `{"title":"x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown team status = %d, want 404", rec.Code)
+	}
+
+	store, _ := team.NewStore(filepath.Join(td, "tasks"))
+	// Priority 0 restores the default.
+	for _, p := range []string{"7", "0"} {
+		if rec := boardPost(t, mux, "/api/team/web/tasks/"+id+"/priority", `{"priority":`+p+`}`); rec.Code != http.StatusOK {
+			t.Fatalf("priority %s status = %d: %s", p, rec.Code, rec.Body.String())
+		}
+	}
+	if got, _ := store.Get(id); got.Priority != 0 {
+		t.Errorf("priority after reset = %d, want 0", got.Priority)
+	}
+	if rec := boardPost(t, mux, "/api/team/web/tasks/"+id+"/priority", `{"priority":-1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("negative priority status = %d, want 400", rec.Code)
+	}
+	if rec := boardPost(t, mux, "/api/team/web/tasks/nope/priority", `{"priority":1}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown task status = %d, want 404", rec.Code)
+	}
+
+	if rec := boardPost(t, mux, "/api/team/web/tasks/"+id+"/assign", `{"owner":"worker-1"}`); rec.Code != http.StatusOK {
+		t.Fatalf("assign status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got, _ := store.Get(id); got.Owner != "worker-1" {
+		t.Errorf("owner = %q, want worker-1", got.Owner)
+	}
+	ib, _ := team.NewInbox(team.InboxDir(td, "worker-1"))
+	if msgs, _ := ib.Peek(0); len(msgs) != 1 || msgs[0].Subject != "task_assigned" {
+		t.Errorf("assignee inbox = %+v, want one task_assigned message", msgs)
+	}
+
+	if rec := boardPost(t, mux, "/api/team/web/tasks/"+id+"/fail", `{}`); rec.Code != http.StatusOK {
+		t.Fatalf("fail status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got, _ := store.Get(id); got.Status != team.TaskStatusFailed {
+		t.Errorf("status = %s, want failed", got.Status)
+	}
+}
+
+func TestBoardActions_PlansAndInbox(t *testing.T) {
+	td := newBoardTestTeam(t, "web")
+	mux := http.NewServeMux()
+	registerBoardActions(mux, testBoardToken)
+
+	ps, err := team.NewPlanStore(filepath.Join(dataDir(), "plans"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, _ := ps.Submit("web", "worker-1", "Plan A", "body", nil)
+	no, _ := ps.Submit("web", "worker-1", "Plan B", "body", nil)
+	other, _ := ps.Submit("other-team", "worker-1", "Plan C", "body", nil)
+
+	if rec := boardPost(t, mux, "/api/team/web/plans/"+ok.ID+"/approve", ``); rec.Code != http.StatusOK {
+		t.Fatalf("approve status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := boardPost(t, mux, "/api/team/web/plans/"+no.ID+"/reject", `{"reason":"too broad"}`); rec.Code != http.StatusOK {
+		t.Fatalf("reject status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if p, _ := ps.Get(ok.ID); p.Status != team.PlanStatusApproved || p.ReviewedBy != boardActor {
+		t.Errorf("approved plan = %+v", p)
+	}
+	if p, _ := ps.Get(no.ID); p.Status != team.PlanStatusRejected || p.RejectionReason != "too broad" {
+		t.Errorf("rejected plan = %+v", p)
+	}
+	if rec := boardPost(t, mux, "/api/team/web/plans/"+other.ID+"/approve", ``); rec.Code != http.StatusBadRequest {
+		t.Errorf("approve other team's plan status = %d, want 400", rec.Code)
+	}
+
+	if rec := boardPost(t, mux, "/api/team/web/inbox/send", `{"to":"worker-1"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("send without body status = %d, want 400", rec.Code)
+	}
+	if rec := boardPost(t, mux, "/api/team/web/inbox/send", `{"to":"worker-1","body":"hi"}`); rec.Code != http.StatusOK {
+		t.Fatalf("send status = %d: %s", rec.Code, rec.Body.String())
+	}
+	ib, _ := team.NewInbox(team.InboxDir(td, "worker-1"))
+	if msgs, _ := ib.Peek(0); len(msgs) != 1 || msgs[0].From != boardActor || msgs[0].Body != "hi" {
+		t.Errorf("worker-1 inbox = %+v, want one message from board", msgs)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,7 +29,10 @@ type boardCacheEntry struct {
 type boardTaskItem struct {
 	ID        string   `json:"id"`
 	Subject   string   `json:"subject"`
+	Status    string   `json:"status"`
 	Owner     string   `json:"owner,omitempty"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Priority  int      `json:"priority,omitempty"`
 	BlockedBy []string `json:"blockedBy,omitempty"`
 }
 
@@ -37,11 +41,13 @@ type boardMember struct {
 	Name       string `json:"name"`
 	User       string `json:"user,omitempty"`
 	AgentType  string `json:"agentType"`
+	Status     string `json:"status"`
 	InboxCount int    `json:"inboxCount"`
 }
 
 // boardMessage is the wire format for a message in the event log panel.
 type boardMessage struct {
+	ID        string `json:"id"`
 	From      string `json:"from"`
 	To        string `json:"to,omitempty"`
 	Type      string `json:"type"`
@@ -49,8 +55,35 @@ type boardMessage struct {
 	Content   string `json:"content"`
 }
 
+// boardPlan is the wire format for a plan awaiting (or past) review.
+type boardPlan struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	SubmittedBy string `json:"submittedBy"`
+	Status      string `json:"status"`
+	SubmittedAt string `json:"submittedAt"`
+}
+
+// boardTeam is the team header of a board snapshot.
+type boardTeam struct {
+	Name        string `json:"name"`
+	LeaderName  string `json:"leaderName"`
+	Description string `json:"description"`
+}
+
+// boardSnapshot is the full board payload for a team.
+type boardSnapshot struct {
+	Team        boardTeam                  `json:"team"`
+	Members     []boardMember              `json:"members"`
+	Tasks       map[string][]boardTaskItem `json:"tasks"`
+	TaskSummary map[string]int             `json:"taskSummary"`
+	Messages    []boardMessage             `json:"messages"`
+	Plans       []boardPlan                `json:"plans"`
+}
+
 // collectBoardData builds the full board payload for a team.
-func collectBoardData(name string) (map[string]interface{}, error) {
+func collectBoardData(name string) (*boardSnapshot, error) {
 	td := teamDir(name)
 	if _, err := os.Stat(td); os.IsNotExist(err) {
 		return nil, fmt.Errorf("team %q not found", name)
@@ -116,6 +149,7 @@ func collectBoardData(name string) (map[string]interface{}, error) {
 		members = append(members, boardMember{
 			Name:       a.ID,
 			AgentType:  string(a.Role),
+			Status:     string(a.Status),
 			InboxCount: inboxCount,
 		})
 	}
@@ -138,10 +172,18 @@ func collectBoardData(name string) (map[string]interface{}, error) {
 	}
 	for _, t := range allTasks {
 		status := string(t.Status)
+		owner := t.AgentID
+		if owner == "" {
+			owner = t.Owner
+		}
 		item := boardTaskItem{
-			ID:      t.ID,
-			Subject: t.Title,
-			Owner:   t.AgentID,
+			ID:        t.ID,
+			Subject:   t.Title,
+			Status:    status,
+			Owner:     owner,
+			Assignee:  t.Owner,
+			Priority:  t.Priority,
+			BlockedBy: t.DependsOn,
 		}
 		if _, ok := taskGroups[status]; ok {
 			taskGroups[status] = append(taskGroups[status], item)
@@ -155,6 +197,7 @@ func collectBoardData(name string) (map[string]interface{}, error) {
 	messages := make([]boardMessage, 0, len(logMsgs))
 	for _, m := range logMsgs {
 		messages = append(messages, boardMessage{
+			ID:        m.ID,
 			From:      m.From,
 			To:        m.To,
 			Type:      string(m.Kind),
@@ -163,15 +206,33 @@ func collectBoardData(name string) (map[string]interface{}, error) {
 		})
 	}
 
-	return map[string]interface{}{
-		"team": map[string]string{
-			"name":        name,
-			"leaderName":  leaderName,
-			"description": cfg.Description,
+	// Plans submitted by this team's agents, newest first.
+	plans := []boardPlan{}
+	if ps, err := team.NewPlanStore(filepath.Join(dataDir(), "plans")); err == nil {
+		list, _ := ps.List(name, "")
+		sort.Slice(list, func(i, j int) bool { return list[i].SubmittedAt.After(list[j].SubmittedAt) })
+		for _, p := range list {
+			plans = append(plans, boardPlan{
+				ID:          p.ID,
+				Title:       p.Title,
+				Body:        p.Body,
+				SubmittedBy: p.SubmittedBy,
+				Status:      string(p.Status),
+				SubmittedAt: p.SubmittedAt.Format(time.RFC3339),
+			})
+		}
+	}
+
+	return &boardSnapshot{
+		Team: boardTeam{
+			Name:        name,
+			LeaderName:  leaderName,
+			Description: cfg.Description,
 		},
-		"members":     members,
-		"tasks":       taskGroups,
-		"taskSummary": summary,
-		"messages":    messages,
+		Members:     members,
+		Tasks:       taskGroups,
+		TaskSummary: summary,
+		Messages:    messages,
+		Plans:       plans,
 	}, nil
 }
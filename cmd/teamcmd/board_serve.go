@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -19,21 +20,31 @@ var boardHTML string
 // newBoardServeCmd starts a local HTTP dashboard server.
 // Endpoints:
 //
-//	GET /              -> HTML dashboard (React SPA)
+//	GET /              -> HTML dashboard (React SPA, carries the CSRF token)
 //	GET /api/overview  -> JSON list of all teams
 //	GET /api/team/:name -> JSON snapshot of a single team
-//	GET /api/events/:name -> SSE stream (pushes team snapshot on interval)
+//	GET /api/events/:name -> SSE stream (snapshot, then diffs on file changes)
+//	POST /api/team/:name/... -> write actions, see registerBoardActions
+//	GET /api/team/:name/agents/:id/terminal -> agent terminal stream, see registerBoardTerminal
 func newBoardServeCmd() *cobra.Command {
 	var (
-		port     int
-		host     string
-		interval float64
+		port      int
+		host      string
+		interval  float64
+		allowKeys bool
 	)
 	cmd := &cobra.Command{
 		Use:   "serve [team-name]",
 		Short: "Start a web dashboard server (http://localhost:8080)",
-		Long: `Start a local HTTP server with a real-time web UI for monitoring team activity.
-Open http://localhost:8080 in your browser after starting.
+		Long: `Start a local HTTP server with a real-time web UI for monitoring and steering
+team activity. Open http://localhost:8080 in your browser after starting.
+
+The UI can create, reassign, reprioritize and fail tasks, approve or reject
+plans, send messages and stop agents. Write requests must carry the CSRF
+token embedded in the page, so other sites cannot drive the board.
+
+Each agent has a live terminal view: its tmux pane, container output or
+subprocess log. With --allow-send-keys, tmux agents also accept typed input.
 
 Example:
   jikime team board serve               # show all teams
@@ -46,6 +57,12 @@ Example:
 				defaultTeam = args[0]
 			}
 
+			token, err := newBoardCSRFToken()
+			if err != nil {
+				return err
+			}
+			page := []byte(strings.Replace(boardHTML, "{{CSRF_TOKEN}}", token, 1))
+
 			mux := http.NewServeMux()
 
 			// --- GET / -> HTML SPA ---
@@ -55,7 +72,7 @@ Example:
 					return
 				}
 				w.Header().Set("Content-Type", "text/html; charset=utf-8")
-				_, _ = w.Write([]byte(boardHTML))
+				_, _ = w.Write(page)
 			})
 
 			// --- GET /api/overview -> [{name, description}, ...] ---
@@ -89,8 +106,8 @@ Example:
 			})
 
 			// --- GET /api/team/:name -> team snapshot ---
-			mux.HandleFunc("/api/team/", func(w http.ResponseWriter, r *http.Request) {
-				name := strings.TrimPrefix(r.URL.Path, "/api/team/")
+			mux.HandleFunc("GET /api/team/{name}", func(w http.ResponseWriter, r *http.Request) {
+				name := r.PathValue("name")
 				if name == "" {
 					http.Error(w, `{"error":"team name required"}`, http.StatusBadRequest)
 					return
@@ -123,33 +140,52 @@ Example:
 					return
 				}
 
-				tick := time.NewTicker(time.Duration(interval*1000) * time.Millisecond)
-				defer tick.Stop()
+				// The server WriteTimeout would cut long-lived streams.
+				_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+				period := time.Duration(interval*1000) * time.Millisecond
 				ctx := r.Context()
+				changes := watchBoard(ctx, teamDir(name), period)
+				heartbeat := time.NewTicker(period * 10)
+				defer heartbeat.Stop()
 
-				push := func() {
-					data, err := collectBoardData(name)
-					var payload []byte
-					if err != nil {
-						payload, _ = json.Marshal(map[string]string{"error": err.Error()})
-					} else {
-						payload, _ = json.Marshal(data)
-					}
-					_, _ = fmt.Fprintf(w, "data: %s\n\n", payload)
+				send := func(event string, v interface{}) {
+					payload, _ := json.Marshal(v)
+					_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
 					flusher.Flush()
 				}
 
-				push() // immediate first push
+				// Full snapshot first, then only what changed.
+				prev, err := collectBoardData(name)
+				if err != nil {
+					send("board-error", map[string]string{"error": err.Error()})
+					return
+				}
+				send("snapshot", prev)
 				for {
 					select {
 					case <-ctx.Done():
 						return
-					case <-tick.C:
-						push()
+					case <-heartbeat.C:
+						_, _ = fmt.Fprint(w, ": ping\n\n")
+						flusher.Flush()
+					case <-changes:
+						next, err := collectBoardData(name)
+						if err != nil {
+							send("board-error", map[string]string{"error": err.Error()})
+							continue
+						}
+						if d := diffBoard(prev, next); !d.empty() {
+							send("diff", d)
+						}
+						prev = next
 					}
 				}
 			})
 
+			registerBoardActions(mux, token)
+			registerBoardTerminal(mux, token, allowKeys, 500*time.Millisecond)
+
 			addr := fmt.Sprintf("%s:%d", host, port)
 			fmt.Printf("jikime dashboard: http://%s\n", addr)
 			if defaultTeam != "" {
@@ -158,7 +194,7 @@ Example:
 			fmt.Println("   Press Ctrl+C to stop.")
 			srv := &http.Server{
 				Addr:         addr,
-				Handler:      mux,
+				Handler:      requireBoardHost(host, mux),
 				ReadTimeout:  15 * time.Second,
 				WriteTimeout: 60 * time.Second,
 				IdleTimeout:  120 * time.Second,
@@ -168,10 +204,45 @@ Example:
 	}
 	cmd.Flags().IntVarP(&port, "port", "p", 8080, "HTTP server port")
 	cmd.Flags().StringVar(&host, "host", "127.0.0.1", "Bind address")
-	cmd.Flags().Float64VarP(&interval, "interval", "i", 2.0, "SSE push interval in seconds")
+	cmd.Flags().BoolVar(&allowKeys, "allow-send-keys", false, "Allow typing into agent tmux panes from the browser")
+	cmd.Flags().Float64VarP(&interval, "interval", "i", 2.0, "Poll interval in seconds when file watching is unavailable (heartbeat every 10x)")
 	return cmd
 }
 
+// requireBoardHost rejects requests whose Host header is not a loopback name
+// or the explicit bind address. A DNS-rebinding page reaches the server under
+// its own host name, so without this check it could read the CSRF token from
+// GET / and then drive the write endpoints.
+func requireBoardHost(bind string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !boardHostAllowed(r.Host, bind) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// boardHostAllowed reports whether hostport names a loopback address or the
+// bind address given with --host (wildcard binds add nothing).
+func boardHostAllowed(hostport, bind string) bool {
+	h := hostport
+	if hh, _, err := net.SplitHostPort(hostport); err == nil {
+		h = hh
+	}
+	h = strings.TrimSuffix(strings.Trim(h, "[]"), ".")
+	if strings.EqualFold(h, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(h); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	if ip := net.ParseIP(bind); ip != nil && ip.IsUnspecified() {
+		return false
+	}
+	return h != "" && strings.EqualFold(h, bind)
+}
+
 // corsOrigin returns the Origin header if it is a localhost address,
 // otherwise falls back to http://localhost:4000.
 func corsOrigin(r *http.Request) string {
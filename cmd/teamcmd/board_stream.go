@@ -0,0 +1,172 @@
+package teamcmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// boardDebounce coalesces bursts of file events (tmp + rename writes) into
+// a single recomputation.
+const boardDebounce = 150 * time.Millisecond
+
+// boardDiff is the SSE payload sent after the initial snapshot.
+// Only changed sections are present; the client merges it into its state.
+type boardDiff struct {
+	Team        *boardTeam                `json:"team,omitempty"`
+	TaskSummary map[string]int            `json:"taskSummary,omitempty"`
+	Tasks       *keyedDiff[boardTaskItem] `json:"tasks,omitempty"`
+	Members     *keyedDiff[boardMember]   `json:"members,omitempty"`
+	Plans       *keyedDiff[boardPlan]     `json:"plans,omitempty"`
+	Messages    []boardMessage            `json:"messages,omitempty"` // appended
+}
+
+// keyedDiff lists upserted items and removed keys of one board section.
+type keyedDiff[T any] struct {
+	Upsert []T      `json:"upsert,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// empty reports whether the diff carries no changes.
+func (d *boardDiff) empty() bool {
+	return d.Team == nil && d.TaskSummary == nil && d.Tasks == nil &&
+		d.Members == nil && d.Plans == nil && len(d.Messages) == 0
+}
+
+// diffBoard computes the changes from prev to next.
+func diffBoard(prev, next *boardSnapshot) *boardDiff {
+	d := &boardDiff{}
+	if prev.Team != next.Team {
+		t := next.Team
+		d.Team = &t
+	}
+	if !reflect.DeepEqual(prev.TaskSummary, next.TaskSummary) {
+		d.TaskSummary = next.TaskSummary
+	}
+	taskKey := func(t boardTaskItem) string { return t.ID }
+	d.Tasks = diffKeyed(flattenTasks(prev.Tasks), flattenTasks(next.Tasks), taskKey)
+	d.Members = diffKeyed(prev.Members, next.Members, func(m boardMember) string { return m.Name })
+	d.Plans = diffKeyed(prev.Plans, next.Plans, func(p boardPlan) string { return p.ID })
+
+	// The event log is append-only; send messages not seen before.
+	seen := make(map[string]bool, len(prev.Messages))
+	for _, m := range prev.Messages {
+		seen[m.ID] = true
+	}
+	for _, m := range next.Messages {
+		if !seen[m.ID] {
+			d.Messages = append(d.Messages, m)
+		}
+	}
+	return d
+}
+
+// diffKeyed returns items in next that are new or changed, and keys of
+// items missing from next. Returns nil when nothing changed.
+func diffKeyed[T any](prev, next []T, key func(T) string) *keyedDiff[T] {
+	old := make(map[string]T, len(prev))
+	for _, v := range prev {
+		old[key(v)] = v
+	}
+	d := &keyedDiff[T]{}
+	for _, v := range next {
+		k := key(v)
+		if o, ok := old[k]; !ok || !reflect.DeepEqual(o, v) {
+			d.Upsert = append(d.Upsert, v)
+		}
+		delete(old, k)
+	}
+	for k := range old {
+		d.Remove = append(d.Remove, k)
+	}
+	if len(d.Upsert) == 0 && len(d.Remove) == 0 {
+		return nil
+	}
+	return d
+}
+
+// flattenTasks turns status columns into one list; each item carries its status.
+func flattenTasks(groups map[string][]boardTaskItem) []boardTaskItem {
+	var out []boardTaskItem
+	for _, col := range []string{"pending", "in_progress", "done", "failed", "blocked"} {
+		out = append(out, groups[col]...)
+	}
+	return out
+}
+
+// watchBoard sends a tick on the returned channel whenever anything under the
+// team directory (or the shared plans directory) changes, debounced. If the
+// watcher cannot be created, it falls back to ticking every fallback.
+func watchBoard(ctx context.Context, td string, fallback time.Duration) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case out <- struct{}{}:
+		default:
+		}
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		go func() {
+			t := time.NewTicker(fallback)
+			defer t.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-t.C:
+					notify()
+				}
+			}
+		}()
+		return out
+	}
+
+	// fsnotify is not recursive: watch every directory that exists now and
+	// pick up new ones (e.g. a new agent's inbox) as they are created.
+	addTree := func(root string) {
+		_ = filepath.WalkDir(root, func(path string, e os.DirEntry, err error) error {
+			if err == nil && e.IsDir() {
+				_ = w.Add(path)
+			}
+			return nil
+		})
+	}
+	addTree(td)
+	plansDir := filepath.Join(dataDir(), "plans")
+	_ = os.MkdirAll(plansDir, 0o755)
+	_ = w.Add(plansDir)
+
+	go func() {
+		defer w.Close()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if ev.Op&fsnotify.Create != 0 {
+					if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+						addTree(ev.Name)
+					}
+				}
+				if debounce == nil {
+					debounce = time.After(boardDebounce)
+				}
+			case <-w.Errors:
+			case <-debounce:
+				debounce = nil
+				notify()
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,141 @@
+package teamcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"jikime-adk/internal/team"
+)
+
+// boardTerminalLines is how much scrollback a terminal view shows.
+const boardTerminalLines = 300
+
+// registerBoardTerminal adds per-agent terminal streaming:
+//
+//	GET  /api/team/{name}/agents/{id}/terminal   SSE: "screen" replaces the view,
+//	                                             "append" adds log output
+//	POST /api/team/{name}/agents/{id}/keys       {text, enter} → tmux send-keys
+//
+// The keys endpoint is only registered when allowKeys is set.
+func registerBoardTerminal(mux *http.ServeMux, token string, allowKeys bool, period time.Duration) {
+	mux.HandleFunc("GET /api/team/{name}/agents/{id}/terminal", func(w http.ResponseWriter, r *http.Request) {
+		term, err := boardTerminal(r)
+		if err != nil {
+			writeBoardError(w, http.StatusNotFound, err)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		send := func(event string, v interface{}) {
+			payload, _ := json.Marshal(v)
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+			flusher.Flush()
+		}
+		send("info", map[string]interface{}{
+			"agent":    term.AgentID,
+			"kind":     term.Kind,
+			"sendKeys": allowKeys && term.CanSendKeys(),
+		})
+
+		// Terminal output changes far more often than board state and tmux
+		// panes have no change notification, so this stream polls.
+		tick := time.NewTicker(period)
+		defer tick.Stop()
+		var last string
+		var cur team.LogCursor
+
+		poll := func() {
+			if term.Kind == team.TerminalLog {
+				// A truncated or replaced log redraws the view instead of
+				// appending the whole file again.
+				text, reset, err := term.Follow(&cur, boardTerminalLines)
+				if err != nil {
+					send("term-error", map[string]string{"error": err.Error()})
+					return
+				}
+				if reset {
+					send("screen", map[string]string{"text": text})
+				} else if text != "" {
+					send("append", map[string]string{"text": text})
+				}
+				return
+			}
+			screen, err := term.Snapshot(boardTerminalLines)
+			if err != nil {
+				send("term-error", map[string]string{"error": err.Error()})
+				return
+			}
+			if screen != last {
+				last = screen
+				send("screen", map[string]string{"text": screen})
+			}
+		}
+
+		poll()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-tick.C:
+				poll()
+			}
+		}
+	})
+
+	if !allowKeys {
+		return
+	}
+	mux.HandleFunc("POST /api/team/{name}/agents/{id}/keys", requireCSRF(token, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Text  string `json:"text"`
+			Enter bool   `json:"enter"`
+		}
+		if err := decodeBoardJSON(r, &req); err != nil {
+			writeBoardError(w, http.StatusBadRequest, err)
+			return
+		}
+		if req.Text == "" && !req.Enter {
+			writeBoardError(w, http.StatusBadRequest, errors.New("text or enter is required"))
+			return
+		}
+		term, err := boardTerminal(r)
+		if err != nil {
+			writeBoardError(w, http.StatusNotFound, err)
+			return
+		}
+		if err := term.SendKeys(req.Text, req.Enter); err != nil {
+			writeBoardError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON2(w, map[string]string{"agent": term.AgentID, "status": "sent"})
+	}))
+}
+
+// boardTerminal resolves the terminal for the agent named in the request path.
+func boardTerminal(r *http.Request) (*team.AgentTerminal, error) {
+	name, agentID := r.PathValue("name"), r.PathValue("id")
+	reg, err := team.NewRegistry(filepath.Join(teamDir(name), "registry"))
+	if err != nil {
+		return nil, err
+	}
+	a, err := reg.Get(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("agent %q not found in team %q", agentID, name)
+	}
+	return team.ResolveTerminal(dataDir(), name, a)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"jikime-adk/internal/team"
@@ -13,34 +14,45 @@ import (
 func newBudgetCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "budget",
-		Short: "Manage team token budget",
+		Short: "Manage team token and dollar budgets",
 	}
 	cmd.AddCommand(newBudgetShowCmd())
 	cmd.AddCommand(newBudgetSetCmd())
+	cmd.AddCommand(newBudgetRecordCmd())
 	cmd.AddCommand(newBudgetReportCmd())
 	return cmd
 }
 
+// loadBudgetConfig reads the team config and opens its cost store with the
+// team's price overrides applied.
+func loadBudgetConfig(td string) (*team.TeamConfig, *team.CostStore, error) {
+	var cfg team.TeamConfig
+	data, _ := os.ReadFile(filepath.Join(td, "config.json"))
+	_ = json.Unmarshal(data, &cfg)
+
+	costStore, err := team.NewCostStore(filepath.Join(td, "costs"), cfg.Budget)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cost store: %w", err)
+	}
+	if cfg.Budgets != nil {
+		costStore.SetPrices(cfg.Budgets.Prices)
+	}
+	return &cfg, costStore, nil
+}
+
 func newBudgetShowCmd() *cobra.Command {
 	var agentID string
 	cmd := &cobra.Command{
 		Use:   "show <team-name>",
-		Short: "Show current token usage and budget",
+		Short: "Show current token usage, dollar spend and budget",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			td := teamDir(name)
 
-			// Load config for budget
-			cfg := struct {
-				Budget int `json:"budget"`
-			}{}
-			data, _ := os.ReadFile(filepath.Join(td, "config.json"))
-			_ = json.Unmarshal(data, &cfg)
-
-			costStore, err := team.NewCostStore(filepath.Join(td, "costs"), cfg.Budget)
+			cfg, costStore, err := loadBudgetConfig(td)
 			if err != nil {
-				return fmt.Errorf("cost store: %w", err)
+				return err
 			}
 
 			if agentID != "" {
@@ -61,28 +73,30 @@ func newBudgetShowCmd() *cobra.Command {
 			agents, _ := reg.List()
 
 			var totalTokens int
+			var totalUSD float64
 			fmt.Printf("Budget for team %q (limit: %d tokens)\n\n", name, cfg.Budget)
-			fmt.Printf("  %-14s  %-10s  %-10s  %-10s  %s\n",
-				"AGENT", "INPUT", "OUTPUT", "TOTAL", "BUDGET%")
+			fmt.Printf("  %-14s  %-10s  %-10s  %-10s  %-10s  %s\n",
+				"AGENT", "INPUT", "OUTPUT", "TOTAL", "COST", "BUDGET%")
 
 			for _, a := range agents {
 				summary, _ := costStore.Summary(a.ID)
 				if summary == nil {
-					fmt.Printf("  %-14s  %-10d  %-10d  %-10d  %.1f%%\n",
-						a.ID, 0, 0, 0, 0.0)
+					fmt.Printf("  %-14s  %-10d  %-10d  %-10d  %-10s  %.1f%%\n",
+						a.ID, 0, 0, 0, formatUSD(0), 0.0)
 					continue
 				}
 				totalTokens += summary.TotalTokens
+				totalUSD += summary.TotalCostUSD
 				pct := 0.0
 				if cfg.Budget > 0 {
 					pct = float64(summary.TotalTokens) / float64(cfg.Budget) * 100
 				}
-				fmt.Printf("  %-14s  %-10d  %-10d  %-10d  %.1f%%\n",
+				fmt.Printf("  %-14s  %-10d  %-10d  %-10d  %-10s  %.1f%%\n",
 					a.ID, summary.TotalInputTokens, summary.TotalOutputTokens,
-					summary.TotalTokens, pct)
+					summary.TotalTokens, formatUSD(summary.TotalCostUSD), pct)
 			}
 
-			fmt.Printf("\n  %-14s  %s\n", "TOTAL", fmt.Sprintf("%d tokens", totalTokens))
+			fmt.Printf("\n  %-14s  %s (%s)\n", "TOTAL", fmt.Sprintf("%d tokens", totalTokens), formatUSD(totalUSD))
 			if cfg.Budget > 0 {
 				pct := float64(totalTokens) / float64(cfg.Budget) * 100
 				exceeded := ""
@@ -92,6 +106,23 @@ func newBudgetShowCmd() *cobra.Command {
 				fmt.Printf("  Budget used: %.1f%% (%d / %d)%s\n",
 					pct, totalTokens, cfg.Budget, exceeded)
 			}
+			if p := cfg.Budgets; p != nil {
+				fmt.Println("\n  Dollar limits (soft / hard):")
+				printBudgetLimit("team", p.Team)
+				printBudgetLimit("default agent", p.DefaultAgent)
+				for _, id := range sortedLimitKeys(p.Agents) {
+					printBudgetLimit("agent "+id, p.Agents[id])
+				}
+				printBudgetLimit("default task", p.DefaultTask)
+				for _, id := range sortedLimitKeys(p.Tasks) {
+					printBudgetLimit("task "+shortID(id), p.Tasks[id])
+				}
+				alerts, _ := costStore.CheckBudgets(p, "", "")
+				for _, a := range alerts {
+					fmt.Printf("  ⚠️  team %s limit reached: %s / %s\n",
+						a.Level, formatUSD(a.SpentUSD), formatUSD(a.LimitUSD))
+				}
+			}
 			return nil
 		},
 	}
@@ -100,15 +131,36 @@ func newBudgetShowCmd() *cobra.Command {
 }
 
 func newBudgetSetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "set <team-name> <tokens>",
-		Short: "Set token budget for a team",
-		Args:  cobra.ExactArgs(2),
+	var (
+		agentID string
+		taskID  string
+		softUSD float64
+		hardUSD float64
+	)
+	cmd := &cobra.Command{
+		Use:   "set <team-name> [tokens]",
+		Short: "Set token budget or dollar limits for a team, agent or task",
+		Long: `Set the team token budget, or dollar soft/hard limits at team, agent or task scope.
+
+Soft limits warn the leader via inbox; hard limits stop the agent.
+Use --agent "*" or --task "*" to set the default for all agents or tasks.
+
+Examples:
+  jikime team budget set my-team 200000
+  jikime team budget set my-team --soft 20 --hard 25
+  jikime team budget set my-team --agent worker-1 --hard 5
+  jikime team budget set my-team --task "*" --soft 1.5`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			td := teamDir(name)
 			cfgPath := filepath.Join(td, "config.json")
 
+			usdSet := cmd.Flags().Changed("soft") || cmd.Flags().Changed("hard")
+			if len(args) < 2 && !usdSet {
+				return fmt.Errorf("provide a token budget or --soft/--hard dollar limits")
+			}
+
 			// Read existing config
 			data, err := os.ReadFile(cfgPath)
 			if err != nil {
@@ -119,88 +171,286 @@ func newBudgetSetCmd() *cobra.Command {
 				return fmt.Errorf("parse config: %w", err)
 			}
 
-			var budget int
-			if _, err := fmt.Sscanf(args[1], "%d", &budget); err != nil {
-				return fmt.Errorf("invalid budget value: %s", args[1])
+			if len(args) == 2 {
+				var budget int
+				if _, err := fmt.Sscanf(args[1], "%d", &budget); err != nil {
+					return fmt.Errorf("invalid budget value: %s", args[1])
+				}
+				cfg["budget"] = budget
+				fmt.Printf("✅ Budget for team %q set to %d tokens\n", name, budget)
 			}
-			cfg["budget"] = budget
 
-			if err := writeJSON(cfgPath, cfg); err != nil {
-				return err
+			if usdSet {
+				var policy team.BudgetPolicy
+				if raw, ok := cfg["budgets"]; ok {
+					b, _ := json.Marshal(raw)
+					_ = json.Unmarshal(b, &policy)
+				}
+
+				target, label := &policy.Team, "team "+name
+				var limit team.BudgetLimit
+				switch {
+				case agentID == "*":
+					target, label = &policy.DefaultAgent, "every agent"
+				case agentID != "":
+					limit = policy.Agents[agentID]
+					target, label = &limit, "agent "+agentID
+				case taskID == "*":
+					target, label = &policy.DefaultTask, "every task"
+				case taskID != "":
+					store, err := team.NewStore(filepath.Join(td, "tasks"))
+					if err != nil {
+						return err
+					}
+					t, err := store.Get(taskID)
+					if err != nil {
+						return err
+					}
+					if t == nil {
+						return fmt.Errorf("task %q not found", taskID)
+					}
+					taskID = t.ID
+					limit = policy.Tasks[taskID]
+					target, label = &limit, "task "+shortID(taskID)
+				}
+				if cmd.Flags().Changed("soft") {
+					target.SoftUSD = softUSD
+				}
+				if cmd.Flags().Changed("hard") {
+					target.HardUSD = hardUSD
+				}
+				switch {
+				case agentID != "" && agentID != "*":
+					if policy.Agents == nil {
+						policy.Agents = map[string]team.BudgetLimit{}
+					}
+					policy.Agents[agentID] = limit
+				case taskID != "" && taskID != "*":
+					if policy.Tasks == nil {
+						policy.Tasks = map[string]team.BudgetLimit{}
+					}
+					policy.Tasks[taskID] = limit
+				}
+				cfg["budgets"] = policy
+				fmt.Printf("✅ Dollar limits for %s: soft %s, hard %s\n",
+					label, formatLimit(target.SoftUSD), formatLimit(target.HardUSD))
 			}
-			fmt.Printf("✅ Budget for team %q set to %d tokens\n", name, budget)
-			return nil
+
+			return writeJSON(cfgPath, cfg)
 		},
 	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", `Apply dollar limits to this agent ("*" = default for all agents)`)
+	cmd.Flags().StringVar(&taskID, "task", "", `Apply dollar limits to this task ("*" = default for all tasks)`)
+	cmd.Flags().Float64Var(&softUSD, "soft", 0, "Soft limit in USD (warn leader; 0 = disabled)")
+	cmd.Flags().Float64Var(&hardUSD, "hard", 0, "Hard limit in USD (stop agent; 0 = disabled)")
+	return cmd
 }
 
-func newBudgetReportCmd() *cobra.Command {
-	var (
-		agentID      string
-		taskID       string
-		model        string
-		inputTokens  int
-		outputTokens int
-	)
+// budgetUsage holds the flags of `jikime team budget record`.
+type budgetUsage struct {
+	agentID      string
+	taskID       string
+	model        string
+	inputTokens  int
+	outputTokens int
+	cacheWrite   int
+	cacheRead    int
+}
+
+// budgetUsageFlags are the flag names registered by addUsageFlags.
+var budgetUsageFlags = []string{"agent", "task", "model", "input-tokens", "output-tokens", "cache-write-tokens", "cache-read-tokens"}
+
+// addUsageFlags registers the usage flags on cmd.
+func addUsageFlags(cmd *cobra.Command, u *budgetUsage) {
+	cmd.Flags().StringVarP(&u.agentID, "agent", "a", "", "Agent ID (default: JIKIME_AGENT_ID)")
+	cmd.Flags().StringVar(&u.taskID, "task", "", "Task ID this cost belongs to")
+	cmd.Flags().StringVar(&u.model, "model", "", "Model name (e.g. claude-sonnet-4-6)")
+	cmd.Flags().IntVar(&u.inputTokens, "input-tokens", 0, "Input tokens consumed")
+	cmd.Flags().IntVar(&u.outputTokens, "output-tokens", 0, "Output tokens consumed")
+	cmd.Flags().IntVar(&u.cacheWrite, "cache-write-tokens", 0, "Tokens written to the prompt cache")
+	cmd.Flags().IntVar(&u.cacheRead, "cache-read-tokens", 0, "Tokens read from the prompt cache")
+}
+
+func newBudgetRecordCmd() *cobra.Command {
+	var u budgetUsage
 	cmd := &cobra.Command{
-		Use:   "report <team-name>",
-		Short: "Report token usage for an agent (call from agent hooks)",
-		Long: `Report token usage to the team cost store.
+		Use:   "record <team-name>",
+		Short: "Record token usage for an agent (call from agent hooks)",
+		Long: `Record token usage to the team cost store.
 Agents call this after each interaction to track cumulative token consumption.
+Usage is priced in dollars using the team's model price table.
 
 Example (from a Claude Code hook):
-  jikime team budget report $JIKIME_TEAM_NAME \
+  jikime team budget record $JIKIME_TEAM_NAME \
     --agent $JIKIME_AGENT_ID \
     --input-tokens 1234 --output-tokens 567 \
+    --cache-read-tokens 8000 \
     --model claude-sonnet-4-6`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if agentID == "" {
-				agentID = os.Getenv("JIKIME_AGENT_ID")
-			}
-			if agentID == "" {
-				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
-			}
+			return recordUsage(args[0], &u)
+		},
+	}
+	addUsageFlags(cmd, &u)
+	return cmd
+}
+
+// recordUsage records u to the cost store of team name and prints budget state.
+func recordUsage(name string, u *budgetUsage) error {
+	agentID := u.agentID
+	if agentID == "" {
+		agentID = os.Getenv("JIKIME_AGENT_ID")
+	}
+	if agentID == "" {
+		return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+	}
+
+	td := teamDir(name)
+	cfg, costStore, err := loadBudgetConfig(td)
+	if err != nil {
+		return err
+	}
+
+	ev, err := costStore.RecordEvent(&team.CostEvent{
+		TeamName:            name,
+		AgentID:             agentID,
+		TaskID:              u.taskID,
+		Model:               u.model,
+		InputTokens:         u.inputTokens,
+		OutputTokens:        u.outputTokens,
+		CacheCreationTokens: u.cacheWrite,
+		CacheReadTokens:     u.cacheRead,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Recorded: agent=%s in=%d out=%d total=%d cost=%s (id: %s)\n",
+		agentID, ev.InputTokens, ev.OutputTokens, ev.TotalTokens, formatUSD(ev.CostUSD), ev.ID[:8])
+
+	if cfg.Budget > 0 {
+		if exceeded, _ := costStore.BudgetExceeded(); exceeded {
+			fmt.Printf("⚠️  Budget exceeded! Notify leader.\n")
+		} else {
+			s, _ := costStore.Summary("")
+			fmt.Printf("   Budget used: %.1f%% (%d / %d)\n",
+				s.BudgetUsedPercent, s.TotalTokens, cfg.Budget)
+		}
+	}
+	alerts, _ := costStore.CheckBudgets(cfg.Budgets, agentID, u.taskID)
+	for _, a := range alerts {
+		fmt.Printf("⚠️  %s %s limit reached: %s / %s\n",
+			a.Scope, a.Level, formatUSD(a.SpentUSD), formatUSD(a.LimitUSD))
+	}
+	return nil
+}
+
+// budgetReportRow is one line of `jikime team budget report`.
+type budgetReportRow struct {
+	TaskID  string            `json:"task_id,omitempty"`
+	Title   string            `json:"title"`
+	Status  team.TaskStatus   `json:"status,omitempty"`
+	AgentID string            `json:"agent_id,omitempty"`
+	Cost    *team.CostSummary `json:"cost"`
+	Limit   *team.BudgetLimit `json:"limit,omitempty"`
+}
+
+func newBudgetReportCmd() *cobra.Command {
+	var (
+		jsonOut bool
+		u       budgetUsage
+	)
+	cmd := &cobra.Command{
+		Use:   "report <team-name>",
+		Short: "Break down token and dollar spend by task",
+		Long: `Break down token and dollar spend by task title.
 
+For compatibility, usage flags (--agent, --input-tokens, ...) make report
+record usage like "budget record", as it did before record existed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
+			for _, flag := range budgetUsageFlags {
+				if cmd.Flags().Changed(flag) {
+					return recordUsage(name, &u)
+				}
+			}
 			td := teamDir(name)
 
-			cfg := struct {
-				Budget int `json:"budget"`
-			}{}
-			data, _ := os.ReadFile(filepath.Join(td, "config.json"))
-			_ = json.Unmarshal(data, &cfg)
-
-			costStore, err := team.NewCostStore(filepath.Join(td, "costs"), cfg.Budget)
+			cfg, costStore, err := loadBudgetConfig(td)
 			if err != nil {
-				return fmt.Errorf("cost store: %w", err)
+				return err
 			}
-
-			ev, err := costStore.Record(agentID, taskID, "", model, inputTokens, outputTokens)
+			byTask, err := costStore.SummaryByTask()
+			if err != nil {
+				return err
+			}
+			store, err := team.NewStore(filepath.Join(td, "tasks"))
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("✅ Reported: agent=%s in=%d out=%d total=%d (id: %s)\n",
-				agentID, ev.InputTokens, ev.OutputTokens, ev.TotalTokens, ev.ID[:8])
+			var rows []budgetReportRow
+			var totalUSD float64
+			for taskID, s := range byTask {
+				totalUSD += s.TotalCostUSD
+				row := budgetReportRow{TaskID: taskID, Title: "(no task)", Cost: s}
+				if taskID != "" {
+					row.Title = "(deleted task " + shortID(taskID) + ")"
+					if t, _ := store.Get(taskID); t != nil {
+						row.Title = t.Title
+						row.Status = t.Status
+						row.AgentID = t.AgentID
+					}
+					if cfg.Budgets != nil {
+						limit, ok := cfg.Budgets.Tasks[taskID]
+						if !ok {
+							limit = cfg.Budgets.DefaultTask
+						}
+						if !limit.IsZero() {
+							row.Limit = &limit
+						}
+					}
+				}
+				rows = append(rows, row)
+			}
+			sort.Slice(rows, func(i, j int) bool {
+				return rows[i].Cost.TotalCostUSD > rows[j].Cost.TotalCostUSD
+			})
 
-			if cfg.Budget > 0 {
-				if exceeded, _ := costStore.BudgetExceeded(); exceeded {
-					fmt.Printf("⚠️  Budget exceeded! Notify leader.\n")
-				} else {
-					s, _ := costStore.Summary("")
-					fmt.Printf("   Budget used: %.1f%% (%d / %d)\n",
-						s.BudgetUsedPercent, s.TotalTokens, cfg.Budget)
+			if jsonOut {
+				return printJSONList(rows)
+			}
+
+			fmt.Printf("Spend by task — team %q\n\n", name)
+			fmt.Printf("  %-40s  %-12s  %-12s  %-10s  %-10s  %s\n",
+				"TASK", "STATUS", "AGENT", "TOKENS", "COST", "LIMIT")
+			for _, r := range rows {
+				limit := "-"
+				if r.Limit != nil {
+					limit = formatLimit(r.Limit.SoftUSD) + " / " + formatLimit(r.Limit.HardUSD)
+				}
+				status := string(r.Status)
+				if status == "" {
+					status = "-"
+				}
+				agent := r.AgentID
+				if agent == "" {
+					agent = "-"
 				}
+				fmt.Printf("  %-40s  %-12s  %-12s  %-10d  %-10s  %s\n",
+					truncate(r.Title, 40), status, agent, r.Cost.TotalTokens,
+					formatUSD(r.Cost.TotalCostUSD), limit)
 			}
+			fmt.Printf("\n  %-40s  %s\n", "TOTAL", formatUSD(totalUSD))
 			return nil
 		},
 	}
-	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID (default: JIKIME_AGENT_ID)")
-	cmd.Flags().StringVar(&taskID, "task", "", "Task ID this cost belongs to")
-	cmd.Flags().StringVar(&model, "model", "", "Model name (e.g. claude-sonnet-4-6)")
-	cmd.Flags().IntVar(&inputTokens, "input-tokens", 0, "Input tokens consumed")
-	cmd.Flags().IntVar(&outputTokens, "output-tokens", 0, "Output tokens consumed")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	addUsageFlags(cmd, &u)
+	for _, flag := range budgetUsageFlags {
+		_ = cmd.Flags().MarkDeprecated(flag, `use "jikime team budget record" to record usage`)
+	}
 	return cmd
 }
 
@@ -208,10 +458,52 @@ func printBudgetSummary(teamName, agentID string, summary *team.CostSummary, bud
 	fmt.Printf("Budget summary — team:%s  agent:%s\n\n", teamName, agentID)
 	fmt.Printf("  Input tokens:   %d\n", summary.TotalInputTokens)
 	fmt.Printf("  Output tokens:  %d\n", summary.TotalOutputTokens)
+	fmt.Printf("  Cache write:    %d\n", summary.TotalCacheCreationTokens)
+	fmt.Printf("  Cache read:     %d\n", summary.TotalCacheReadTokens)
 	fmt.Printf("  Total tokens:   %d\n", summary.TotalTokens)
+	fmt.Printf("  Cost:           %s\n", formatUSD(summary.TotalCostUSD))
 	if budget > 0 {
 		fmt.Printf("  Budget limit:   %d\n", budget)
 		fmt.Printf("  Budget used:    %.1f%%\n", summary.BudgetUsedPercent)
 	}
 	fmt.Printf("  Events:         %d\n", summary.EventCount)
 }
+
+// printBudgetLimit prints one soft/hard limit line, skipping unset limits.
+func printBudgetLimit(label string, l team.BudgetLimit) {
+	if l.IsZero() {
+		return
+	}
+	fmt.Printf("    %-22s  %s / %s\n", label, formatLimit(l.SoftUSD), formatLimit(l.HardUSD))
+}
+
+// sortedLimitKeys returns the keys of m in sorted order.
+func sortedLimitKeys(m map[string]team.BudgetLimit) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatUSD renders a dollar amount with cent precision.
+func formatUSD(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// formatLimit renders a dollar limit, or "-" when disabled.
+func formatLimit(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return formatUSD(v)
+}
+
+// shortID returns the first 8 characters of a UUID for display.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
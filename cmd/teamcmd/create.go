@@ -19,6 +19,7 @@ func newCreateCmd() *cobra.Command {
 		timeout    int
 		maxAgents  int
 		tmplName   string
+		container  containerFlags
 	)
 
 	cmd := &cobra.Command{
@@ -65,6 +66,10 @@ func newCreateCmd() *cobra.Command {
 			if workers > 0 {
 				cfg.MaxAgents = workers + 1 // +1 for leader
 			}
+			if team.SpawnBackend(backend) == team.SpawnBackendContainer {
+				opts := container.options(cmd, td)
+				cfg.Container = &opts
+			}
 
 			data, err := json.MarshalIndent(cfg, "", "  ")
 			if err != nil {
@@ -89,10 +94,11 @@ func newCreateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().IntVarP(&workers, "workers", "w", 0, "Number of worker agents (0 = unlimited)")
-	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux or subprocess")
+	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux, subprocess or container")
 	cmd.Flags().IntVar(&budget, "budget", 0, "Token budget limit (0 = no limit)")
 	cmd.Flags().IntVar(&timeout, "timeout", 0, "Execution timeout in seconds (0 = no timeout)")
 	cmd.Flags().IntVar(&maxAgents, "max-agents", 0, "Max concurrent agents (0 = unlimited)")
 	cmd.Flags().StringVarP(&tmplName, "template", "t", "", "Template name to use")
+	container.register(cmd)
 	return cmd
 }
@@ -133,6 +133,7 @@ func newDiscoverJoinCmd() *cobra.Command {
 		role            string
 		agentID         string
 		backend         string
+		container       containerFlags
 		worktreePath    string
 		skipPermissions bool
 	)
@@ -170,6 +171,7 @@ Example:
 				Backend:         team.SpawnBackend(backend),
 				DataDir:         dataDir(),
 				SkipPermissions: skipPermissions,
+				Container:       container.options(cmd, td),
 			}
 
 			spawner := team.NewSpawner()
@@ -184,12 +186,16 @@ Example:
 				return fmt.Errorf("registry: %w", err)
 			}
 			info := &team.AgentInfo{
-				ID:          res.AgentID,
-				TeamName:    teamName,
-				Role:        role,
-				Status:      team.AgentStatusActive,
-				PID:         res.PID,
-				TmuxSession: res.TmuxSession,
+				ID:               res.AgentID,
+				TeamName:         teamName,
+				Role:             role,
+				Status:           team.AgentStatusActive,
+				PID:              res.PID,
+				TmuxSession:      res.TmuxSession,
+				ContainerID:      res.ContainerID,
+				ContainerRuntime: res.ContainerRuntime,
+				Backend:          team.SpawnBackend(backend),
+				WorktreePath:     worktreePath,
 			}
 			_ = reg.Register(info)
 
@@ -217,9 +223,10 @@ Example:
 	}
 	cmd.Flags().StringVarP(&role, "role", "r", "worker", "Agent role: worker, reviewer")
 	cmd.Flags().StringVar(&agentID, "agent-id", "", "Agent ID (auto-generated if empty)")
-	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux or subprocess")
+	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux, subprocess or container")
 	cmd.Flags().StringVar(&worktreePath, "worktree", "", "Git worktree path for this agent")
 	cmd.Flags().BoolVar(&skipPermissions, "skip-permissions", true, "Pass --dangerously-skip-permissions to Claude")
+	container.register(cmd)
 	return cmd
 }
 
@@ -316,6 +323,7 @@ func buildJoinPrompt(teamName, agentID, role string) string {
 	sb.WriteString("Your join request has been sent to the leader. Proceed as follows:\n\n")
 	sb.WriteString("1. Check your inbox for approval or rejection:\n")
 	sb.WriteString(fmt.Sprintf("   jikime team inbox receive %s\n", teamName))
+	sb.WriteString(fmt.Sprintf("   jikime team inbox ack %s <message-id>\n", teamName))
 	sb.WriteString("2. Once approved, begin claiming pending tasks:\n")
 	sb.WriteString(fmt.Sprintf("   jikime team tasks list %s --status pending\n", teamName))
 	sb.WriteString(fmt.Sprintf("   jikime team tasks claim %s <task-id> --agent %s\n", teamName, agentID))
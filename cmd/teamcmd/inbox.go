@@ -1,9 +1,11 @@
 package teamcmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -21,6 +23,11 @@ func newInboxCmd() *cobra.Command {
 	cmd.AddCommand(newInboxPeekCmd())
 	cmd.AddCommand(newInboxWatchCmd())
 	cmd.AddCommand(newInboxLogCmd())
+	cmd.AddCommand(newInboxAckCmd())
+	cmd.AddCommand(newInboxThreadCmd())
+	cmd.AddCommand(newInboxDeadLetterCmd())
+	cmd.AddCommand(newInboxAskCmd())
+	cmd.AddCommand(newInboxAnswerCmd())
 	return cmd
 }
 
@@ -28,6 +35,7 @@ func newInboxSendCmd() *cobra.Command {
 	var (
 		from    string
 		subject string
+		replyTo string
 	)
 	cmd := &cobra.Command{
 		Use:   "send <team-name> <to-agent> <message>",
@@ -48,17 +56,23 @@ func newInboxSendCmd() *cobra.Command {
 				To:       args[1],
 				Subject:  subject,
 				Body:     args[2],
+				ReplyTo:  replyTo,
 				SentAt:   time.Now(),
 			}
 			if err := ti.Send(msg); err != nil {
 				return err
 			}
-			fmt.Printf("✅ Message sent to %s\n", args[1])
+			if msg.DeadLetterReason != "" {
+				fmt.Printf("⚠️  %s is offline; message %.8s moved to dead-letter queue\n", args[1], msg.ID)
+				return nil
+			}
+			fmt.Printf("✅ Message %.8s sent to %s\n", msg.ID, args[1])
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&from, "from", "f", "", "Sender agent ID (default: JIKIME_AGENT_ID)")
 	cmd.Flags().StringVarP(&subject, "subject", "s", "", "Message subject")
+	cmd.Flags().StringVarP(&replyTo, "reply-to", "r", "", "ID of the message being answered (threads the conversation)")
 	return cmd
 }
 
@@ -106,13 +120,22 @@ func newInboxBroadcastCmd() *cobra.Command {
 
 func newInboxReceiveCmd() *cobra.Command {
 	var (
-		agentID string
-		limit   int
+		agentID    string
+		limit      int
+		ack        bool
+		visibility time.Duration
 	)
 	cmd := &cobra.Command{
 		Use:   "receive <team-name>",
-		Short: "Receive and consume messages from inbox",
-		Args:  cobra.ExactArgs(1),
+		Short: "Receive (lease) messages from inbox",
+		Long: `Receive messages from an agent inbox in FIFO order.
+
+By default messages are leased: hidden for --visibility, then redelivered
+unless acknowledged with 'jikime team inbox ack' once they have been handled.
+Messages leased more than 5 times without an ack move to the dead-letter queue.
+With --ack messages are acknowledged (removed) immediately, so a crash before
+they are handled loses them.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if agentID == "" {
 				agentID = os.Getenv("JIKIME_AGENT_ID")
@@ -124,7 +147,12 @@ func newInboxReceiveCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			msgs, err := ib.Receive(limit)
+			var msgs []*team.Message
+			if ack {
+				msgs, err = ib.Receive(limit)
+			} else {
+				msgs, err = ib.Lease(limit, visibility)
+			}
 			if err != nil {
 				return err
 			}
@@ -133,14 +161,24 @@ func newInboxReceiveCmd() *cobra.Command {
 				return nil
 			}
 			for _, m := range msgs {
-				fmt.Printf("[%s] from:%s  %s\n  %s\n",
-					m.SentAt.Format("15:04:05"), m.From, m.Subject, m.Body)
+				redelivered := ""
+				if m.DeliveryCount > 1 {
+					redelivered = fmt.Sprintf("  (delivery #%d)", m.DeliveryCount)
+				}
+				fmt.Printf("[%s] %.8s from:%s  %s%s%s\n  %s\n",
+					m.SentAt.Format("15:04:05"), m.ID, m.From, kindTag(m), m.Subject, redelivered, m.Body)
+			}
+			if !ack {
+				fmt.Printf("\n%d message(s) leased for %s — ack with: jikime team inbox ack %s <id...>\n",
+					len(msgs), visibility, args[0])
 			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
 	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Max messages to receive")
+	cmd.Flags().BoolVar(&ack, "ack", false, "Acknowledge messages immediately instead of leasing them")
+	cmd.Flags().DurationVar(&visibility, "visibility", team.DefaultVisibilityTimeout, "Lease duration before unacked messages are redelivered")
 	return cmd
 }
 
@@ -167,7 +205,7 @@ func newInboxPeekCmd() *cobra.Command {
 			}
 			fmt.Printf("Inbox for %s (%d messages):\n", agentID, len(msgs))
 			for _, m := range msgs {
-				fmt.Printf("  [%s] from:%-12s  %s\n", m.SentAt.Format("15:04:05"), m.From, m.Body)
+				fmt.Printf("  [%s] %.8s from:%-12s  %s%s\n", m.SentAt.Format("15:04:05"), m.ID, m.From, kindTag(m), m.Body)
 			}
 			return nil
 		},
@@ -278,3 +316,256 @@ Unlike 'receive', this does not consume messages — it reads the event log.`,
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
 	return cmd
 }
+
+func newInboxAckCmd() *cobra.Command {
+	var (
+		agentID string
+		nack    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "ack <team-name> <message-id...>",
+		Short: "Acknowledge leased messages (or return them with --nack)",
+		Long: `Acknowledge messages leased by 'receive' once they have been handled so they
+are not redelivered. IDs may be full UUIDs or the 8-character prefix shown by receive.
+With --nack the messages are returned to the queue immediately.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				agentID = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if agentID == "" {
+				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+			}
+			ib, err := team.NewInbox(team.InboxDir(teamDir(args[0]), agentID))
+			if err != nil {
+				return err
+			}
+			if nack {
+				if err := ib.Nack(args[1:]...); err != nil {
+					return err
+				}
+				fmt.Printf("↩️  Returned %d message(s) to the queue\n", len(args)-1)
+				return nil
+			}
+			if err := ib.Ack(args[1:]...); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Acknowledged %d message(s)\n", len(args)-1)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
+	cmd.Flags().BoolVar(&nack, "nack", false, "Return messages to the queue for redelivery")
+	return cmd
+}
+
+func newInboxThreadCmd() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "thread <team-name> [message-id]",
+		Short: "Show a conversation thread (messages linked by reply-to)",
+		Long: `Show the full conversation containing message-id, from the root message
+through every reply. Without a message ID, lists all threads with replies.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ti := team.NewTeamInbox(teamDir(args[0]))
+			if len(args) == 2 {
+				msgs, err := ti.Thread(args[1])
+				if err != nil {
+					return err
+				}
+				if jsonOut {
+					return printJSONList(msgs)
+				}
+				fmt.Printf("Thread %.8s: %d message(s)\n\n", msgs[0].ID, len(msgs))
+				for _, m := range msgs {
+					indent := ""
+					if m.ReplyTo != "" {
+						indent = "  ↳ "
+					}
+					fmt.Printf("  %s[%s] %.8s  %s → %s  %s\n",
+						indent, m.SentAt.Format("01-02 15:04:05"), m.ID, m.From, m.To, truncate(m.Body, 80))
+				}
+				return nil
+			}
+
+			threads, err := ti.Threads()
+			if err != nil {
+				return err
+			}
+			if len(threads) == 0 {
+				fmt.Println("No threads.")
+				return nil
+			}
+			roots := make([]*team.Message, 0, len(threads))
+			for _, msgs := range threads {
+				roots = append(roots, msgs[0])
+			}
+			sort.Slice(roots, func(i, j int) bool { return roots[i].SentAt.Before(roots[j].SentAt) })
+			if jsonOut {
+				return printJSONList(roots)
+			}
+			fmt.Printf("%d thread(s):\n\n", len(roots))
+			for _, r := range roots {
+				fmt.Printf("  %.8s  %-12s  %2d msg  %s\n",
+					r.ID, r.From, len(threads[r.ID]), truncate(r.Body, 60))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+func newInboxDeadLetterCmd() *cobra.Command {
+	var (
+		redrive bool
+		to      string
+		jsonOut bool
+	)
+	cmd := &cobra.Command{
+		Use:     "deadletter <team-name> [message-id...]",
+		Aliases: []string{"dlq"},
+		Short:   "List or redrive undeliverable messages",
+		Long: `Messages end up in the dead-letter queue when they are addressed to an
+agent that has left the team, or when they were leased too many times without
+an acknowledgement. With --redrive they are delivered again (optionally to a
+different agent via --to). Without message IDs, --redrive applies to all.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ti := team.NewTeamInbox(teamDir(args[0]))
+			if redrive {
+				n, err := ti.Redrive(to, args[1:]...)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✅ Redrove %d message(s)\n", n)
+				return nil
+			}
+
+			msgs, err := ti.DeadLetters()
+			if err != nil {
+				return err
+			}
+			if jsonOut {
+				return printJSONList(msgs)
+			}
+			if len(msgs) == 0 {
+				fmt.Println("Dead-letter queue is empty.")
+				return nil
+			}
+			fmt.Printf("Dead-letter queue: %d message(s)\n\n", len(msgs))
+			for _, m := range msgs {
+				fmt.Printf("  [%s] %.8s  %s → %s  %s\n      reason: %s\n",
+					m.SentAt.Format("01-02 15:04:05"), m.ID, m.From, m.To,
+					truncate(m.Body, 60), m.DeadLetterReason)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&redrive, "redrive", false, "Re-deliver the messages")
+	cmd.Flags().StringVar(&to, "to", "", "Redirect redriven messages to this agent")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+func newInboxAskCmd() *cobra.Command {
+	var (
+		from    string
+		to      string
+		kind    string
+		subject string
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "ask <team-name> <question>",
+		Short: "Send a request and block until the answer arrives",
+		Long: `Send a question (or review-request / handoff with --kind) and wait for the
+recipient to answer with 'jikime team inbox answer'. The answer body is
+printed to stdout. Exits with an error if no answer arrives within --timeout.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				from = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if from == "" {
+				from = "cli"
+			}
+			k := team.MessageKind(kind)
+			if !k.IsRequest() {
+				return fmt.Errorf("invalid --kind %q (use question, review-request or handoff)", kind)
+			}
+			rpc := team.NewRPC(team.NewFileTransport(teamDir(args[0])), args[0], from)
+			req, err := rpc.Send(k, to, subject, args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "⏳ %s %.8s sent to %s; waiting up to %s…\n", k, req.ID, to, timeout)
+
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			ans, err := rpc.Await(ctx, req)
+			if err != nil {
+				return fmt.Errorf("no answer from %s to %.8s: %w", to, req.ID, err)
+			}
+			fmt.Println(ans.Body)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&from, "from", "f", "", "Sender agent ID (default: JIKIME_AGENT_ID)")
+	cmd.Flags().StringVar(&to, "to", "leader", "Agent to ask")
+	cmd.Flags().StringVarP(&kind, "kind", "k", string(team.MessageKindQuestion), "Request kind: question, review-request, handoff")
+	cmd.Flags().StringVarP(&subject, "subject", "s", "", "Request subject")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 10*time.Minute, "Max time to wait for the answer (0 = no limit)")
+	return cmd
+}
+
+func newInboxAnswerCmd() *cobra.Command {
+	var from string
+	cmd := &cobra.Command{
+		Use:   "answer <team-name> <message-id> <answer>",
+		Short: "Answer a question, review-request or handoff",
+		Long: `Reply to a request sent with 'jikime team inbox ask'. The message ID may be
+the full ID or the 8-character prefix shown by receive, peek or tasks wait.
+The request is removed from your inbox, or acknowledged if it was leased.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				from = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if from == "" {
+				from = "leader"
+			}
+			td := teamDir(args[0])
+			req, err := team.NewTeamInbox(td).FindRequest(args[1])
+			if err != nil {
+				return err
+			}
+			tr := team.NewFileTransport(td)
+			ans, err := team.NewRPC(tr, args[0], from).Reply(req, args[2])
+			if err != nil {
+				return err
+			}
+			_, _ = tr.Take(from, req.ID)
+			if ib, err := team.NewInbox(team.InboxDir(td, from)); err == nil {
+				_ = ib.Ack(req.ID)
+			}
+			fmt.Printf("✅ Answered %s %.8s from %s (answer %.8s)\n", req.Kind, req.ID, req.From, ans.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&from, "from", "f", "", "Answering agent ID (default: JIKIME_AGENT_ID or leader)")
+	return cmd
+}
+
+// kindTag labels request and answer messages so agents know to respond.
+func kindTag(m *team.Message) string {
+	if m.Kind.IsRequest() || m.Kind == team.MessageKindAnswer {
+		return "(" + string(m.Kind) + ") "
+	}
+	return ""
+}
@@ -18,6 +18,7 @@ func newLaunchCmd() *cobra.Command {
 		backend      string
 		worktree     bool
 		budget       int
+		container    containerFlags
 	)
 
 	cmd := &cobra.Command{
@@ -58,6 +59,7 @@ Example:
 			cfg := team.TeamConfig{
 				Name:      teamName,
 				Template:  templateName,
+				Goal:      goal,
 				BaseDir:   td,
 				Budget:    budget,
 				CreatedAt: time.Now(),
@@ -66,6 +68,10 @@ Example:
 			if def.DefaultBudget > 0 && budget == 0 {
 				cfg.Budget = def.DefaultBudget
 			}
+			if team.SpawnBackend(backend) == team.SpawnBackendContainer {
+				opts := container.options(cmd, td)
+				cfg.Container = &opts
+			}
 			if err := writeJSON(filepath.Join(td, "config.json"), cfg); err != nil {
 				return err
 			}
@@ -132,7 +138,7 @@ Example:
 				wsPath := cwd // default: launch directory
 				if worktree {
 					wsPath = workspaceRoot(teamName, agentDef.ID)
-					branch := fmt.Sprintf("jikime-%s-%s", teamName, agentDef.ID)
+					branch := workspaceBranch(teamName, agentDef.ID)
 					if err := createWorktree(gitRoot, wsPath, branch); err != nil {
 						fmt.Printf("  ⚠️  worktree %s: %v — using cwd\n", agentDef.ID, err)
 						wsPath = cwd
@@ -167,6 +173,9 @@ Example:
 					DataDir:         dataDir(),
 					SkipPermissions: true,
 				}
+				if cfg.Container != nil {
+					spawnCfg.Container = *cfg.Container
+				}
 
 				res, err := spawner.Spawn(spawnCfg)
 				if err != nil {
@@ -174,17 +183,23 @@ Example:
 					continue
 				}
 				info := &team.AgentInfo{
-					ID:          res.AgentID,
-					TeamName:    teamName,
-					Role:        agentDef.Role,
-					Status:      team.AgentStatusActive,
-					PID:         res.PID,
-					TmuxSession: res.TmuxSession,
+					ID:               res.AgentID,
+					TeamName:         teamName,
+					Role:             agentDef.Role,
+					Status:           team.AgentStatusActive,
+					PID:              res.PID,
+					TmuxSession:      res.TmuxSession,
+					ContainerID:      res.ContainerID,
+					ContainerRuntime: res.ContainerRuntime,
+					Backend:          team.SpawnBackend(backend),
+					WorktreePath:     wsPath,
 				}
 				_ = reg.Register(info)
 
 				if res.TmuxSession != "" {
 					fmt.Printf("  ✅ Agent %-12s [%s] → tmux:%s\n", agentDef.ID, agentDef.Role, res.TmuxSession)
+				} else if res.ContainerID != "" {
+					fmt.Printf("  ✅ Agent %-12s [%s] → %s:%s\n", agentDef.ID, agentDef.Role, res.ContainerRuntime, shortContainerID(res.ContainerID))
 				} else {
 					fmt.Printf("  ✅ Agent %-12s [%s] → pid:%d\n", agentDef.ID, agentDef.Role, res.PID)
 				}
@@ -200,9 +215,10 @@ Example:
 	cmd.Flags().StringVarP(&templateName, "template", "t", "", "Template name (required)")
 	cmd.Flags().StringVar(&teamName, "name", "", "Team name (auto-generated if empty)")
 	cmd.Flags().StringVarP(&goal, "goal", "g", "", "Goal to inject into agent prompts")
-	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux or subprocess")
+	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux, subprocess or container")
 	cmd.Flags().BoolVarP(&worktree, "worktree", "w", false, "Create isolated git worktree per agent")
 	cmd.Flags().IntVar(&budget, "budget", 0, "Token budget (overrides template default)")
+	container.register(cmd)
 	_ = cmd.MarkFlagRequired("template")
 	return cmd
 }
@@ -0,0 +1,196 @@
+package teamcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/team"
+)
+
+func newReportCmd() *cobra.Command {
+	var (
+		format string
+		output string
+		repo   string
+		target string
+	)
+	cmd := &cobra.Command{
+		Use:   "report <team-name>",
+		Short: "Generate a self-contained run report (Markdown, HTML or JSON)",
+		Long: `Generate a report of a team run: goal, template, timeline, per-task
+outcomes with results and DoD, per-agent token spend, plan decisions,
+failures and the commits merged from agent workspaces.
+
+The format defaults to the extension of --output, or Markdown on stdout.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			td := teamDir(name)
+
+			if format == "" {
+				format = strings.TrimPrefix(filepath.Ext(output), ".")
+				if format == "" {
+					format = string(team.ReportMarkdown)
+				}
+			}
+			fmtKind, err := team.ParseReportFormat(format)
+			if err != nil {
+				return err
+			}
+
+			if _, err := os.Stat(filepath.Join(td, "config.json")); err != nil {
+				return fmt.Errorf("team %q not found", name)
+			}
+			cfg, costStore, err := loadBudgetConfig(td)
+			if err != nil {
+				return err
+			}
+			if cfg.Name == "" {
+				cfg.Name = name
+			}
+			reg, err := team.NewRegistry(filepath.Join(td, "registry"))
+			if err != nil {
+				return err
+			}
+			agents, _ := reg.List()
+			store, err := team.NewStore(filepath.Join(td, "tasks"))
+			if err != nil {
+				return err
+			}
+			tasks, err := store.List("", "")
+			if err != nil {
+				return err
+			}
+			var plans []*team.Plan
+			if ps, err := team.NewPlanStore(filepath.Join(dataDir(), "plans")); err == nil {
+				plans, _ = ps.List(name, "")
+			}
+
+			commits, err := reportCommits(name, agents, repo, target, cfg.CreatedAt)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  workspace commits unavailable: %v\n", err)
+			}
+
+			report, err := team.BuildReport(team.ReportInput{
+				Config:  cfg,
+				Agents:  agents,
+				Tasks:   tasks,
+				Plans:   plans,
+				Costs:   costStore,
+				Commits: commits,
+			})
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				return report.Render(os.Stdout, fmtKind)
+			}
+			var buf bytes.Buffer
+			if err := report.Render(&buf, fmtKind); err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("write report: %w", err)
+			}
+			fmt.Printf("✅ Report written to %s\n", output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Output format: md, html or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the report to a file instead of stdout")
+	cmd.Flags().StringVar(&repo, "repo", "", "Git repository the workspaces branch from (default: detected)")
+	cmd.Flags().StringVarP(&target, "target", "t", "", "Branch agent work is merged into (default: HEAD of the repo)")
+	return cmd
+}
+
+// reportCommits lists commits on each agent's workspace branch
+// (jikime-<team>-<agent>) made since the team was created, marking those
+// reachable from target as merged.
+func reportCommits(teamName string, agents []*team.AgentInfo, repo, target string, since time.Time) ([]team.ReportCommit, error) {
+	ids := workspaceAgents(teamName, agents)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	repo, err := workspaceRepo(teamName, ids, repo)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		target = "HEAD"
+	}
+
+	var out []team.ReportCommit
+	for _, id := range ids {
+		branch := workspaceBranch(teamName, id)
+		if _, err := gitOutput(repo, "rev-parse", "--verify", "--quiet", branch); err != nil {
+			continue
+		}
+		args := []string{"log", "--no-merges", "--format=%H%x1f%an%x1f%aI%x1f%s", branch}
+		if base := branchForkPoint(repo, branch); base != "" {
+			args = append(args, "^"+base)
+		} else if !since.IsZero() {
+			args = append(args, "--since="+since.Format(time.RFC3339))
+		}
+		logOut, err := gitOutput(repo, args...)
+		if err != nil {
+			return out, err
+		}
+		unmerged := make(map[string]bool)
+		if pending, err := gitOutput(repo, "rev-list", target+".."+branch); err == nil {
+			for _, sha := range strings.Fields(pending) {
+				unmerged[sha] = true
+			}
+		}
+		for _, line := range strings.Split(strings.TrimSpace(logOut), "\n") {
+			f := strings.Split(line, "\x1f")
+			if len(f) != 4 {
+				continue
+			}
+			date, _ := time.Parse(time.RFC3339, f[2])
+			out = append(out, team.ReportCommit{
+				Agent:   id,
+				Branch:  branch,
+				SHA:     f[0],
+				Author:  f[1],
+				Date:    date,
+				Subject: f[3],
+				Merged:  !unmerged[f[0]],
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+// branchForkPoint returns the commit branch was created at, from the oldest
+// entry of its reflog, or "" when the reflog is gone.
+func branchForkPoint(repo, branch string) string {
+	out, err := gitOutput(repo, "reflog", "show", "--format=%H", "refs/heads/"+branch)
+	if err != nil {
+		return ""
+	}
+	shas := strings.Fields(out)
+	if len(shas) == 0 {
+		return ""
+	}
+	return shas[len(shas)-1]
+}
+
+// gitOutput runs git in dir and returns its stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	ctx, cancel := gitCtx()
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
@@ -1,8 +1,11 @@
 package teamcmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"jikime-adk/internal/team"
@@ -16,6 +19,7 @@ func newSessionCmd() *cobra.Command {
 	cmd.AddCommand(newSessionSaveCmd())
 	cmd.AddCommand(newSessionShowCmd())
 	cmd.AddCommand(newSessionClearCmd())
+	cmd.AddCommand(newSessionRestoreCmd())
 	return cmd
 }
 
@@ -118,3 +122,145 @@ func newSessionClearCmd() *cobra.Command {
 	cmd.Flags().StringVar(&sessionID, "id", "", "Specific session ID to delete")
 	return cmd
 }
+
+func newSessionRestoreCmd() *cobra.Command {
+	var (
+		backend string
+		prune   bool
+		noSpawn bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore <team-name> <session-id|latest>",
+		Short: "Restore tasks from a session snapshot and respawn its agents",
+		Long: `Rehydrate a team from a saved session.
+
+1. Task files are rewritten to the snapshot (use --prune to delete tasks created since).
+2. Agents recorded in the session that are no longer running are respawned with
+   their original role, worktree and backend. Running agents are left untouched.
+3. In-progress tasks whose agent is gone and was not respawned are released to pending.
+
+Examples:
+  jikime team session restore my-team latest
+  jikime team session restore my-team 3f2a9c1d --backend subprocess
+  jikime team session restore my-team latest --no-spawn`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			td := teamDir(name)
+
+			ss, err := team.NewSessionStore(filepath.Join(dataDir(), "sessions", name))
+			if err != nil {
+				return err
+			}
+			sess, err := findSession(ss, args[1])
+			if err != nil {
+				return err
+			}
+
+			for _, sub := range []string{"tasks", "inbox", "registry", "costs", "events"} {
+				if err := mkdirAll(filepath.Join(td, sub)); err != nil {
+					return err
+				}
+			}
+			var cfg team.TeamConfig
+			cfgData, _ := os.ReadFile(filepath.Join(td, "config.json"))
+			_ = json.Unmarshal(cfgData, &cfg)
+
+			store, err := team.NewStore(filepath.Join(td, "tasks"))
+			if err != nil {
+				return err
+			}
+			reg, err := team.NewRegistry(filepath.Join(td, "registry"))
+			if err != nil {
+				return err
+			}
+
+			opts := team.RestoreOptions{
+				DataDir: dataDir(),
+				Goal:    cfg.Goal,
+				Backend: team.SpawnBackend(backend),
+				Prune:   prune,
+				NoSpawn: noSpawn,
+			}
+			if cfg.Container != nil {
+				opts.Container = *cfg.Container
+			}
+
+			fmt.Printf("♻️  Restoring team %q from session %s (saved %s)\n",
+				name, sess.ID[:8], sess.SavedAt.Format("2006-01-02 15:04:05"))
+			res, err := team.RestoreSession(sess, store, reg, team.NewSpawner(), opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("  ✅ Tasks restored: %d\n", res.TasksRestored)
+			for _, id := range res.TasksPruned {
+				fmt.Printf("  🗑  Pruned task %s\n", shortID(id))
+			}
+			for _, id := range res.AlreadyAlive {
+				fmt.Printf("  ⏭  Agent %-12s already running\n", id)
+			}
+			for _, r := range res.Respawned {
+				switch {
+				case r.TmuxSession != "":
+					fmt.Printf("  ✅ Agent %-12s → tmux:%s\n", r.AgentID, r.TmuxSession)
+				case r.ContainerID != "":
+					fmt.Printf("  ✅ Agent %-12s → %s:%s\n", r.AgentID, r.ContainerRuntime, shortContainerID(r.ContainerID))
+				default:
+					fmt.Printf("  ✅ Agent %-12s → pid:%d\n", r.AgentID, r.PID)
+				}
+			}
+			for id, ferr := range res.Failed {
+				fmt.Printf("  ⚠️  Agent %-12s not respawned: %v\n", id, ferr)
+			}
+			for _, id := range res.TasksReleased {
+				fmt.Printf("  ↩️  Released task %s (agent gone)\n", shortID(id))
+			}
+
+			if err := ss.MarkRestored(sess.ID); err != nil {
+				return fmt.Errorf("mark restored: %w", err)
+			}
+			fmt.Printf("\n✅ Team %q restored.\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&backend, "backend", "b", "", "Override spawn backend for all agents (default: as recorded)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete tasks created after the snapshot")
+	cmd.Flags().BoolVar(&noSpawn, "no-spawn", false, "Restore tasks only; do not respawn agents")
+	return cmd
+}
+
+// findSession resolves "latest", a full session ID, or a unique ID prefix.
+func findSession(ss *team.SessionStore, ref string) (*team.Session, error) {
+	if ref == "latest" {
+		sess, err := ss.Latest()
+		if err != nil {
+			return nil, err
+		}
+		if sess == nil {
+			return nil, fmt.Errorf("no saved sessions")
+		}
+		return sess, nil
+	}
+	if sess, err := ss.Load(ref); err != nil || sess != nil {
+		return sess, err
+	}
+	sessions, err := ss.List()
+	if err != nil {
+		return nil, err
+	}
+	var match *team.Session
+	for _, s := range sessions {
+		if strings.HasPrefix(s.ID, ref) {
+			if match != nil {
+				return nil, fmt.Errorf("session prefix %q is ambiguous", ref)
+			}
+			match = s
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("session %q not found", ref)
+	}
+	return match, nil
+}
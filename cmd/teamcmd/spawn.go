@@ -1,7 +1,10 @@
 package teamcmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"jikime-adk/internal/team"
@@ -16,6 +19,7 @@ func newSpawnCmd() *cobra.Command {
 		prompt          string
 		skipPermissions bool
 		resume          bool
+		container       containerFlags
 	)
 
 	cmd := &cobra.Command{
@@ -35,6 +39,7 @@ func newSpawnCmd() *cobra.Command {
 				Backend:         team.SpawnBackend(backend),
 				DataDir:         dataDir(),
 				SkipPermissions: skipPermissions,
+				Container:       container.options(cmd, td),
 			}
 			if resume {
 				cfg.ExtraEnv = map[string]string{"JIKIME_RESUME": "1"}
@@ -52,12 +57,16 @@ func newSpawnCmd() *cobra.Command {
 				return fmt.Errorf("registry: %w", err)
 			}
 			info := &team.AgentInfo{
-				ID:          res.AgentID,
-				TeamName:    teamName,
-				Role:        role,
-				Status:      team.AgentStatusActive,
-				PID:         res.PID,
-				TmuxSession: res.TmuxSession,
+				ID:               res.AgentID,
+				TeamName:         teamName,
+				Role:             role,
+				Status:           team.AgentStatusActive,
+				PID:              res.PID,
+				TmuxSession:      res.TmuxSession,
+				ContainerID:      res.ContainerID,
+				ContainerRuntime: res.ContainerRuntime,
+				Backend:          team.SpawnBackend(backend),
+				WorktreePath:     worktreePath,
 			}
 			if err := reg.Register(info); err != nil {
 				return fmt.Errorf("register: %w", err)
@@ -70,16 +79,78 @@ func newSpawnCmd() *cobra.Command {
 			if res.PID > 0 {
 				fmt.Printf("   pid:   %d\n", res.PID)
 			}
+			if res.ContainerID != "" {
+				fmt.Printf("   %s: %s\n", res.ContainerRuntime, shortContainerID(res.ContainerID))
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&role, "role", "r", "worker", "Agent role: leader, worker, reviewer")
 	cmd.Flags().StringVar(&agentID, "agent-id", "", "Agent ID (auto-generated if empty)")
-	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux or subprocess")
+	cmd.Flags().StringVarP(&backend, "backend", "b", "tmux", "Spawn backend: tmux, subprocess or container")
 	cmd.Flags().StringVar(&worktreePath, "worktree", "", "Git worktree path for this agent")
 	cmd.Flags().StringVarP(&prompt, "prompt", "p", "", "Initial prompt for the agent")
 	cmd.Flags().BoolVar(&skipPermissions, "skip-permissions", true, "Pass --dangerously-skip-permissions to Claude")
 	cmd.Flags().BoolVar(&resume, "resume", false, "Resume previous session if available")
+	container.register(cmd)
 	return cmd
 }
+
+// containerFlags holds CLI overrides for the container spawn backend.
+type containerFlags struct {
+	runtime string
+	image   string
+	cpus    string
+	memory  string
+	network string
+}
+
+// register adds the container flags to cmd.
+func (f *containerFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.runtime, "container-runtime", "", "Container runtime for --backend container: docker or podman (default: auto-detect)")
+	cmd.Flags().StringVar(&f.image, "image", "", "Container image for --backend container (default: "+team.DefaultContainerImage+")")
+	cmd.Flags().StringVar(&f.cpus, "cpus", "", "CPU limit per agent container (e.g. 2)")
+	cmd.Flags().StringVar(&f.memory, "memory", "", "Memory limit per agent container (e.g. 4g)")
+	cmd.Flags().StringVar(&f.network, "network", "", "Container network mode (e.g. none)")
+}
+
+// options returns the team's container defaults from config.json with any
+// flags set on cmd applied on top.
+func (f *containerFlags) options(cmd *cobra.Command, td string) team.ContainerOptions {
+	var cfg team.TeamConfig
+	data, _ := os.ReadFile(filepath.Join(td, "config.json"))
+	_ = json.Unmarshal(data, &cfg)
+
+	var opts team.ContainerOptions
+	if cfg.Container != nil {
+		opts = *cfg.Container
+	}
+	if cmd.Flags().Changed("container-runtime") {
+		opts.Runtime = f.runtime
+	}
+	if cmd.Flags().Changed("image") {
+		opts.Image = f.image
+	}
+	if cmd.Flags().Changed("cpus") {
+		opts.CPUs = f.cpus
+	}
+	if cmd.Flags().Changed("memory") {
+		opts.Memory = f.memory
+	}
+	if cmd.Flags().Changed("network") {
+		opts.Network = f.network
+	}
+	if len(opts.PassEnv) == 0 {
+		opts.PassEnv = []string{"ANTHROPIC_API_KEY"}
+	}
+	return opts
+}
+
+// shortContainerID returns the 12-character short form of a container ID.
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
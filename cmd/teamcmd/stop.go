@@ -37,15 +37,9 @@ func newStopCmd() *cobra.Command {
 			agents, _ := reg.List()
 			spawner := team.NewSpawner()
 			for _, a := range agents {
-				res := &team.SpawnResult{
-					AgentID:     a.ID,
-					TmuxSession: a.TmuxSession,
-					PID:         a.PID,
-				}
-				if err := spawner.Kill(res); err != nil {
+				if err := killAgent(spawner, reg, a); err != nil {
 					fmt.Fprintf(os.Stderr, "  warn: kill %s: %v\n", a.ID, err)
 				}
-				_ = reg.MarkDead(a.ID)
 			}
 
 			// Remove team directory
@@ -63,3 +57,17 @@ func newStopCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
 	return cmd
 }
+
+// killAgent terminates an agent's process, tmux session or container and
+// marks it offline in the registry.
+func killAgent(spawner *team.Spawner, reg *team.Registry, a *team.AgentInfo) error {
+	err := spawner.Kill(&team.SpawnResult{
+		AgentID:          a.ID,
+		TmuxSession:      a.TmuxSession,
+		PID:              a.PID,
+		ContainerID:      a.ContainerID,
+		ContainerRuntime: a.ContainerRuntime,
+	})
+	_ = reg.MarkDead(a.ID)
+	return err
+}
@@ -313,13 +313,21 @@ func newTaskListCmd() *cobra.Command {
 
 func newTaskWaitCmd() *cobra.Command {
 	var (
-		timeout  int
-		interval int
+		timeout   int
+		interval  int
+		agentID   string
+		noRequest bool
 	)
 	cmd := &cobra.Command{
 		Use:   "wait <team-name>",
 		Short: "Wait until all tasks are completed",
-		Args:  cobra.ExactArgs(1),
+		Long: `Wait until every task is done or failed.
+
+The wait also returns early, with status "request", when a question,
+review-request or handoff is waiting in the caller's inbox. Answer it with
+'jikime team inbox answer' and run wait again. Use --ignore-requests to
+keep waiting regardless.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			td := teamDir(name)
@@ -343,8 +351,25 @@ func newTaskWaitCmd() *cobra.Command {
 				},
 			}
 
+			var leaderInbox *team.Inbox
+			if !noRequest {
+				if agentID == "" {
+					agentID = os.Getenv("JIKIME_AGENT_ID")
+				}
+				if agentID == "" {
+					agentID = "leader"
+				}
+				if leaderInbox, err = team.NewInbox(team.InboxDir(td, agentID)); err != nil {
+					return err
+				}
+				cb.OnRequest = func(m *team.Message) bool {
+					fmt.Printf("\n  ❓ %s %.8s from %s: %s\n", m.Kind, m.ID, m.From, truncate(m.Body, 100))
+					return true
+				}
+			}
+
 			dur := time.Duration(interval) * time.Second
-			w := team.NewWaiter(store, reg, nil, name, dur, cb)
+			w := team.NewWaiter(store, reg, leaderInbox, name, dur, cb)
 
 			ctx := context.Background()
 			if timeout > 0 {
@@ -359,11 +384,16 @@ func newTaskWaitCmd() *cobra.Command {
 				return err
 			}
 			fmt.Printf("\n  status: %s  elapsed: %s\n", result.Status, result.Elapsed.Round(time.Second))
+			if result.Status == "request" {
+				fmt.Printf("  Answer with: jikime team inbox answer %s <message-id> \"...\"  — then run wait again.\n", name)
+			}
 			return nil
 		},
 	}
 	cmd.Flags().IntVarP(&timeout, "timeout", "t", 0, "Max wait time in seconds (0 = no limit)")
 	cmd.Flags().IntVarP(&interval, "interval", "i", 5, "Poll interval in seconds")
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent whose inbox is checked for requests (default: JIKIME_AGENT_ID or leader)")
+	cmd.Flags().BoolVar(&noRequest, "ignore-requests", false, "Do not return early when a request is pending")
 	return cmd
 }
 
@@ -60,6 +60,7 @@ Examples:
 	cmd.AddCommand(newLaunchCmd())
 	cmd.AddCommand(newBoardCmd())
 	cmd.AddCommand(newBudgetCmd())
+	cmd.AddCommand(newReportCmd())
 	cmd.AddCommand(newDiscoverCmd())
 
 	return cmd
@@ -2,14 +2,17 @@ package teamcmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/team"
 )
 
 // gitCtx returns a 30-second context for git commands — 무한 대기 방지
@@ -24,7 +27,11 @@ func newWorkspaceCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newWorkspaceListCmd())
 	cmd.AddCommand(newWorkspaceCheckpointCmd())
+	cmd.AddCommand(newWorkspaceCheckpointsCmd())
+	cmd.AddCommand(newWorkspaceDiffCmd())
+	cmd.AddCommand(newWorkspaceRollbackCmd())
 	cmd.AddCommand(newWorkspaceMergeCmd())
+	cmd.AddCommand(newWorkspaceQueueCmd())
 	cmd.AddCommand(newWorkspaceCleanupCmd())
 	cmd.AddCommand(newWorkspaceStatusCmd())
 	return cmd
@@ -34,6 +41,51 @@ func workspaceRoot(teamName, agentID string) string {
 	return filepath.Join(dataDir(), "worktrees", teamName, agentID)
 }
 
+// workspaceBranch is the branch an agent's worktree is created on.
+func workspaceBranch(teamName, agentID string) string {
+	return fmt.Sprintf("jikime-%s-%s", teamName, agentID)
+}
+
+// workspaceAgents returns the sorted IDs of registered agents plus agents
+// that still have a workspace directory (workspaces outlive registry entries).
+func workspaceAgents(teamName string, agents []*team.AgentInfo) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, a := range agents {
+		add(a.ID)
+	}
+	if entries, err := os.ReadDir(filepath.Join(dataDir(), "worktrees", teamName)); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				add(e.Name())
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// workspaceRepo returns repo if set, else the main repository of the first
+// agent workspace found, else the repository containing the current directory.
+func workspaceRepo(teamName string, agentIDs []string, repo string) (string, error) {
+	if repo != "" {
+		return gitMainRepoRoot(repo)
+	}
+	for _, id := range agentIDs {
+		if root, err := gitMainRepoRoot(workspaceRoot(teamName, id)); err == nil {
+			return root, nil
+		}
+	}
+	cwd, _ := os.Getwd()
+	return gitMainRepoRoot(cwd)
+}
+
 // gitRepoRoot returns the root directory of the git repository containing dir.
 // For a worktree, this returns the worktree's own root (not the main repo).
 func gitRepoRoot(dir string) (string, error) {
@@ -122,46 +174,6 @@ func newWorkspaceListCmd() *cobra.Command {
 	}
 }
 
-func newWorkspaceCheckpointCmd() *cobra.Command {
-	var (
-		agentID string
-		message string
-	)
-	cmd := &cobra.Command{
-		Use:   "checkpoint <team-name>",
-		Short: "Auto-commit current workspace changes",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if agentID == "" {
-				agentID = os.Getenv("JIKIME_AGENT_ID")
-			}
-			if agentID == "" {
-				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
-			}
-			wsDir := workspaceRoot(args[0], agentID)
-			if message == "" {
-				message = fmt.Sprintf("checkpoint: %s %s", agentID, time.Now().Format("2006-01-02T15:04"))
-			}
-			for _, c := range [][]string{
-				{"git", "-C", wsDir, "add", "-A"},
-				{"git", "-C", wsDir, "commit", "-m", message},
-			} {
-				ctx, cancel := gitCtx()
-				out, err := exec.CommandContext(ctx, c[0], c[1:]...).CombinedOutput()
-				cancel()
-				if err != nil && !strings.Contains(string(out), "nothing to commit") {
-					return fmt.Errorf("git: %w\n%s", err, out)
-				}
-			}
-			fmt.Printf("✅ Checkpoint committed in %s\n", wsDir)
-			return nil
-		},
-	}
-	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
-	cmd.Flags().StringVarP(&message, "message", "m", "", "Commit message")
-	return cmd
-}
-
 func newWorkspaceMergeCmd() *cobra.Command {
 	var (
 		agentID string
@@ -180,7 +192,7 @@ func newWorkspaceMergeCmd() *cobra.Command {
 				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
 			}
 			wsDir := workspaceRoot(args[0], agentID)
-			branch := fmt.Sprintf("jikime-%s-%s", args[0], agentID)
+			branch := workspaceBranch(args[0], agentID)
 
 			// Find the MAIN repo root (not the worktree root) so the merge lands on main.
 			gitRoot, err := gitMainRepoRoot(wsDir)
@@ -270,3 +282,139 @@ func newWorkspaceStatusCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
 	return cmd
 }
+
+func newWorkspaceQueueCmd() *cobra.Command {
+	var (
+		target     string
+		staging    string
+		verify     string
+		verifyEach bool
+		timeout    time.Duration
+		repo       string
+		dryRun     bool
+		notify     bool
+		jsonOut    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "queue <team-name>",
+		Short: "Merge all agent branches through a verified staging branch",
+		Long: `Merge every agent workspace branch with unmerged commits, in task
+dependency order, onto a staging branch cut from --target.
+
+Branches that conflict are skipped. When a verify command is set (--verify
+or the team's merge_verify config), it runs on staging after the merges;
+if it fails the queue bisects to find the branch that broke it and drops
+that branch. Target is then fast-forwarded to staging (unless --dry-run),
+and each rejected agent gets the conflict or test output in its inbox.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			td := teamDir(name)
+			var cfg team.TeamConfig
+			data, err := os.ReadFile(filepath.Join(td, "config.json"))
+			if err != nil {
+				return fmt.Errorf("team %q not found", name)
+			}
+			_ = json.Unmarshal(data, &cfg)
+			if verify == "" {
+				verify = cfg.MergeVerify
+			}
+			if staging == "" {
+				staging = workspaceBranch(name, "staging")
+			}
+
+			reg, err := team.NewRegistry(filepath.Join(td, "registry"))
+			if err != nil {
+				return err
+			}
+			agents, _ := reg.List()
+			store, err := team.NewStore(filepath.Join(td, "tasks"))
+			if err != nil {
+				return err
+			}
+			tasks, _ := store.List("", "")
+
+			ids := workspaceAgents(name, agents)
+			gitRoot, err := workspaceRepo(name, ids, repo)
+			if err != nil {
+				return fmt.Errorf("find git root: %w", err)
+			}
+			var cands []team.MergeCandidate
+			for _, id := range ids {
+				branch := workspaceBranch(name, id)
+				ctx, cancel := gitCtx()
+				out, err := exec.CommandContext(ctx, "git", "-C", gitRoot, "rev-list", "--count", target+".."+branch).Output()
+				cancel()
+				if err != nil || strings.TrimSpace(string(out)) == "0" {
+					continue // no branch, or nothing to merge
+				}
+				cands = append(cands, team.MergeCandidate{AgentID: id, Branch: branch})
+			}
+			if len(cands) == 0 {
+				fmt.Printf("Nothing to merge into %s.\n", target)
+				return nil
+			}
+			cands = team.OrderMergeCandidates(cands, tasks)
+
+			q := &team.MergeQueue{
+				Repo:          gitRoot,
+				Target:        target,
+				Staging:       staging,
+				Verify:        verify,
+				VerifyEach:    verifyEach,
+				VerifyTimeout: timeout,
+				DryRun:        dryRun,
+			}
+			if !jsonOut {
+				q.Log = os.Stdout
+			}
+			res, err := q.Run(cands)
+			if err != nil {
+				return err
+			}
+
+			if notify && len(res.Rejected) > 0 {
+				from := os.Getenv("JIKIME_AGENT_ID")
+				if from == "" {
+					from = "merge-queue"
+				}
+				ti := team.NewTeamInbox(td)
+				for i := range res.Rejected {
+					if err := ti.Send(res.Rejected[i].RejectionMessage(name, from, target)); err != nil {
+						fmt.Fprintf(os.Stderr, "⚠️  notify %s: %v\n", res.Rejected[i].AgentID, err)
+					}
+				}
+			}
+
+			if jsonOut {
+				return printJSONList(res)
+			}
+			fmt.Println()
+			for _, c := range res.Merged {
+				fmt.Printf("✅ %-14s %s\n", c.AgentID, c.Branch)
+			}
+			for _, r := range res.Rejected {
+				fmt.Printf("❌ %-14s %s (%s)\n", r.AgentID, r.Branch, r.Reason)
+			}
+			switch {
+			case res.Landed:
+				fmt.Printf("\n%s fast-forwarded to %.8s (%d merged, %d rejected)\n", target, res.StagingSHA, len(res.Merged), len(res.Rejected))
+			case dryRun:
+				fmt.Printf("\nDry run: %s is at %.8s; %s was not moved\n", staging, res.StagingSHA, target)
+			default:
+				fmt.Printf("\nNothing merged into %s\n", target)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&target, "target", "t", "main", "Target branch")
+	cmd.Flags().StringVar(&staging, "staging", "", "Staging branch (default jikime-<team>-staging)")
+	cmd.Flags().StringVar(&verify, "verify", "", "Verification command run on staging (default: team merge_verify config)")
+	cmd.Flags().BoolVar(&verifyEach, "verify-each", false, "Verify after every merge instead of once with bisection")
+	cmd.Flags().DurationVar(&timeout, "verify-timeout", team.DefaultMergeVerifyTimeout, "Timeout for one verification run")
+	cmd.Flags().StringVar(&repo, "repo", "", "Git repository (default: detected from the workspaces)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Build and verify staging without moving the target branch")
+	cmd.Flags().BoolVar(&notify, "notify", true, "Send rejected agents the conflict or verification output")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output the result as JSON")
+	return cmd
+}
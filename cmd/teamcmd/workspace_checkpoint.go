@@ -0,0 +1,301 @@
+package teamcmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/team"
+)
+
+// checkpointStore opens the team's checkpoint records.
+func checkpointStore(teamName string) (*team.CheckpointStore, error) {
+	return team.NewCheckpointStore(filepath.Join(teamDir(teamName), "checkpoints"))
+}
+
+// currentTaskID guesses the task an agent is working on: JIKIME_TASK_ID,
+// then the registry, then the agent's only in-progress task.
+func currentTaskID(teamName, agentID string) string {
+	if id := os.Getenv("JIKIME_TASK_ID"); id != "" {
+		return id
+	}
+	td := teamDir(teamName)
+	if reg, err := team.NewRegistry(filepath.Join(td, "registry")); err == nil {
+		if info, _ := reg.Get(agentID); info != nil && info.CurrentTaskID != "" {
+			return info.CurrentTaskID
+		}
+	}
+	if store, err := team.NewStore(filepath.Join(td, "tasks")); err == nil {
+		if tasks, _ := store.List(team.TaskStatusInProgress, agentID); len(tasks) == 1 {
+			return tasks[0].ID
+		}
+	}
+	return ""
+}
+
+func newWorkspaceCheckpointCmd() *cobra.Command {
+	var (
+		agentID string
+		message string
+		taskID  string
+	)
+	cmd := &cobra.Command{
+		Use:   "checkpoint <team-name>",
+		Short: "Commit current workspace changes and record a restore point",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				agentID = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if agentID == "" {
+				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+			}
+			if taskID == "" {
+				taskID = currentTaskID(args[0], agentID)
+			}
+			wsDir := workspaceRoot(args[0], agentID)
+			if message == "" {
+				message = fmt.Sprintf("checkpoint: %s %s", agentID, time.Now().Format("2006-01-02T15:04"))
+			}
+			for _, c := range [][]string{
+				{"git", "-C", wsDir, "add", "-A"},
+				{"git", "-C", wsDir, "commit", "-m", message},
+			} {
+				ctx, cancel := gitCtx()
+				out, err := exec.CommandContext(ctx, c[0], c[1:]...).CombinedOutput()
+				cancel()
+				if err != nil && !strings.Contains(string(out), "nothing to commit") {
+					return fmt.Errorf("git: %w\n%s", err, out)
+				}
+			}
+
+			sha, err := gitOutput(wsDir, "rev-parse", "HEAD")
+			if err != nil {
+				return err
+			}
+			sha = strings.TrimSpace(sha)
+			cs, err := checkpointStore(args[0])
+			if err != nil {
+				return err
+			}
+			if last, _ := cs.Latest(agentID); last != nil && last.SHA == sha {
+				fmt.Printf("No changes since checkpoint %.8s (%.8s)\n", last.ID, sha)
+				return nil
+			}
+			cp, err := cs.Record(&team.Checkpoint{
+				TeamName: args[0],
+				AgentID:  agentID,
+				TaskID:   taskID,
+				SHA:      sha,
+				Message:  message,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Checkpoint %.8s committed in %s (commit %.8s", cp.ID, wsDir, sha)
+			if taskID != "" {
+				fmt.Printf(", task %.8s", taskID)
+			}
+			fmt.Println(")")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Commit message")
+	cmd.Flags().StringVar(&taskID, "task", "", "Task ID (default: the agent's current task)")
+	return cmd
+}
+
+func newWorkspaceCheckpointsCmd() *cobra.Command {
+	var (
+		agentID string
+		jsonOut bool
+	)
+	cmd := &cobra.Command{
+		Use:   "checkpoints <team-name>",
+		Short: "List an agent's workspace checkpoints",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				agentID = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if agentID == "" {
+				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+			}
+			cs, err := checkpointStore(args[0])
+			if err != nil {
+				return err
+			}
+			cps, err := cs.List(agentID)
+			if err != nil {
+				return err
+			}
+			if jsonOut {
+				return printJSONList(cps)
+			}
+			if len(cps) == 0 {
+				fmt.Printf("No checkpoints for %s.\n", agentID)
+				return nil
+			}
+			head, _ := gitOutput(workspaceRoot(args[0], agentID), "rev-parse", "HEAD")
+			head = strings.TrimSpace(head)
+			fmt.Printf("Checkpoints for %s:\n", agentID)
+			fmt.Printf("  %-4s %-9s %-9s %-9s %-17s %s\n", "#", "ID", "COMMIT", "TASK", "CREATED", "MESSAGE")
+			for i, cp := range cps {
+				marker := " "
+				if cp.SHA == head {
+					marker = "*"
+				}
+				task := "-"
+				if cp.TaskID != "" {
+					task = fmt.Sprintf("%.8s", cp.TaskID)
+				}
+				fmt.Printf("%s #%-3d %-9.8s %-9.8s %-9s %-17s %s\n", marker, i+1, cp.ID, cp.SHA, task,
+					cp.CreatedAt.Format("2006-01-02 15:04"), truncate(cp.Message, 50))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+func newWorkspaceDiffCmd() *cobra.Command {
+	var (
+		agentID string
+		stat    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "diff <team-name> <from> [to]",
+		Short: "Diff two checkpoints (or a checkpoint and the current worktree)",
+		Long: `Show the changes between two checkpoints of an agent's workspace.
+Checkpoints are referenced by ID prefix, commit prefix, or list position
+("#2"). Without <to>, the checkpoint is compared to the current worktree.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				agentID = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if agentID == "" {
+				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+			}
+			cs, err := checkpointStore(args[0])
+			if err != nil {
+				return err
+			}
+			from, err := cs.Resolve(agentID, args[1])
+			if err != nil {
+				return err
+			}
+			gitArgs := []string{"diff"}
+			if stat {
+				gitArgs = append(gitArgs, "--stat")
+			}
+			gitArgs = append(gitArgs, from.SHA)
+			if len(args) == 3 {
+				to, err := cs.Resolve(agentID, args[2])
+				if err != nil {
+					return err
+				}
+				gitArgs = append(gitArgs, to.SHA)
+			}
+			out, err := gitOutput(workspaceRoot(args[0], agentID), gitArgs...)
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				fmt.Println("No changes.")
+				return nil
+			}
+			fmt.Print(out)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
+	cmd.Flags().BoolVar(&stat, "stat", false, "Show a diffstat only")
+	return cmd
+}
+
+func newWorkspaceRollbackCmd() *cobra.Command {
+	var (
+		agentID string
+		force   bool
+		reopen  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "rollback <team-name> <checkpoint>",
+		Short: "Hard-reset an agent's workspace to a checkpoint and re-open its task",
+		Long: `Reset an agent's worktree to a checkpoint with git reset --hard and
+remove untracked files. The checkpoint's task is re-opened (set back to
+pending with its result cleared) and the agent is told in its inbox.
+
+Refuses to discard uncommitted changes unless --force is given.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if agentID == "" {
+				agentID = os.Getenv("JIKIME_AGENT_ID")
+			}
+			if agentID == "" {
+				return fmt.Errorf("--agent or JIKIME_AGENT_ID required")
+			}
+			cs, err := checkpointStore(name)
+			if err != nil {
+				return err
+			}
+			cp, err := cs.Resolve(agentID, args[1])
+			if err != nil {
+				return err
+			}
+			wsDir := workspaceRoot(name, agentID)
+			if !force {
+				dirty, err := gitOutput(wsDir, "status", "--porcelain")
+				if err != nil {
+					return err
+				}
+				if strings.TrimSpace(dirty) != "" {
+					return fmt.Errorf("workspace %s has uncommitted changes (checkpoint them or use --force)", wsDir)
+				}
+			}
+			if _, err := gitOutput(wsDir, "reset", "--hard", cp.SHA); err != nil {
+				return err
+			}
+			if _, err := gitOutput(wsDir, "clean", "-fd"); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Workspace %s reset to checkpoint %.8s (commit %.8s)\n", agentID, cp.ID, cp.SHA)
+
+			if !reopen || cp.TaskID == "" {
+				return nil
+			}
+			store, err := team.NewStore(filepath.Join(teamDir(name), "tasks"))
+			if err != nil {
+				return err
+			}
+			t, err := store.Reopen(cp.TaskID)
+			if err != nil {
+				return fmt.Errorf("re-open task %.8s: %w", cp.TaskID, err)
+			}
+			fmt.Printf("🔄 Task %.8s %q re-opened (%s)\n", t.ID, t.Title, t.Status)
+			_ = team.NewTeamInbox(teamDir(name)).Send(&team.Message{
+				TeamName: name,
+				Kind:     team.MessageKindSystem,
+				From:     "orchestrator",
+				To:       agentID,
+				Subject:  "workspace_rollback",
+				Body: fmt.Sprintf("Your workspace was rolled back to checkpoint %.8s (commit %.8s, %s). Task %.8s %q was re-opened; claim it again with: jikime team tasks claim %s %s",
+					cp.ID, cp.SHA, cp.CreatedAt.Format("2006-01-02 15:04"), t.ID, t.Title, name, t.ID),
+			})
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&agentID, "agent", "a", "", "Agent ID")
+	cmd.Flags().BoolVar(&force, "force", false, "Discard uncommitted changes")
+	cmd.Flags().BoolVar(&reopen, "reopen", true, "Re-open the checkpoint's task")
+	return cmd
+}
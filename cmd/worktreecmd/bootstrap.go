@@ -0,0 +1,86 @@
+package worktreecmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
+)
+
+func newBootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap <spec-id>",
+		Short: "Re-run worktree.yaml post_create steps",
+		Long: `Re-run the post_create steps from worktree.yaml in an existing worktree.
+
+Use this after fixing a failed bootstrap (missing tool, bad install command).
+Results replace the previously recorded ones; an allocated port range is
+kept if it is still free of other worktrees.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := getManager()
+			if err != nil {
+				return err
+			}
+
+			info, err := manager.Bootstrap(args[0])
+			if info != nil {
+				printStepResults(info.Hooks, worktree.HookPostCreate)
+			}
+			if err != nil {
+				color.Red("✗ %v", err)
+				return err
+			}
+			color.Green("✓ Bootstrap %s: %s", args[0], info.BootstrapHealth())
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printStepResults lists the recorded results of one hook.
+func printStepResults(results []worktree.StepResult, hook string) {
+	for _, r := range results {
+		if r.Hook != hook {
+			continue
+		}
+		line := fmt.Sprintf("  %s %s (%s)", stepIcon(r), r.Name, (time.Duration(r.DurationMs) * time.Millisecond).Round(time.Millisecond))
+		if r.Detail != "" {
+			line += ": " + r.Detail
+		}
+		switch {
+		case r.Status == worktree.StepOK:
+			color.Green(line)
+		case r.Optional:
+			color.Yellow(line)
+		default:
+			color.Red(line)
+		}
+	}
+}
+
+func stepIcon(r worktree.StepResult) string {
+	switch {
+	case r.Status == worktree.StepOK:
+		return "✓"
+	case r.Optional:
+		return "!"
+	}
+	return "✗"
+}
+
+// healthColor picks the color for a bootstrap health value.
+func healthColor(health string) *color.Color {
+	switch health {
+	case worktree.HealthHealthy:
+		return color.New(color.FgGreen)
+	case worktree.HealthDegraded:
+		return color.New(color.FgYellow)
+	case worktree.HealthFailed:
+		return color.New(color.FgRed)
+	}
+	return color.New(color.Reset)
+}
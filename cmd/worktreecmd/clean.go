@@ -15,6 +15,7 @@ import (
 func newCleanCmd() *cobra.Command {
 	var (
 		mergedOnly  bool
+		prMerged    bool
 		stale       bool
 		days        int
 		interactive bool
@@ -26,8 +27,9 @@ func newCleanCmd() *cobra.Command {
 		Long: `Remove worktrees for merged branches or stale worktrees.
 
 By default, removes all worktrees. Use --merged-only to only remove
-worktrees whose branches have been merged to main. Use --stale to
-remove worktrees not accessed within the specified days.`,
+worktrees whose branches have been merged to main. Use --pr-merged to
+remove worktrees whose pull requests (from 'done --pr') were merged on the
+forge. Use --stale to remove worktrees not accessed within the specified days.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager, err := getManager()
 			if err != nil {
@@ -38,6 +40,18 @@ remove worktrees not accessed within the specified days.`,
 
 			if mergedOnly {
 				cleaned = manager.CleanMerged()
+			} else if prMerged {
+				f, err := getForge(manager)
+				if err != nil {
+					color.Red("✗ %v", err)
+					return err
+				}
+				ctx, cancel := forgeContext()
+				defer cancel()
+				cleaned, err = manager.CleanMergedPRs(ctx, f)
+				if err != nil {
+					color.Yellow("Some pull requests could not be checked: %v", err)
+				}
 			} else if stale {
 				// Clean stale worktrees (not accessed within N days)
 				threshold := time.Now().AddDate(0, 0, -days)
@@ -167,6 +181,7 @@ remove worktrees not accessed within the specified days.`,
 	}
 
 	cmd.Flags().BoolVar(&mergedOnly, "merged-only", false, "Only remove merged branch worktrees")
+	cmd.Flags().BoolVar(&prMerged, "pr-merged", false, "Only remove worktrees whose pull requests are merged")
 	cmd.Flags().BoolVar(&stale, "stale", false, "Remove worktrees not accessed within the specified days")
 	cmd.Flags().IntVar(&days, "days", 30, "Stale threshold in days (default: 30)")
 	cmd.Flags().BoolVar(&interactive, "interactive", false, "Interactive cleanup with confirmation prompts")
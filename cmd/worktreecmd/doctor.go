@@ -0,0 +1,95 @@
+package worktreecmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var (
+		fix    bool
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the worktree registry against git and repair it",
+		Long: `Check the worktree registry against git and repair it.
+
+Compares the registry with 'git worktree list --porcelain' and reports:
+- prunable:        git tracks the worktree but its directory is gone
+- missing:         registered, but neither on disk nor known to git
+- moved:           the branch is checked out at a different path
+- branch-mismatch: git has a different branch checked out
+- branch-gone:     the branch's upstream was deleted (after fetch --prune)
+- unregistered:    a git worktree under the worktree root is not registered
+- corrupt:         the registry file cannot be parsed
+
+With --fix, everything except branch-gone is repaired: prunable entries are
+pruned, moved/mismatched entries updated, unregistered worktrees added, and
+a corrupt registry backed up and rebuilt. Worktrees whose upstream is gone
+are left for you to remove, since that would discard local work.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := getManager()
+			if err != nil {
+				return err
+			}
+
+			issues, err := manager.Doctor(fix)
+			worktree.SortIssues(issues)
+			if format == "json" {
+				output, _ := json.MarshalIndent(issues, "", "  ")
+				fmt.Println(string(output))
+				return err
+			}
+			if err != nil {
+				color.Red("✗ %v", err)
+				return err
+			}
+
+			if len(issues) == 0 {
+				color.Green("✓ Registry matches git (%s)", manager.Registry.Path())
+				return nil
+			}
+
+			pending, fixable := 0, 0
+			for _, issue := range issues {
+				label := issue.SpecID
+				if label == "" {
+					label = issue.Path
+				}
+				switch {
+				case issue.Fixed:
+					color.Green("✓ %s [%s] fixed: %s", label, issue.Kind, issue.Fix)
+				default:
+					pending++
+					if issue.Kind != worktree.IssueBranchGone {
+						fixable++
+					}
+					color.Yellow("✗ %s [%s]", label, issue.Kind)
+				}
+				fmt.Printf("    %s\n", issue.Detail)
+				if !issue.Fixed {
+					fmt.Printf("    fix: %s\n", issue.Fix)
+				}
+			}
+
+			fmt.Println()
+			if fixable > 0 && !fix {
+				color.Cyan("%d issue(s) found. Run 'jikime worktree doctor --fix' to repair.", pending)
+			} else if pending > 0 {
+				color.Cyan("%d issue(s) need manual attention.", pending)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Repair the registry")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format (table, json)")
+
+	return cmd
+}
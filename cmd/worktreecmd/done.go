@@ -5,6 +5,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
 )
 
 func newDoneCmd() *cobra.Command {
@@ -12,6 +13,8 @@ func newDoneCmd() *cobra.Command {
 		base  string
 		push  bool
 		force bool
+		pr    bool
+		draft bool
 	)
 
 	cmd := &cobra.Command{
@@ -23,7 +26,21 @@ This command performs the full completion workflow:
 1. Checkout base branch (main)
 2. Merge worktree branch into base
 3. Remove worktree
-4. Delete feature branch`,
+4. Delete feature branch
+
+worktree.yaml pre_remove steps run before the worktree is removed and
+post_done steps run in the main repository afterwards.
+
+Worktrees stacked on this one (see 'new --parent') are rebased onto the
+base branch and take this worktree's place in the stack.
+
+With --pr, nothing is merged locally. The branch is pushed and a pull
+request (GitHub) or merge request (GitLab) is opened into the base branch,
+with a description built from .jikime/specs/<SPEC-ID>/ and the @SPEC tags
+in the changed files. The worktree is kept until the PR is merged; then
+'jikime worktree clean --pr-merged' removes it. The forge is detected from
+the origin remote (override with JIKIME_FORGE, JIKIME_FORGE_API) and needs
+GITHUB_TOKEN/GH_TOKEN or GITLAB_TOKEN.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			specID := args[0]
@@ -40,7 +57,34 @@ This command performs the full completion workflow:
 				return fmt.Errorf("worktree not found: %s", specID)
 			}
 
+			if pr {
+				f, err := getForge(manager)
+				if err != nil {
+					color.Red("✗ %v", err)
+					return err
+				}
+				color.Cyan("Opening %s pull request for %s (%s → %s)", f.Name(), specID, info.Branch, base)
+				ctx, cancel := forgeContext()
+				defer cancel()
+				result, err := manager.DonePR(ctx, specID, base, f, draft)
+				if err != nil {
+					color.Red("✗ %v", err)
+					return err
+				}
+				if result.PRExisted {
+					color.Yellow("✓ Pushed; pull request #%d was already open", result.PullRequest.Number)
+				} else {
+					color.Green("✓ Pull request #%d opened", result.PullRequest.Number)
+				}
+				fmt.Printf("  %s\n", result.PullRequest.URL)
+				fmt.Printf("  Worktree kept until merged: jikime worktree clean --pr-merged\n")
+				return nil
+			}
+
 			color.Cyan("Completing worktree: %s", specID)
+			if info.Parent != "" {
+				color.Yellow("  %s is stacked on %s; merging into %s includes its commits", specID, info.Parent, base)
+			}
 			fmt.Printf("  Branch: %s\n", info.Branch)
 			fmt.Printf("  Merging into: %s\n", base)
 			fmt.Println()
@@ -60,6 +104,16 @@ This command performs the full completion workflow:
 			color.Yellow("Branch cleanup:")
 			fmt.Printf("  - Worktree removed: %s\n", specID)
 			fmt.Printf("  - Branch deleted: %s\n", result.MergedBranch)
+			if len(result.Restacked) > 0 {
+				fmt.Println()
+				color.Yellow("Stacked worktrees:")
+				printRestacked(result.Restacked)
+			}
+			if len(result.Hooks) > 0 {
+				fmt.Println()
+				color.Yellow("post_done:")
+				printStepResults(result.Hooks, worktree.HookPostDone)
+			}
 
 			return nil
 		},
@@ -68,6 +122,8 @@ This command performs the full completion workflow:
 	cmd.Flags().StringVar(&base, "base", "main", "Base branch to merge into")
 	cmd.Flags().BoolVar(&push, "push", false, "Push to remote after merge")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force remove with uncommitted changes")
+	cmd.Flags().BoolVar(&pr, "pr", false, "Push and open a pull request instead of merging locally")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Open the pull request as a draft (with --pr)")
 
 	return cmd
 }
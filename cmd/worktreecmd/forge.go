@@ -0,0 +1,52 @@
+package worktreecmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"jikime-adk/internal/forge"
+	"jikime-adk/internal/worktree"
+)
+
+// forgeTimeout bounds forge API calls made by a single command.
+const forgeTimeout = 30 * time.Second
+
+// getForge picks the forge for the repository's origin remote.
+func getForge(manager *worktree.Manager) (forge.Forge, error) {
+	out, err := exec.Command("git", "-C", manager.RepoPath, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return nil, fmt.Errorf("no origin remote: %w", err)
+	}
+	return forge.FromRemote(strings.TrimSpace(string(out)))
+}
+
+func forgeContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), forgeTimeout)
+}
+
+// prSummary renders a pull request for tables, e.g. "#12 open".
+func prSummary(pr *forge.PullRequest) (string, string) {
+	if pr == nil {
+		return "-", "-"
+	}
+	state := string(pr.State)
+	if pr.Draft && pr.State == forge.PRStateOpen {
+		state = "draft"
+	}
+	return fmt.Sprintf("#%d %s", pr.Number, state), ciIcon(pr.CI) + " " + string(pr.CI)
+}
+
+func ciIcon(ci forge.CIState) string {
+	switch ci {
+	case forge.CIStateSuccess:
+		return "✓"
+	case forge.CIStateFailure:
+		return "✗"
+	case forge.CIStatePending:
+		return "…"
+	}
+	return "-"
+}
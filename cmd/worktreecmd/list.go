@@ -8,15 +8,23 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
 )
 
 func newListCmd() *cobra.Command {
-	var format string
+	var (
+		format  string
+		offline bool
+	)
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all active worktrees",
+		Long: `List all active worktrees.
+
+Stacked worktrees (created with 'new --parent') are listed under their
+parent as a tree.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager, err := getManager()
 			if err != nil {
@@ -29,6 +37,23 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
+			// Refresh PR/CI state from the forge; fall back to the last known state.
+			if !offline && hasPullRequests(worktrees) {
+				if f, err := getForge(manager); err != nil {
+					color.Yellow("PR status not refreshed: %v", err)
+				} else {
+					ctx, cancel := forgeContext()
+					refreshed, err := manager.RefreshPullRequests(ctx, f)
+					cancel()
+					if err != nil {
+						color.Yellow("PR status not refreshed: %v", err)
+					}
+					if refreshed != nil {
+						worktrees = refreshed
+					}
+				}
+			}
+
 			if format == "json" {
 				var data []map[string]any
 				for _, wt := range worktrees {
@@ -38,14 +63,18 @@ func newListCmd() *cobra.Command {
 				fmt.Println(string(output))
 			} else {
 				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-				fmt.Fprintln(w, "SPEC ID\tBRANCH\tPATH\tSTATUS\tCREATED")
-				fmt.Fprintln(w, "-------\t------\t----\t------\t-------")
-				for _, info := range worktrees {
-					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-						info.SpecID,
+				fmt.Fprintln(w, "SPEC ID\tBRANCH\tPATH\tSTATUS\tPR\tCI\tCREATED")
+				fmt.Fprintln(w, "-------\t------\t----\t------\t--\t--\t-------")
+				for _, row := range stackRows(worktrees) {
+					info := row.info
+					pr, ci := prSummary(info.PullRequest)
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						row.prefix+info.SpecID,
 						info.Branch,
 						info.Path,
 						info.Status,
+						pr,
+						ci,
 						info.CreatedAt.Format("2006-01-02 15:04:05"),
 					)
 				}
@@ -57,6 +86,44 @@ func newListCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&format, "format", "table", "Output format (table, json)")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Show the last known PR/CI status without querying the forge")
 
 	return cmd
 }
+
+func hasPullRequests(worktrees []*worktree.WorktreeInfo) bool {
+	for _, info := range worktrees {
+		if info.PullRequest != nil {
+			return true
+		}
+	}
+	return false
+}
+
+type stackRow struct {
+	prefix string
+	info   *worktree.WorktreeInfo
+}
+
+// stackRows orders worktrees as a tree of stacks, with box-drawing
+// prefixes for stacked worktrees.
+func stackRows(worktrees []*worktree.WorktreeInfo) []stackRow {
+	roots, children := worktree.StackForest(worktrees)
+	var rows []stackRow
+	var walk func(info *worktree.WorktreeInfo, prefix, indent string)
+	walk = func(info *worktree.WorktreeInfo, prefix, indent string) {
+		rows = append(rows, stackRow{prefix: prefix, info: info})
+		kids := children[info.SpecID]
+		for i, child := range kids {
+			if i == len(kids)-1 {
+				walk(child, indent+"└─ ", indent+"   ")
+			} else {
+				walk(child, indent+"├─ ", indent+"│  ")
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+	return rows
+}
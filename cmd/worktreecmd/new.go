@@ -7,6 +7,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
 )
 
 func newNewCmd() *cobra.Command {
@@ -15,6 +16,7 @@ func newNewCmd() *cobra.Command {
 		base      string
 		force     bool
 		llmConfig string
+		parent    string
 	)
 
 	cmd := &cobra.Command{
@@ -23,7 +25,15 @@ func newNewCmd() *cobra.Command {
 		Long: `Create a new worktree for a SPEC.
 
 Creates a new Git worktree with an isolated branch for the specified SPEC ID.
-The worktree will be created in the worktree root directory under the project namespace.`,
+The worktree will be created in the worktree root directory under the project namespace.
+
+If the project has a worktree.yaml, its post_create steps run afterwards:
+copying or symlinking ignored files (.env, node_modules), install commands,
+and port allocation written to .env.local.
+
+With --parent, the worktree is stacked on another worktree: its branch
+starts from the parent's branch, 'sync' restacks it when the parent
+changes, and 'done' on the parent moves it onto the base branch.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			specID := args[0]
@@ -43,7 +53,12 @@ The worktree will be created in the worktree root directory under the project na
 				}
 			}
 
-			info, err := manager.Create(specID, branch, base, force, llmConfigPath)
+			var info *worktree.WorktreeInfo
+			if parent != "" {
+				info, err = manager.CreateStacked(specID, branch, parent, force, llmConfigPath)
+			} else {
+				info, err = manager.Create(specID, branch, base, force, llmConfigPath)
+			}
 			if err != nil {
 				color.Red("✗ %v", err)
 				return err
@@ -54,9 +69,23 @@ The worktree will be created in the worktree root directory under the project na
 			fmt.Printf("  Path:       %s\n", info.Path)
 			fmt.Printf("  Branch:     %s\n", info.Branch)
 			fmt.Printf("  Status:     %s\n", info.Status)
+			if info.Parent != "" {
+				fmt.Printf("  Parent:     %s\n", info.Parent)
+			}
 			if llmConfigPath != "" {
 				fmt.Printf("  LLM Config: %s\n", filepath.Base(llmConfigPath))
 			}
+			if info.Ports != nil {
+				fmt.Printf("  Ports:      %d-%d\n", info.Ports.Start, info.Ports.End())
+			}
+			if health := info.BootstrapHealth(); health != worktree.HealthNone {
+				fmt.Println()
+				healthColor(health).Printf("Bootstrap: %s\n", health)
+				printStepResults(info.Hooks, worktree.HookPostCreate)
+				if health == worktree.HealthFailed {
+					fmt.Printf("  Fix the step, then re-run: jikime-wt bootstrap %s\n", specID)
+				}
+			}
 			fmt.Println()
 			color.Yellow("Next steps:")
 			fmt.Printf("  jikime-wt go %s       # Go to this worktree\n", specID)
@@ -69,6 +98,8 @@ The worktree will be created in the worktree root directory under the project na
 	cmd.Flags().StringVar(&base, "base", "main", "Base branch to create from")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force creation even if worktree exists")
 	cmd.Flags().StringVar(&llmConfig, "llm-config", "", "Path to custom LLM config file")
+	cmd.Flags().StringVar(&parent, "parent", "", "Stack on another worktree (SPEC ID) instead of --base")
+	cmd.MarkFlagsMutuallyExclusive("parent", "base")
 
 	return cmd
 }
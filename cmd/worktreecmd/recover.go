@@ -15,7 +15,8 @@ func newRecoverCmd() *cobra.Command {
 
 Scans the worktree root directory for existing worktrees and
 re-registers them in the registry file. Useful when the registry
-is lost or corrupted.`,
+is lost or corrupted. See also 'jikime worktree doctor', which checks
+the registry against git itself.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager, err := getManager()
 			if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"jikime-adk/internal/worktree"
 )
 
 func newStatusCmd() *cobra.Command {
@@ -39,6 +40,20 @@ func newStatusCmd() *cobra.Command {
 				fmt.Printf("  Branch: %s\n", info.Branch)
 				fmt.Printf("  Path:   %s\n", info.Path)
 				fmt.Printf("  Status: %s\n", info.Status)
+				if info.Parent != "" {
+					fmt.Printf("  Parent: %s\n", info.Parent)
+				}
+				if info.Ports != nil {
+					fmt.Printf("  Ports:  %d-%d\n", info.Ports.Start, info.Ports.End())
+				}
+				if health := info.BootstrapHealth(); health != worktree.HealthNone {
+					fmt.Print("  Bootstrap: ")
+					healthColor(health).Println(health)
+					if health != worktree.HealthHealthy {
+						printStepResults(info.Hooks, worktree.HookPostCreate)
+					}
+				}
+				printStepResults(info.Hooks, worktree.HookPreRemove)
 				fmt.Println()
 			}
 
@@ -24,7 +24,18 @@ func newSyncCmd() *cobra.Command {
 		Long: `Sync worktree with base branch.
 
 Fetches latest changes from the base branch and merges them into
-the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
+the worktree. Supports merge, rebase, squash, and fast-forward strategies.
+
+With --auto-resolve, conflicts are resolved by git rerere, a diff3 merge
+of each hunk, and file-aware strategies: JSON/YAML are merged key by key,
+import blocks are unioned, and lockfiles are regenerated by their package
+manager. If anything is left unresolved the merge is aborted and each
+remaining hunk is reported.
+
+Stacked worktrees (created with 'new --parent') sync from their parent
+instead of the base branch: their own commits are rebased onto the
+parent's branch. After a worktree syncs, the worktrees stacked on it are
+restacked the same way.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !syncAll {
 				color.Red("✗ Either SPEC_ID or --all option is required")
@@ -53,11 +64,13 @@ the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
 				successCount := 0
 				conflictCount := 0
 
-				for _, info := range worktrees {
-					err := manager.Sync(info.SpecID, base, rebase, ffOnly, squash, autoResolve)
+				// Parents first, so each stack is restacked onto fresh parents.
+				for _, info := range worktree.StackOrder(worktrees) {
+					report, err := manager.SyncWithReport(info.SpecID, base, rebase, ffOnly, squash, autoResolve)
 					if err != nil {
-						if _, ok := err.(*worktree.MergeConflictError); ok {
+						if conflictErr, ok := err.(*worktree.MergeConflictError); ok {
 							color.Red("✗ %s (conflicts)", info.SpecID)
+							printUnresolved(conflictErr)
 							conflictCount++
 						} else {
 							color.Red("✗ %s (failed: %v)", info.SpecID, err)
@@ -72,7 +85,11 @@ the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
 						} else if squash {
 							method = "squash"
 						}
+						if info.Parent != "" {
+							method = "restacked on " + info.Parent
+						}
 						color.Green("✓ %s (%s)", info.SpecID, method)
+						printResolved(report)
 						successCount++
 					}
 				}
@@ -81,7 +98,14 @@ the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
 				color.Green("Summary: %d synced, %d failed", successCount, conflictCount)
 			} else {
 				specID := args[0]
-				if err := manager.Sync(specID, base, rebase, ffOnly, squash, autoResolve); err != nil {
+				report, err := manager.SyncWithReport(specID, base, rebase, ffOnly, squash, autoResolve)
+				if err != nil {
+					if conflictErr, ok := err.(*worktree.MergeConflictError); ok && conflictErr.Unresolved != nil {
+						color.Red("✗ Could not auto-resolve conflicts in %s; merge aborted", specID)
+						printResolved(report)
+						printUnresolved(conflictErr)
+						return err
+					}
 					color.Red("✗ %v", err)
 					return err
 				}
@@ -94,7 +118,19 @@ the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
 				} else if squash {
 					method = "squash"
 				}
+				if info := manager.Registry.Get(specID, manager.ProjectName); info != nil && info.Parent != "" {
+					method = "restacked on " + info.Parent
+				}
 				color.Green("✓ Worktree synced: %s (%s)", specID, method)
+				printResolved(report)
+
+				if restacked := manager.RestackDescendants(specID, autoResolve); len(restacked) > 0 {
+					fmt.Println()
+					color.Yellow("Stacked worktrees:")
+					if printRestacked(restacked) > 0 {
+						return fmt.Errorf("some stacked worktrees could not be restacked")
+					}
+				}
 			}
 
 			return nil
@@ -106,7 +142,73 @@ the worktree. Supports merge, rebase, squash, and fast-forward strategies.`,
 	cmd.Flags().BoolVar(&ffOnly, "ff-only", false, "Only sync if fast-forward is possible")
 	cmd.Flags().BoolVar(&squash, "squash", false, "Squash all commits into a single commit")
 	cmd.Flags().BoolVar(&syncAll, "all", false, "Sync all worktrees")
-	cmd.Flags().BoolVar(&autoResolve, "auto-resolve", false, "Automatically resolve conflicts")
+	cmd.Flags().BoolVar(&autoResolve, "auto-resolve", false, "Automatically resolve conflicts (rerere, diff3, structural merges)")
 
 	return cmd
 }
+
+// printResolved lists files that were resolved automatically.
+func printResolved(report *worktree.ResolveReport) {
+	if report == nil {
+		return
+	}
+	for _, r := range report.Resolved {
+		line := fmt.Sprintf("  ✓ %s (%s)", r.Path, r.Strategy)
+		if r.Detail != "" {
+			line += ": " + r.Detail
+		}
+		color.Green(line)
+	}
+}
+
+// printUnresolved reports each file and hunk that needs manual resolution.
+func printUnresolved(err *worktree.MergeConflictError) {
+	if err.Unresolved == nil {
+		for _, f := range err.ConflictedFiles {
+			color.Yellow("  ✗ %s", f)
+		}
+		return
+	}
+	for _, r := range err.Unresolved {
+		color.Yellow("  ✗ %s: %s", r.Path, r.Detail)
+		for _, h := range r.Hunks {
+			fmt.Printf("      lines %d-%d: ours %d line(s), base %d, theirs %d\n",
+				h.StartLine, h.EndLine, len(h.Ours), len(h.Base), len(h.Theirs))
+			for _, l := range firstLines(h.Ours, 2) {
+				fmt.Printf("        < %s\n", l)
+			}
+			for _, l := range firstLines(h.Theirs, 2) {
+				fmt.Printf("        > %s\n", l)
+			}
+		}
+	}
+}
+
+func firstLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return append(lines[:n:n], "...")
+	}
+	return lines
+}
+
+// printRestacked reports stacked worktrees moved onto a new base and
+// returns how many failed.
+func printRestacked(results []worktree.RestackResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Err == nil {
+			color.Green("  ✓ %s restacked onto %s", r.SpecID, r.Onto)
+			printResolved(r.Report)
+			continue
+		}
+		failed++
+		color.Red("  ✗ %s not restacked onto %s: %v", r.SpecID, r.Onto, r.Err)
+		if conflictErr, ok := r.Err.(*worktree.MergeConflictError); ok {
+			printUnresolved(conflictErr)
+		}
+		if r.Command != "" {
+			fmt.Printf("    finish with: %s\n", r.Command)
+		}
+	}
+	return failed
+}
@@ -44,6 +44,8 @@ stashing or switching branches.`,
 	cmd.AddCommand(newRecoverCmd())
 	cmd.AddCommand(newDoneCmd())
 	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newBootstrapCmd())
+	cmd.AddCommand(newDoctorCmd())
 
 	return cmd
 }
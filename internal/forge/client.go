@@ -0,0 +1,110 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// client is the JSON-over-HTTP transport shared by the REST forges.
+type client struct {
+	forge   string
+	baseURL string
+	headers map[string]string
+	http    *http.Client
+}
+
+func newClient(forge, baseURL string, headers map[string]string) *client {
+	return &client{
+		forge:   forge,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		headers: headers,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request with an optional JSON body and decodes a JSON reply
+// into out (if non-nil).
+func (c *client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("forge/%s: encode request: %w", c.forge, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("forge/%s: %w", c.forge, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("forge/%s: %s %s: %w", c.forge, method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("forge/%s: read response: %w", c.forge, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return &APIError{Forge: c.forge, Status: resp.StatusCode, Message: apiMessage(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("forge/%s: decode response: %w", c.forge, err)
+	}
+	return nil
+}
+
+// apiMessage extracts the "message" field both APIs use for errors.
+func apiMessage(data []byte) string {
+	var body struct {
+		Message any `json:"message"`
+		Error   any `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		for _, v := range []any{body.Message, body.Error} {
+			if v != nil {
+				if s, ok := v.(string); ok {
+					return s
+				}
+				b, _ := json.Marshal(v)
+				return string(b)
+			}
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// combineCI folds individual check states into one, worst first.
+func combineCI(states []CIState) CIState {
+	if len(states) == 0 {
+		return CIStateNone
+	}
+	result := CIStateSuccess
+	for _, s := range states {
+		switch s {
+		case CIStateFailure:
+			return CIStateFailure
+		case CIStatePending:
+			result = CIStatePending
+		}
+	}
+	return result
+}
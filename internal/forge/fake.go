@@ -0,0 +1,104 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory Forge for tests. Pull requests start open with CI
+// pending; tests move them along with SetState and SetCI.
+type Fake struct {
+	mu     sync.Mutex
+	pulls  map[int]*PullRequest
+	bodies map[int]string
+	next   int
+}
+
+// NewFake creates an empty fake forge.
+func NewFake() *Fake {
+	return &Fake{pulls: map[int]*PullRequest{}, bodies: map[int]string{}, next: 1}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+// CreatePullRequest records a new open pull request.
+func (f *Fake) CreatePullRequest(ctx context.Context, opts CreateOptions) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pr := range f.pulls {
+		if pr.Head == opts.Head && pr.State == PRStateOpen {
+			return nil, &APIError{Forge: "fake", Status: 422, Message: "a pull request already exists for " + opts.Head}
+		}
+	}
+	n := f.next
+	f.next++
+	pr := &PullRequest{
+		Forge:     "fake",
+		Number:    n,
+		URL:       fmt.Sprintf("https://forge.test/pulls/%d", n),
+		Title:     opts.Title,
+		Head:      opts.Head,
+		Base:      opts.Base,
+		State:     PRStateOpen,
+		Draft:     opts.Draft,
+		CI:        CIStatePending,
+		UpdatedAt: time.Now(),
+	}
+	f.pulls[n] = pr
+	f.bodies[n] = opts.Body
+	cp := *pr
+	return &cp, nil
+}
+
+// GetPullRequest returns a copy of a recorded pull request.
+func (f *Fake) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pr, ok := f.pulls[number]
+	if !ok {
+		return nil, &APIError{Forge: "fake", Status: 404, Message: "Not Found"}
+	}
+	cp := *pr
+	cp.UpdatedAt = time.Now()
+	return &cp, nil
+}
+
+// FindPullRequest returns the open pull request for head, if any.
+func (f *Fake) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pr := range f.pulls {
+		if pr.Head == head && pr.State == PRStateOpen {
+			cp := *pr
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetState changes a pull request's state.
+func (f *Fake) SetState(number int, state PRState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if pr, ok := f.pulls[number]; ok {
+		pr.State = state
+	}
+}
+
+// SetCI changes a pull request's CI state.
+func (f *Fake) SetCI(number int, ci CIState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if pr, ok := f.pulls[number]; ok {
+		pr.CI = ci
+	}
+}
+
+// Body returns the description a pull request was opened with.
+func (f *Fake) Body(number int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bodies[number]
+}
@@ -0,0 +1,157 @@
+// Package forge abstracts code-hosting services (GitHub, GitLab) for
+// opening pull requests and reading their review and CI state.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// PRState is the lifecycle state of a pull request.
+type PRState string
+
+const (
+	PRStateOpen   PRState = "open"
+	PRStateMerged PRState = "merged"
+	PRStateClosed PRState = "closed"
+)
+
+// CIState summarizes the checks on a pull request's head commit.
+type CIState string
+
+const (
+	CIStateNone    CIState = "none"
+	CIStatePending CIState = "pending"
+	CIStateSuccess CIState = "success"
+	CIStateFailure CIState = "failure"
+)
+
+// PullRequest is a pull request (GitHub) or merge request (GitLab).
+type PullRequest struct {
+	Forge     string    `json:"forge"`
+	Number    int       `json:"number"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Head      string    `json:"head"`
+	Base      string    `json:"base"`
+	State     PRState   `json:"state"`
+	Draft     bool      `json:"draft,omitempty"`
+	CI        CIState   `json:"ci"`
+	UpdatedAt time.Time `json:"updated_at"` // when this state was fetched
+}
+
+// CreateOptions describes a pull request to open.
+type CreateOptions struct {
+	Head  string
+	Base  string
+	Title string
+	Body  string
+	Draft bool
+}
+
+// Forge is a code-hosting service.
+type Forge interface {
+	// Name identifies the forge ("github", "gitlab", "fake").
+	Name() string
+	// CreatePullRequest opens a pull request from Head into Base.
+	CreatePullRequest(ctx context.Context, opts CreateOptions) (*PullRequest, error)
+	// GetPullRequest returns a pull request with its current CI state.
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+	// FindPullRequest returns the open pull request for a head branch, or
+	// nil if there is none.
+	FindPullRequest(ctx context.Context, head string) (*PullRequest, error)
+}
+
+// ErrNoToken is returned when the forge's API token is not set.
+var ErrNoToken = errors.New("forge: API token not set")
+
+// APIError is a non-2xx response from a forge API.
+type APIError struct {
+	Forge   string
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("forge/%s: HTTP %d: %s", e.Forge, e.Status, e.Message)
+}
+
+// Remote is a parsed git remote URL.
+type Remote struct {
+	Host string // e.g. github.com
+	Path string // owner/repo or group/subgroup/repo, without .git
+}
+
+// ParseRemote parses scp-style (git@host:owner/repo.git), ssh:// and
+// http(s):// remote URLs.
+func ParseRemote(raw string) (*Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("forge: parse remote %q: %w", raw, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(raw, "@"); at >= 0 && strings.Contains(raw[at:], ":") {
+		hostPath := raw[at+1:]
+		i := strings.Index(hostPath, ":")
+		host, path = hostPath[:i], hostPath[i+1:]
+	} else {
+		return nil, fmt.Errorf("forge: unsupported remote URL %q", raw)
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("forge: cannot find owner/repo in %q", raw)
+	}
+	return &Remote{Host: host, Path: path}, nil
+}
+
+// FromRemote picks a forge for a git remote URL. The kind comes from
+// JIKIME_FORGE (github, gitlab) or the host name; the API base URL from
+// JIKIME_FORGE_API, defaulting to the public or self-hosted convention.
+// Tokens are read from GITHUB_TOKEN/GH_TOKEN or GITLAB_TOKEN.
+func FromRemote(remoteURL string) (Forge, error) {
+	remote, err := ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	kind := os.Getenv("JIKIME_FORGE")
+	if kind == "" {
+		switch {
+		case strings.Contains(remote.Host, "gitlab"):
+			kind = "gitlab"
+		case strings.Contains(remote.Host, "github"):
+			kind = "github"
+		default:
+			return nil, fmt.Errorf("forge: cannot tell the forge for host %s (set JIKIME_FORGE=github|gitlab)", remote.Host)
+		}
+	}
+	api := os.Getenv("JIKIME_FORGE_API")
+
+	switch kind {
+	case "github":
+		if api == "" {
+			api = "https://api.github.com"
+			if remote.Host != "github.com" {
+				api = "https://" + remote.Host + "/api/v3"
+			}
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		return NewGitHub(api, remote.Path, token)
+	case "gitlab":
+		if api == "" {
+			api = "https://" + remote.Host + "/api/v4"
+		}
+		return NewGitLab(api, remote.Path, os.Getenv("GITLAB_TOKEN"))
+	}
+	return nil, fmt.Errorf("forge: unknown JIKIME_FORGE %q", kind)
+}
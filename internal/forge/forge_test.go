@@ -0,0 +1,120 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		raw, host, path string
+	}{
+		{"git@github.com:acme/shop.git", "github.com", "acme/shop"},
+		{"https://github.com/acme/shop.git", "github.com", "acme/shop"},
+		{"https://token@gitlab.example.com/group/sub/app", "gitlab.example.com", "group/sub/app"},
+		{"ssh://git@gitlab.com:2222/group/app.git", "gitlab.com", "group/app"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRemote(tt.raw)
+		if err != nil {
+			t.Fatalf("ParseRemote(%q): %v", tt.raw, err)
+		}
+		if r.Host != tt.host || r.Path != tt.path {
+			t.Errorf("ParseRemote(%q) = %s %s, want %s %s", tt.raw, r.Host, r.Path, tt.host, tt.path)
+		}
+	}
+	for _, raw := range []string{"/srv/git/repo.git", "https://github.com/onlyowner"} {
+		if _, err := ParseRemote(raw); err == nil {
+			t.Errorf("ParseRemote(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestFromRemoteNeedsToken(t *testing.T) {
+	t.Setenv("JIKIME_FORGE", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	if _, err := FromRemote("git@github.com:acme/shop.git"); !errors.Is(err, ErrNoToken) {
+		t.Fatalf("err = %v, want ErrNoToken", err)
+	}
+	if _, err := FromRemote("git@git.internal:acme/shop.git"); err == nil {
+		t.Fatal("unknown host should need JIKIME_FORGE")
+	}
+}
+
+func serveJSON(t *testing.T, routes map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply, ok := routes[r.Method+" "+r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitHubPullRequest(t *testing.T) {
+	srv := serveJSON(t, map[string]any{
+		"GET /repos/acme/shop/pulls/7": map[string]any{
+			"number": 7, "html_url": "https://github.com/acme/shop/pull/7", "state": "open",
+			"head": map[string]any{"ref": "feature/SPEC-A-001", "sha": "abc"},
+			"base": map[string]any{"ref": "main"},
+		},
+		"GET /repos/acme/shop/commits/abc/status": map[string]any{"state": "success", "total_count": 1},
+		"GET /repos/acme/shop/commits/abc/check-runs": map[string]any{"check_runs": []any{
+			map[string]any{"status": "completed", "conclusion": "success"},
+			map[string]any{"status": "in_progress"},
+		}},
+		"GET /repos/acme/shop/pulls?head=acme%3Afeature%2FSPEC-A-001&state=open": []any{},
+	})
+	gh, err := NewGitHub(srv.URL, "acme/shop", "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	pr, err := gh.GetPullRequest(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.State != PRStateOpen || pr.CI != CIStatePending || pr.Head != "feature/SPEC-A-001" {
+		t.Errorf("pr = %+v", pr)
+	}
+	if found, err := gh.FindPullRequest(ctx, "feature/SPEC-A-001"); err != nil || found != nil {
+		t.Errorf("FindPullRequest = %v, %v; want nil, nil", found, err)
+	}
+
+	var apiErr *APIError
+	if _, err := gh.GetPullRequest(ctx, 8); !errors.As(err, &apiErr) || apiErr.Status != 404 {
+		t.Errorf("missing PR err = %v", err)
+	}
+}
+
+func TestGitLabMergeRequest(t *testing.T) {
+	srv := serveJSON(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests/3": map[string]any{
+			"iid": 3, "web_url": "https://gitlab.com/group/app/-/merge_requests/3", "state": "merged",
+			"source_branch": "feature/SPEC-A-001", "target_branch": "main",
+			"head_pipeline": map[string]any{"status": "failed"},
+		},
+	})
+	gl, err := NewGitLab(srv.URL, "group/app", "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := gl.GetPullRequest(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.State != PRStateMerged || pr.CI != CIStateFailure || pr.Number != 3 {
+		t.Errorf("pr = %+v", pr)
+	}
+}
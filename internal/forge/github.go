@@ -0,0 +1,164 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHub implements Forge with the GitHub REST API (v3).
+type GitHub struct {
+	repo   string // owner/repo
+	client *client
+}
+
+// NewGitHub creates a GitHub forge for owner/repo. apiURL is
+// https://api.github.com or https://<host>/api/v3 for GitHub Enterprise.
+func NewGitHub(apiURL, repo, token string) (*GitHub, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: set GITHUB_TOKEN or GH_TOKEN", ErrNoToken)
+	}
+	return &GitHub{
+		repo: repo,
+		client: newClient("github", apiURL, map[string]string{
+			"Authorization":        "Bearer " + token,
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		}),
+	}, nil
+}
+
+func (g *GitHub) Name() string { return "github" }
+
+type githubPull struct {
+	Number   int    `json:"number"`
+	HTMLURL  string `json:"html_url"`
+	Title    string `json:"title"`
+	State    string `json:"state"` // open, closed
+	Merged   bool   `json:"merged"`
+	MergedAt string `json:"merged_at"`
+	Draft    bool   `json:"draft"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *githubPull) toPullRequest() *PullRequest {
+	state := PRStateOpen
+	switch {
+	case p.Merged || p.MergedAt != "":
+		state = PRStateMerged
+	case p.State == "closed":
+		state = PRStateClosed
+	}
+	return &PullRequest{
+		Forge:     "github",
+		Number:    p.Number,
+		URL:       p.HTMLURL,
+		Title:     p.Title,
+		Head:      p.Head.Ref,
+		Base:      p.Base.Ref,
+		State:     state,
+		Draft:     p.Draft,
+		CI:        CIStateNone,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// CreatePullRequest opens a pull request.
+func (g *GitHub) CreatePullRequest(ctx context.Context, opts CreateOptions) (*PullRequest, error) {
+	var pull githubPull
+	body := map[string]any{
+		"title": opts.Title,
+		"head":  opts.Head,
+		"base":  opts.Base,
+		"body":  opts.Body,
+		"draft": opts.Draft,
+	}
+	if err := g.client.do(ctx, http.MethodPost, "/repos/"+g.repo+"/pulls", body, &pull); err != nil {
+		return nil, err
+	}
+	return pull.toPullRequest(), nil
+}
+
+// GetPullRequest fetches a pull request and the CI state of its head.
+func (g *GitHub) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var pull githubPull
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), nil, &pull); err != nil {
+		return nil, err
+	}
+	pr := pull.toPullRequest()
+	ci, err := g.ciState(ctx, pull.Head.SHA)
+	if err != nil {
+		return nil, err
+	}
+	pr.CI = ci
+	return pr, nil
+}
+
+// FindPullRequest returns the open pull request for a branch.
+func (g *GitHub) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(g.repo, "/")
+	q := url.Values{"head": {owner + ":" + head}, "state": {"open"}}
+	var pulls []githubPull
+	if err := g.client.do(ctx, http.MethodGet, "/repos/"+g.repo+"/pulls?"+q.Encode(), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return pulls[0].toPullRequest(), nil
+}
+
+// ciState combines commit statuses and check runs for a commit.
+func (g *GitHub) ciState(ctx context.Context, sha string) (CIState, error) {
+	if sha == "" {
+		return CIStateNone, nil
+	}
+	var status struct {
+		State      string `json:"state"` // pending, success, failure, error
+		TotalCount int    `json:"total_count"`
+	}
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/status", g.repo, sha), nil, &status); err != nil {
+		return "", err
+	}
+	var checks struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`     // queued, in_progress, completed
+			Conclusion string `json:"conclusion"` // success, failure, neutral, cancelled, skipped, timed_out, action_required
+		} `json:"check_runs"`
+	}
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/check-runs", g.repo, sha), nil, &checks); err != nil {
+		return "", err
+	}
+
+	var states []CIState
+	if status.TotalCount > 0 {
+		switch status.State {
+		case "success":
+			states = append(states, CIStateSuccess)
+		case "pending":
+			states = append(states, CIStatePending)
+		default:
+			states = append(states, CIStateFailure)
+		}
+	}
+	for _, run := range checks.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			states = append(states, CIStatePending)
+		case run.Conclusion == "success" || run.Conclusion == "neutral" || run.Conclusion == "skipped":
+			states = append(states, CIStateSuccess)
+		default:
+			states = append(states, CIStateFailure)
+		}
+	}
+	return combineCI(states), nil
+}
@@ -0,0 +1,118 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GitLab implements Forge with the GitLab REST API (v4), using merge
+// requests as pull requests.
+type GitLab struct {
+	project string // URL-encoded group/subgroup/repo
+	client  *client
+}
+
+// NewGitLab creates a GitLab forge for a project path. apiURL is
+// https://<host>/api/v4.
+func NewGitLab(apiURL, projectPath, token string) (*GitLab, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: set GITLAB_TOKEN", ErrNoToken)
+	}
+	return &GitLab{
+		project: url.PathEscape(projectPath),
+		client:  newClient("gitlab", apiURL, map[string]string{"PRIVATE-TOKEN": token}),
+	}, nil
+}
+
+func (g *GitLab) Name() string { return "gitlab" }
+
+type gitlabMR struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	Title        string `json:"title"`
+	State        string `json:"state"` // opened, closed, locked, merged
+	Draft        bool   `json:"draft"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+func (m *gitlabMR) toPullRequest() *PullRequest {
+	state := PRStateOpen
+	switch m.State {
+	case "merged":
+		state = PRStateMerged
+	case "closed":
+		state = PRStateClosed
+	}
+	ci := CIStateNone
+	if m.HeadPipeline != nil {
+		switch m.HeadPipeline.Status {
+		case "success", "skipped":
+			ci = CIStateSuccess
+		case "failed", "canceled":
+			ci = CIStateFailure
+		default: // created, pending, running, manual, scheduled, ...
+			ci = CIStatePending
+		}
+	}
+	return &PullRequest{
+		Forge:     "gitlab",
+		Number:    m.IID,
+		URL:       m.WebURL,
+		Title:     m.Title,
+		Head:      m.SourceBranch,
+		Base:      m.TargetBranch,
+		State:     state,
+		Draft:     m.Draft,
+		CI:        ci,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// CreatePullRequest opens a merge request.
+func (g *GitLab) CreatePullRequest(ctx context.Context, opts CreateOptions) (*PullRequest, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+	body := map[string]any{
+		"source_branch":        opts.Head,
+		"target_branch":        opts.Base,
+		"title":                title,
+		"description":          opts.Body,
+		"remove_source_branch": true,
+	}
+	var mr gitlabMR
+	if err := g.client.do(ctx, http.MethodPost, "/projects/"+g.project+"/merge_requests", body, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPullRequest(), nil
+}
+
+// GetPullRequest fetches a merge request with its head pipeline status.
+func (g *GitLab) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var mr gitlabMR
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, number), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPullRequest(), nil
+}
+
+// FindPullRequest returns the open merge request for a source branch.
+func (g *GitLab) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	q := url.Values{"source_branch": {head}, "state": {"opened"}}
+	var mrs []gitlabMR
+	if err := g.client.do(ctx, http.MethodGet, "/projects/"+g.project+"/merge_requests?"+q.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].toPullRequest(), nil
+}
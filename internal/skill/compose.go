@@ -0,0 +1,261 @@
+// Package skill provides skill composition: requires, extends and conflicts.
+package skill
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Composition fields, as named in SKILL.md front-matter.
+const (
+	FieldRequires  = "requires"
+	FieldExtends   = "extends"
+	FieldConflicts = "conflicts"
+)
+
+// NameList is a list of skill or tool names. In YAML it is a sequence or
+// a single comma-separated string ("requires: react-19, typescript-strict").
+type NameList []string
+
+// UnmarshalYAML accepts a sequence or a comma-separated scalar.
+func (l *NameList) UnmarshalYAML(node *yaml.Node) error {
+	var items []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+	case yaml.ScalarNode:
+		items = strings.Split(node.Value, ",")
+	default:
+		return fmt.Errorf("line %d: expected a skill name or a list of names", node.Line)
+	}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// DependencyError is an invalid composition entry: a requires, extends or
+// conflicts target that does not exist or refers back to the skill, or a
+// requires/extends cycle (Cycle lists its skills, smallest name first).
+type DependencyError struct {
+	Skill   string
+	Field   string
+	Target  string
+	Cycle   []string
+	Message string
+}
+
+func (e DependencyError) Error() string {
+	return e.Skill + ": " + e.Message
+}
+
+// dependencies returns the skills s builds on: extends first, then requires.
+func dependencies(s *Skill) []string {
+	return append(append([]string{}, s.Extends...), s.Requires...)
+}
+
+// ValidateDependencies checks every skill's requires, extends and
+// conflicts entries, reporting missing targets, self-references, skills
+// that require what they conflict with, and requires/extends cycles.
+func (r *Registry) ValidateDependencies() []DependencyError {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var errs []DependencyError
+	names := make([]string, 0, len(r.skills))
+	for name := range r.skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := r.skills[name]
+		for _, field := range []struct {
+			name    string
+			targets []string
+		}{{FieldRequires, s.Requires}, {FieldExtends, s.Extends}, {FieldConflicts, s.Conflicts}} {
+			for _, target := range field.targets {
+				switch {
+				case target == name:
+					errs = append(errs, DependencyError{Skill: name, Field: field.name, Target: target,
+						Message: fmt.Sprintf("%s lists the skill itself", field.name)})
+				case r.skills[target] == nil:
+					errs = append(errs, DependencyError{Skill: name, Field: field.name, Target: target,
+						Message: fmt.Sprintf("%s unknown skill %q", field.name, target)})
+				}
+			}
+		}
+		for _, target := range s.Conflicts {
+			if containsName(dependencies(s), target) {
+				errs = append(errs, DependencyError{Skill: name, Field: FieldConflicts, Target: target,
+					Message: fmt.Sprintf("conflicts with %q, which it also requires or extends", target)})
+			}
+		}
+	}
+
+	for _, cycle := range r.dependencyCycles(names) {
+		errs = append(errs, DependencyError{Skill: cycle[0], Field: FieldRequires, Cycle: cycle,
+			Message: "dependency cycle: " + strings.Join(append(cycle, cycle[0]), " → ")})
+	}
+	return errs
+}
+
+// dependencyCycles finds requires/extends cycles by depth-first search.
+// Each cycle is reported once, rotated to start at its smallest name.
+// Callers hold r.mu.
+func (r *Registry) dependencyCycles(names []string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+	var cycles [][]string
+	seen := map[string]bool{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range dependencies(r.skills[name]) {
+			if r.skills[dep] == nil || dep == name {
+				continue
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycle := rotateToSmallest(append([]string{}, stack[i:]...))
+						if key := strings.Join(cycle, "\x00"); !seen[key] {
+							seen[key] = true
+							cycles = append(cycles, cycle)
+						}
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return cycles
+}
+
+func rotateToSmallest(cycle []string) []string {
+	first := 0
+	for i, name := range cycle {
+		if name < cycle[first] {
+			first = i
+		}
+	}
+	return append(cycle[first:], cycle[:first]...)
+}
+
+// ResolvedSkill is a member of a composed skill set with the reason it
+// was included: "requested", "required by X" or "extended by X".
+type ResolvedSkill struct {
+	Skill  *Skill
+	Reason string
+}
+
+// Resolve composes the skill set to load for the named skills: each
+// skill with everything it extends and requires, transitively, in load
+// order (a skill after the skills it builds on). It fails on unknown
+// skills, cycles, and skills in the set that conflict with each other.
+func (r *Registry) Resolve(names ...string) ([]*ResolvedSkill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var order []*ResolvedSkill
+	state := map[string]int{} // 1 = visiting, 2 = done
+	var path []string
+
+	var visit func(name, reason string) error
+	visit = func(name, reason string) error {
+		switch state[name] {
+		case 2:
+			return nil
+		case 1:
+			i := len(path) - 1
+			for i > 0 && path[i] != name {
+				i--
+			}
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[i:], name), " → "))
+		}
+		s := r.skills[name]
+		if s == nil {
+			if reason == "requested" {
+				return fmt.Errorf("skill not found: %s", name)
+			}
+			return fmt.Errorf("skill not found: %s (%s)", name, reason)
+		}
+
+		state[name] = 1
+		path = append(path, name)
+		for _, base := range s.Extends {
+			if err := visit(base, "extended by "+name); err != nil {
+				return err
+			}
+		}
+		for _, req := range s.Requires {
+			if err := visit(req, "required by "+name); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = 2
+		order = append(order, &ResolvedSkill{Skill: s, Reason: reason})
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, "requested"); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	inSet := map[string]*ResolvedSkill{}
+	for _, rs := range order {
+		inSet[rs.Skill.Name] = rs
+	}
+	reported := map[[2]string]bool{}
+	for _, rs := range order {
+		for _, other := range rs.Skill.Conflicts {
+			pair := [2]string{min(rs.Skill.Name, other), max(rs.Skill.Name, other)}
+			if o := inSet[other]; o != nil && !reported[pair] {
+				reported[pair] = true
+				errs = append(errs, fmt.Errorf("%s (%s) conflicts with %s (%s)", rs.Skill.Name, rs.Reason, other, o.Reason))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return order, nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
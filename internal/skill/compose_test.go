@@ -0,0 +1,103 @@
+package skill
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func composeRegistry(skills ...*Skill) *Registry {
+	r := NewRegistry()
+	r.RegisterAll(skills)
+	return r
+}
+
+func TestParseComposition(t *testing.T) {
+	s, err := ParseSkillContent("---\nname: nextjs-migration\ndescription: d\nextends: nextjs\nrequires: react-19, typescript-strict\nconflicts: [vue]\n---\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Extends, NameList{"nextjs"}) ||
+		!reflect.DeepEqual(s.Requires, NameList{"react-19", "typescript-strict"}) ||
+		!reflect.DeepEqual(s.Conflicts, NameList{"vue"}) {
+		t.Errorf("extends %v, requires %v, conflicts %v", s.Extends, s.Requires, s.Conflicts)
+	}
+	if _, err := ParseSkillContent("---\nname: x\nrequires: {a: b}\n---\n"); err == nil {
+		t.Error("requires mapping was accepted")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := composeRegistry(
+		&Skill{Name: "nextjs-migration", Extends: NameList{"nextjs"}, Requires: NameList{"react-19", "typescript-strict"}},
+		&Skill{Name: "nextjs", Requires: NameList{"react-19"}},
+		&Skill{Name: "react-19", Requires: NameList{"typescript-strict"}},
+		&Skill{Name: "typescript-strict"},
+		&Skill{Name: "vue", Conflicts: NameList{"react-19"}},
+	)
+
+	set, err := r.Resolve("nextjs-migration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rs := range set {
+		got = append(got, rs.Skill.Name+" ("+rs.Reason+")")
+	}
+	want := []string{
+		"typescript-strict (required by react-19)",
+		"react-19 (required by nextjs)",
+		"nextjs (extended by nextjs-migration)",
+		"nextjs-migration (requested)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve = %v, want %v", got, want)
+	}
+
+	if _, err := r.Resolve("nextjs", "vue"); err == nil || !strings.Contains(err.Error(), "react-19 (required by nextjs) conflicts with vue") &&
+		!strings.Contains(err.Error(), "vue (requested) conflicts with react-19 (required by nextjs)") {
+		t.Errorf("conflict error = %v", err)
+	}
+	if _, err := r.Resolve("missing"); err == nil || err.Error() != "skill not found: missing" {
+		t.Errorf("missing error = %v", err)
+	}
+
+	// Suggestions bring their dependencies along; a conflicting one is dropped.
+	composed := r.Compose([]*Suggestion{{Skill: r.Get("react-19")}, {Skill: r.Get("vue")}})
+	var names []string
+	for _, s := range composed {
+		names = append(names, s.Skill.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"typescript-strict", "react-19"}) {
+		t.Errorf("Compose = %v", names)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	r := composeRegistry(
+		&Skill{Name: "a", Requires: NameList{"b"}},
+		&Skill{Name: "b", Extends: NameList{"c"}},
+		&Skill{Name: "c", Requires: NameList{"a", "ghost"}},
+		&Skill{Name: "d", Requires: NameList{"d", "a"}, Conflicts: NameList{"a"}},
+	)
+	var got []string
+	for _, e := range r.ValidateDependencies() {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`c: requires unknown skill "ghost"`,
+		`d: requires lists the skill itself`,
+		`d: conflicts with "a", which it also requires or extends`,
+		`a: dependency cycle: a → b → c → a`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateDependencies =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := r.Resolve("a"); err == nil || err.Error() != "dependency cycle: a → b → c → a" {
+		t.Errorf("cycle error = %v", err)
+	}
+	if related := r.GetRelated("b", 0); len(related) != 2 || related[0].Name == related[1].Name {
+		t.Errorf("GetRelated(b) = %v", related)
+	}
+}
@@ -0,0 +1,347 @@
+// Package skill provides the full-text search index over skill bodies.
+package skill
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"jikime-adk/internal/tag"
+)
+
+// IndexFile is the persisted search index, relative to the project root.
+const IndexFile = ".jikime/cache/skill-index.json"
+
+// indexVersion changes whenever tokenization or the file layout does,
+// so that older index files are rebuilt.
+const indexVersion = 1
+
+// BM25 parameters: term frequency saturation and length normalization.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// SearchIndex is an inverted index over skill sections for BM25 ranking.
+// Each skill contributes a metadata section (name, description, tags,
+// keywords) and one section per Markdown heading of its body.
+type SearchIndex struct {
+	Version   int                  `json:"version"`
+	Files     map[string]fileStamp `json:"files"`
+	Sections  []IndexSection       `json:"sections"`
+	Postings  map[string][]Posting `json:"postings"`
+	AvgLength float64              `json:"avg_length"`
+}
+
+// IndexSection is a searchable part of a skill: its metadata or the text
+// under one heading of SKILL.md. Line is the heading's line in the file.
+type IndexSection struct {
+	Skill   string `json:"skill"`
+	Heading string `json:"heading,omitempty"`
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Length  int    `json:"length"`
+}
+
+// Posting records how often a term occurs in a section.
+type Posting struct {
+	Section int `json:"s"`
+	Freq    int `json:"f"`
+}
+
+// fileStamp identifies a SKILL.md version without reading it.
+type fileStamp struct {
+	ModTime int64 `json:"mtime"`
+	Size    int64 `json:"size"`
+}
+
+// TextResult is a skill found by full-text search, with the best
+// matching section and a snippet from it.
+type TextResult struct {
+	Skill   *Skill
+	Score   float64
+	Heading string // heading of the matching section ("" for metadata)
+	Line    int    // line of the snippet in SKILL.md (0 if unknown)
+	Snippet string
+}
+
+// BuildIndex indexes skills. Bodies are read from FilePath for skills
+// loaded with metadata only.
+func BuildIndex(skills []*Skill) *SearchIndex {
+	idx := &SearchIndex{Version: indexVersion, Files: map[string]fileStamp{}, Postings: map[string][]Posting{}}
+	sorted := append([]*Skill{}, skills...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	for _, s := range sorted {
+		body, bodyLine := s.Body, 1
+		if s.FilePath != "" {
+			if info, err := os.Stat(s.FilePath); err == nil {
+				idx.Files[s.FilePath] = fileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+			}
+			if raw, err := os.ReadFile(s.FilePath); err == nil {
+				if _, b, err := SplitFrontmatter(string(raw)); err == nil {
+					body = b
+					if i := strings.Index(string(raw), b); i >= 0 && b != "" {
+						bodyLine = strings.Count(string(raw[:i]), "\n") + 1
+					}
+				}
+			}
+		}
+
+		meta := strings.Join([]string{s.Name, s.Description, strings.Join(s.Tags, " "), strings.Join(s.Triggers.Keywords, " ")}, "\n")
+		idx.add(IndexSection{Skill: s.Name, Text: strings.TrimSpace(meta)})
+		for _, sec := range splitSections(body, bodyLine) {
+			sec.Skill = s.Name
+			idx.add(sec)
+		}
+	}
+
+	total := 0
+	for _, sec := range idx.Sections {
+		total += sec.Length
+	}
+	if len(idx.Sections) > 0 {
+		idx.AvgLength = float64(total) / float64(len(idx.Sections))
+	}
+	return idx
+}
+
+func (idx *SearchIndex) add(sec IndexSection) {
+	terms := Tokenize(sec.Text)
+	if len(terms) == 0 {
+		return
+	}
+	sec.Length = len(terms)
+	id := len(idx.Sections)
+	idx.Sections = append(idx.Sections, sec)
+
+	freq := map[string]int{}
+	for _, t := range terms {
+		freq[t]++
+	}
+	for t, n := range freq {
+		idx.Postings[t] = append(idx.Postings[t], Posting{Section: id, Freq: n})
+	}
+}
+
+// splitSections splits a Markdown body at its headings. Lines inside code
+// fences are never headings. firstLine is the body's line in the file.
+func splitSections(body string, firstLine int) []IndexSection {
+	var sections []IndexSection
+	cur := IndexSection{Line: firstLine}
+	var text []string
+	inFence := false
+
+	flush := func() {
+		cur.Text = strings.TrimSpace(strings.Join(text, "\n"))
+		if cur.Text != "" {
+			sections = append(sections, cur)
+		}
+	}
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(trimmed, "#") {
+			if heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); heading != "" {
+				flush()
+				cur = IndexSection{Heading: heading, Line: firstLine + i}
+				text = nil
+			}
+		}
+		text = append(text, line)
+	}
+	flush()
+	return sections
+}
+
+// upToDate reports whether idx was built from exactly these skill files
+// as they are on disk now.
+func (idx *SearchIndex) upToDate(skills []*Skill) bool {
+	if idx.Version != indexVersion || len(idx.Files) != len(skills) {
+		return false
+	}
+	for _, s := range skills {
+		stamp, ok := idx.Files[s.FilePath]
+		if !ok || s.FilePath == "" {
+			return false
+		}
+		info, err := os.Stat(s.FilePath)
+		if err != nil || info.ModTime().UnixNano() != stamp.ModTime || info.Size() != stamp.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadIndex returns the persisted index under projectRoot when it still
+// matches skills, and otherwise builds a new one and saves it. Saving
+// requires the project's .jikime directory; without it the index is only
+// kept in memory.
+func LoadIndex(projectRoot string, skills []*Skill) (*SearchIndex, error) {
+	path := filepath.Join(projectRoot, IndexFile)
+	if data, err := os.ReadFile(path); err == nil {
+		var idx SearchIndex
+		if json.Unmarshal(data, &idx) == nil && idx.upToDate(skills) {
+			return &idx, nil
+		}
+	}
+
+	idx := BuildIndex(skills)
+	if info, err := os.Stat(filepath.Join(projectRoot, ".jikime")); err != nil || !info.IsDir() {
+		return idx, nil
+	}
+	return idx, idx.Save(path)
+}
+
+// Save writes the index as JSON, replacing the file atomically.
+func (idx *SearchIndex) Save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	// A uniquely named temp file: hooks of several agents may save the
+	// same index at once.
+	return tag.AtomicWriteText(path, string(data))
+}
+
+// Search ranks sections against query with BM25 and returns the best
+// section of each skill, highest score first. resolve maps skill names to
+// skills; sections of unknown skills are skipped.
+func (idx *SearchIndex) Search(query string, limit int, resolve func(string) *Skill) []*TextResult {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 || len(idx.Sections) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.Sections))
+	scores := map[int]float64{}
+	for _, t := range terms {
+		postings := idx.Postings[t]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, p := range postings {
+			tf := float64(p.Freq)
+			norm := 1 - bm25B + bm25B*float64(idx.Sections[p.Section].Length)/idx.AvgLength
+			scores[p.Section] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+
+	best := map[string]int{}
+	for id, score := range scores {
+		name := idx.Sections[id].Skill
+		if prev, ok := best[name]; !ok || score > scores[prev] || (score == scores[prev] && id < prev) {
+			best[name] = id
+		}
+	}
+
+	var results []*TextResult
+	for name, id := range best {
+		s := resolve(name)
+		if s == nil {
+			continue
+		}
+		sec := idx.Sections[id]
+		line, snippet := snippetFor(sec, terms)
+		results = append(results, &TextResult{
+			Skill:   s,
+			Score:   math.Round(scores[id]*100) / 100,
+			Heading: sec.Heading,
+			Line:    line,
+			Snippet: snippet,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Skill.Name < results[j].Skill.Name
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// snippetMaxRunes bounds the length of a search snippet.
+const snippetMaxRunes = 160
+
+// snippetFor returns the first non-heading line of sec that contains the
+// most query terms, with its line number, shortened around the first hit.
+func snippetFor(sec IndexSection, terms []string) (int, string) {
+	want := map[string]bool{}
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	bestLine, bestHits, bestText := 0, 0, ""
+	for i, line := range strings.Split(sec.Text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || (i == 0 && sec.Heading != "") {
+			continue
+		}
+		hits := map[string]bool{}
+		for _, t := range Tokenize(trimmed) {
+			if want[t] {
+				hits[t] = true
+			}
+		}
+		if len(hits) > bestHits {
+			bestLine, bestHits, bestText = i, len(hits), trimmed
+		}
+	}
+	if bestHits == 0 {
+		return 0, ""
+	}
+
+	line := 0
+	if sec.Line > 0 {
+		line = sec.Line + bestLine
+	}
+	return line, shortenAround(bestText, terms)
+}
+
+// shortenAround trims text to snippetMaxRunes, keeping the first query
+// term match in view.
+func shortenAround(text string, terms []string) string {
+	runes := []rune(text)
+	if len(runes) <= snippetMaxRunes {
+		return text
+	}
+	start := 0
+	lower := strings.ToLower(text)
+	for _, t := range terms {
+		if i := strings.Index(lower, t); i >= 0 {
+			start = max(0, len([]rune(lower[:i]))-snippetMaxRunes/4)
+			break
+		}
+	}
+	end := min(len(runes), start+snippetMaxRunes)
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
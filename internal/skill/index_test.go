@@ -0,0 +1,160 @@
+package skill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := map[string][]string{
+		"Hydration mismatches in the renderer": {"hydrat", "mismatch", "renderer"},
+		"hydrated, hydrating; hydrate":         {"hydrat", "hydrat", "hydrat"},
+		"caching cached cache":                 {"cach", "cach", "cach"},
+		"running tests":                        {"run", "test"},
+		"Next.js 서버 불일치":                       {"next", "js", "서버", "불일", "일치"},
+		"ハイドレーション":                             {"ハイ", "イド", "ドレ", "レー", "ーシ", "ショ", "ョン"},
+		"水":                                    {"水"},
+	}
+	for text, want := range cases {
+		if got := Tokenize(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+const nextjsSkill = `---
+name: nextjs
+description: Next.js App Router patterns
+tags: [framework]
+---
+
+# Next.js
+
+Server components by default.
+
+## Troubleshooting
+
+### Hydration errors
+
+A hydration mismatch happens when server HTML differs from the client render.
+
+` + "```bash\n# not a heading\n```" + `
+
+## 한국어
+
+하이드레이션 불일치는 렌더링 결과가 다를 때 발생합니다.
+`
+
+func writeSkill(t *testing.T, dir, name, content string) *Skill {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", "skills", name, SkillFileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadMetadataOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSearchText(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".jikime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeSkill(t, root, "nextjs", nextjsSkill)
+	writeSkill(t, root, "react", "---\nname: react\ndescription: React rendering\n---\n\n# React\n\nRender components on the client.\n")
+
+	r := NewRegistry()
+	if err := r.LoadFromProjectRoot(root); err != nil {
+		t.Fatal(err)
+	}
+
+	results := r.SearchText("hydration mismatch", 0)
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	got := results[0]
+	if got.Skill.Name != "nextjs" || got.Heading != "Hydration errors" || got.Line != 15 ||
+		got.Snippet != "A hydration mismatch happens when server HTML differs from the client render." {
+		t.Errorf("result = %+v", got)
+	}
+
+	if results := r.SearchText("렌더링 불일치", 0); len(results) != 1 || results[0].Heading != "한국어" {
+		t.Errorf("CJK results = %+v", results)
+	}
+	if results := r.SearchText("rendering", 0); len(results) != 2 || results[0].Skill.Name != "react" {
+		t.Errorf("rendering results = %+v", results)
+	}
+	// A "#" line in a code fence belongs to the section around it.
+	if results := r.SearchText("heading", 0); len(results) != 1 || results[0].Heading != "Hydration errors" {
+		t.Errorf("code fence results = %+v", results)
+	}
+
+	// The persisted index is reused until a SKILL.md changes.
+	path := filepath.Join(root, IndexFile)
+	idx, err := LoadIndex(root, r.All())
+	if err != nil || !idx.upToDate(r.All()) {
+		t.Fatalf("LoadIndex = %v, up to date %v", err, idx != nil && idx.upToDate(r.All()))
+	}
+	before, _ := os.Stat(path)
+
+	react := r.Get("react")
+	if err := os.WriteFile(react.FilePath, []byte("---\nname: react\n---\n\n# React\n\nSuspense boundaries.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(react.FilePath, future, future); err != nil {
+		t.Fatal(err)
+	}
+	r = NewRegistry()
+	if err := r.LoadFromProjectRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.Stat(path); !after.ModTime().After(before.ModTime()) && after.Size() == before.Size() {
+		t.Error("index was not rebuilt after a skill changed")
+	}
+	if results := r.SearchText("suspense", 0); len(results) != 1 || results[0].Skill.Name != "react" {
+		t.Errorf("results after change = %+v", results)
+	}
+}
+
+func TestSearchIndexSave_Concurrent(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, root, "react", "---\nname: react\ndescription: React rendering\n---\n\n# React\n\nRender components on the client.\n")
+	r := NewRegistry()
+	if err := r.LoadFromProjectRoot(root); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "cache", "index.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := BuildIndex(r.All()).Save(path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var idx SearchIndex
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &idx) != nil || len(idx.Sections) == 0 {
+		t.Fatalf("index after concurrent saves: %v %s", err, data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("leftover files: %v", entries)
+	}
+}
@@ -0,0 +1,590 @@
+// Package skill provides SKILL.md linting.
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint levels.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// Lint rules, as reported in LintIssue.Rule.
+const (
+	RuleFrontMatter = "front-matter"
+	RuleSchema      = "schema"
+	RulePhase       = "phase"
+	RuleAgent       = "agent"
+	RuleTool        = "tool"
+	RuleLink        = "link"
+	RuleDependency  = "dependency"
+	RuleSize        = "size"
+	RuleKeyword     = "keyword"
+)
+
+// DefaultMaxBodyTokens is the body size above which lint warns; it matches
+// the Level 2 budget of progressive disclosure.
+const DefaultMaxBodyTokens = 5000
+
+// KnownTools are the tool names allowed in "allowed-tools", besides MCP
+// tools (mcp__server__tool).
+var KnownTools = map[string]bool{
+	"Agent": true, "AskUserQuestion": true, "Bash": true, "BashOutput": true,
+	"Edit": true, "ExitPlanMode": true, "Glob": true, "Grep": true,
+	"KillShell": true, "LS": true, "MultiEdit": true, "NotebookEdit": true,
+	"NotebookRead": true, "Read": true, "Skill": true, "SlashCommand": true,
+	"Task": true, "TodoRead": true, "TodoWrite": true, "WebFetch": true,
+	"WebSearch": true, "Write": true,
+}
+
+// LintIssue is a problem found in a SKILL.md. File is relative to the
+// project root and Line is 1-based (0 when it applies to the whole file).
+type LintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Level   string `json:"level"`
+	Rule    string `json:"rule"`
+	Skill   string `json:"skill,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintReport is the result of linting a set of SKILL.md files.
+type LintReport struct {
+	Root   string      `json:"root"`
+	Files  int         `json:"files"`
+	Issues []LintIssue `json:"issues"`
+}
+
+// Errors returns the number of error-level issues.
+func (r *LintReport) Errors() int {
+	return r.count(LintError)
+}
+
+// Warnings returns the number of warning-level issues.
+func (r *LintReport) Warnings() int {
+	return r.count(LintWarning)
+}
+
+func (r *LintReport) count(level string) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// LintOptions tunes Lint.
+type LintOptions struct {
+	// MaxBodyTokens is the body size warning threshold
+	// (0 = DefaultMaxBodyTokens).
+	MaxBodyTokens int
+}
+
+// lintFile is a parsed SKILL.md with what is needed to place issues.
+type lintFile struct {
+	path     string
+	rel      string
+	skill    *Skill
+	doc      *yaml.Node // front-matter mapping, nil when it did not parse
+	fmLine   int        // file line of the first front-matter line
+	body     string
+	bodyLine int // file line of the first body line
+}
+
+// Lint checks SKILL.md files of the project at root. With no paths, every
+// skill in SkillDirs is linted; paths may name SKILL.md files or
+// directories to search. Cross-skill checks (dependencies, shared
+// keywords) consider all of the project's skills.
+func Lint(root string, paths []string, opts LintOptions) (*LintReport, error) {
+	if opts.MaxBodyTokens <= 0 {
+		opts.MaxBodyTokens = DefaultMaxBodyTokens
+	}
+	report := &LintReport{Root: root, Issues: []LintIssue{}}
+
+	files, err := lintTargets(root, paths)
+	if err != nil {
+		return nil, err
+	}
+	report.Files = len(files)
+
+	// The registry holds every project and pack skill, with the linted
+	// versions last
+	registry := NewRegistry()
+	if packSkills, err := LoadPackSkills(root); err == nil {
+		registry.RegisterAll(packSkills)
+	}
+	for _, dir := range SkillDirs(root) {
+		if skills, err := LoadMetadataFromDirectory(dir, true); err == nil {
+			registry.RegisterAll(skills)
+		}
+	}
+
+	agents, haveAgents := projectAgents(root)
+	var parsed []*lintFile
+	for _, path := range files {
+		lf, issues := parseLintFile(root, path)
+		report.Issues = append(report.Issues, issues...)
+		if lf.skill == nil {
+			continue
+		}
+		registry.Register(lf.skill)
+		parsed = append(parsed, lf)
+
+		report.Issues = append(report.Issues, lf.checkTriggers(agents, haveAgents)...)
+		report.Issues = append(report.Issues, lf.checkTools()...)
+		report.Issues = append(report.Issues, lf.checkLinks()...)
+		if tokens := EstimateTokens(lf.body); tokens > opts.MaxBodyTokens {
+			report.Issues = append(report.Issues, lf.issue(lf.bodyLine, LintWarning, RuleSize,
+				fmt.Sprintf("body is about %d tokens, above the %d token budget; move details into referenced files", tokens, opts.MaxBodyTokens)))
+		}
+	}
+
+	report.Issues = append(report.Issues, checkDependencies(registry, parsed)...)
+	report.Issues = append(report.Issues, checkSharedKeywords(registry, parsed)...)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// lintTargets returns the SKILL.md files to lint, sorted.
+func lintTargets(root string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		for _, dir := range SkillDirs(root) {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				paths = append(paths, dir)
+			}
+		}
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !seen[p] {
+				seen[p] = true
+				files = append(files, p)
+			}
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && d.Name() == SkillFileName && !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// yamlLinePattern finds the line in YAML errors such as "yaml: line N: ...".
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// parseLintFile reads a SKILL.md, checking its front-matter against the
+// schema. The returned file has a nil skill when the front-matter is
+// missing or does not decode.
+func parseLintFile(root, path string) (*lintFile, []LintIssue) {
+	lf := &lintFile{path: path, rel: path}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		lf.rel = rel
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return lf, []LintIssue{lf.issue(0, LintError, RuleFrontMatter, err.Error())}
+	}
+
+	lines := strings.Split(string(content), "\n")
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != FrontmatterDelimiter {
+		return lf, []LintIssue{lf.issue(1, LintError, RuleFrontMatter, "missing YAML front-matter (--- name/description ---)")}
+	}
+	end := start + 1
+	for end < len(lines) && strings.TrimRight(lines[end], " \t\r") != FrontmatterDelimiter {
+		end++
+	}
+	if end == len(lines) {
+		return lf, []LintIssue{lf.issue(start+1, LintError, RuleFrontMatter, "front-matter is not closed with ---")}
+	}
+	lf.fmLine = start + 2
+	lf.bodyLine = end + 2
+	lf.body = strings.Join(lines[end+1:], "\n")
+	frontMatter := strings.Join(lines[start+1:end], "\n")
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontMatter), &doc); err != nil {
+		return lf, []LintIssue{lf.issue(lf.yamlErrorLine(err), LintError, RuleFrontMatter, "invalid YAML: "+strings.TrimPrefix(err.Error(), "yaml: "))}
+	}
+	lf.doc = &doc
+
+	var issues []LintIssue
+	for _, v := range ValidateFrontMatter(&doc) {
+		level := LintError
+		if v.Unknown {
+			level = LintWarning
+		}
+		issues = append(issues, lf.issue(lf.fileLine(v.Line), level, RuleSchema, v.Message))
+	}
+
+	s := &Skill{}
+	if err := doc.Decode(s); err != nil {
+		// The schema reports the offending value; the skill cannot be checked further
+		if len(issues) == 0 {
+			issues = append(issues, lf.issue(lf.yamlErrorLine(err), LintError, RuleSchema, strings.TrimPrefix(err.Error(), "yaml: ")))
+		}
+		return lf, issues
+	}
+	s.FilePath = path
+	s.Body = strings.TrimSpace(lf.body)
+	if s.Name == "" {
+		return lf, issues
+	}
+	lf.skill = s
+	for i := range issues {
+		issues[i].Skill = s.Name
+	}
+	return lf, issues
+}
+
+func (lf *lintFile) issue(line int, level, rule, message string) LintIssue {
+	name := ""
+	if lf.skill != nil {
+		name = lf.skill.Name
+	}
+	return LintIssue{File: lf.rel, Line: line, Level: level, Rule: rule, Skill: name, Message: message}
+}
+
+// fileLine converts a front-matter line to a file line.
+func (lf *lintFile) fileLine(line int) int {
+	if line <= 0 {
+		return lf.fmLine - 1
+	}
+	return lf.fmLine + line - 1
+}
+
+func (lf *lintFile) yamlErrorLine(err error) int {
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		var n int
+		fmt.Sscanf(m[1], "%d", &n)
+		return lf.fileLine(n)
+	}
+	return lf.fileLine(0)
+}
+
+// node returns the front-matter value at a dotted path ("triggers.agents"),
+// or nil.
+func (lf *lintFile) node(path string) *yaml.Node {
+	if lf.doc == nil || len(lf.doc.Content) == 0 {
+		return nil
+	}
+	n := lf.doc.Content[0]
+	for _, key := range strings.Split(path, ".") {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				next = n.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// valueLine returns the file line of value in the list (or scalar) at
+// path, falling back to the line of the list itself.
+func (lf *lintFile) valueLine(path, value string) int {
+	n := lf.node(path)
+	if n == nil {
+		return lf.fileLine(0)
+	}
+	for _, item := range n.Content {
+		if item.Value == value {
+			return lf.fileLine(item.Line)
+		}
+	}
+	return lf.fileLine(n.Line)
+}
+
+// checkTriggers checks trigger phases against ValidPhases and agent names
+// against the project's agents.
+func (lf *lintFile) checkTriggers(agents map[string]bool, haveAgents bool) []LintIssue {
+	var issues []LintIssue
+	s := lf.skill
+	for _, phase := range s.Triggers.Phases {
+		if !ValidPhases[phase] {
+			issues = append(issues, lf.issue(lf.valueLine("triggers.phases", phase), LintError, RulePhase,
+				fmt.Sprintf("unknown phase %q (valid: %s)", phase, validPhaseList())))
+		}
+	}
+	if !haveAgents {
+		return issues
+	}
+	if s.Agent != "" && !agents[s.Agent] {
+		issues = append(issues, lf.issue(lf.valueLine("agent", s.Agent), LintError, RuleAgent,
+			fmt.Sprintf("agent %q not found in .claude/agents", s.Agent)))
+	}
+	for _, agent := range s.Triggers.Agents {
+		if !agents[agent] {
+			issues = append(issues, lf.issue(lf.valueLine("triggers.agents", agent), LintError, RuleAgent,
+				fmt.Sprintf("trigger agent %q not found in .claude/agents", agent)))
+		}
+	}
+	return issues
+}
+
+func validPhaseList() string {
+	phases := make([]string, 0, len(ValidPhases))
+	for p := range ValidPhases {
+		phases = append(phases, p)
+	}
+	sort.Strings(phases)
+	return strings.Join(phases, ", ")
+}
+
+// checkTools checks allowed-tools entries. "Bash(git diff:*)" names the
+// Bash tool; MCP tools are accepted as mcp__server__tool.
+func (lf *lintFile) checkTools() []LintIssue {
+	var issues []LintIssue
+	for _, tool := range lf.skill.AllowedTools {
+		name, _, _ := strings.Cut(tool, "(")
+		name = strings.TrimSpace(name)
+		if KnownTools[name] || (strings.HasPrefix(name, "mcp__") && len(name) > len("mcp__")) {
+			continue
+		}
+		issues = append(issues, lf.issue(lf.valueLine("allowed-tools", tool), LintError, RuleTool,
+			fmt.Sprintf("unknown tool %q in allowed-tools", tool)))
+	}
+	return issues
+}
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^)]*["'])?\s*\)`)
+	inlineCodePattern   = regexp.MustCompile("`[^`]*`")
+)
+
+// checkLinks reports relative Markdown links in the body whose target
+// does not exist. Links in code are ignored.
+func (lf *lintFile) checkLinks() []LintIssue {
+	var issues []LintIssue
+	dir := filepath.Dir(lf.path)
+	inFence := false
+	for i, line := range strings.Split(lf.body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range markdownLinkPattern.FindAllStringSubmatch(inlineCodePattern.ReplaceAllString(line, ""), -1) {
+			target := m[1]
+			if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "/") ||
+				strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+				continue
+			}
+			target, _, _ = strings.Cut(target, "#")
+			target, _, _ = strings.Cut(target, "?")
+			if decoded, err := url.PathUnescape(target); err == nil {
+				target = decoded
+			}
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(target))); err != nil {
+				issues = append(issues, lf.issue(lf.bodyLine+i, LintError, RuleLink,
+					fmt.Sprintf("link target %q does not exist", m[1])))
+			}
+		}
+	}
+	return issues
+}
+
+// checkDependencies reports ValidateDependencies errors of linted skills
+// at the line of the offending entry.
+func checkDependencies(registry *Registry, files []*lintFile) []LintIssue {
+	byName := map[string]*lintFile{}
+	for _, lf := range files {
+		byName[lf.skill.Name] = lf
+	}
+	var issues []LintIssue
+	for _, e := range registry.ValidateDependencies() {
+		lf := byName[e.Skill]
+		if lf == nil {
+			continue
+		}
+		field, target := e.Field, e.Target
+		if len(e.Cycle) > 0 {
+			// Point at the entry that starts the cycle
+			target = e.Cycle[1%len(e.Cycle)]
+			if containsName(lf.skill.Extends, target) {
+				field = FieldExtends
+			}
+		}
+		line := lf.valueLine(field, target)
+		issues = append(issues, lf.issue(line, LintError, RuleDependency, e.Message))
+	}
+	return issues
+}
+
+// checkSharedKeywords warns when a trigger keyword of a linted skill is
+// also a keyword of an unrelated skill: one it does not require, extend
+// or conflict with (in either direction) and shares no tag with. Such
+// keywords load both skills for the same prompts.
+func checkSharedKeywords(registry *Registry, files []*lintFile) []LintIssue {
+	var issues []LintIssue
+	for _, lf := range files {
+		s := lf.skill
+		for _, keyword := range s.Triggers.Keywords {
+			var others []string
+			for _, other := range registry.GetByKeyword(keyword) {
+				if other.Name != s.Name && !skillsRelated(s, other) && !containsName(others, other.Name) {
+					others = append(others, other.Name)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			sort.Strings(others)
+			issues = append(issues, lf.issue(lf.valueLine("triggers.keywords", keyword), LintWarning, RuleKeyword,
+				fmt.Sprintf("keyword %q is also a trigger of unrelated skill(s) %s", keyword, strings.Join(others, ", "))))
+		}
+	}
+	return issues
+}
+
+func skillsRelated(a, b *Skill) bool {
+	linked := func(x, y *Skill) bool {
+		return containsName(x.Requires, y.Name) || containsName(x.Extends, y.Name) || containsName(x.Conflicts, y.Name)
+	}
+	if linked(a, b) || linked(b, a) {
+		return true
+	}
+	for _, tag := range a.Tags {
+		if b.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectAgents returns the agent names defined under .claude/agents (and
+// templates/.claude/agents): each agent file's base name and its
+// front-matter name. The bool is false when no agents directory exists.
+func projectAgents(root string) (map[string]bool, bool) {
+	agents := map[string]bool{}
+	found := false
+	for _, dir := range []string{
+		filepath.Join(root, ".claude", "agents"),
+		filepath.Join(root, "templates", ".claude", "agents"),
+	} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		found = true
+		_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+				return nil
+			}
+			agents[strings.TrimSuffix(d.Name(), ".md")] = true
+			if content, err := os.ReadFile(path); err == nil {
+				if fm, _, err := SplitFrontmatter(string(content)); err == nil {
+					var meta struct {
+						Name string `yaml:"name"`
+					}
+					if yaml.Unmarshal([]byte(fm), &meta) == nil && meta.Name != "" {
+						agents[meta.Name] = true
+					}
+				}
+			}
+			return nil
+		})
+	}
+	return agents, found
+}
+
+// LintFormat selects how a LintReport is rendered.
+type LintFormat string
+
+const (
+	LintText   LintFormat = "text"
+	LintJSON   LintFormat = "json"
+	LintGitHub LintFormat = "github"
+)
+
+// ParseLintFormat accepts "text", "json" and "github" (workflow commands
+// that annotate pull requests).
+func ParseLintFormat(s string) (LintFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "text":
+		return LintText, nil
+	case "json":
+		return LintJSON, nil
+	case "github":
+		return LintGitHub, nil
+	}
+	return "", fmt.Errorf("skill/lint: unknown format %q (want text, json or github)", s)
+}
+
+// Render writes the report in the given format.
+func (r *LintReport) Render(w io.Writer, format LintFormat) error {
+	switch format {
+	case LintJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*LintReport
+			Errors   int `json:"errors"`
+			Warnings int `json:"warnings"`
+		}{r, r.Errors(), r.Warnings()})
+	case LintGitHub:
+		for _, issue := range r.Issues {
+			fmt.Fprintf(w, "::%s file=%s,line=%d,title=skill %s::%s\n", issue.Level, filepath.ToSlash(issue.File), max(issue.Line, 1), issue.Rule, issue.Message)
+		}
+		return nil
+	case LintText, "":
+		for _, issue := range r.Issues {
+			location := issue.File
+			if issue.Line > 0 {
+				location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+			}
+			fmt.Fprintf(w, "%s: %s [%s] %s\n", location, issue.Level, issue.Rule, issue.Message)
+		}
+		fmt.Fprintf(w, "%d file(s) checked: %d error(s), %d warning(s)\n", r.Files, r.Errors(), r.Warnings())
+		return nil
+	}
+	return fmt.Errorf("skill/lint: unknown format %q", format)
+}
@@ -0,0 +1,120 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const badSkill = `---
+name: bad
+description: A skill with one of everything wrong
+trigger: typo
+triggers:
+  keywords: [test]
+  phases: [plan, deploy]
+  agents: [frontend, backend]
+allowed-tools: Read, Bash(git diff:*), Frobnicate, mcp__github__create_pr
+requires: [ghost]
+context: spawn
+---
+
+# Bad
+
+See [the guide](refs/guide.md#usage), [missing](refs/nope.md) and [web](https://example.com).
+` + "`[code](code.md)`" + `
+`
+
+func TestLint(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, root, "bad", badSkill)
+	writeSkill(t, root, "go-testing", "---\nname: go-testing\ndescription: Go tests\ntriggers:\n  keywords: [test]\n---\n\nBody\n")
+	for path, content := range map[string]string{
+		".claude/skills/bad/refs/guide.md": "# Guide",
+		".claude/agents/frontend.md":       "---\nname: frontend\n---\n",
+		".claude/skills/broken/SKILL.md":   "---\nname: broken\ndescription: a: b\n---\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Lint(root, nil, LintOptions{MaxBodyTokens: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, filepath.Base(filepath.Dir(issue.File))+fmt.Sprintf(":%d %s %s", issue.Line, issue.Level, issue.Rule))
+	}
+	want := []string{
+		"bad:4 warning schema",
+		"bad:6 warning keyword",
+		"bad:7 error phase",
+		"bad:8 error agent",
+		"bad:9 error tool",
+		"bad:10 error dependency",
+		"bad:11 error schema",
+		"bad:13 warning size",
+		"bad:16 error link",
+		"broken:3 error front-matter",
+		"go-testing:5 warning keyword",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if report.Files != 3 || report.Errors() != 7 || report.Warnings() != 4 {
+		t.Errorf("files %d, errors %d, warnings %d", report.Files, report.Errors(), report.Warnings())
+	}
+
+	// Linting one skill still checks keywords against the others
+	report, err = Lint(root, []string{filepath.Join(root, ".claude", "skills", "go-testing")}, LintOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 1 || !strings.Contains(report.Issues[0].Message, `"test" is also a trigger of unrelated skill(s) bad`) {
+		t.Errorf("go-testing issues = %+v", report.Issues)
+	}
+}
+
+func TestLintRender(t *testing.T) {
+	report := &LintReport{Files: 1, Issues: []LintIssue{
+		{File: ".claude/skills/x/SKILL.md", Line: 3, Level: LintError, Rule: RulePhase, Message: `unknown phase "deploy"`},
+	}}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, LintText); err != nil {
+		t.Fatal(err)
+	}
+	if want := ".claude/skills/x/SKILL.md:3: error [phase] unknown phase \"deploy\"\n1 file(s) checked: 1 error(s), 0 warning(s)\n"; buf.String() != want {
+		t.Errorf("text = %q", buf.String())
+	}
+
+	buf.Reset()
+	report.Render(&buf, LintGitHub)
+	if want := "::error file=.claude/skills/x/SKILL.md,line=3,title=skill phase::unknown phase \"deploy\"\n"; buf.String() != want {
+		t.Errorf("github = %q", buf.String())
+	}
+
+	buf.Reset()
+	report.Render(&buf, LintJSON)
+	var decoded struct {
+		Errors int         `json:"errors"`
+		Issues []LintIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Errors != 1 || decoded.Issues[0].Line != 3 {
+		t.Errorf("json = %s (%v)", buf.String(), err)
+	}
+
+	if _, err := ParseLintFormat("xml"); err == nil {
+		t.Error("ParseLintFormat(xml) succeeded")
+	}
+}
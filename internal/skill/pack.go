@@ -0,0 +1,744 @@
+// Package skill provides remote skill packs: skill collections fetched
+// from git repositories or archives into a shared cache and pinned in a
+// project lockfile.
+package skill
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"jikime-adk/internal/tag"
+	"jikime-adk/version"
+)
+
+// LockFile records a project's installed packs, relative to the project
+// root. It is meant to be committed so every checkout loads the same packs.
+const LockFile = ".jikime/skill-packs.lock"
+
+const lockVersion = 1
+
+// Pack source kinds.
+const (
+	PackKindGit     = "git"
+	PackKindArchive = "archive"
+)
+
+// maxArchiveSize bounds downloaded and extracted archive data.
+const maxArchiveSize = 256 << 20
+
+var packNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// PackCacheDir returns the pack cache shared by all projects:
+// $JIKIME_SKILL_CACHE, or ~/.jikime/cache/skill-packs.
+func PackCacheDir() string {
+	if dir := os.Getenv("JIKIME_SKILL_CACHE"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "jikime-skill-packs")
+	}
+	return filepath.Join(home, ".jikime", "cache", "skill-packs")
+}
+
+// PackSource is where a pack comes from: a git repository, or a tar
+// archive (.tar.gz, .tgz or .tar) by URL or local path.
+type PackSource struct {
+	URL     string
+	Version string // git tag, branch or commit; archive label, substituted for {version} in URL
+	Kind    string
+}
+
+// ParsePackSource parses "<git-url|archive>[@version]". The version suffix
+// is the text after the last "@" when it contains no "/" or ":", so
+// "git@github.com:org/skills.git@v1.2.0" is version v1.2.0 of an SSH URL.
+func ParsePackSource(spec string) (PackSource, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return PackSource{}, fmt.Errorf("empty pack source")
+	}
+	src := PackSource{URL: spec}
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		if v := spec[i+1:]; v != "" && !strings.ContainsAny(v, "/:") {
+			src.URL, src.Version = spec[:i], v
+		}
+	}
+	src.Kind = PackKindGit
+	if isArchive(src.URL) {
+		src.Kind = PackKindArchive
+	}
+	return src, nil
+}
+
+// String returns the source in ParsePackSource form.
+func (s PackSource) String() string {
+	if s.Version == "" {
+		return s.URL
+	}
+	return s.URL + "@" + s.Version
+}
+
+// isLocalSource reports whether url is a path on disk rather than a
+// remote URL: no scheme other than file://, and not scp-like
+// ("git@host:path", a ":" before any "/").
+func isLocalSource(url string) bool {
+	if strings.HasPrefix(url, "file://") {
+		return true
+	}
+	if strings.Contains(url, "://") {
+		return false
+	}
+	if filepath.VolumeName(url) != "" {
+		return true
+	}
+	colon := strings.Index(url, ":")
+	return colon < 0 || strings.Contains(url[:colon], "/")
+}
+
+// relativeSource rewrites a local source typed relative to the current
+// directory, or as an absolute path, relative to the project root, so the
+// lockfile works in every checkout. Remote URLs are returned unchanged.
+func relativeSource(projectRoot, url string) (string, error) {
+	if !isLocalSource(url) {
+		return url, nil
+	}
+	abs, err := filepath.Abs(strings.TrimPrefix(url, "file://"))
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		// Another volume: there is no relative path
+		return abs, nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// resolveSource returns the path a local source refers to, resolving a
+// relative one against baseDir. Remote URLs are returned unchanged.
+func resolveSource(baseDir, url string) string {
+	if !isLocalSource(url) {
+		return url
+	}
+	url = filepath.FromSlash(strings.TrimPrefix(url, "file://"))
+	if baseDir == "" || filepath.IsAbs(url) {
+		return url
+	}
+	return filepath.Join(baseDir, url)
+}
+
+func isArchive(url string) bool {
+	url = strings.ToLower(url)
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar"} {
+		if strings.HasSuffix(url, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPackName derives a pack name from the last path element of a
+// source: "https://github.com/acme/legacy-skills.git" is "legacy-skills".
+func defaultPackName(url string) string {
+	name := strings.ToLower(strings.TrimRight(url, "/"))
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, ext := range []string{".git", ".tar.gz", ".tgz", ".tar"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// LockedPack is an installed pack as recorded in LockFile.
+type LockedPack struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source"`
+	Kind        string    `json:"kind"`
+	Version     string    `json:"version,omitempty"` // as requested
+	Resolved    string    `json:"resolved"`          // git commit or archive URL
+	Checksum    string    `json:"checksum"`          // sha256 of the pack's files
+	Signed      bool      `json:"signed,omitempty"`  // signature verified on install
+	Skills      []string  `json:"skills"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Dir returns the pack's directory in the cache. Packs are stored by
+// content, so projects locking the same checksum share one copy.
+func (p *LockedPack) Dir() string {
+	sum := strings.TrimPrefix(p.Checksum, "sha256:")
+	return filepath.Join(PackCacheDir(), p.Name, sum[:min(16, len(sum))])
+}
+
+// PackSource returns the source the pack was installed from.
+func (p *LockedPack) PackSource() PackSource {
+	return PackSource{URL: p.Source, Version: p.Version, Kind: p.Kind}
+}
+
+// PackLock is the content of LockFile.
+type PackLock struct {
+	Version int           `json:"version"`
+	Packs   []*LockedPack `json:"packs"`
+}
+
+// LoadPackLock reads the project's lockfile; a missing file is an empty lock.
+func LoadPackLock(projectRoot string) (*PackLock, error) {
+	lock := &PackLock{Version: lockVersion, Packs: []*LockedPack{}}
+	data, err := os.ReadFile(filepath.Join(projectRoot, LockFile))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", LockFile, err)
+	}
+	if lock.Version > lockVersion {
+		return nil, fmt.Errorf("%s: version %d is newer than supported (%d); update jikime-adk", LockFile, lock.Version, lockVersion)
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile, replacing it atomically.
+func (l *PackLock) Save(projectRoot string) error {
+	l.Version = lockVersion
+	return tag.AtomicWriteJSON(filepath.Join(projectRoot, LockFile), l)
+}
+
+// Get returns the locked pack with the given name, or nil.
+func (l *PackLock) Get(name string) *LockedPack {
+	for _, p := range l.Packs {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Put adds or replaces a pack, keeping packs sorted by name.
+func (l *PackLock) Put(pack *LockedPack) {
+	l.Remove(pack.Name)
+	l.Packs = append(l.Packs, pack)
+	sort.Slice(l.Packs, func(i, j int) bool { return l.Packs[i].Name < l.Packs[j].Name })
+}
+
+// Remove drops a pack, reporting whether it was locked.
+func (l *PackLock) Remove(name string) bool {
+	for i, p := range l.Packs {
+		if p.Name == name {
+			l.Packs = append(l.Packs[:i], l.Packs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// InstallOptions controls how a pack is fetched and verified.
+type InstallOptions struct {
+	// Name overrides the pack name derived from the source.
+	Name string
+	// Checksum is the expected "sha256:<hex>" (or bare hex) of the
+	// archive file or of the pack's files (LockedPack.Checksum).
+	Checksum string
+	// VerifySignature requires a valid signature on the git tag or commit
+	// (git verify-tag / verify-commit). Archives cannot be signed.
+	VerifySignature bool
+	// Commit pins a git source to an exact commit, as when restoring a
+	// locked pack.
+	Commit string
+	// BaseDir resolves a relative local source path, as recorded in the
+	// lockfile relative to the project root.
+	BaseDir string
+}
+
+// FetchPack downloads a pack into the cache, verifies it and returns its
+// lock entry. The pack must contain at least one SKILL.md.
+func FetchPack(src PackSource, opts InstallOptions) (*LockedPack, error) {
+	name := opts.Name
+	if name == "" {
+		name = defaultPackName(src.URL)
+	}
+	if !packNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid pack name %q; choose one with --name", name)
+	}
+	if opts.VerifySignature && src.Kind != PackKindGit {
+		return nil, fmt.Errorf("signatures can only be verified for git sources; use a checksum for archives")
+	}
+
+	cache := PackCacheDir()
+	if err := os.MkdirAll(cache, 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(cache, ".fetch-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, "pack")
+
+	pack := &LockedPack{Name: name, Source: src.URL, Kind: src.Kind, Version: src.Version, InstalledAt: time.Now().UTC()}
+	fetch := src
+	fetch.URL = resolveSource(opts.BaseDir, src.URL)
+	var archiveSum string
+	switch src.Kind {
+	case PackKindGit:
+		commit, err := fetchGit(fetch, opts, dir)
+		if err != nil {
+			return nil, err
+		}
+		pack.Resolved = commit
+		pack.Signed = opts.VerifySignature
+	case PackKindArchive:
+		pack.Resolved = strings.ReplaceAll(src.URL, "{version}", src.Version)
+		if archiveSum, err = fetchArchive(strings.ReplaceAll(fetch.URL, "{version}", src.Version), dir); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown pack kind %q", src.Kind)
+	}
+
+	if pack.Checksum, err = DirChecksum(dir); err != nil {
+		return nil, err
+	}
+	if want := opts.Checksum; want != "" {
+		want = "sha256:" + strings.ToLower(strings.TrimPrefix(want, "sha256:"))
+		if want != pack.Checksum && want != archiveSum {
+			got := pack.Checksum
+			if archiveSum != "" {
+				got = archiveSum + " (archive), " + got + " (files)"
+			}
+			return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", src, want, got)
+		}
+	}
+
+	skills, err := LoadMetadataFromDirectory(dir, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(skills) == 0 {
+		return nil, fmt.Errorf("%s contains no %s", src, SkillFileName)
+	}
+	for _, s := range skills {
+		pack.Skills = append(pack.Skills, s.Name)
+	}
+	sort.Strings(pack.Skills)
+
+	final := pack.Dir()
+	if _, err := os.Stat(final); err == nil {
+		// Same content is already cached
+		return pack, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(final), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(dir, final); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// fetchGit clones src into dir, verifies its signature if asked, and
+// returns the checked-out commit. The .git directory is not kept, and
+// symlinks are removed as extractTar skips them: a link could pull files
+// from outside the pack, which DirChecksum would not cover.
+func fetchGit(src PackSource, opts InstallOptions, dir string) (string, error) {
+	ref := src.Version
+	switch {
+	case opts.Commit != "":
+		if err := runGit("", "clone", "--quiet", src.URL, dir); err != nil {
+			return "", err
+		}
+		if err := runGit(dir, "checkout", "--quiet", opts.Commit); err != nil {
+			return "", err
+		}
+	case ref != "":
+		if runGit("", "clone", "--quiet", "--depth", "1", "--branch", ref, src.URL, dir) != nil {
+			// Not a branch or tag: try it as a commit
+			os.RemoveAll(dir)
+			if err := runGit("", "clone", "--quiet", src.URL, dir); err != nil {
+				return "", err
+			}
+			if err := runGit(dir, "checkout", "--quiet", ref); err != nil {
+				return "", fmt.Errorf("%s has no tag, branch or commit %q", src.URL, ref)
+			}
+		}
+	default:
+		if err := runGit("", "clone", "--quiet", "--depth", "1", src.URL, dir); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w", err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	if opts.VerifySignature {
+		verify := []string{"verify-commit", "HEAD"}
+		if ref != "" && opts.Commit == "" {
+			if tags, _ := exec.Command("git", "-C", dir, "tag", "--list", ref).Output(); strings.TrimSpace(string(tags)) != "" {
+				verify = []string{"verify-tag", ref}
+			}
+		}
+		if err := runGit(dir, verify...); err != nil {
+			return "", fmt.Errorf("signature verification failed for %s: %w", src, err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+		return "", err
+	}
+	return commit, removeSymlinks(dir)
+}
+
+// removeSymlinks deletes the symbolic links under dir.
+func removeSymlinks(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			return os.Remove(p)
+		}
+		return nil
+	})
+}
+
+// runGit runs git, returning its stderr in the error.
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// fetchArchive extracts a tar archive (optionally gzipped) from a URL or
+// local path into dir and returns the archive's checksum. A single
+// top-level directory, as in GitHub release tarballs, is stripped.
+func fetchArchive(url, dir string) (string, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		client := &http.Client{Timeout: 5 * time.Minute}
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("User-Agent", "jikime-adk/"+version.String())
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return "", fmt.Errorf("download %s: HTTP %d", url, resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(strings.TrimPrefix(url, "file://"))
+		if err != nil {
+			return "", err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	br := bufio.NewReader(io.TeeReader(io.LimitReader(r, maxArchiveSize), h))
+	var tr *tar.Reader
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		tr = tar.NewReader(gz)
+	} else {
+		tr = tar.NewReader(br)
+	}
+
+	if err := extractTar(tr, dir); err != nil {
+		return "", fmt.Errorf("extract %s: %w", url, err)
+	}
+	// Hash any trailing bytes so the checksum covers the whole file
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return "", err
+	}
+	if err := stripSingleDir(dir); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractTar writes regular files and directories of tr under dir,
+// rejecting entries that would land outside it. Links are skipped.
+func extractTar(tr *tar.Reader, dir string) error {
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(hdr.Name)
+		if name == "." {
+			continue
+		}
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("unsafe path %q", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if total += hdr.Size; total > maxArchiveSize {
+				return fmt.Errorf("archive is larger than %d MB", maxArchiveSize>>20)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// stripSingleDir moves the content of dir's only entry up when that entry
+// is a directory.
+func stripSingleDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return err
+	}
+	inner := filepath.Join(dir, entries[0].Name())
+	moved := dir + ".strip"
+	if err := os.Rename(inner, moved); err != nil {
+		return err
+	}
+	if err := os.Remove(dir); err != nil {
+		return err
+	}
+	return os.Rename(moved, dir)
+}
+
+// DirChecksum hashes the regular files under dir by relative path and
+// content, independent of timestamps and of how the files were fetched.
+// Fetched packs contain no symlinks; one found here is an error, since
+// its target would be loaded without being covered by the checksum.
+func DirChecksum(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			rel, _ := filepath.Rel(dir, p)
+			return fmt.Errorf("symlink in pack: %s", filepath.ToSlash(rel))
+		}
+		if d.Type().IsRegular() {
+			rel, _ := filepath.Rel(dir, p)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(h, "%s\x00%x\n", rel, sum)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// InstallPack fetches the pack at spec ("<git-url|archive>[@version]") and
+// records it in the project's lockfile, replacing a pack of the same name.
+// A local path is recorded relative to projectRoot.
+func InstallPack(projectRoot, spec string, opts InstallOptions) (*LockedPack, error) {
+	src, err := ParsePackSource(spec)
+	if err != nil {
+		return nil, err
+	}
+	if src.URL, err = relativeSource(projectRoot, src.URL); err != nil {
+		return nil, err
+	}
+	lock, err := LoadPackLock(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	opts.BaseDir = projectRoot
+	pack, err := FetchPack(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	lock.Put(pack)
+	return pack, lock.Save(projectRoot)
+}
+
+// RestorePacks fetches locked packs missing from the cache, at their
+// locked commit or archive, and fails when the content no longer matches
+// the locked checksum. It returns the packs it fetched.
+func RestorePacks(projectRoot string) ([]*LockedPack, error) {
+	lock, err := LoadPackLock(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	var restored []*LockedPack
+	for _, p := range lock.Packs {
+		if _, err := os.Stat(p.Dir()); err == nil {
+			continue
+		}
+		opts := InstallOptions{Name: p.Name, Checksum: p.Checksum, VerifySignature: p.Signed, BaseDir: projectRoot}
+		src := p.PackSource()
+		if p.Kind == PackKindGit {
+			opts.Commit = p.Resolved
+		}
+		fetched, err := FetchPack(src, opts)
+		if err != nil {
+			return restored, fmt.Errorf("restore %s: %w", p.Name, err)
+		}
+		restored = append(restored, fetched)
+	}
+	return restored, nil
+}
+
+// PackUpdate is the result of updating one pack.
+type PackUpdate struct {
+	Old, New *LockedPack
+}
+
+// Changed reports whether the update fetched different content.
+func (u PackUpdate) Changed() bool {
+	return u.Old.Checksum != u.New.Checksum
+}
+
+// UpdatePacks re-fetches the named packs (all when names is empty) from
+// their recorded source, so branches move to their latest commit. A
+// non-empty version switches the named packs to that version; it requires
+// names, since packs rarely share version names.
+func UpdatePacks(projectRoot string, names []string, version string) ([]PackUpdate, error) {
+	if version != "" && len(names) == 0 {
+		return nil, fmt.Errorf("a version applies to named packs; give the pack to switch")
+	}
+	lock, err := LoadPackLock(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	targets := lock.Packs
+	if len(names) > 0 {
+		targets = nil
+		for _, name := range names {
+			p := lock.Get(name)
+			if p == nil {
+				return nil, fmt.Errorf("pack not installed: %s", name)
+			}
+			targets = append(targets, p)
+		}
+	}
+
+	var updates []PackUpdate
+	for _, old := range targets {
+		src := old.PackSource()
+		if version != "" {
+			src.Version = version
+		}
+		pack, err := FetchPack(src, InstallOptions{Name: old.Name, VerifySignature: old.Signed, BaseDir: projectRoot})
+		if err != nil {
+			return updates, fmt.Errorf("update %s: %w", old.Name, err)
+		}
+		if pack.Checksum == old.Checksum {
+			pack.InstalledAt = old.InstalledAt
+		}
+		lock.Put(pack)
+		updates = append(updates, PackUpdate{Old: old, New: pack})
+	}
+	return updates, lock.Save(projectRoot)
+}
+
+// RemovePack drops a pack from the project's lockfile. The cached copy is
+// kept for other projects that lock it.
+func RemovePack(projectRoot, name string) (*LockedPack, error) {
+	lock, err := LoadPackLock(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	pack := lock.Get(name)
+	if pack == nil {
+		return nil, fmt.Errorf("pack not installed: %s", name)
+	}
+	lock.Remove(name)
+	return pack, lock.Save(projectRoot)
+}
+
+// LoadPackSkills loads the skill metadata of the project's locked packs
+// from the cache, setting Skill.Pack. Packs missing from the cache are
+// skipped; RestorePacks fetches them.
+func LoadPackSkills(projectRoot string) ([]*Skill, error) {
+	lock, err := LoadPackLock(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	var skills []*Skill
+	for _, p := range lock.Packs {
+		dir := p.Dir()
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		loaded, err := LoadMetadataFromDirectory(dir, true)
+		if err != nil {
+			continue
+		}
+		for _, s := range loaded {
+			s.Pack = p.Name
+		}
+		skills = append(skills, loaded...)
+	}
+	return skills, nil
+}
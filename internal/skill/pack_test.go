@@ -0,0 +1,259 @@
+package skill
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePackSource(t *testing.T) {
+	cases := map[string]PackSource{
+		"https://github.com/acme/legacy-skills.git@v1.2.0": {URL: "https://github.com/acme/legacy-skills.git", Version: "v1.2.0", Kind: PackKindGit},
+		"git@github.com:acme/legacy-skills.git":            {URL: "git@github.com:acme/legacy-skills.git", Kind: PackKindGit},
+		"git@github.com:acme/legacy-skills.git@main":       {URL: "git@github.com:acme/legacy-skills.git", Version: "main", Kind: PackKindGit},
+		"https://example.com/skills-{version}.tgz@2.0":     {URL: "https://example.com/skills-{version}.tgz", Version: "2.0", Kind: PackKindArchive},
+		"./packs/legacy.tar.gz":                            {URL: "./packs/legacy.tar.gz", Kind: PackKindArchive},
+	}
+	for spec, want := range cases {
+		got, err := ParsePackSource(spec)
+		if err != nil || got != want {
+			t.Errorf("ParsePackSource(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for url, want := range map[string]string{
+		"https://github.com/acme/Legacy-Skills.git": "legacy-skills",
+		"git@github.com:acme/skills.git":            "skills",
+		"/tmp/packs/legacy.tar.gz":                  "legacy",
+	} {
+		if got := defaultPackName(url); got != want {
+			t.Errorf("defaultPackName(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+// writeTarball writes a gzipped tar of files under a top-level directory.
+func writeTarball(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallPack(t *testing.T) {
+	t.Setenv("JIKIME_SKILL_CACHE", t.TempDir())
+	root := t.TempDir()
+	writeSkill(t, root, "go-testing", "---\nname: go-testing\ndescription: Project version\n---\n")
+
+	archive := filepath.Join(t.TempDir(), "legacy-skills-1.0.tgz")
+	writeTarball(t, archive, map[string]string{
+		"legacy-skills/struts/SKILL.md":     "---\nname: struts\ndescription: Struts 1 patterns\n---\n\n# Struts\n",
+		"legacy-skills/go-testing/SKILL.md": "---\nname: go-testing\ndescription: Pack version\n---\n",
+	})
+
+	if _, err := InstallPack(root, archive, InstallOptions{Checksum: "sha256:0000"}); err == nil ||
+		!strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("bad checksum error = %v", err)
+	}
+	pack, err := InstallPack(root, archive+"@1.0", InstallOptions{Name: "legacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Kind != PackKindArchive || pack.Version != "1.0" || !reflect.DeepEqual(pack.Skills, []string{"go-testing", "struts"}) {
+		t.Errorf("pack = %+v", pack)
+	}
+	if _, err := os.Stat(filepath.Join(pack.Dir(), "struts", SkillFileName)); err != nil {
+		t.Errorf("top-level directory not stripped: %v", err)
+	}
+
+	lock, err := LoadPackLock(root)
+	if err != nil || len(lock.Packs) != 1 || lock.Get("legacy").Checksum != pack.Checksum {
+		t.Fatalf("lock = %+v, %v", lock, err)
+	}
+	if src := lock.Get("legacy").Source; filepath.IsAbs(src) || !strings.HasSuffix(src, "/legacy-skills-1.0.tgz") {
+		t.Errorf("local source %q not recorded relative to the project root", src)
+	}
+
+	// Pack skills load with project skills; the project's go-testing wins
+	r := NewRegistry()
+	if err := r.LoadFromProjectRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	if s := r.Get("struts"); s == nil || s.Pack != "legacy" {
+		t.Errorf("struts = %+v", s)
+	}
+	if s := r.Get("go-testing"); s == nil || s.Pack != "" || s.Description != "Project version" {
+		t.Errorf("go-testing = %+v", s)
+	}
+
+	// Restoring refetches a missing pack and checks it against the lock,
+	// resolving the local source against the project root
+	if err := os.RemoveAll(pack.Dir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	if restored, err := RestorePacks(root); err != nil || len(restored) != 1 {
+		t.Fatalf("RestorePacks = %v, %v", restored, err)
+	}
+	os.RemoveAll(pack.Dir())
+	writeTarball(t, archive, map[string]string{"struts/SKILL.md": "---\nname: struts\ndescription: changed\n---\n"})
+	if _, err := RestorePacks(root); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("changed archive restore error = %v", err)
+	}
+
+	if _, err := RemovePack(root, "legacy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RemovePack(root, "legacy"); err == nil {
+		t.Error("removing a missing pack succeeded")
+	}
+}
+
+func TestGitPackUpdate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("JIKIME_SKILL_CACHE", t.TempDir())
+	root := t.TempDir()
+	repo := filepath.Join(t.TempDir(), "legacy-skills")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(description string) {
+		if err := os.WriteFile(filepath.Join(repo, "struts", SkillFileName), []byte("---\nname: struts\ndescription: "+description+"\n---\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", description)
+	}
+	if err := os.MkdirAll(filepath.Join(repo, "struts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "t")
+	git("config", "user.email", "t@t")
+	commit("v1")
+	git("tag", "v1")
+	commit("v2")
+
+	pack, err := InstallPack(root, repo+"@v1", InstallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Name != "legacy-skills" || len(pack.Resolved) != 40 {
+		t.Errorf("pack = %+v", pack)
+	}
+	if _, err := os.Stat(filepath.Join(pack.Dir(), ".git")); !os.IsNotExist(err) {
+		t.Errorf(".git kept in cache: %v", err)
+	}
+
+	// A tag stays put; switching to the branch picks up the new commit
+	updates, err := UpdatePacks(root, nil, "")
+	if err != nil || len(updates) != 1 || updates[0].Changed() {
+		t.Fatalf("UpdatePacks = %+v, %v", updates, err)
+	}
+	updates, err = UpdatePacks(root, []string{"legacy-skills"}, "main")
+	if err != nil || len(updates) != 1 || !updates[0].Changed() {
+		t.Fatalf("UpdatePacks(main) = %+v, %v", updates, err)
+	}
+	r := NewRegistry()
+	if err := r.LoadFromProjectRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	if s := r.Get("struts"); s == nil || s.Description != "v2" {
+		t.Errorf("struts = %+v", s)
+	}
+	if _, err := UpdatePacks(root, []string{"missing"}, ""); err == nil {
+		t.Error("updating a missing pack succeeded")
+	}
+	if _, err := UpdatePacks(root, nil, "v1"); err == nil {
+		t.Error("switching every pack to one version succeeded")
+	}
+}
+
+func TestGitPackDropsSymlinks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("JIKIME_SKILL_CACHE", t.TempDir())
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), SkillFileName)
+	if err := os.WriteFile(outside, []byte("---\nname: outside\ndescription: host file\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(t.TempDir(), "linked")
+	for _, dir := range []string{"real", "linked"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "real", SkillFileName), []byte("---\nname: real\ndescription: d\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(repo, "linked", SkillFileName)); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"}, {"config", "user.name", "t"}, {"config", "user.email", "t@t"},
+		{"add", "-A"}, {"commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	pack, err := InstallPack(root, repo, InstallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pack.Skills, []string{"real"}) {
+		t.Errorf("skills = %v, want the symlinked SKILL.md dropped", pack.Skills)
+	}
+	if _, err := os.Lstat(filepath.Join(pack.Dir(), "linked", SkillFileName)); !os.IsNotExist(err) {
+		t.Errorf("symlink kept in cache: %v", err)
+	}
+
+	// A link planted in the cache is not silently left out of the checksum
+	if err := os.Symlink(outside, filepath.Join(pack.Dir(), "linked", SkillFileName)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DirChecksum(pack.Dir()); err == nil {
+		t.Error("DirChecksum ignored a symlink")
+	}
+}
+
+func TestExtractTarRejectsUnsafePaths(t *testing.T) {
+	t.Setenv("JIKIME_SKILL_CACHE", t.TempDir())
+	archive := filepath.Join(t.TempDir(), "evil.tgz")
+	writeTarball(t, archive, map[string]string{"../evil/SKILL.md": "---\nname: evil\ndescription: d\n---\n"})
+	if _, err := FetchPack(PackSource{URL: archive, Kind: PackKindArchive}, InstallOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("FetchPack error = %v", err)
+	}
+}
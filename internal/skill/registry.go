@@ -22,6 +22,9 @@ type Registry struct {
 	byPhase    map[string][]*Skill // phase -> skills active in that phase
 	byAgent    map[string][]*Skill // agent -> skills used by that agent
 	byLanguage map[string][]*Skill // language -> skills supporting that language
+
+	// Full-text index over skill bodies, built on demand when nil
+	index *SearchIndex
 }
 
 // NewRegistry creates a new empty Registry.
@@ -47,6 +50,7 @@ func (r *Registry) Register(skill *Skill) {
 
 	// Add to primary storage
 	r.skills[skill.Name] = skill
+	r.index = nil
 
 	// Update tag index
 	for _, tag := range skill.Tags {
@@ -150,7 +154,9 @@ func (r *Registry) GetByLanguage(language string) []*Skill {
 	return r.byLanguage[strings.ToLower(language)]
 }
 
-// GetRelated returns skills related to a given skill (sharing tags or triggers).
+// GetRelated returns skills related to a given skill: those it requires or
+// extends and those requiring or extending it first, then skills sharing
+// tags or triggers.
 func (r *Registry) GetRelated(skillName string, maxResults int) []*Skill {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -163,6 +169,18 @@ func (r *Registry) GetRelated(skillName string, maxResults int) []*Skill {
 	// Score skills by relationship strength
 	scores := make(map[string]int)
 
+	// Score by declared composition (strongest relationship)
+	for _, name := range dependencies(skill) {
+		if r.skills[name] != nil && name != skillName {
+			scores[name] += 5
+		}
+	}
+	for name, other := range r.skills {
+		if name != skillName && containsName(dependencies(other), skillName) {
+			scores[name] += 5
+		}
+	}
+
 	// Score by shared tags
 	for _, tag := range skill.Tags {
 		for _, related := range r.byTag[tag] {
@@ -275,20 +293,25 @@ func (r *Registry) AllLanguages() []string {
 	return languages
 }
 
-// LoadFromProjectRoot loads all skills from a project's skill directories.
-// It searches both .claude/skills (for initialized projects) and
-// templates/.claude/skills (for development/build environment).
-func (r *Registry) LoadFromProjectRoot(projectRoot string) error {
-	// Define paths to search for skills
-	skillPaths := []string{
+// SkillDirs returns the skill directories of a project, in load order:
+// .claude/skills and templates/.claude/skills.
+func SkillDirs(projectRoot string) []string {
+	return []string{
 		filepath.Join(projectRoot, ".claude", "skills"),
 		filepath.Join(projectRoot, "templates", ".claude", "skills"),
 	}
+}
 
+// LoadFromProjectRoot loads all skills from a project's skill directories.
+// It searches both .claude/skills (for initialized projects) and
+// templates/.claude/skills (for development/build environment), adds the
+// skills of installed packs (LockFile) whose names are not taken, then
+// loads or rebuilds the full-text index (IndexFile).
+func (r *Registry) LoadFromProjectRoot(projectRoot string) error {
 	var foundAny bool
 	var lastErr error
 
-	for _, skillsDir := range skillPaths {
+	for _, skillsDir := range SkillDirs(projectRoot) {
 		// Check if directory exists
 		info, err := os.Stat(skillsDir)
 		if err != nil || !info.IsDir() {
@@ -308,13 +331,50 @@ func (r *Registry) LoadFromProjectRoot(projectRoot string) error {
 		}
 	}
 
+	// Installed packs fill in around project skills, which win on name clashes
+	if packSkills, err := LoadPackSkills(projectRoot); err != nil {
+		lastErr = err
+	} else {
+		for _, s := range packSkills {
+			if r.Get(s.Name) == nil {
+				r.Register(s)
+				foundAny = true
+			}
+		}
+	}
+
 	if !foundAny && lastErr != nil {
 		return lastErr
 	}
 
+	if foundAny {
+		// A stale or unwritable cache only costs a rebuild next time
+		idx, _ := LoadIndex(projectRoot, r.All())
+		r.mu.Lock()
+		r.index = idx
+		r.mu.Unlock()
+	}
+
 	return nil
 }
 
+// SearchText ranks skills by BM25 relevance of their bodies and metadata
+// to query, returning each skill's best section with a snippet.
+func (r *Registry) SearchText(query string, limit int) []*TextResult {
+	r.mu.Lock()
+	if r.index == nil {
+		skills := make([]*Skill, 0, len(r.skills))
+		for _, s := range r.skills {
+			skills = append(skills, s)
+		}
+		r.index = BuildIndex(skills)
+	}
+	idx := r.index
+	r.mu.Unlock()
+
+	return idx.Search(query, limit, r.Get)
+}
+
 // Clear removes all skills and indexes from the registry.
 func (r *Registry) Clear() {
 	r.mu.Lock()
@@ -326,6 +386,7 @@ func (r *Registry) Clear() {
 	r.byPhase = make(map[string][]*Skill)
 	r.byAgent = make(map[string][]*Skill)
 	r.byLanguage = make(map[string][]*Skill)
+	r.index = nil
 }
 
 // DefaultRegistry is the global default registry.
@@ -0,0 +1,247 @@
+// Package skill provides JSON Schema validation of SKILL.md front-matter.
+package skill
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaJSON is the JSON Schema for SKILL.md front-matter.
+//
+//go:embed skill.schema.json
+var SchemaJSON []byte
+
+// schema is the subset of JSON Schema the front-matter schema uses: type,
+// required, properties, additionalProperties, items, enum, pattern,
+// minLength, maxLength, uniqueItems and local $ref.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaTypes        `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	UniqueItems          bool               `json:"uniqueItems"`
+	Defs                 map[string]*schema `json:"$defs"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes is "type" as a single name or a list of names.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var one string
+	if json.Unmarshal(data, &one) == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// SchemaViolation is a front-matter value that does not match the schema.
+// Unknown is set for properties the schema does not define, which are
+// usually typos ("trigger:" for "triggers:").
+type SchemaViolation struct {
+	Path    string // e.g. "triggers.keywords[2]"
+	Line    int    // line within the front-matter
+	Message string
+	Unknown bool
+}
+
+var frontMatterSchema = mustLoadSchema(SchemaJSON)
+
+func mustLoadSchema(data []byte) *schema {
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		panic(fmt.Sprintf("skill: invalid schema: %v", err))
+	}
+	s.compile()
+	return &s
+}
+
+// compile prepares the patterns of s and its subschemas.
+func (s *schema) compile() {
+	if s.Pattern != "" {
+		s.pattern = regexp.MustCompile(s.Pattern)
+	}
+	for _, sub := range s.Properties {
+		sub.compile()
+	}
+	for _, sub := range s.Defs {
+		sub.compile()
+	}
+	if s.Items != nil {
+		s.Items.compile()
+	}
+}
+
+// ValidateFrontMatter checks a parsed front-matter document against
+// SchemaJSON.
+func ValidateFrontMatter(doc *yaml.Node) []SchemaViolation {
+	if doc.Kind == yaml.DocumentNode || doc.Kind == 0 {
+		if len(doc.Content) == 0 {
+			// Empty front-matter is an empty mapping
+			doc = &yaml.Node{Kind: yaml.MappingNode, Line: 1}
+		} else {
+			doc = doc.Content[0]
+		}
+	}
+	var out []SchemaViolation
+	frontMatterSchema.validate(frontMatterSchema, doc, "", &out)
+	return out
+}
+
+func (s *schema) resolve(root *schema) *schema {
+	if name, ok := strings.CutPrefix(s.Ref, "#/$defs/"); ok {
+		if def := root.Defs[name]; def != nil {
+			return def.resolve(root)
+		}
+	}
+	return s
+}
+
+func (s *schema) validate(root *schema, node *yaml.Node, path string, out *[]SchemaViolation) {
+	s = s.resolve(root)
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	fail := func(format string, args ...any) {
+		*out = append(*out, SchemaViolation{Path: displayPath(path), Line: node.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	kind := nodeType(node)
+	if len(s.Type) > 0 && !typeAllowed(s.Type, kind) {
+		fail("%s must be %s, got %s", displayPath(path), strings.Join(s.Type, " or "), kind)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			seen[key.Value] = true
+			if prop := s.Properties[key.Value]; prop != nil {
+				prop.validate(root, value, joinPath(path, key.Value), out)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*out = append(*out, SchemaViolation{Path: displayPath(joinPath(path, key.Value)), Line: key.Line,
+					Message: fmt.Sprintf("unknown property %q", key.Value), Unknown: true})
+			}
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				fail("missing required property %q", name)
+			}
+		}
+
+	case yaml.SequenceNode:
+		values := map[string]bool{}
+		for i, item := range node.Content {
+			if s.Items != nil {
+				s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+			if s.UniqueItems && item.Kind == yaml.ScalarNode {
+				if values[item.Value] {
+					*out = append(*out, SchemaViolation{Path: displayPath(fmt.Sprintf("%s[%d]", path, i)), Line: item.Line,
+						Message: fmt.Sprintf("duplicate item %q in %s", item.Value, displayPath(path))})
+				}
+				values[item.Value] = true
+			}
+		}
+
+	case yaml.ScalarNode:
+		if len(s.Enum) > 0 && !enumContains(s.Enum, node.Value) {
+			fail("%s must be one of %s, got %q", displayPath(path), enumList(s.Enum), node.Value)
+		}
+		if kind != "string" {
+			return
+		}
+		n := utf8.RuneCountInString(node.Value)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("%s must not be empty", displayPath(path))
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("%s is %d characters, the maximum is %d", displayPath(path), n, *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(node.Value) {
+			fail("%s %q does not match %s", displayPath(path), node.Value, s.Pattern)
+		}
+	}
+}
+
+// nodeType returns the JSON type of a YAML node.
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func typeAllowed(allowed []string, kind string) bool {
+	for _, t := range allowed {
+		if t == kind || (t == "number" && kind == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func enumContains(enum []any, value string) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = fmt.Sprintf("%q", fmt.Sprint(e))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "front-matter"
+	}
+	return path
+}
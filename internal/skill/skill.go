@@ -2,6 +2,8 @@
 // Implements tag-based skill discovery for JikiME-ADK.
 package skill
 
+import "strings"
+
 // Version of the Skill system.
 const Version = "1.0.0"
 
@@ -19,11 +21,17 @@ type Skill struct {
 	UserInvocable  bool     `yaml:"user-invocable,omitempty"` // whether user can invoke directly
 	Context        string   `yaml:"context,omitempty"`        // context type (e.g., "fork")
 	Agent          string   `yaml:"agent,omitempty"`          // agent name
-	AllowedTools   []string `yaml:"allowed-tools,omitempty"`  // allowed tools list
+	AllowedTools   NameList `yaml:"allowed-tools,omitempty"`  // allowed tools list
+
+	// Composition (see compose.go)
+	Requires  NameList `yaml:"requires,omitempty"`  // skills loaded together with this one
+	Extends   NameList `yaml:"extends,omitempty"`   // base skills this one specializes
+	Conflicts NameList `yaml:"conflicts,omitempty"` // skills never loaded with this one
 
 	// Internal fields (not from YAML)
 	FilePath string `yaml:"-"` // Path to SKILL.md file
 	Body     string `yaml:"-"` // Markdown body content (Level 2)
+	Pack     string `yaml:"-"` // Installed pack the skill comes from (see pack.go)
 }
 
 // Triggers defines conditions for loading a skill.
@@ -99,10 +107,11 @@ func (s *Skill) HasAgent(agent string) bool {
 	return false
 }
 
-// HasLanguage checks if the skill supports a specific language.
+// HasLanguage checks if the skill supports a specific language, ignoring
+// case as the registry's language index does.
 func (s *Skill) HasLanguage(language string) bool {
 	for _, l := range s.Triggers.Languages {
-		if l == language {
+		if strings.EqualFold(l, language) {
 			return true
 		}
 	}
@@ -154,6 +163,9 @@ func (s *Skill) ToMap() map[string]interface{} {
 			"agents":    s.Triggers.Agents,
 			"languages": s.Triggers.Languages,
 		},
+		"requires":  s.Requires,
+		"extends":   s.Extends,
+		"conflicts": s.Conflicts,
 		"file_path": s.FilePath,
 	}
 }
@@ -0,0 +1,281 @@
+// Package skill provides prompt-driven skill suggestion for jikime-adk.
+package skill
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultTokenBudget is the default number of tokens SuggestionContext may
+// spend on skill bodies and pointers.
+const DefaultTokenBudget = 2000
+
+// Scores added to MatchInput's keyword score for context triggers.
+const (
+	phaseBonus    = 25
+	agentBonus    = 25
+	languageBonus = 10
+)
+
+// PromptContext is what is known about a submitted prompt: its text and
+// the phase, agents and languages it applies to.
+type PromptContext struct {
+	Prompt    string
+	Phases    []string
+	Agents    []string
+	Languages []string
+}
+
+// Suggestion is a skill ranked for a prompt with the triggers it matched.
+type Suggestion struct {
+	Skill   *Skill
+	Score   float64
+	Matched []string // e.g. "keyword", "phase:run", "agent:backend", "language:go"
+}
+
+// Suggest ranks the skills relevant to pc: MatchInput scores the prompt
+// against trigger keywords, and FindByTriggers adds skills triggered by the
+// phase or agents. Languages only boost skills already selected, since
+// nearly every prompt in a project shares its language. At most limit
+// suggestions are returned (0 = unlimited).
+func (r *Registry) Suggest(pc PromptContext, limit int) []*Suggestion {
+	byName := make(map[string]*Suggestion)
+	for _, res := range r.MatchInput(pc.Prompt) {
+		byName[res.Skill.Name] = &Suggestion{Skill: res.Skill, Score: res.Score, Matched: []string{"keyword"}}
+	}
+	for _, s := range r.FindByTriggers(nil, pc.Phases, pc.Agents, nil) {
+		if byName[s.Name] == nil {
+			byName[s.Name] = &Suggestion{Skill: s}
+		}
+	}
+
+	results := make([]*Suggestion, 0, len(byName))
+	for _, sug := range byName {
+		for _, phase := range pc.Phases {
+			if sug.Skill.HasPhase(phase) {
+				sug.Score += phaseBonus
+				sug.Matched = append(sug.Matched, "phase:"+phase)
+			}
+		}
+		for _, agent := range pc.Agents {
+			if sug.Skill.HasAgent(agent) {
+				sug.Score += agentBonus
+				sug.Matched = append(sug.Matched, "agent:"+agent)
+			}
+		}
+		for _, lang := range pc.Languages {
+			if sug.Skill.HasLanguage(lang) {
+				sug.Score += languageBonus
+				sug.Matched = append(sug.Matched, "language:"+strings.ToLower(lang))
+			}
+		}
+		results = append(results, sug)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Skill.Name < results[j].Skill.Name
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Compose adds the skills that suggestions extend or require, each placed
+// before the first suggestion needing it. A suggestion whose composed set
+// conflicts with skills already chosen is dropped; one that cannot be
+// resolved (a missing dependency or a cycle) is kept on its own.
+func (r *Registry) Compose(suggestions []*Suggestion) []*Suggestion {
+	var out []*Suggestion
+	chosen := make(map[string]*Skill)
+
+	for _, sug := range suggestions {
+		if chosen[sug.Skill.Name] != nil {
+			continue
+		}
+		set, err := r.Resolve(sug.Skill.Name)
+		if err != nil {
+			set = []*ResolvedSkill{{Skill: sug.Skill, Reason: "requested"}}
+		}
+		if conflictsWithChosen(set, chosen) {
+			continue
+		}
+		for _, rs := range set {
+			if chosen[rs.Skill.Name] != nil {
+				continue
+			}
+			chosen[rs.Skill.Name] = rs.Skill
+			if rs.Skill.Name == sug.Skill.Name {
+				out = append(out, sug)
+			} else {
+				out = append(out, &Suggestion{Skill: rs.Skill, Score: sug.Score, Matched: []string{rs.Reason}})
+			}
+		}
+	}
+	return out
+}
+
+func conflictsWithChosen(set []*ResolvedSkill, chosen map[string]*Skill) bool {
+	for _, rs := range set {
+		for _, other := range rs.Skill.Conflicts {
+			if chosen[other] != nil {
+				return true
+			}
+		}
+		for _, c := range chosen {
+			if containsName(c.Conflicts, rs.Skill.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EstimateTokens approximates the token count of text (about 4 bytes per
+// token for English prose and code).
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// SuggestionContext renders suggestions as prompt context within budget
+// tokens. Skills are taken in rank order; each is inlined with its body
+// while it fits, otherwise listed as a pointer to its SKILL.md. Rendering
+// stops when not even a pointer fits. It returns the context, "" when
+// nothing fits, and the names of the skills included.
+func SuggestionContext(suggestions []*Suggestion, budget int) (string, []string) {
+	var inlined, pointers, names []string
+	used := EstimateTokens(suggestionHeader) + EstimateTokens(pointerHeader)
+
+	for _, sug := range suggestions {
+		s := sug.Skill
+		if body := skillBody(s); body != "" {
+			block := fmt.Sprintf("## Skill: %s\n\n%s\n", s.Name, body)
+			if cost := EstimateTokens(block); used+cost <= budget {
+				inlined = append(inlined, block)
+				names = append(names, s.Name)
+				used += cost
+				continue
+			}
+		}
+		line := "- " + s.Name
+		if s.Description != "" {
+			line += ": " + s.Description
+		}
+		if s.FilePath != "" {
+			line += " (" + s.FilePath + ")"
+		}
+		cost := EstimateTokens(line) + 1
+		if used+cost > budget {
+			break
+		}
+		pointers = append(pointers, line)
+		names = append(names, s.Name)
+		used += cost
+	}
+
+	if len(names) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	b.WriteString(suggestionHeader)
+	for _, block := range inlined {
+		b.WriteString("\n")
+		b.WriteString(block)
+	}
+	if len(pointers) > 0 {
+		b.WriteString("\n")
+		b.WriteString(pointerHeader)
+		b.WriteString(strings.Join(pointers, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String(), names
+}
+
+const (
+	suggestionHeader = "# Relevant skills\n\nSkills matched to this request by their triggers.\n"
+	pointerHeader    = "Also relevant (read the SKILL.md when needed):\n"
+)
+
+// skillBody returns the skill's body, reading it from FilePath when the
+// skill was loaded with metadata only.
+func skillBody(s *Skill) string {
+	if s.Body != "" || s.FilePath == "" {
+		return strings.TrimSpace(s.Body)
+	}
+	full, err := LoadFromFile(s.FilePath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(full.Body)
+}
+
+// phaseCommandPattern matches the workflow commands /jikime:1-plan,
+// /jikime:2-run and /jikime:3-sync.
+var phaseCommandPattern = regexp.MustCompile(`/jikime:\d-(plan|run|sync)\b`)
+
+// DetectPhase returns the workflow phase a prompt invokes, or "".
+func DetectPhase(prompt string) string {
+	if m := phaseCommandPattern.FindStringSubmatch(strings.ToLower(prompt)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// languageMarkers maps project files to the language they indicate.
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "go"},
+	{"tsconfig.json", "typescript"},
+	{"package.json", "javascript"},
+	{"pyproject.toml", "python"},
+	{"requirements.txt", "python"},
+	{"Cargo.toml", "rust"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"build.gradle.kts", "kotlin"},
+	{"Gemfile", "ruby"},
+	{"composer.json", "php"},
+	{"pubspec.yaml", "dart"},
+	{"Package.swift", "swift"},
+}
+
+// DetectLanguages returns the languages of the project at root, judged by
+// its marker files (go.mod, package.json, ...).
+func DetectLanguages(root string) []string {
+	var langs []string
+	seen := make(map[string]bool)
+	for _, m := range languageMarkers {
+		if seen[m.language] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, m.file)); err == nil {
+			langs = append(langs, m.language)
+			seen[m.language] = true
+		}
+	}
+	return langs
+}
+
+// DetectAgents returns the registry's trigger agents that a prompt names,
+// as "@agent-backend", "backend agent" or "agent backend".
+func (r *Registry) DetectAgents(prompt string) []string {
+	lower := strings.ToLower(prompt)
+	var agents []string
+	for _, agent := range r.AllAgents() {
+		a := regexp.QuoteMeta(strings.ToLower(agent))
+		re := regexp.MustCompile(`(?:@agent-` + a + `\b|\b` + a + `\s+(?:sub-?)?agent\b|\bagent\s+` + a + `\b)`)
+		if re.MatchString(lower) {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
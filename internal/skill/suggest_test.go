@@ -0,0 +1,115 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func suggestRegistry() *Registry {
+	r := NewRegistry()
+	r.RegisterAll([]*Skill{
+		{Name: "go-testing", Description: "Table-driven Go tests", Body: "Use t.Run.",
+			Triggers: Triggers{Keywords: []string{"test", "coverage"}, Languages: []string{"go"}}},
+		{Name: "py-testing", Description: "pytest fixtures", Body: "Use fixtures.",
+			Triggers: Triggers{Keywords: []string{"test"}, Languages: []string{"python"}}},
+		{Name: "spec-writing", Description: "EARS requirements", Body: "Write EARS.",
+			Triggers: Triggers{Phases: []string{"plan"}}},
+		{Name: "api-design", Description: "REST conventions", Body: "Use nouns.",
+			Triggers: Triggers{Agents: []string{"backend"}}},
+		{Name: "go-style", Description: "Go style", Body: "gofmt.",
+			Triggers: Triggers{Languages: []string{"go"}}},
+	})
+	return r
+}
+
+func TestSuggest(t *testing.T) {
+	r := suggestRegistry()
+	names := func(sugs []*Suggestion) []string {
+		var out []string
+		for _, s := range sugs {
+			out = append(out, s.Skill.Name)
+		}
+		return out
+	}
+
+	// Language breaks the keyword tie; a language-only skill is not added.
+	got := r.Suggest(PromptContext{Prompt: "add a test", Languages: []string{"Go"}}, 0)
+	if want := []string{"go-testing", "py-testing"}; !reflect.DeepEqual(names(got), want) {
+		t.Errorf("keyword suggestions = %v, want %v", names(got), want)
+	}
+	if want := []string{"keyword", "language:go"}; !reflect.DeepEqual(got[0].Matched, want) {
+		t.Errorf("matched = %v, want %v", got[0].Matched, want)
+	}
+
+	pc := PromptContext{
+		Prompt: "/jikime:1-plan orders endpoint for the @agent-backend",
+		Phases: []string{DetectPhase("/jikime:1-plan orders endpoint")},
+		Agents: r.DetectAgents("/jikime:1-plan orders endpoint for the @agent-backend"),
+	}
+	if pc.Phases[0] != "plan" || !reflect.DeepEqual(pc.Agents, []string{"backend"}) {
+		t.Fatalf("detected phase %v, agents %v", pc.Phases, pc.Agents)
+	}
+	if got := names(r.Suggest(pc, 1)); !reflect.DeepEqual(got, []string{"api-design"}) {
+		t.Errorf("limited suggestions = %v", got)
+	}
+	if got := names(r.Suggest(PromptContext{Prompt: "hello"}, 0)); got != nil {
+		t.Errorf("unrelated prompt suggestions = %v", got)
+	}
+}
+
+func TestSuggestionContext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "SKILL.md")
+	body := strings.Repeat("Check every error. ", 40)
+	if err := os.WriteFile(path, []byte("---\nname: errors\ndescription: Error handling\n---\n\n"+body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := LoadMetadataOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sugs := []*Suggestion{
+		{Skill: &Skill{Name: "small", Description: "Small skill", Body: "Short body."}},
+		{Skill: meta},
+		{Skill: &Skill{Name: "last", Description: "Never fits"}},
+	}
+
+	// The whole body fits a large budget, even when loaded lazily.
+	ctx, included := SuggestionContext(sugs, 1000)
+	if !strings.Contains(ctx, "## Skill: errors") || !strings.Contains(ctx, "Check every error.") ||
+		!reflect.DeepEqual(included, []string{"small", "errors", "last"}) {
+		t.Errorf("large budget context = %q, included %v", ctx, included)
+	}
+
+	// A small budget inlines the short body and points to the long one.
+	ctx, included = SuggestionContext(sugs, 60)
+	if !strings.Contains(ctx, "Short body.") || strings.Contains(ctx, "Check every error.") ||
+		!strings.Contains(ctx, "- errors: Error handling ("+path+")") {
+		t.Errorf("small budget context = %q", ctx)
+	}
+	if !reflect.DeepEqual(included, []string{"small", "errors"}) {
+		t.Errorf("small budget included %v", included)
+	}
+	if EstimateTokens(ctx) > 60 {
+		t.Errorf("context uses %d tokens, budget 60", EstimateTokens(ctx))
+	}
+
+	if ctx, included = SuggestionContext(sugs, 5); ctx != "" || included != nil {
+		t.Errorf("tiny budget context = %q, included %v", ctx, included)
+	}
+}
+
+func TestDetectLanguages(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"package.json", "tsconfig.json", "requirements.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := DetectLanguages(dir), []string{"typescript", "javascript", "python"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectLanguages = %v, want %v", got, want)
+	}
+}
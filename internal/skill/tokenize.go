@@ -0,0 +1,148 @@
+// Package skill provides text tokenization for the skill search index.
+package skill
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into search terms. Latin-script words are
+// lowercased, stripped of stop words and stemmed, so "hydrated" and
+// "hydration" share a term. Korean, Japanese and Chinese text has no
+// reliable word boundaries, so each run of those scripts becomes
+// overlapping character bigrams ("불일치" → "불일", "일치").
+func Tokenize(text string) []string {
+	var terms []string
+	var word, cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			if w := string(word); !stopWords[w] {
+				terms = append(terms, Stem(w))
+			}
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+		case 1:
+			terms = append(terms, string(cjk))
+		default:
+			for i := 0; i+1 < len(cjk); i++ {
+				terms = append(terms, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return terms
+}
+
+func isCJK(r rune) bool {
+	// U+30FC, the katakana long vowel mark, is in the Common script
+	return r == 'ー' || unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// stopWords are common English words that carry no meaning for search.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "how": true, "in": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "what": true,
+	"when": true, "with": true, "you": true, "your": true,
+}
+
+// Stem reduces an English word to a search stem by removing plural,
+// verb and common derivational endings. It is a light Porter-style
+// stemmer: stems need not be words, only consistent ("caching", "cached"
+// and "cache" all become "cach").
+func Stem(word string) string {
+	if len(word) <= 3 || !isASCIIWord(word) {
+		return word
+	}
+
+	// Plurals
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+
+	// Verb endings, when a vowel remains: "rendering", "rendered"
+	for _, suffix := range []string{"ing", "ed"} {
+		if stem := strings.TrimSuffix(word, suffix); stem != word && len(stem) >= 3 && hasVowel(stem) {
+			word = stem
+			// "running" → "run", but "falling" keeps its "ll"
+			if n := len(word); n >= 2 && word[n-1] == word[n-2] && !strings.ContainsRune("lsz", rune(word[n-1])) && !isVowel(word[n-1]) {
+				word = word[:n-1]
+			}
+			break
+		}
+	}
+
+	// Derivational endings
+	for _, r := range []struct{ suffix, replace string }{
+		{"ization", "ize"},
+		{"ational", "ate"},
+		{"ation", "ate"},
+		{"fulness", "ful"},
+		{"iveness", "ive"},
+		{"ousness", "ous"},
+		{"ness", ""},
+		{"ment", ""},
+		{"ly", ""},
+	} {
+		if stem := strings.TrimSuffix(word, r.suffix); stem != word && len(stem) >= 4 {
+			word = stem + r.replace
+			break
+		}
+	}
+
+	// Final "e": "hydrate" → "hydrat", matching "hydrated"
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+func isASCIIWord(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiouy", c) >= 0
+}
+
+func hasVowel(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if isVowel(s[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package tag
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TagFix is a malformed TAG comment, such as "@spec spec-auth-1 Impl",
+// that the parser would skip or misread. Fixed is the corrected comment
+// text, or empty when it cannot be repaired automatically.
+type TagFix struct {
+	FilePath string   `json:"file_path"`
+	Line     int      `json:"line"`
+	Original string   `json:"original"`
+	Fixed    string   `json:"fixed,omitempty"`
+	Problems []string `json:"problems"`
+}
+
+// Fixable reports whether the TAG can be rewritten automatically.
+func (f TagFix) Fixable() bool {
+	return f.Fixed != ""
+}
+
+// verbAliases maps common misspellings of verbs to the verb meant. An
+// alias is only rewritten when it is the last word of the TAG, so prose
+// such as "@SPEC SPEC-AUTH-001 implementation details" is left alone.
+var verbAliases = map[string]string{
+	"implements":     "impl",
+	"implement":      "impl",
+	"implementation": "impl",
+	"test":           "verify",
+	"tests":          "verify",
+	"verifies":       "verify",
+	"depend":         "depends",
+	"depends-on":     "depends",
+	"relates":        "related",
+}
+
+var looseSpecIDPattern = regexp.MustCompile(`^(?:SPEC-)?([A-Z0-9]+)-(\d{1,3})$`)
+
+// FindMalformedTags returns the TAG comments in source that are not in
+// canonical form: a marker other than "@SPEC", a SPEC-ID that is not
+// SPEC-{DOMAIN}-{NNN}, or a verb that is not lowercase. A third word that
+// is not a verb or a known alias is treated as prose, as the parser does.
+func FindMalformedTags(source, filePath string) []TagFix {
+	var fixes []TagFix
+	for _, c := range LexComments(source, SyntaxForFile(filePath)) {
+		if fix, ok := checkTagText(c.Text); ok {
+			fix.FilePath, fix.Line = filePath, c.Line
+			fixes = append(fixes, fix)
+		}
+	}
+	return fixes
+}
+
+func checkTagText(text string) (TagFix, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "@SPEC") {
+		return TagFix{}, false
+	}
+	fix := TagFix{Original: text}
+	replace := map[string]string{}
+
+	if fields[0] != "@SPEC" {
+		fix.Problems = append(fix.Problems, fmt.Sprintf("marker %q should be @SPEC", fields[0]))
+		replace[fields[0]] = "@SPEC"
+	}
+	if len(fields) < 2 {
+		fix.Problems = append(fix.Problems, "missing SPEC-ID")
+		return fix, true
+	}
+
+	id := fields[1]
+	fixable := true
+	if !ValidateSpecIDFormat(id) {
+		if normalized, ok := normalizeSpecID(id); ok {
+			fix.Problems = append(fix.Problems, fmt.Sprintf("SPEC-ID %q should be %s", id, normalized))
+			replace[id] = normalized
+		} else {
+			fix.Problems = append(fix.Problems, fmt.Sprintf("invalid SPEC-ID %q (expected SPEC-{DOMAIN}-{NNN})", id))
+			fixable = false
+		}
+	}
+
+	if len(fields) > 2 && !ValidateVerb(fields[2]) {
+		verb := strings.ToLower(fields[2])
+		if alias, ok := verbAliases[verb]; ok && len(fields) == 3 {
+			verb = alias
+		}
+		if ValidateVerb(verb) {
+			fix.Problems = append(fix.Problems, fmt.Sprintf("verb %q should be %s", fields[2], verb))
+			replace[fields[2]] = verb
+		}
+	}
+
+	if len(fix.Problems) == 0 {
+		return TagFix{}, false
+	}
+	if fixable {
+		fix.Fixed = replaceFields(text, fields[:min(len(fields), 3)], replace)
+	}
+	return fix, true
+}
+
+// normalizeSpecID repairs case, separators, a missing "SPEC-" prefix and
+// unpadded numbers: "spec_auth_1" and "AUTH-1" become "SPEC-AUTH-001".
+func normalizeSpecID(id string) (string, bool) {
+	up := strings.ReplaceAll(strings.ToUpper(id), "_", "-")
+	m := looseSpecIDPattern.FindStringSubmatch(up)
+	if m == nil {
+		return "", false
+	}
+	n, _ := strconv.Atoi(m[2])
+	normalized := fmt.Sprintf("SPEC-%s-%03d", m[1], n)
+	return normalized, ValidateSpecIDFormat(normalized)
+}
+
+// replaceFields rewrites the leading fields of text in order, keeping the
+// spacing and anything after them.
+func replaceFields(text string, fields []string, replace map[string]string) string {
+	var b strings.Builder
+	pos := 0
+	for _, f := range fields {
+		i := strings.Index(text[pos:], f)
+		if i < 0 {
+			break
+		}
+		b.WriteString(text[pos : pos+i])
+		if r, ok := replace[f]; ok {
+			b.WriteString(r)
+		} else {
+			b.WriteString(f)
+		}
+		pos += i + len(f)
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// FixTags rewrites every fixable malformed TAG in source and returns the
+// new source with all malformed TAGs found (fixed or not).
+func FixTags(source, filePath string) (string, []TagFix) {
+	fixes := FindMalformedTags(source, filePath)
+	if len(fixes) == 0 {
+		return source, nil
+	}
+	lines := strings.SplitAfter(source, "\n")
+	for _, f := range fixes {
+		if !f.Fixable() || f.Line < 1 || f.Line > len(lines) {
+			continue
+		}
+		lines[f.Line-1] = strings.Replace(lines[f.Line-1], f.Original, f.Fixed, 1)
+	}
+	return strings.Join(lines, ""), fixes
+}
+
+// FixTagsInFile applies FixTags to a file, writing it with
+// AtomicWriteText (keeping its permissions) when anything changed.
+func FixTagsInFile(filePath string) ([]TagFix, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	fixed, fixes := FixTags(string(content), filePath)
+	if fixed == string(content) {
+		return fixes, nil
+	}
+	if err := AtomicWriteText(filePath, fixed); err != nil {
+		return fixes, err
+	}
+	return fixes, os.Chmod(filePath, info.Mode().Perm())
+}
@@ -0,0 +1,308 @@
+package tag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GraphFormat selects how a Graph or Impact is rendered.
+type GraphFormat string
+
+const (
+	GraphText GraphFormat = "text"
+	GraphJSON GraphFormat = "json"
+	GraphDOT  GraphFormat = "dot"
+)
+
+// ParseGraphFormat accepts "text", "json" and "dot".
+func ParseGraphFormat(s string) (GraphFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "text", "txt":
+		return GraphText, nil
+	case "json":
+		return GraphJSON, nil
+	case "dot", "gv":
+		return GraphDOT, nil
+	}
+	return "", fmt.Errorf("tag/graph: unknown format %q (want text, json or dot)", s)
+}
+
+// SpecNode is a SPEC in the relationship graph with its edges to other
+// SPECs and to the files that implement and verify it.
+type SpecNode struct {
+	SpecID     string     `json:"spec_id"`
+	Title      string     `json:"title,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Documented bool       `json:"documented"` // has a SPEC directory
+	Depends    []string   `json:"depends"`
+	Dependents []string   `json:"dependents"` // SPECs that depend on this one
+	Related    []string   `json:"related"`
+	Impl       []Location `json:"impl"`
+	Verify     []Location `json:"verify"`
+	Acceptance []string   `json:"acceptance_criteria"`
+}
+
+// Graph relates SPECs to each other and to files. SPEC-to-SPEC edges come
+// from spec.md front-matter (depends/related) and from TAGs: a depends or
+// related TAG in a file that implements or verifies SPEC X links X to the
+// referenced SPEC. related is symmetric; depends is directed.
+type Graph struct {
+	Root  string      `json:"root"`
+	Specs []*SpecNode `json:"specs"`
+
+	specsDir string
+	nodes    map[string]*SpecNode
+	files    map[string][]*TAG // absolute file path -> TAGs in it
+	previous map[string][]*TAG // changed path -> TAGs it had before the change
+	deleted  map[string]bool   // changed paths that no longer exist
+}
+
+// BuildGraph builds the relationship graph from SPEC documents in
+// specsDir and TAGs. File paths are shown relative to root.
+func BuildGraph(root, specsDir string, specs []*SpecDocument, tags []*TAG) *Graph {
+	g := &Graph{Root: root, specsDir: specsDir, nodes: map[string]*SpecNode{}, files: map[string][]*TAG{}, deleted: map[string]bool{}}
+	for _, doc := range specs {
+		n := g.node(doc.ID)
+		n.Title, n.Status, n.Documented, n.Acceptance = doc.Title, doc.Status, true, doc.Acceptance
+		for _, dep := range doc.Depends {
+			g.addDepends(doc.ID, dep)
+		}
+		for _, rel := range doc.Related {
+			g.addRelated(doc.ID, rel)
+		}
+	}
+
+	for _, t := range tags {
+		path := filepath.Clean(t.FilePath)
+		g.files[path] = append(g.files[path], t)
+		n := g.node(t.SpecID)
+		loc := Location{File: relPath(root, t.FilePath), Line: t.Line}
+		switch t.Verb {
+		case "verify":
+			n.Verify = append(n.Verify, loc)
+		case "impl":
+			n.Impl = append(n.Impl, loc)
+		}
+	}
+	for _, fileTags := range g.files {
+		for _, owner := range fileTags {
+			if owner.Verb != "impl" && owner.Verb != "verify" {
+				continue
+			}
+			for _, t := range fileTags {
+				switch t.Verb {
+				case "depends":
+					g.addDepends(owner.SpecID, t.SpecID)
+				case "related":
+					g.addRelated(owner.SpecID, t.SpecID)
+				}
+			}
+		}
+	}
+
+	for _, n := range g.nodes {
+		for _, list := range []*[]string{&n.Depends, &n.Dependents, &n.Related} {
+			*list = uniqueSorted(*list)
+		}
+		sortLocations(n.Impl)
+		sortLocations(n.Verify)
+		g.Specs = append(g.Specs, n)
+	}
+	sort.Slice(g.Specs, func(i, j int) bool { return g.Specs[i].SpecID < g.Specs[j].SpecID })
+	return g
+}
+
+func (g *Graph) node(specID string) *SpecNode {
+	n := g.nodes[specID]
+	if n == nil {
+		n = &SpecNode{SpecID: specID, Depends: []string{}, Dependents: []string{}, Related: []string{},
+			Impl: []Location{}, Verify: []Location{}, Acceptance: []string{}}
+		g.nodes[specID] = n
+	}
+	return n
+}
+
+func (g *Graph) addDepends(from, to string) {
+	if from == to {
+		return
+	}
+	g.node(from).Depends = append(g.node(from).Depends, to)
+	g.node(to).Dependents = append(g.node(to).Dependents, from)
+}
+
+func (g *Graph) addRelated(a, b string) {
+	if a == b {
+		return
+	}
+	g.node(a).Related = append(g.node(a).Related, b)
+	g.node(b).Related = append(g.node(b).Related, a)
+}
+
+// Node returns a SPEC in the graph, or nil.
+func (g *Graph) Node(specID string) *SpecNode {
+	return g.nodes[specID]
+}
+
+// DependencyCycles returns each depends cycle once, as the SPEC-IDs along
+// it starting from the smallest.
+func (g *Graph) DependencyCycles() [][]string {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := map[string]int{}
+	var (
+		stack  []string
+		cycles [][]string
+		visit  func(id string)
+	)
+	visit = func(id string) {
+		state[id] = active
+		stack = append(stack, id)
+		for _, dep := range g.nodes[id].Depends {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case active:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycles = append(cycles, rotateSmallest(stack[i:]))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, n := range g.Specs {
+		if state[n.SpecID] == unvisited {
+			visit(n.SpecID)
+		}
+	}
+	return cycles
+}
+
+func rotateSmallest(ids []string) []string {
+	first := 0
+	for i, id := range ids {
+		if id < ids[first] {
+			first = i
+		}
+	}
+	return append(append([]string{}, ids[first:]...), ids[:first]...)
+}
+
+// Render writes the graph: text lists each SPEC with its edges (and file
+// locations when withFiles is set), dot is Graphviz source.
+func (g *Graph) Render(w io.Writer, format GraphFormat, withFiles bool) error {
+	switch format {
+	case GraphJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	case GraphDOT:
+		return g.writeDOT(w, withFiles)
+	case GraphText, "":
+		return g.writeText(w, withFiles)
+	}
+	return fmt.Errorf("tag/graph: unknown format %q", format)
+}
+
+func (g *Graph) writeText(w io.Writer, withFiles bool) error {
+	var b strings.Builder
+	for _, n := range g.Specs {
+		b.WriteString(n.SpecID)
+		if n.Title != "" {
+			fmt.Fprintf(&b, "  %s", n.Title)
+		}
+		if n.Status != "" {
+			fmt.Fprintf(&b, " [%s]", n.Status)
+		}
+		if !n.Documented {
+			b.WriteString(" (no SPEC document)")
+		}
+		b.WriteString("\n")
+		for _, edge := range []struct {
+			label string
+			ids   []string
+		}{{"depends:", n.Depends}, {"needed by:", n.Dependents}, {"related:", n.Related}} {
+			if len(edge.ids) > 0 {
+				fmt.Fprintf(&b, "    %-11s %s\n", edge.label, strings.Join(edge.ids, ", "))
+			}
+		}
+		if withFiles {
+			writeTableLocations(&b, "impl", n.Impl)
+			writeTableLocations(&b, "verify", n.Verify)
+		} else {
+			fmt.Fprintf(&b, "    %-11s %d impl, %d verify\n", "files:", len(n.Impl), len(n.Verify))
+		}
+	}
+	for _, cycle := range g.DependencyCycles() {
+		fmt.Fprintf(&b, "\nWarning: dependency cycle %s → %s\n", strings.Join(cycle, " → "), cycle[0])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) writeDOT(w io.Writer, withFiles bool) error {
+	var b strings.Builder
+	b.WriteString("digraph specs {\n  rankdir=LR;\n  node [shape=box, fontname=\"Helvetica\"];\n")
+	for _, n := range g.Specs {
+		label := n.SpecID
+		if n.Title != "" {
+			label += "\n" + n.Title
+		}
+		style := ""
+		if !n.Documented {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n", n.SpecID, label, style)
+	}
+	for _, n := range g.Specs {
+		for _, dep := range n.Depends {
+			fmt.Fprintf(&b, "  %q -> %q;\n", n.SpecID, dep)
+		}
+		for _, rel := range n.Related {
+			if n.SpecID < rel {
+				fmt.Fprintf(&b, "  %q -> %q [dir=none, style=dotted];\n", n.SpecID, rel)
+			}
+		}
+	}
+	if withFiles {
+		b.WriteString("  node [shape=note];\n")
+		for _, n := range g.Specs {
+			for _, files := range []struct {
+				locs  []Location
+				style string
+			}{{n.Impl, ""}, {n.Verify, " [color=darkgreen, label=verify]"}} {
+				seen := map[string]bool{}
+				for _, l := range files.locs {
+					if !seen[l.File] {
+						seen[l.File] = true
+						fmt.Fprintf(&b, "  %q -> %q%s;\n", n.SpecID, l.File, files.style)
+					}
+				}
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func uniqueSorted(list []string) []string {
+	sort.Strings(list)
+	out := list[:0]
+	for i, s := range list {
+		if i == 0 || s != list[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
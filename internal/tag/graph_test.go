@@ -0,0 +1,137 @@
+package tag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpecFrontMatter(t *testing.T) {
+	specs := t.TempDir()
+	writeSpecFile(t, specs, "SPEC-API-001", "spec.md",
+		"---\nstatus: Approved\ndepends: [SPEC-AUTH-001, not-a-spec]\nrelated: SPEC-USER-001, SPEC-USER-002\n---\n\n# SPEC-API-001: Orders API\n\nOrders over HTTP.\n")
+
+	doc := LoadSpecDocument(specs, "SPEC-API-001")
+	if doc.Title != "Orders API" || doc.Summary != "Orders over HTTP." || doc.Status != "approved" {
+		t.Errorf("doc = %+v", doc)
+	}
+	if !reflect.DeepEqual(doc.Depends, []string{"SPEC-AUTH-001"}) ||
+		!reflect.DeepEqual(doc.Related, []string{"SPEC-USER-001", "SPEC-USER-002"}) {
+		t.Errorf("depends = %v, related = %v", doc.Depends, doc.Related)
+	}
+}
+
+func TestGraphImpact(t *testing.T) {
+	root := "/proj"
+	specsDir := filepath.Join(root, ".jikime", "specs")
+	specs := []*SpecDocument{
+		{ID: "SPEC-AUTH-001", Title: "Login", Acceptance: []string{"a", "b"}},
+		{ID: "SPEC-API-001", Depends: []string{"SPEC-AUTH-001"}, Acceptance: []string{"c"}},
+		{ID: "SPEC-UI-001", Related: []string{"SPEC-API-001"}},
+	}
+	tags := []*TAG{
+		NewTAG("SPEC-AUTH-001", "impl", "/proj/auth/login.go", 1),
+		NewTAG("SPEC-AUTH-001", "verify", "/proj/auth/login_test.go", 1),
+		NewTAG("SPEC-API-001", "verify", "/proj/api/orders_test.go", 1),
+		// The report code implements SPEC-REPORT-001 and uses the orders API.
+		NewTAG("SPEC-REPORT-001", "impl", "/proj/report/report.go", 1),
+		NewTAG("SPEC-API-001", "depends", "/proj/report/report.go", 2),
+	}
+	g := BuildGraph(root, specsDir, specs, tags)
+
+	if n := g.Node("SPEC-API-001"); !reflect.DeepEqual(n.Dependents, []string{"SPEC-REPORT-001"}) ||
+		!reflect.DeepEqual(n.Related, []string{"SPEC-UI-001"}) {
+		t.Errorf("SPEC-API-001 = %+v", n)
+	}
+	if n := g.Node("SPEC-REPORT-001"); n.Documented || !reflect.DeepEqual(n.Depends, []string{"SPEC-API-001"}) {
+		t.Errorf("SPEC-REPORT-001 = %+v", n)
+	}
+
+	im := g.Impact([]string{"/proj/auth/login.go", "/proj/README.go"})
+	var got []string
+	for _, s := range im.Specs {
+		got = append(got, s.SpecID+"<"+strings.Join(s.Via, ","))
+	}
+	want := []string{"SPEC-AUTH-001<", "SPEC-API-001<SPEC-AUTH-001", "SPEC-REPORT-001<SPEC-API-001,SPEC-AUTH-001"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("impact = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(im.Tests, []string{"api/orders_test.go", "auth/login_test.go"}) {
+		t.Errorf("tests = %v", im.Tests)
+	}
+	if !reflect.DeepEqual(im.Related, []string{"SPEC-UI-001"}) || !reflect.DeepEqual(im.Untagged, []string{"README.go"}) {
+		t.Errorf("related = %v, untagged = %v", im.Related, im.Untagged)
+	}
+	if im.Criteria() != 3 {
+		t.Errorf("criteria = %d, want 3", im.Criteria())
+	}
+
+	// Editing a SPEC document affects that SPEC.
+	im = g.Impact([]string{filepath.Join(specsDir, "SPEC-UI-001", "spec.md")})
+	if len(im.Specs) != 1 || im.Specs[0].SpecID != "SPEC-UI-001" || im.Specs[0].Reasons[0] != ImpactDocument {
+		t.Errorf("document impact = %+v", im.Specs)
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	g := BuildGraph("/proj", "", []*SpecDocument{
+		{ID: "SPEC-C-001", Depends: []string{"SPEC-A-001"}},
+		{ID: "SPEC-A-001", Depends: []string{"SPEC-B-001"}},
+		{ID: "SPEC-B-001", Depends: []string{"SPEC-C-001"}},
+		{ID: "SPEC-D-001", Depends: []string{"SPEC-A-001"}},
+	}, nil)
+	cycles := g.DependencyCycles()
+	if want := [][]string{{"SPEC-A-001", "SPEC-B-001", "SPEC-C-001"}}; !reflect.DeepEqual(cycles, want) {
+		t.Errorf("cycles = %v, want %v", cycles, want)
+	}
+}
+
+func TestLoadProjectGraph_DeletedFileImpact(t *testing.T) {
+	root := t.TempDir()
+	login := filepath.Join(root, "auth", "login.go")
+	orders := filepath.Join(root, "api", "orders.go")
+	for path, content := range map[string]string{
+		login:  "package auth\n\n// @SPEC SPEC-AUTH-001\n",
+		orders: "package api\n\n// @SPEC SPEC-API-001\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lm, err := NewLinkageManager(filepath.Join(root, ".jikime", "tag_linkage.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lm.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+
+	// One file deleted, the other loses its TAG.
+	if err := os.Remove(login); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orders, []byte("package api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed := []string{login, orders}
+	g, err := LoadProjectGraph(root, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im := g.Impact(changed)
+	var got []string
+	for _, s := range im.Specs {
+		got = append(got, s.SpecID+":"+strings.Join(s.Reasons, ","))
+	}
+	if want := []string{"SPEC-API-001:impl", "SPEC-AUTH-001:impl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("impact = %v, want %v", got, want)
+	}
+	if len(im.Untagged) != 0 {
+		t.Errorf("untagged = %v, want none", im.Untagged)
+	}
+}
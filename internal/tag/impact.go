@@ -0,0 +1,245 @@
+package tag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Reasons a SPEC is affected by a change.
+const (
+	ImpactImpl      = "impl"     // an implementing file changed
+	ImpactVerify    = "verify"   // a verifying test changed
+	ImpactDocument  = "document" // the SPEC document changed
+	ImpactDependsOn = "depends"  // a SPEC it depends on is affected
+)
+
+// ImpactedSpec is a SPEC affected by a change, with the tests and
+// acceptance criteria that need verifying again.
+type ImpactedSpec struct {
+	SpecID     string     `json:"spec_id"`
+	Title      string     `json:"title,omitempty"`
+	Reasons    []string   `json:"reasons"`
+	Via        []string   `json:"via,omitempty"` // dependency chain to a changed SPEC
+	Files      []string   `json:"files,omitempty"`
+	Tests      []Location `json:"tests"`
+	Acceptance []string   `json:"acceptance_criteria"`
+}
+
+// Direct reports whether the SPEC's own files or document changed, as
+// opposed to one it depends on.
+func (s *ImpactedSpec) Direct() bool {
+	return len(s.Via) == 0
+}
+
+// Impact is the result of an impact query: the SPECs, tests and
+// acceptance criteria affected by a set of changed files.
+type Impact struct {
+	Changed  []string        `json:"changed"`
+	Specs    []*ImpactedSpec `json:"specs"`
+	Related  []string        `json:"related"`  // related to an affected SPEC, not affected themselves
+	Tests    []string        `json:"tests"`    // test files to run
+	Untagged []string        `json:"untagged"` // changed source files without TAGs
+}
+
+// Impact answers "which SPECs and tests are affected by changing these
+// files?". A SPEC is affected directly when a file with its impl or verify
+// TAG, or its SPEC document, changed, and transitively when it depends on
+// an affected SPEC. Directly affected SPECs come first, then dependents by
+// distance.
+func (g *Graph) Impact(changed []string) *Impact {
+	im := &Impact{Changed: []string{}, Specs: []*ImpactedSpec{}, Related: []string{}, Tests: []string{}, Untagged: []string{}}
+	affected := map[string]*ImpactedSpec{}
+	hit := func(specID, reason, file string) {
+		s := affected[specID]
+		if s == nil {
+			n := g.node(specID)
+			s = &ImpactedSpec{SpecID: specID, Title: n.Title, Tests: n.Verify, Acceptance: n.Acceptance}
+			affected[specID] = s
+			im.Specs = append(im.Specs, s)
+		}
+		if !containsString(s.Reasons, reason) {
+			s.Reasons = append(s.Reasons, reason)
+		}
+		if file != "" && !containsString(s.Files, file) {
+			s.Files = append(s.Files, file)
+		}
+	}
+
+	tests := map[string]bool{}
+	for _, path := range changed {
+		path = filepath.Clean(path)
+		rel := relPath(g.Root, path)
+		im.Changed = append(im.Changed, rel)
+		if specID := g.specDocumentOf(path); specID != "" {
+			hit(specID, ImpactDocument, rel)
+			continue
+		}
+		// TAGs the file had before the change count too, so deleting a
+		// file or its TAG still affects the SPEC.
+		fileTags := append(append([]*TAG{}, g.files[path]...), g.previous[path]...)
+		for _, t := range fileTags {
+			switch t.Verb {
+			case "impl":
+				hit(t.SpecID, ImpactImpl, rel)
+			case "verify":
+				hit(t.SpecID, ImpactVerify, rel)
+				tests[rel] = true
+			}
+		}
+		if len(fileTags) == 0 && IsSupportedFile(path) && !g.deleted[path] {
+			im.Untagged = append(im.Untagged, rel)
+		}
+	}
+	sort.Slice(im.Specs, func(i, j int) bool { return im.Specs[i].SpecID < im.Specs[j].SpecID })
+
+	// Walk dependents breadth first so each SPEC gets its shortest chain.
+	queue := append([]*ImpactedSpec{}, im.Specs...)
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, dependent := range g.node(s.SpecID).Dependents {
+			if affected[dependent] != nil {
+				continue
+			}
+			hit(dependent, ImpactDependsOn, "")
+			d := affected[dependent]
+			d.Via = append([]string{s.SpecID}, s.Via...)
+			queue = append(queue, d)
+		}
+	}
+
+	related := map[string]bool{}
+	for _, s := range im.Specs {
+		for _, l := range s.Tests {
+			tests[l.File] = true
+		}
+		for _, rel := range g.node(s.SpecID).Related {
+			if affected[rel] == nil {
+				related[rel] = true
+			}
+		}
+	}
+	for file := range tests {
+		im.Tests = append(im.Tests, file)
+	}
+	for id := range related {
+		im.Related = append(im.Related, id)
+	}
+	sort.Strings(im.Tests)
+	sort.Strings(im.Related)
+	return im
+}
+
+// specDocumentOf returns the SPEC-ID whose directory contains path.
+func (g *Graph) specDocumentOf(path string) string {
+	if g.specsDir == "" || !isUnder(path, g.specsDir) {
+		return ""
+	}
+	rel, _ := filepath.Rel(g.specsDir, path)
+	id := strings.Split(filepath.ToSlash(rel), "/")[0]
+	if !ValidateSpecIDFormat(id) {
+		return ""
+	}
+	return id
+}
+
+// Criteria counts the acceptance criteria to verify again.
+func (im *Impact) Criteria() int {
+	n := 0
+	for _, s := range im.Specs {
+		n += len(s.Acceptance)
+	}
+	return n
+}
+
+// Render writes the impact report as text or JSON.
+func (im *Impact) Render(w io.Writer, format GraphFormat) error {
+	switch format {
+	case GraphJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(im)
+	case GraphText, "":
+		_, err := io.WriteString(w, im.String())
+		return err
+	}
+	return fmt.Errorf("tag/graph: impact cannot be rendered as %q", format)
+}
+
+func (im *Impact) String() string {
+	var b strings.Builder
+	if len(im.Specs) == 0 {
+		fmt.Fprintf(&b, "%d changed file(s) affect no SPEC\n", len(im.Changed))
+	}
+	for _, s := range im.Specs {
+		b.WriteString(s.SpecID)
+		if s.Title != "" {
+			fmt.Fprintf(&b, "  %s", s.Title)
+		}
+		b.WriteString("\n")
+		if s.Direct() {
+			fmt.Fprintf(&b, "    %-11s %s\n", "changed:", strings.Join(s.Reasons, ", "))
+			for _, f := range s.Files {
+				fmt.Fprintf(&b, "    %-11s %s\n", "", f)
+			}
+		} else {
+			fmt.Fprintf(&b, "    %-11s %s\n", "depends on:", strings.Join(s.Via, " → "))
+		}
+		writeTableLocations(&b, "tests", s.Tests)
+		fmt.Fprintf(&b, "    %-11s %d criteria to re-verify\n", "acceptance:", len(s.Acceptance))
+	}
+	if len(im.Related) > 0 {
+		fmt.Fprintf(&b, "\nRelated (review): %s\n", strings.Join(im.Related, ", "))
+	}
+	if len(im.Untagged) > 0 {
+		fmt.Fprintf(&b, "\nChanged files without TAGs:\n")
+		for _, f := range im.Untagged {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+	if len(im.Specs) > 0 {
+		fmt.Fprintf(&b, "\n%d SPEC(s) affected, %d test file(s), %d acceptance criteria\n",
+			len(im.Specs), len(im.Tests), im.Criteria())
+	}
+	return b.String()
+}
+
+// LoadProjectGraph builds the graph from the project's linkage database and
+// .jikime/specs. The database is brought up to date in memory only and never
+// written (see CurrentTags): with changed == nil the whole tree is checked,
+// otherwise only the changed files are re-read, which is cheap enough for
+// hooks. A project without a database yields the TAGs of changed files only.
+// Impact on the returned graph also counts the TAGs the database recorded
+// for the changed files before the update, and does not report deleted
+// files as untagged.
+func LoadProjectGraph(root string, changed []string) (*Graph, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	lm := &LinkageManager{DBPath: filepath.Join(root, ".jikime", "tag_linkage.json")}
+	tags, previous, err := lm.currentTags(root, changed)
+	if err != nil {
+		return nil, err
+	}
+	specsDir := filepath.Join(root, ".jikime", "specs")
+	specs, err := LoadSpecDocuments(specsDir)
+	if err != nil {
+		return nil, err
+	}
+	g := BuildGraph(root, specsDir, specs, tags)
+	g.previous = previous
+	for _, path := range changed {
+		if path, err := filepath.Abs(path); err == nil {
+			if _, err := os.Lstat(path); os.IsNotExist(err) {
+				g.deleted[path] = true
+			}
+		}
+	}
+	return g, nil
+}
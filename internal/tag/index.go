@@ -0,0 +1,363 @@
+package tag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileEntry records the state of a scanned file in the linkage database,
+// so unchanged files are not parsed again.
+type FileEntry struct {
+	ModTime int64  `json:"mtime"` // modification time, Unix nanoseconds
+	Size    int64  `json:"size"`
+	Hash    string `json:"sha256"`
+}
+
+// IndexStats summarizes an incremental index update.
+type IndexStats struct {
+	Scanned   int `json:"scanned"`   // files looked at
+	Parsed    int `json:"parsed"`    // files whose content changed and were re-parsed
+	Unchanged int `json:"unchanged"` // files skipped by mtime/size or hash
+	Removed   int `json:"removed"`   // deleted files dropped from the database
+	Tags      int `json:"tags"`      // TAGs in the database afterwards
+}
+
+func (s *IndexStats) String() string {
+	return fmt.Sprintf("%d file(s) scanned: %d parsed, %d unchanged, %d removed; %d TAG(s) indexed",
+		s.Scanned, s.Parsed, s.Unchanged, s.Removed, s.Tags)
+}
+
+// Changed reports whether the update parsed or removed any file.
+func (s *IndexStats) Changed() bool {
+	return s.Parsed > 0 || s.Removed > 0
+}
+
+// Sync brings the database up to date with every supported file under
+// root in a single walk. A file is re-parsed only when its mtime or size
+// differs from the index and its content hash changed; files that no
+// longer exist lose their TAGs.
+func (lm *LinkageManager) Sync(root string) (*IndexStats, error) {
+	db, err := lm.loadDatabase()
+	if err != nil {
+		return nil, err
+	}
+	ix := newIndexUpdate(db)
+	if err := ix.sync(root); err != nil {
+		return nil, err
+	}
+	return ix.commit(lm)
+}
+
+// UpdateFiles updates the database for the given paths only, as reported
+// by git or a file watcher. Missing paths drop their TAGs (a missing
+// directory drops every file under it), directories are walked, and files
+// without a supported extension are ignored.
+func (lm *LinkageManager) UpdateFiles(paths []string) (*IndexStats, error) {
+	db, err := lm.loadDatabase()
+	if err != nil {
+		return nil, err
+	}
+	ix := newIndexUpdate(db)
+	ix.updateFiles(paths)
+	return ix.commit(lm)
+}
+
+// CurrentTags returns the database's TAGs brought up to date in memory,
+// without writing the database: with changed == nil every file under root
+// is checked like Sync, otherwise only the changed paths are refreshed like
+// UpdateFiles. Read-only queries use it so they never rewrite the index.
+func (lm *LinkageManager) CurrentTags(root string, changed []string) ([]*TAG, error) {
+	tags, _, err := lm.currentTags(root, changed)
+	return tags, err
+}
+
+// currentTags is CurrentTags that also returns, per changed path, the TAGs
+// the database recorded for it (or for files under it) before the update,
+// so a change that deletes a file or its TAGs can still be traced.
+func (lm *LinkageManager) currentTags(root string, changed []string) ([]*TAG, map[string][]*TAG, error) {
+	db, err := lm.loadDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	previous := map[string][]*TAG{}
+	for _, path := range changed {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range db.Tags {
+			fp, _ := entry["file_path"].(string)
+			if fp != path && !isUnder(fp, path) {
+				continue
+			}
+			if t := TAGFromMap(entry); t != nil {
+				previous[path] = append(previous[path], t)
+			}
+		}
+	}
+
+	ix := newIndexUpdate(db)
+	if changed == nil {
+		if err := ix.sync(root); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		ix.updateFiles(changed)
+	}
+	ix.flush()
+	tags := make([]*TAG, 0, len(db.Tags))
+	for _, entry := range db.Tags {
+		if t := TAGFromMap(entry); t != nil {
+			tags = append(tags, t)
+		}
+	}
+	return tags, previous, nil
+}
+
+// sync checks every supported file under root and drops files under root
+// that no longer exist.
+func (ix *indexUpdate) sync(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	walkSupportedFiles(root, true, func(path string, e fs.DirEntry) {
+		seen[path] = true
+		if info, err := e.Info(); err == nil {
+			ix.update(path, info)
+		}
+	})
+	for _, path := range ix.knownFiles() {
+		if !seen[path] && isUnder(path, root) {
+			ix.remove(path)
+		}
+	}
+	return nil
+}
+
+// updateFiles applies the given paths (see UpdateFiles).
+func (ix *indexUpdate) updateFiles(paths []string) {
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			for _, known := range ix.knownFiles() {
+				if known == path || isUnder(known, path) {
+					ix.remove(known)
+				}
+			}
+		case info.IsDir():
+			walkSupportedFiles(path, true, func(file string, e fs.DirEntry) {
+				if fi, err := e.Info(); err == nil {
+					ix.update(file, fi)
+				}
+			})
+		case info.Mode().IsRegular() && IsSupportedFile(path):
+			ix.update(path, info)
+		}
+	}
+}
+
+// indexUpdate applies file changes to a loaded database and writes it
+// back once.
+type indexUpdate struct {
+	db    *LinkageDatabase
+	stats IndexStats
+	dirty bool
+	// replaced holds the new TAGs of re-parsed or removed files until
+	// flush filters db.Tags once, rather than once per file.
+	replaced map[string][]*TAG
+}
+
+func newIndexUpdate(db *LinkageDatabase) *indexUpdate {
+	if db.Index == nil {
+		db.Index = map[string]*FileEntry{}
+	}
+	return &indexUpdate{db: db}
+}
+
+func (ix *indexUpdate) update(path string, info os.FileInfo) {
+	ix.stats.Scanned++
+	mtime, size := info.ModTime().UnixNano(), info.Size()
+	entry := ix.db.Index[path]
+	if entry != nil && entry.ModTime == mtime && entry.Size == size {
+		ix.stats.Unchanged++
+		return
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	ix.db.Index[path] = &FileEntry{ModTime: mtime, Size: size, Hash: hash}
+	ix.dirty = true
+	if entry != nil && entry.Hash == hash {
+		ix.stats.Unchanged++ // touched, e.g. by a checkout, but identical
+		return
+	}
+
+	ix.stats.Parsed++
+	ix.setFileTags(path, ExtractTagsFromSource(string(content), path))
+}
+
+func (ix *indexUpdate) remove(path string) {
+	ix.stats.Removed++
+	delete(ix.db.Index, path)
+	ix.setFileTags(path, nil)
+}
+
+// setFileTags replaces the TAGs recorded for a file. db.Files is updated
+// at once; db.Tags on flush.
+func (ix *indexUpdate) setFileTags(path string, tags []*TAG) {
+	ix.dirty = true
+	if ix.replaced == nil {
+		ix.replaced = map[string][]*TAG{}
+	}
+	ix.replaced[path] = tags
+
+	delete(ix.db.Files, path)
+	for _, t := range tags {
+		if !containsString(ix.db.Files[path], t.SpecID) {
+			ix.db.Files[path] = append(ix.db.Files[path], t.SpecID)
+		}
+	}
+}
+
+// flush applies the pending TAG replacements to db.Tags in a single pass.
+func (ix *indexUpdate) flush() {
+	if len(ix.replaced) == 0 {
+		return
+	}
+	kept := ix.db.Tags[:0]
+	for _, entry := range ix.db.Tags {
+		fp, _ := entry["file_path"].(string)
+		if _, ok := ix.replaced[fp]; !ok {
+			kept = append(kept, entry)
+		}
+	}
+	ix.db.Tags = kept
+
+	paths := make([]string, 0, len(ix.replaced))
+	for path := range ix.replaced {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, t := range ix.replaced[path] {
+			ix.db.Tags = append(ix.db.Tags, t.ToMap())
+		}
+	}
+	ix.replaced = nil
+}
+
+// knownFiles lists every file the database has an index entry or TAGs for.
+func (ix *indexUpdate) knownFiles() []string {
+	set := map[string]bool{}
+	for path := range ix.db.Index {
+		set[path] = true
+	}
+	for path := range ix.db.Files {
+		set[path] = true
+	}
+	files := make([]string, 0, len(set))
+	for path := range set {
+		files = append(files, path)
+	}
+	return files
+}
+
+func (ix *indexUpdate) commit(lm *LinkageManager) (*IndexStats, error) {
+	ix.flush()
+	ix.stats.Tags = len(ix.db.Tags)
+	if !ix.dirty {
+		return &ix.stats, nil
+	}
+	return &ix.stats, lm.writeDatabase(ix.db)
+}
+
+// GitChangedFiles lists the files git reports as changed in the work tree
+// at root, as absolute paths. With staged, only the index (what is about
+// to be committed) is compared; otherwise changes since ref (HEAD when
+// empty) plus untracked files are listed. Only the paths come from git:
+// callers read the work-tree copies, including any unstaged edits.
+func GitChangedFiles(root, ref string, staged bool) ([]string, error) {
+	top, err := gitOutput(root, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+
+	var lists []string
+	if staged {
+		out, err := gitOutput(root, "diff", "--cached", "--name-only", "--no-renames")
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, out)
+	} else {
+		if ref == "" {
+			ref = "HEAD"
+		}
+		out, err := gitOutput(root, "diff", "--name-only", "--no-renames", ref)
+		if err != nil {
+			return nil, err
+		}
+		untracked, err := gitOutput(root, "ls-files", "--others", "--exclude-standard", "--full-name")
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, out, untracked)
+	}
+
+	var files []string
+	for _, list := range lists {
+		for _, name := range strings.Split(list, "\n") {
+			if name = strings.TrimSpace(name); name != "" {
+				files = append(files, filepath.Join(top, filepath.FromSlash(name)))
+			}
+		}
+	}
+	return files, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// isUnder reports whether path is inside dir.
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package tag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncrementalIndex(t *testing.T) {
+	root := t.TempDir()
+	lm, err := NewLinkageManager(filepath.Join(root, ".jikime", "tag_linkage.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(rel, content string) string {
+		t.Helper()
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	sync := func(want IndexStats) {
+		t.Helper()
+		stats, err := lm.Sync(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *stats != want {
+			t.Fatalf("stats = %+v, want %+v", *stats, want)
+		}
+	}
+
+	auth := write("src/auth.go", "package auth\n\n// @SPEC SPEC-AUTH-001\nfunc Login() {}\n")
+	write("src/auth_test.go", "package auth\n\n// @SPEC SPEC-AUTH-001 verify\n")
+	write("node_modules/dep/index.js", "// @SPEC SPEC-DEP-001\n")
+	write("README.md", "# @SPEC SPEC-DOC-001\n")
+
+	sync(IndexStats{Scanned: 2, Parsed: 2, Tags: 2})
+	sync(IndexStats{Scanned: 2, Unchanged: 2, Tags: 2})
+
+	// Touched but identical: hashed, not parsed.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(auth, later, later); err != nil {
+		t.Fatal(err)
+	}
+	sync(IndexStats{Scanned: 2, Unchanged: 2, Tags: 2})
+
+	write("src/auth.go", "package auth\n\n// @SPEC SPEC-AUTH-001\n// @SPEC SPEC-AUTH-002 depends\n")
+	sync(IndexStats{Scanned: 2, Parsed: 1, Unchanged: 1, Tags: 3})
+	if got := lm.GetTagsByFile(auth); len(got) != 2 || got[1].SpecID != "SPEC-AUTH-002" {
+		t.Errorf("auth.go tags = %v", got)
+	}
+
+	if err := os.Remove(auth); err != nil {
+		t.Fatal(err)
+	}
+	sync(IndexStats{Scanned: 1, Unchanged: 1, Removed: 1, Tags: 1})
+
+	// UpdateFiles only looks at the given paths.
+	write("src/auth.go", "// @SPEC SPEC-AUTH-001\n")
+	write("src/api/handler.go", "// @SPEC SPEC-API-001\n")
+	stats, err := lm.UpdateFiles([]string{auth, filepath.Join(root, "README.md")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *stats != (IndexStats{Scanned: 1, Parsed: 1, Tags: 2}) {
+		t.Errorf("UpdateFiles stats = %+v", *stats)
+	}
+
+	// A removed directory drops every file under it.
+	if err := os.RemoveAll(filepath.Join(root, "src")); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err = lm.UpdateFiles([]string{filepath.Join(root, "src")}); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Removed != 2 || stats.Tags != 0 || len(lm.GetAllTags()) != 0 {
+		t.Errorf("after removing src: %+v, tags %v", *stats, lm.GetAllTags())
+	}
+}
+
+func TestCurrentTagsDoesNotWrite(t *testing.T) {
+	root := t.TempDir()
+	dbPath := filepath.Join(root, ".jikime", "tag_linkage.json")
+	src := filepath.Join(root, "src", "auth.go")
+	if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("// @SPEC SPEC-AUTH-001\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lm := &LinkageManager{DBPath: dbPath}
+	tags, err := lm.CurrentTags(root, nil)
+	if err != nil || len(tags) != 1 {
+		t.Fatalf("CurrentTags(all) = %v, %v", tags, err)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Fatalf("CurrentTags created the database (stat err %v)", err)
+	}
+
+	if _, err := lm.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(dbPath)
+	if err := os.WriteFile(src, []byte("// @SPEC SPEC-AUTH-001\n// @SPEC SPEC-AUTH-002 verify\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tags, err = lm.CurrentTags(root, []string{src})
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("CurrentTags(changed) = %v, %v; want both TAGs of the edited file", tags, err)
+	}
+	if after, _ := os.ReadFile(dbPath); string(after) != string(before) {
+		t.Error("CurrentTags rewrote the database")
+	}
+}
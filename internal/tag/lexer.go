@@ -0,0 +1,270 @@
+package tag
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CommentSyntax describes how a language writes comments and string
+// literals, which is all the TAG parser needs to find @SPEC comments
+// without being fooled by "// @SPEC" inside a string.
+type CommentSyntax struct {
+	Name    string
+	Line    []string      // line comment markers, e.g. "//", "--", "#"
+	Block   []BlockSyntax // block comment delimiters, e.g. /* */
+	Strings []StringSyntax
+
+	once   sync.Once
+	tokens []syntaxToken
+}
+
+// BlockSyntax is a pair of block comment delimiters.
+type BlockSyntax struct {
+	Open, Close string
+}
+
+// StringSyntax is a string literal delimiter. Escape means a backslash
+// escapes the next character; Multiline strings may span lines, others
+// end at the end of the line even when unterminated, so a stray quote in
+// prose cannot hide the rest of the file.
+type StringSyntax struct {
+	Open, Close string
+	Escape      bool
+	Multiline   bool
+}
+
+type tokenKind int
+
+const (
+	tokenLine tokenKind = iota
+	tokenBlock
+	tokenString
+)
+
+type syntaxToken struct {
+	open string
+	kind tokenKind
+	idx  int
+}
+
+// compile orders every opener longest first, so "<%--" wins over "--"
+// and `"""` over `"`.
+func (s *CommentSyntax) compile() []syntaxToken {
+	s.once.Do(func() {
+		for i, m := range s.Line {
+			s.tokens = append(s.tokens, syntaxToken{m, tokenLine, i})
+		}
+		for i, b := range s.Block {
+			s.tokens = append(s.tokens, syntaxToken{b.Open, tokenBlock, i})
+		}
+		for i, str := range s.Strings {
+			s.tokens = append(s.tokens, syntaxToken{str.Open, tokenString, i})
+		}
+		sort.SliceStable(s.tokens, func(i, j int) bool {
+			return len(s.tokens[i].open) > len(s.tokens[j].open)
+		})
+	})
+	return s.tokens
+}
+
+// Comment is the text of one comment line, without its markers.
+type Comment struct {
+	Line int
+	Text string
+}
+
+var (
+	cStrings = []StringSyntax{
+		{Open: `"`, Close: `"`, Escape: true},
+		{Open: `'`, Close: `'`, Escape: true},
+	}
+	cComments = []BlockSyntax{{"/*", "*/"}}
+
+	// HashComments is the syntax of #-comment languages without string
+	// rules. It is used for files with unknown extensions (Dockerfile,
+	// Makefile, .env, ...).
+	HashComments = &CommentSyntax{Name: "hash", Line: []string{"#"}}
+)
+
+// CommentSyntaxes maps a lowercase file extension to its comment syntax.
+// Add languages with RegisterCommentSyntax.
+var CommentSyntaxes = map[string]*CommentSyntax{}
+
+func init() {
+	python := &CommentSyntax{Name: "python", Line: []string{"#"}, Strings: []StringSyntax{
+		{Open: `"""`, Close: `"""`, Escape: true, Multiline: true},
+		{Open: `'''`, Close: `'''`, Escape: true, Multiline: true},
+		{Open: `"`, Close: `"`, Escape: true},
+		{Open: `'`, Close: `'`, Escape: true},
+	}}
+	script := &CommentSyntax{Name: "script", Line: []string{"#"}, Strings: cStrings}
+	yaml := &CommentSyntax{Name: "yaml", Line: []string{"#"}}
+	toml := &CommentSyntax{Name: "toml", Line: []string{"#"}, Strings: []StringSyntax{
+		{Open: `"""`, Close: `"""`, Escape: true, Multiline: true},
+		{Open: `"`, Close: `"`, Escape: true},
+		{Open: `'`, Close: `'`},
+	}}
+
+	golang := &CommentSyntax{Name: "go", Line: []string{"//"}, Block: cComments, Strings: []StringSyntax{
+		{Open: "`", Close: "`", Multiline: true},
+		{Open: `"`, Close: `"`, Escape: true},
+		{Open: `'`, Close: `'`, Escape: true},
+	}}
+	java := &CommentSyntax{Name: "java", Line: []string{"//"}, Block: cComments, Strings: append([]StringSyntax{
+		{Open: `"""`, Close: `"""`, Escape: true, Multiline: true},
+	}, cStrings...)}
+	csharp := &CommentSyntax{Name: "csharp", Line: []string{"//"}, Block: cComments, Strings: append([]StringSyntax{
+		{Open: `"""`, Close: `"""`, Multiline: true},
+		{Open: `@"`, Close: `"`, Multiline: true},
+	}, cStrings...)}
+	javascript := &CommentSyntax{Name: "javascript", Line: []string{"//"}, Block: cComments, Strings: append([]StringSyntax{
+		{Open: "`", Close: "`", Escape: true, Multiline: true},
+	}, cStrings...)}
+	clike := &CommentSyntax{Name: "c", Line: []string{"//"}, Block: cComments, Strings: cStrings}
+	php := &CommentSyntax{Name: "php", Line: []string{"//", "#"}, Block: cComments, Strings: cStrings}
+	sql := &CommentSyntax{Name: "sql", Line: []string{"--"}, Block: cComments, Strings: []StringSyntax{
+		{Open: `'`, Close: `'`, Multiline: true}, // '' is an escaped quote: close, then reopen
+		{Open: `"`, Close: `"`},
+	}}
+	lua := &CommentSyntax{Name: "lua", Line: []string{"--"}, Block: []BlockSyntax{{"--[[", "]]"}}, Strings: cStrings}
+	markup := &CommentSyntax{Name: "markup", Block: []BlockSyntax{{"<!--", "-->"}}}
+	// Single-file components: markup comments in <template>, C-style
+	// comments in <script> and <style>.
+	vue := &CommentSyntax{
+		Name:  "vue",
+		Line:  []string{"//"},
+		Block: []BlockSyntax{{"<!--", "-->"}, {"/*", "*/"}},
+		// No apostrophes: they are common in template text.
+		Strings: []StringSyntax{{Open: "`", Close: "`", Escape: true, Multiline: true}, {Open: `"`, Close: `"`, Escape: true}},
+	}
+	jsp := &CommentSyntax{
+		Name:  "jsp",
+		Line:  []string{"//"},
+		Block: []BlockSyntax{{"<%--", "--%>"}, {"<!--", "-->"}, {"/*", "*/"}},
+		// Only double quotes: apostrophes are common in page text.
+		Strings: []StringSyntax{{Open: `"`, Close: `"`, Escape: true}},
+	}
+
+	for syntax, exts := range map[*CommentSyntax][]string{
+		python:     {".py"},
+		script:     {".sh", ".bash", ".rb", ".pl", ".r", ".coffee"},
+		yaml:       {".yaml", ".yml"},
+		toml:       {".toml"},
+		golang:     {".go"},
+		java:       {".java", ".kt", ".scala", ".groovy"},
+		csharp:     {".cs"},
+		javascript: {".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx"},
+		clike:      {".c", ".h", ".cpp", ".hpp", ".cc", ".swift"},
+		php:        {".php"},
+		sql:        {".sql"},
+		lua:        {".lua"},
+		markup:     {".html", ".htm", ".xml"},
+		vue:        {".vue"},
+		jsp:        {".jsp", ".jspf", ".tag"},
+	} {
+		for _, ext := range exts {
+			CommentSyntaxes[ext] = syntax
+		}
+	}
+	SupportedExtensions = supportedExtensions()
+}
+
+// RegisterCommentSyntax sets the comment syntax for file extensions
+// (".ext"), which makes them supported.
+func RegisterCommentSyntax(syntax *CommentSyntax, extensions ...string) {
+	for _, ext := range extensions {
+		CommentSyntaxes[strings.ToLower(ext)] = syntax
+	}
+	SupportedExtensions = supportedExtensions()
+}
+
+// SyntaxForFile returns the comment syntax for a file by extension,
+// falling back to HashComments.
+func SyntaxForFile(filePath string) *CommentSyntax {
+	if syntax, ok := CommentSyntaxes[strings.ToLower(filepath.Ext(filePath))]; ok {
+		return syntax
+	}
+	return HashComments
+}
+
+// LexComments returns every comment in source, one entry per line of a
+// block comment, skipping string literals. Markers are removed, and so is
+// block decoration such as the leading "*" of Javadoc lines.
+func LexComments(source string, syntax *CommentSyntax) []Comment {
+	tokens := syntax.compile()
+	var comments []Comment
+	line := 1
+	for i := 0; i < len(source); {
+		if source[i] == '\n' {
+			line++
+			i++
+			continue
+		}
+		tok, ok := matchToken(source, i, tokens)
+		if !ok {
+			i++
+			continue
+		}
+		start := i + len(tok.open)
+		switch tok.kind {
+		case tokenLine:
+			end := indexFrom(source, start, "\n")
+			text := strings.TrimLeft(source[start:end], tok.open[len(tok.open)-1:])
+			comments = append(comments, Comment{Line: line, Text: strings.TrimSpace(text)})
+			i = end
+		case tokenBlock:
+			block := syntax.Block[tok.idx]
+			end := indexFrom(source, start, block.Close)
+			decoration := block.Open[len(block.Open)-1:]
+			for n, text := range strings.Split(source[start:end], "\n") {
+				text = strings.TrimLeft(strings.TrimSpace(text), decoration)
+				comments = append(comments, Comment{Line: line + n, Text: strings.TrimSpace(text)})
+			}
+			line += strings.Count(source[start:end], "\n")
+			i = min(end+len(block.Close), len(source))
+		case tokenString:
+			end := skipString(source, start, syntax.Strings[tok.idx])
+			line += strings.Count(source[i:end], "\n")
+			i = end
+		}
+	}
+	return comments
+}
+
+func matchToken(source string, i int, tokens []syntaxToken) (syntaxToken, bool) {
+	for _, tok := range tokens {
+		if strings.HasPrefix(source[i:], tok.open) {
+			return tok, true
+		}
+	}
+	return syntaxToken{}, false
+}
+
+// indexFrom returns the index of sub in source at or after start, or
+// len(source).
+func indexFrom(source string, start int, sub string) int {
+	if j := strings.Index(source[start:], sub); j >= 0 {
+		return start + j
+	}
+	return len(source)
+}
+
+// skipString returns the index just past a string literal's closing
+// delimiter (or where an unterminated literal ends).
+func skipString(source string, i int, str StringSyntax) int {
+	for i < len(source) {
+		switch {
+		case str.Escape && source[i] == '\\':
+			i += 2
+		case strings.HasPrefix(source[i:], str.Close):
+			return i + len(str.Close)
+		case source[i] == '\n' && !str.Multiline:
+			return i
+		default:
+			i++
+		}
+	}
+	return len(source)
+}
@@ -13,6 +13,7 @@ import (
 type LinkageDatabase struct {
 	Tags  []map[string]interface{} `json:"tags"`
 	Files map[string][]string      `json:"files"`
+	Index map[string]*FileEntry    `json:"index,omitempty"` // scanned files, see Sync
 }
 
 // LinkageManager manages bidirectional TAG↔CODE mapping database.
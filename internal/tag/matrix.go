@@ -0,0 +1,338 @@
+package tag
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MatrixFormat selects how a Matrix is rendered.
+type MatrixFormat string
+
+const (
+	MatrixTable MatrixFormat = "table"
+	MatrixCSV   MatrixFormat = "csv"
+	MatrixHTML  MatrixFormat = "html"
+	MatrixJSON  MatrixFormat = "json"
+)
+
+// ParseMatrixFormat accepts "table", "csv", "html" and "json".
+func ParseMatrixFormat(s string) (MatrixFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "table", "txt":
+		return MatrixTable, nil
+	case "csv":
+		return MatrixCSV, nil
+	case "html", "htm":
+		return MatrixHTML, nil
+	case "json":
+		return MatrixJSON, nil
+	}
+	return "", fmt.Errorf("tag/matrix: unknown format %q (want table, csv, html or json)", s)
+}
+
+// Coverage gaps flagged on a MatrixRow.
+const (
+	GapNoImpl  = "no-impl"
+	GapNoTests = "no-tests"
+)
+
+// Location is a TAG position, "path:line" when rendered.
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (l Location) String() string {
+	return l.File + ":" + strconv.Itoa(l.Line)
+}
+
+// MatrixRow traces one SPEC to the code that implements it, the tests
+// that verify it, and its acceptance criteria.
+type MatrixRow struct {
+	SpecID     string     `json:"spec_id"`
+	Title      string     `json:"title,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Impl       []Location `json:"impl"`
+	Verify     []Location `json:"verify"`
+	Related    []Location `json:"related,omitempty"` // depends and related TAGs
+	Acceptance []string   `json:"acceptance_criteria"`
+	Gaps       []string   `json:"gaps,omitempty"`
+}
+
+// MatrixSummary counts SPECs by coverage.
+type MatrixSummary struct {
+	Specs      int `json:"specs"`
+	WithImpl   int `json:"with_impl"`
+	WithTests  int `json:"with_tests"`
+	Covered    int `json:"covered"` // both implemented and tested
+	Criteria   int `json:"acceptance_criteria"`
+	OrphanTags int `json:"orphan_tags"`
+}
+
+// Matrix is the SPEC traceability matrix: every SPEC in the specs
+// directory with its TAGs, plus TAGs that reference unknown SPECs.
+type Matrix struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Root        string        `json:"root"`
+	Summary     MatrixSummary `json:"summary"`
+	Specs       []*MatrixRow  `json:"specs"`
+	Orphans     []*TAG        `json:"orphans"`
+}
+
+// BuildMatrix maps SPEC documents to TAGs. File paths are made relative
+// to root where possible.
+func BuildMatrix(root string, specs []*SpecDocument, tags []*TAG) *Matrix {
+	m := &Matrix{GeneratedAt: time.Now(), Root: root, Specs: []*MatrixRow{}, Orphans: []*TAG{}}
+	rows := make(map[string]*MatrixRow, len(specs))
+	for _, doc := range specs {
+		row := &MatrixRow{
+			SpecID:     doc.ID,
+			Title:      doc.Title,
+			Status:     doc.Status,
+			Impl:       []Location{},
+			Verify:     []Location{},
+			Acceptance: doc.Acceptance,
+		}
+		rows[doc.ID] = row
+		m.Specs = append(m.Specs, row)
+	}
+
+	for _, t := range tags {
+		row := rows[t.SpecID]
+		if row == nil {
+			m.Orphans = append(m.Orphans, t)
+			continue
+		}
+		loc := Location{File: relPath(root, t.FilePath), Line: t.Line}
+		switch t.Verb {
+		case "verify":
+			row.Verify = append(row.Verify, loc)
+		case "depends", "related":
+			row.Related = append(row.Related, loc)
+		default:
+			row.Impl = append(row.Impl, loc)
+		}
+	}
+
+	m.Summary.Specs = len(m.Specs)
+	m.Summary.OrphanTags = len(m.Orphans)
+	for _, row := range m.Specs {
+		for _, locs := range [][]Location{row.Impl, row.Verify, row.Related} {
+			sortLocations(locs)
+		}
+		if len(row.Impl) == 0 {
+			row.Gaps = append(row.Gaps, GapNoImpl)
+		} else {
+			m.Summary.WithImpl++
+		}
+		if len(row.Verify) == 0 {
+			row.Gaps = append(row.Gaps, GapNoTests)
+		} else {
+			m.Summary.WithTests++
+		}
+		if len(row.Gaps) == 0 {
+			m.Summary.Covered++
+		}
+		m.Summary.Criteria += len(row.Acceptance)
+	}
+	return m
+}
+
+// Gaps returns the SPECs missing an implementation or tests.
+func (m *Matrix) Gaps() []*MatrixRow {
+	var gaps []*MatrixRow
+	for _, row := range m.Specs {
+		if len(row.Gaps) > 0 {
+			gaps = append(gaps, row)
+		}
+	}
+	return gaps
+}
+
+// CoveragePercent is the share of SPECs that are implemented and tested.
+func (m *Matrix) CoveragePercent() float64 {
+	if m.Summary.Specs == 0 {
+		return 0
+	}
+	return float64(m.Summary.Covered) * 100 / float64(m.Summary.Specs)
+}
+
+// Render writes the matrix in the given format.
+func (m *Matrix) Render(w io.Writer, format MatrixFormat) error {
+	switch format {
+	case MatrixJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	case MatrixCSV:
+		return m.writeCSV(w)
+	case MatrixHTML:
+		return matrixHTML.Execute(w, m)
+	case MatrixTable, "":
+		return m.writeTable(w)
+	}
+	return fmt.Errorf("tag/matrix: unknown format %q", format)
+}
+
+// writeCSV writes one row per SPEC; multi-valued cells are joined with
+// newlines so spreadsheets show them as one cell.
+func (m *Matrix) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"spec_id", "title", "status", "impl", "verify", "related", "acceptance_criteria", "gaps"})
+	for _, row := range m.Specs {
+		_ = cw.Write([]string{
+			row.SpecID,
+			row.Title,
+			row.Status,
+			joinLocations(row.Impl, "\n"),
+			joinLocations(row.Verify, "\n"),
+			joinLocations(row.Related, "\n"),
+			strings.Join(row.Acceptance, "\n"),
+			strings.Join(row.Gaps, " "),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (m *Matrix) writeTable(w io.Writer) error {
+	var b strings.Builder
+	for _, row := range m.Specs {
+		mark := "✓"
+		if len(row.Gaps) > 0 {
+			mark = "✗"
+		}
+		fmt.Fprintf(&b, "%s %s", mark, row.SpecID)
+		if row.Title != "" {
+			fmt.Fprintf(&b, "  %s", row.Title)
+		}
+		if row.Status != "" {
+			fmt.Fprintf(&b, " [%s]", row.Status)
+		}
+		b.WriteString("\n")
+		writeTableLocations(&b, "impl", row.Impl)
+		writeTableLocations(&b, "verify", row.Verify)
+		writeTableLocations(&b, "related", row.Related)
+		fmt.Fprintf(&b, "    %-11s %d criteria\n", "acceptance:", len(row.Acceptance))
+		if len(row.Gaps) > 0 {
+			fmt.Fprintf(&b, "    %-11s %s\n", "gaps:", strings.Join(row.Gaps, ", "))
+		}
+	}
+	if len(m.Orphans) > 0 {
+		b.WriteString("\nTAGs referencing unknown SPECs:\n")
+		for _, t := range m.Orphans {
+			fmt.Fprintf(&b, "  %s %s  %s:%d\n", t.SpecID, t.Verb, relPath(m.Root, t.FilePath), t.Line)
+		}
+	}
+	s := m.Summary
+	fmt.Fprintf(&b, "\n%d SPEC(s): %d implemented, %d tested, %d covered (%.0f%%), %d acceptance criteria, %d orphan TAG(s)\n",
+		s.Specs, s.WithImpl, s.WithTests, s.Covered, m.CoveragePercent(), s.Criteria, s.OrphanTags)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTableLocations(b *strings.Builder, label string, locs []Location) {
+	if len(locs) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "    %-11s %s\n", label+":", locs[0])
+	for _, l := range locs[1:] {
+		fmt.Fprintf(b, "    %-11s %s\n", "", l)
+	}
+}
+
+func joinLocations(locs []Location, sep string) string {
+	parts := make([]string, len(locs))
+	for i, l := range locs {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+func sortLocations(locs []Location) {
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].File != locs[j].File {
+			return locs[i].File < locs[j].File
+		}
+		return locs[i].Line < locs[j].Line
+	})
+}
+
+func relPath(root, path string) string {
+	if root == "" || !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// matrixHTML renders a single HTML file with inline CSS and no external
+// assets, so it can be handed over as an audit deliverable.
+var matrixHTML = template.Must(template.New("matrix").Funcs(template.FuncMap{
+	"time":    func(t time.Time) string { return t.Format("2006-01-02 15:04:05 MST") },
+	"percent": func(m *Matrix) string { return fmt.Sprintf("%.0f%%", m.CoveragePercent()) },
+	"rel":     relPath,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SPEC traceability matrix</title>
+<style>
+body{font:14px/1.5 -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;color:#1f2328;max-width:1200px;margin:2rem auto;padding:0 1rem}
+h1{border-bottom:2px solid #d0d7de;padding-bottom:.3rem}
+h2{margin-top:2rem;border-bottom:1px solid #d0d7de;padding-bottom:.2rem}
+table{border-collapse:collapse;width:100%;margin:.5rem 0}
+th,td{border:1px solid #d0d7de;padding:4px 8px;text-align:left;vertical-align:top}
+th{background:#f6f8fa}
+td.n{text-align:right;font-variant-numeric:tabular-nums}
+code{font-family:ui-monospace,SFMono-Regular,Menlo,monospace;font-size:12px}
+ul{margin:0;padding-left:1.2rem}
+tr.gap td:first-child{border-left:4px solid #cf222e}
+.gap-badge{display:inline-block;background:#ffebe9;color:#cf222e;border-radius:4px;padding:0 6px;margin:1px 0;font-size:12px}
+.meta{color:#59636e}
+</style>
+</head>
+<body>
+<h1>SPEC traceability matrix</h1>
+<table>
+<tr><th>Project</th><td><code>{{.Root}}</code></td></tr>
+<tr><th>SPECs</th><td>{{.Summary.Specs}}</td></tr>
+<tr><th>Implemented</th><td>{{.Summary.WithImpl}}</td></tr>
+<tr><th>Tested</th><td>{{.Summary.WithTests}}</td></tr>
+<tr><th>Covered</th><td>{{.Summary.Covered}} ({{percent .}})</td></tr>
+<tr><th>Acceptance criteria</th><td>{{.Summary.Criteria}}</td></tr>
+<tr><th>Generated</th><td>{{time .GeneratedAt}}</td></tr>
+</table>
+
+<h2>SPECs</h2>
+<table>
+<tr><th>SPEC</th><th>Implementation</th><th>Tests</th><th>Acceptance criteria</th><th>Gaps</th></tr>
+{{range .Specs}}<tr{{if .Gaps}} class="gap"{{end}}>
+<td><strong>{{.SpecID}}</strong>{{if .Title}}<br>{{.Title}}{{end}}{{if .Status}}<br><span class="meta">{{.Status}}</span>{{end}}</td>
+<td>{{if .Impl}}<ul>{{range .Impl}}<li><code>{{.}}</code></li>{{end}}</ul>{{else}}—{{end}}</td>
+<td>{{if .Verify}}<ul>{{range .Verify}}<li><code>{{.}}</code></li>{{end}}</ul>{{else}}—{{end}}</td>
+<td>{{if .Acceptance}}<ul>{{range .Acceptance}}<li>{{.}}</li>{{end}}</ul>{{else}}—{{end}}</td>
+<td>{{range .Gaps}}<span class="gap-badge">{{.}}</span><br>{{end}}</td>
+</tr>
+{{else}}<tr><td colspan="5"><em>No SPEC documents found.</em></td></tr>
+{{end}}</table>
+{{if .Orphans}}
+<h2>TAGs referencing unknown SPECs</h2>
+<table>
+<tr><th>SPEC-ID</th><th>Verb</th><th>Location</th></tr>
+{{range .Orphans}}<tr><td>{{.SpecID}}</td><td>{{.Verb}}</td><td><code>{{rel $.Root .FilePath}}:{{.Line}}</code></td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,92 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSpecFile(t *testing.T, specsDir, specID, name, content string) {
+	t.Helper()
+	dir := filepath.Join(specsDir, specID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSpecDocuments(t *testing.T) {
+	specs := t.TempDir()
+	writeSpecFile(t, specs, "SPEC-AUTH-001", "spec.md",
+		"# SPEC-AUTH-001: JWT login\n\n| Field | Value |\n| Status | Draft |\n\nUsers sign in\nwith a token.\n\n## Requirements\n")
+	writeSpecFile(t, specs, "SPEC-AUTH-001", "acceptance.md", "# Acceptance\n\n### Valid login\n- [x] returns a token\n")
+	writeSpecFile(t, specs, "notes", "spec.md", "# not a SPEC\n")
+
+	docs, err := LoadSpecDocuments(specs)
+	if err != nil || len(docs) != 1 {
+		t.Fatalf("docs = %v, %v", docs, err)
+	}
+	doc := docs[0]
+	if doc.Title != "JWT login" || doc.Summary != "Users sign in with a token." || doc.Status != "draft" {
+		t.Errorf("doc = %+v", doc)
+	}
+	if strings.Join(doc.Acceptance, "|") != "Valid login|returns a token" {
+		t.Errorf("acceptance = %q", doc.Acceptance)
+	}
+}
+
+func TestBuildMatrix(t *testing.T) {
+	root := "/proj"
+	specs := []*SpecDocument{
+		{ID: "SPEC-AUTH-001", Acceptance: []string{"a", "b"}},
+		{ID: "SPEC-AUTH-002", Acceptance: []string{}},
+		{ID: "SPEC-AUTH-003", Acceptance: []string{}},
+	}
+	tags := []*TAG{
+		NewTAG("SPEC-AUTH-001", "impl", "/proj/src/b.go", 3),
+		NewTAG("SPEC-AUTH-001", "impl", "/proj/src/a.go", 9),
+		NewTAG("SPEC-AUTH-001", "verify", "/proj/src/a_test.go", 1),
+		NewTAG("SPEC-AUTH-002", "verify", "/proj/src/c_test.go", 1),
+		NewTAG("SPEC-AUTH-002", "depends", "/proj/src/c.go", 1),
+		NewTAG("SPEC-GONE-001", "impl", "/proj/src/old.go", 1),
+	}
+	m := BuildMatrix(root, specs, tags)
+
+	if s := m.Summary; s.Specs != 3 || s.WithImpl != 1 || s.WithTests != 2 || s.Covered != 1 || s.Criteria != 2 || s.OrphanTags != 1 {
+		t.Errorf("summary = %+v", s)
+	}
+	if got := joinLocations(m.Specs[0].Impl, " "); got != "src/a.go:9 src/b.go:3" {
+		t.Errorf("impl = %s", got)
+	}
+	if got := strings.Join(m.Specs[1].Gaps, ","); got != GapNoImpl {
+		t.Errorf("SPEC-AUTH-002 gaps = %s", got)
+	}
+	if got := strings.Join(m.Specs[2].Gaps, ","); got != GapNoImpl+","+GapNoTests {
+		t.Errorf("SPEC-AUTH-003 gaps = %s", got)
+	}
+	if len(m.Gaps()) != 2 {
+		t.Errorf("Gaps() = %d, want 2", len(m.Gaps()))
+	}
+
+	var buf bytes.Buffer
+	if err := m.Render(&buf, MatrixCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 4 {
+		t.Fatalf("csv = %v, %v", records, err)
+	}
+	if records[1][3] != "src/a.go:9\nsrc/b.go:3" || records[1][6] != "a\nb" {
+		t.Errorf("csv row = %q", records[1])
+	}
+
+	buf.Reset()
+	if err := m.Render(&buf, MatrixHTML); err != nil || !strings.Contains(buf.String(), "SPEC-GONE-001") {
+		t.Errorf("html: %v", err)
+	}
+}
@@ -2,36 +2,23 @@
 package tag
 
 import (
-	"bufio"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 // ExtractTagsFromSource extracts all @SPEC TAGs from source code.
-// Works with any language that uses # for comments (Python, shell, YAML, etc.)
+// Comments are found with the syntax for filePath's extension (see
+// CommentSyntaxes): line comments such as //, # and --, block comments
+// such as /* */ and <!-- -->, skipping string literals. A TAG is a comment
+// (or a line of a block comment) that starts with @SPEC.
 func ExtractTagsFromSource(source, filePath string) []*TAG {
 	var tags []*TAG
 
-	scanner := bufio.NewScanner(strings.NewReader(source))
-	lineNumber := 0
-
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-
-		// Find comment portion (from # to end of line)
-		commentStart := strings.Index(line, "#")
-		if commentStart == -1 {
-			continue
-		}
-
-		// Extract the comment portion
-		commentPortion := line[commentStart:]
-
-		// Parse TAG from comment
-		tag := ParseTAGString(commentPortion, filePath, lineNumber)
-		if tag != nil {
+	for _, c := range LexComments(source, SyntaxForFile(filePath)) {
+		if tag := parseTAGText(c.Text, filePath, c.Line); tag != nil {
 			tags = append(tags, tag)
 		}
 	}
@@ -112,40 +99,73 @@ func ExtractTagsFromDirectory(directory, pattern string, recursive bool) []*TAG
 	return ExtractTagsFromFiles(files)
 }
 
-// SupportedExtensions lists file extensions that support # comments.
-var SupportedExtensions = []string{
-	".py",     // Python
-	".sh",     // Shell
-	".bash",   // Bash
-	".yaml",   // YAML
-	".yml",    // YAML
-	".toml",   // TOML
-	".rb",     // Ruby
-	".pl",     // Perl
-	".r",      // R
-	".coffee", // CoffeeScript
+// SupportedExtensions lists, sorted, the file extensions with a known
+// comment syntax. It is filled from CommentSyntaxes and refreshed by
+// RegisterCommentSyntax.
+var SupportedExtensions []string
+
+// supportedExtensions returns the sorted extensions of CommentSyntaxes.
+func supportedExtensions() []string {
+	exts := make([]string, 0, len(CommentSyntaxes))
+	for ext := range CommentSyntaxes {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
 }
 
-// IsSupportedFile checks if a file extension supports # comments.
+// IsSupportedFile checks if a file extension has a known comment syntax.
 func IsSupportedFile(filePath string) bool {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	for _, supported := range SupportedExtensions {
-		if ext == supported {
-			return true
-		}
-	}
-	return false
+	_, ok := CommentSyntaxes[strings.ToLower(filepath.Ext(filePath))]
+	return ok
+}
+
+// IgnoredDirs are directory names never scanned for TAGs: version control
+// metadata and dependency trees.
+var IgnoredDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
 }
 
 // ExtractTagsFromSupportedFiles extracts TAGs only from files with supported extensions.
 func ExtractTagsFromSupportedFiles(directory string, recursive bool) []*TAG {
 	var allTags []*TAG
 
-	for _, ext := range SupportedExtensions {
-		pattern := "*" + ext
-		tags := ExtractTagsFromDirectory(directory, pattern, recursive)
-		allTags = append(allTags, tags...)
-	}
+	walkSupportedFiles(directory, recursive, func(path string, _ fs.DirEntry) {
+		allTags = append(allTags, ExtractTagsFromFile(path)...)
+	})
 
 	return allTags
 }
+
+// SupportedFiles lists every file with a supported extension under
+// directory, skipping IgnoredDirs.
+func SupportedFiles(directory string) []string {
+	var files []string
+	walkSupportedFiles(directory, true, func(path string, _ fs.DirEntry) {
+		files = append(files, path)
+	})
+	return files
+}
+
+// walkSupportedFiles calls fn for every file with a supported extension
+// in directory, in a single pass, skipping IgnoredDirs.
+func walkSupportedFiles(directory string, recursive bool, fn func(path string, e fs.DirEntry)) {
+	_ = filepath.WalkDir(directory, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Continue on error
+		}
+		if e.IsDir() {
+			if path != directory && (!recursive || IgnoredDirs[e.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if e.Type().IsRegular() && IsSupportedFile(path) {
+			fn(path, e)
+		}
+		return nil
+	})
+}
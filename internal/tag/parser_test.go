@@ -0,0 +1,62 @@
+package tag
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractTagsFromSource(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+		want   []string // "SPEC-ID:verb@line"
+	}{
+		{"svc.py", "# @SPEC SPEC-AUTH-001\nx = \"# @SPEC SPEC-AUTH-009\"\n'''\n# @SPEC SPEC-AUTH-008\n'''\n",
+			[]string{"SPEC-AUTH-001:impl@1"}},
+		{"Login.java", "/**\n * Login service.\n * @SPEC SPEC-AUTH-001 impl\n */\nclass Login { String s = \"// @SPEC SPEC-AUTH-009\"; } // @SPEC SPEC-AUTH-002 depends\n",
+			[]string{"SPEC-AUTH-001:impl@3", "SPEC-AUTH-002:depends@5"}},
+		{"Auth.cs", "/// @SPEC SPEC-AUTH-001\nvar p = @\"C:\\dir\\\"; // @SPEC SPEC-AUTH-003 verify\n",
+			[]string{"SPEC-AUTH-001:impl@1", "SPEC-AUTH-003:verify@2"}},
+		{"auth.ts", "const q = `\n// @SPEC SPEC-AUTH-009\n`;\n/* @SPEC SPEC-AUTH-001 verify */\n",
+			[]string{"SPEC-AUTH-001:verify@4"}},
+		{"main.go", "var re = `/* @SPEC SPEC-AUTH-009 */`\n// @SPEC SPEC-AUTH-001\n",
+			[]string{"SPEC-AUTH-001:impl@2"}},
+		{"index.php", "<?php\n# @SPEC SPEC-AUTH-001\necho 'it''s // @SPEC SPEC-AUTH-009';\n",
+			[]string{"SPEC-AUTH-001:impl@2"}},
+		{"schema.sql", "-- @SPEC SPEC-DB-001\nSELECT 'it''s -- @SPEC SPEC-DB-009' FROM t; /* @SPEC SPEC-DB-002 related */\n",
+			[]string{"SPEC-DB-001:impl@1", "SPEC-DB-002:related@2"}},
+		{"login.jsp", "<%-- @SPEC SPEC-WEB-001 --%>\n<p>Don't panic</p>\n<!--\n  @SPEC SPEC-WEB-002 verify\n-->\n",
+			[]string{"SPEC-WEB-001:impl@1", "SPEC-WEB-002:verify@4"}},
+		{"Login.vue", "<template>\n  <!-- @SPEC SPEC-WEB-001 -->\n  <p>Don't panic</p>\n</template>\n<script>\n// @SPEC SPEC-WEB-002 verify\nconst s = \"// @SPEC SPEC-WEB-009\";\n</script>\n",
+			[]string{"SPEC-WEB-001:impl@2", "SPEC-WEB-002:verify@6"}},
+		{"Dockerfile", "FROM x\n# @SPEC SPEC-OPS-001\n", []string{"SPEC-OPS-001:impl@2"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, tag := range ExtractTagsFromSource(tt.source, tt.file) {
+			got = append(got, fmt.Sprintf("%s:%s@%d", tag.SpecID, tag.Verb, tag.Line))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: got %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterCommentSyntax(t *testing.T) {
+	RegisterCommentSyntax(&CommentSyntax{Name: "ada", Line: []string{"--"}}, ".ADB")
+	defer func() {
+		delete(CommentSyntaxes, ".adb")
+		SupportedExtensions = supportedExtensions()
+	}()
+	if !IsSupportedFile("pkg.adb") {
+		t.Error(".adb should be supported after registration")
+	}
+	if !slices.Contains(SupportedExtensions, ".adb") {
+		t.Error("SupportedExtensions should include a registered extension")
+	}
+	if tags := ExtractTagsFromSource("-- @SPEC SPEC-ADA-001 verify\n", "pkg.adb"); len(tags) != 1 || tags[0].Verb != "verify" {
+		t.Errorf("tags = %v", tags)
+	}
+}
@@ -0,0 +1,310 @@
+package tag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyFile is the TAG policy location relative to the project root.
+const PolicyFile = ".jikime/config/tag-policy.yaml"
+
+// Violation levels. A rule at LevelOff is not checked.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelHint    = "hint"
+	LevelOff     = "off"
+)
+
+// Policy rule names, as reported in Violation.Rule.
+const (
+	RuleFormat        = "format"
+	RuleRequireImpl   = "require-impl"
+	RuleRequireVerify = "require-verify"
+	RuleDomain        = "domain"
+	RuleDeprecated    = "deprecated"
+)
+
+// Rule is a policy rule's severity: error, warning, hint or off.
+type Rule struct {
+	Level string `yaml:"level" json:"level"`
+}
+
+// Enabled reports whether the rule is checked.
+func (r Rule) Enabled() bool {
+	return r.Level != "" && r.Level != LevelOff
+}
+
+// PathRule applies to files matching Paths and not Exclude. Patterns are
+// relative to the project root with "*" (within a directory) and "**"
+// (across directories); a pattern without "/" matches the file name.
+type PathRule struct {
+	Rule    `yaml:",inline"`
+	Paths   []string `yaml:"paths" json:"paths"`
+	Exclude []string `yaml:"exclude" json:"exclude"`
+}
+
+// DomainRule restricts SPEC-ID domains (the AUTH in SPEC-AUTH-001).
+type DomainRule struct {
+	Rule    `yaml:",inline"`
+	Allowed []string `yaml:"allowed" json:"allowed"`
+}
+
+// DeprecatedRule forbids TAGs referencing deprecated SPECs: those whose
+// spec.md status is "deprecated" and those listed in Specs.
+type DeprecatedRule struct {
+	Rule  `yaml:",inline"`
+	Specs []string `yaml:"specs" json:"specs"`
+}
+
+// Policy is the project's TAG validation policy, read from PolicyFile.
+type Policy struct {
+	// Mode is the default validation mode: warn, enforce or off.
+	Mode string `yaml:"mode" json:"mode"`
+	// Format flags malformed TAGs, e.g. "@spec spec-auth-1 Impl".
+	Format Rule `yaml:"format" json:"format"`
+	// RequireImpl requires at least one impl TAG in matching files.
+	// Files with a verify TAG are tests and exempt.
+	RequireImpl PathRule `yaml:"require_impl" json:"require_impl"`
+	// RequireVerify requires a verify TAG for every SPEC.
+	RequireVerify Rule `yaml:"require_verify" json:"require_verify"`
+	// Domains limits SPEC-IDs to the project's domain list.
+	Domains DomainRule `yaml:"domains" json:"domains"`
+	// Deprecated forbids references to deprecated SPECs.
+	Deprecated DeprecatedRule `yaml:"deprecated" json:"deprecated"`
+}
+
+// DefaultPolicy is used when the project has no policy file: warn mode,
+// malformed TAGs and references to deprecated SPECs reported as warnings,
+// every other rule off.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Mode:          "warn",
+		Format:        Rule{Level: LevelWarning},
+		RequireImpl:   PathRule{Rule: Rule{Level: LevelOff}},
+		RequireVerify: Rule{Level: LevelOff},
+		Domains:       DomainRule{Rule: Rule{Level: LevelOff}},
+		Deprecated:    DeprecatedRule{Rule: Rule{Level: LevelWarning}},
+	}
+}
+
+// LoadPolicy reads the tag_policy section of PolicyFile under
+// projectRoot. Settings it leaves out keep their DefaultPolicy values.
+func LoadPolicy(projectRoot string) (*Policy, error) {
+	p := DefaultPolicy()
+	data, err := os.ReadFile(filepath.Join(projectRoot, PolicyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return nil, err
+	}
+	file := struct {
+		TagPolicy *Policy `yaml:"tag_policy"`
+	}{TagPolicy: p}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", PolicyFile, err)
+	}
+	return p, p.validate()
+}
+
+func (p *Policy) validate() error {
+	switch p.Mode {
+	case "warn", "enforce", "off":
+	default:
+		return fmt.Errorf("%s: mode must be warn, enforce or off, got %q", PolicyFile, p.Mode)
+	}
+	for name, r := range map[string]Rule{
+		"format": p.Format, "require_impl": p.RequireImpl.Rule, "require_verify": p.RequireVerify,
+		"domains": p.Domains.Rule, "deprecated": p.Deprecated.Rule,
+	} {
+		switch r.Level {
+		case "", LevelError, LevelWarning, LevelHint, LevelOff:
+		default:
+			return fmt.Errorf("%s: %s.level must be error, warning, hint or off, got %q", PolicyFile, name, r.Level)
+		}
+	}
+	for _, pattern := range append(p.RequireImpl.Paths, p.RequireImpl.Exclude...) {
+		if _, err := globRegexp(pattern); err != nil {
+			return fmt.Errorf("%s: require_impl pattern %q: %w", PolicyFile, pattern, err)
+		}
+	}
+	return nil
+}
+
+// Violation is a policy rule broken at a file location. Line is 0 for
+// rules about a whole file or SPEC.
+type Violation struct {
+	Rule    string  `json:"rule"`
+	Level   string  `json:"level"`
+	File    string  `json:"file"`
+	Line    int     `json:"line"`
+	SpecID  string  `json:"spec_id,omitempty"`
+	Message string  `json:"message"`
+	Fix     *TagFix `json:"fix,omitempty"`
+}
+
+// PolicyChecker applies a Policy to files and SPECs of one project.
+type PolicyChecker struct {
+	Policy     *Policy
+	Root       string
+	deprecated map[string]bool
+	domains    map[string]bool
+}
+
+// NewChecker prepares p for the project at root; specs supply the
+// deprecated status of each SPEC.
+func (p *Policy) NewChecker(root string, specs []*SpecDocument) *PolicyChecker {
+	c := &PolicyChecker{Policy: p, Root: root, deprecated: map[string]bool{}, domains: map[string]bool{}}
+	for _, id := range p.Deprecated.Specs {
+		c.deprecated[id] = true
+	}
+	for _, doc := range specs {
+		if doc.Status == "deprecated" {
+			c.deprecated[doc.ID] = true
+		}
+	}
+	for _, d := range p.Domains.Allowed {
+		c.domains[strings.ToUpper(d)] = true
+	}
+	return c
+}
+
+// CheckFile applies the per-file rules (format, require-impl, domain,
+// deprecated) to a file's TAGs.
+func (c *PolicyChecker) CheckFile(filePath string) []Violation {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	source := string(content)
+	rel := relPath(c.Root, filePath)
+	p := c.Policy
+	var out []Violation
+
+	if p.Format.Enabled() {
+		for _, f := range FindMalformedTags(source, filePath) {
+			msg := "Malformed TAG: " + strings.Join(f.Problems, "; ")
+			if f.Fixable() {
+				msg += " (fix with --fix)"
+			}
+			fix := f
+			out = append(out, Violation{Rule: RuleFormat, Level: p.Format.Level, File: rel, Line: f.Line, Message: msg, Fix: &fix})
+		}
+	}
+
+	tags := ExtractTagsFromSource(source, filePath)
+	if p.RequireImpl.Enabled() && c.requiresImpl(rel) && !hasVerb(tags, "impl") && !hasVerb(tags, "verify") {
+		out = append(out, Violation{Rule: RuleRequireImpl, Level: p.RequireImpl.Level, File: rel,
+			Message: "File has no impl TAG (add: @SPEC SPEC-XXX-NNN impl)"})
+	}
+
+	for _, t := range tags {
+		if p.Domains.Enabled() && len(c.domains) > 0 && !c.domains[specDomain(t.SpecID)] {
+			out = append(out, Violation{Rule: RuleDomain, Level: p.Domains.Level, File: rel, Line: t.Line, SpecID: t.SpecID,
+				Message: fmt.Sprintf("SPEC domain %s is not in the project domain list (%s)", specDomain(t.SpecID), strings.Join(p.Domains.Allowed, ", "))})
+		}
+		if p.Deprecated.Enabled() && c.deprecated[t.SpecID] {
+			out = append(out, Violation{Rule: RuleDeprecated, Level: p.Deprecated.Level, File: rel, Line: t.Line, SpecID: t.SpecID,
+				Message: fmt.Sprintf("References deprecated SPEC %s", t.SpecID)})
+		}
+	}
+	return out
+}
+
+// CheckVerify applies require-verify: each SPEC in specIDs needs a verify
+// TAG somewhere in the graph.
+func (c *PolicyChecker) CheckVerify(specIDs []string, g *Graph) []Violation {
+	if !c.Policy.RequireVerify.Enabled() {
+		return nil
+	}
+	sort.Strings(specIDs)
+	var out []Violation
+	for _, id := range specIDs {
+		if c.deprecated[id] {
+			continue
+		}
+		if n := g.Node(id); n == nil || len(n.Verify) == 0 {
+			out = append(out, Violation{Rule: RuleRequireVerify, Level: c.Policy.RequireVerify.Level,
+				File: relPath(c.Root, filepath.Join(c.Root, ".jikime", "specs", id)), SpecID: id,
+				Message: fmt.Sprintf("%s has no verify TAG (tag its tests: @SPEC %s verify)", id, id)})
+		}
+	}
+	return out
+}
+
+func (c *PolicyChecker) requiresImpl(rel string) bool {
+	return matchAnyGlob(c.Policy.RequireImpl.Paths, rel) && !matchAnyGlob(c.Policy.RequireImpl.Exclude, rel)
+}
+
+func hasVerb(tags []*TAG, verb string) bool {
+	for _, t := range tags {
+		if t.Verb == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// specDomain returns the DOMAIN of SPEC-{DOMAIN}-{NUMBER}.
+func specDomain(specID string) string {
+	parts := strings.Split(specID, "-")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+func matchAnyGlob(patterns []string, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		re, err := globRegexp(pattern)
+		if err != nil {
+			continue
+		}
+		target := rel
+		if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+			target = filepath.Base(rel)
+		}
+		if re.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp compiles a path pattern: "**/" matches any directories,
+// "**" anything, "*" and "?" stay within a path segment, and a trailing
+// "/" matches everything under a directory.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case ch == '*':
+			b.WriteString("[^/]*")
+		case ch == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
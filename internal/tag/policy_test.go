@@ -0,0 +1,134 @@
+package tag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFixTags(t *testing.T) {
+	source := "package auth\n\n" +
+		"// @spec spec_auth_1 Impl handles login\n" +
+		"var s = \"// @spec spec-auth-1\"\n" +
+		"/* @SPEC AUTH-002 tests */\n" +
+		"// @SPEC SPEC-AUTH-003 verify\n" +
+		"// @SPEC SPEC-AUTH-004 implementation details follow\n" +
+		"// @SPEC SPEC-AUTH-0001\n"
+
+	fixed, fixes := FixTags(source, "auth.go")
+	want := "package auth\n\n" +
+		"// @SPEC SPEC-AUTH-001 impl handles login\n" +
+		"var s = \"// @spec spec-auth-1\"\n" +
+		"/* @SPEC SPEC-AUTH-002 verify */\n" +
+		"// @SPEC SPEC-AUTH-003 verify\n" +
+		"// @SPEC SPEC-AUTH-004 implementation details follow\n" +
+		"// @SPEC SPEC-AUTH-0001\n"
+	if fixed != want {
+		t.Errorf("fixed source:\n%s\nwant:\n%s", fixed, want)
+	}
+	if len(fixes) != 3 || !fixes[0].Fixable() || fixes[2].Fixable() || fixes[2].Line != 8 {
+		t.Fatalf("fixes = %+v", fixes)
+	}
+	if got := strings.Join(fixes[0].Problems, "; "); got != `marker "@spec" should be @SPEC; SPEC-ID "spec_auth_1" should be SPEC-AUTH-001; verb "Impl" should be impl` {
+		t.Errorf("problems = %s", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "auth.go")
+	if err := os.WriteFile(path, []byte(source), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FixTagsInFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+	if tags := ExtractTagsFromFile(path); len(tags) != 4 {
+		t.Errorf("tags after fix = %d, want 4", len(tags))
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) string {
+		t.Helper()
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	p, err := LoadPolicy(root)
+	if err != nil || !reflect.DeepEqual(p, DefaultPolicy()) {
+		t.Fatalf("LoadPolicy without file = %+v, %v", p, err)
+	}
+
+	write(PolicyFile, `tag_policy:
+  mode: enforce
+  require_impl:
+    level: error
+    paths: ["src/**"]
+    exclude: ["*_test.go", "src/gen/"]
+  require_verify:
+    level: warning
+  domains:
+    level: error
+    allowed: [AUTH, API]
+  deprecated:
+    level: error
+    specs: [SPEC-API-009]
+`)
+	if p, err = LoadPolicy(root); err != nil {
+		t.Fatal(err)
+	}
+	if p.Mode != "enforce" || p.Format.Level != LevelWarning || p.RequireImpl.Level != LevelError {
+		t.Fatalf("policy = %+v", p)
+	}
+
+	specs := []*SpecDocument{{ID: "SPEC-AUTH-001"}, {ID: "SPEC-AUTH-002", Status: "deprecated"}}
+	c := p.NewChecker(root, specs)
+	rules := func(file string) []string {
+		var out []string
+		for _, v := range c.CheckFile(file) {
+			out = append(out, v.Rule+":"+v.Level)
+		}
+		return out
+	}
+
+	cases := []struct {
+		rel, content string
+		want         []string
+	}{
+		{"src/ok.go", "// @SPEC SPEC-AUTH-001\n", nil},
+		{"src/none.go", "package src\n", []string{"require-impl:error"}},
+		{"src/none_test.go", "package src\n", nil},
+		{"src/gen/out.go", "package gen\n", nil},
+		{"src/tested.go", "// @SPEC SPEC-AUTH-001 verify\n", nil},
+		{"lib/free.go", "package lib\n", nil},
+		{"src/old.go", "// @SPEC SPEC-AUTH-002\n// @SPEC SPEC-API-009 depends\n", []string{"deprecated:error", "deprecated:error"}},
+		{"src/domain.go", "// @SPEC SPEC-BILL-001\n", []string{"domain:error"}},
+		{"src/bad.go", "// @SPEC spec-auth-1\n", []string{"format:warning", "require-impl:error"}},
+	}
+	for _, tc := range cases {
+		if got := rules(write(tc.rel, tc.content)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: violations = %v, want %v", tc.rel, got, tc.want)
+		}
+	}
+
+	g := BuildGraph(root, "", specs, []*TAG{NewTAG("SPEC-AUTH-001", "verify", filepath.Join(root, "a_test.go"), 1)})
+	vs := c.CheckVerify([]string{"SPEC-AUTH-001", "SPEC-AUTH-002", "SPEC-API-001"}, g)
+	if len(vs) != 1 || vs[0].SpecID != "SPEC-API-001" || vs[0].Level != LevelWarning {
+		t.Errorf("CheckVerify = %+v", vs)
+	}
+
+	write(PolicyFile, "tag_policy:\n  format:\n    level: fatal\n")
+	if _, err := LoadPolicy(root); err == nil {
+		t.Error("LoadPolicy accepted level fatal")
+	}
+}
@@ -0,0 +1,218 @@
+package tag
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecDocument is what the TAG tools read from a SPEC directory
+// (.jikime/specs/SPEC-XXX-NNN/{spec.md, plan.md, acceptance.md}).
+type SpecDocument struct {
+	ID         string   `json:"spec_id"`
+	Dir        string   `json:"dir"`
+	Title      string   `json:"title,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Acceptance []string `json:"acceptance_criteria"`
+	Depends    []string `json:"depends,omitempty"` // from front-matter
+	Related    []string `json:"related,omitempty"` // from front-matter
+}
+
+// LoadSpecDocuments reads every SPEC directory under specsDir whose name
+// is a valid SPEC-ID, sorted by ID. A missing specsDir yields no SPECs.
+func LoadSpecDocuments(specsDir string) ([]*SpecDocument, error) {
+	entries, err := os.ReadDir(specsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var docs []*SpecDocument
+	for _, e := range entries {
+		if e.IsDir() && ValidateSpecIDFormat(e.Name()) {
+			docs = append(docs, LoadSpecDocument(specsDir, e.Name()))
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+// LoadSpecDocument reads one SPEC directory; missing files leave their
+// fields empty.
+func LoadSpecDocument(specsDir, specID string) *SpecDocument {
+	dir := filepath.Join(specsDir, specID)
+	spec := filepath.Join(dir, "spec.md")
+	doc := &SpecDocument{ID: specID, Dir: dir, Acceptance: []string{}}
+	doc.Title, doc.Summary = ReadSpecSummary(spec, specID)
+	doc.Status = readSpecStatus(spec)
+	fm := readSpecFrontMatter(spec)
+	doc.Depends, doc.Related = fm.Depends, fm.Related
+	if doc.Title == "" {
+		doc.Title = fm.Title
+	}
+	if fm.Status != "" {
+		doc.Status = strings.ToLower(fm.Status)
+	}
+	if criteria := ReadAcceptanceCriteria(filepath.Join(dir, "acceptance.md")); criteria != nil {
+		doc.Acceptance = criteria
+	}
+	return doc
+}
+
+// ReadSpecSummary returns the first heading (minus a leading SPEC ID) and
+// the first prose paragraph of a SPEC document.
+func ReadSpecSummary(path, specID string) (string, string) {
+	if path == "" {
+		return "", ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	var (
+		title       string
+		paragraph   []string
+		inFence     bool
+		frontMatter bool
+	)
+	sc := bufio.NewScanner(f)
+	for n := 0; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if n == 0 && line == "---" || frontMatter {
+			frontMatter = !(frontMatter && line == "---")
+			continue
+		}
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		switch {
+		case inFence:
+			continue
+		case title == "" && strings.HasPrefix(line, "# "):
+			title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			if fields := strings.Fields(title); len(fields) > 1 && isSpecIDWord(strings.TrimRight(fields[0], ":"), specID) {
+				title = strings.TrimLeft(strings.TrimSpace(strings.TrimPrefix(title, fields[0])), ":-– ")
+			}
+		case line == "":
+			if len(paragraph) > 0 {
+				return title, strings.Join(paragraph, " ")
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "|"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "-"), strings.HasPrefix(line, "*"), strings.HasPrefix(line, ">"):
+			if len(paragraph) > 0 {
+				return title, strings.Join(paragraph, " ")
+			}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	return title, strings.Join(paragraph, " ")
+}
+
+func isSpecIDWord(word, specID string) bool {
+	return word == specID || SpecIDPattern.MatchString(word)
+}
+
+// ReadAcceptanceCriteria collects the checklist items and scenario
+// headings (###) of an acceptance.md, without checkboxes.
+func ReadAcceptanceCriteria(path string) []string {
+	if path == "" {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- [ ]"), strings.HasPrefix(trimmed, "- [x]"), strings.HasPrefix(trimmed, "- [X]"):
+			out = append(out, strings.TrimSpace(trimmed[5:]))
+		case strings.HasPrefix(trimmed, "### "):
+			out = append(out, strings.TrimSpace(trimmed[4:]))
+		}
+	}
+	return out
+}
+
+// specFrontMatter is the optional YAML front-matter of a spec.md:
+//
+//	---
+//	status: approved
+//	depends: [SPEC-AUTH-001]
+//	related: SPEC-USER-002, SPEC-USER-003
+//	---
+type specFrontMatter struct {
+	Title   string   `yaml:"title"`
+	Status  string   `yaml:"status"`
+	Depends specList `yaml:"depends"`
+	Related specList `yaml:"related"`
+}
+
+// specList accepts a YAML list or a comma/space separated string of
+// SPEC-IDs, keeping only valid ones.
+type specList []string
+
+func (l *specList) UnmarshalYAML(node *yaml.Node) error {
+	var items []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+	case yaml.ScalarNode:
+		items = strings.FieldsFunc(node.Value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); ValidateSpecIDFormat(item) {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+func readSpecFrontMatter(path string) specFrontMatter {
+	var fm specFrontMatter
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fm
+	}
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return fm
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return fm
+	}
+	_ = yaml.Unmarshal([]byte(text[4:4+end]), &fm)
+	return fm
+}
+
+// specStatusPattern matches "status: Draft", "**Status**: Draft" and
+// "| Status | Draft |" metadata lines.
+var specStatusPattern = regexp.MustCompile(`(?i)^\|?\s*\**status\**\s*[:|]\s*\**([A-Za-z][\w -]*?)\**\s*\|?\s*$`)
+
+func readSpecStatus(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if m := specStatusPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return strings.ToLower(strings.TrimSpace(m[1]))
+		}
+	}
+	return ""
+}
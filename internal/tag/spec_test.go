@@ -0,0 +1,24 @@
+package tag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSpecDocuments(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.md")
+	os.WriteFile(spec, []byte("# SPEC-AUTH-001: JWT login\n\n| ID | x |\n\nUsers sign in\nwith a token.\n\n## Requirements\n"), 0644)
+	acceptance := filepath.Join(dir, "acceptance.md")
+	os.WriteFile(acceptance, []byte("# Acceptance\n\n### Valid login\n- [x] returns a token\n"), 0644)
+
+	title, summary := ReadSpecSummary(spec, "SPEC-AUTH-001")
+	if title != "JWT login" || summary != "Users sign in with a token." {
+		t.Errorf("ReadSpecSummary = %q, %q", title, summary)
+	}
+	criteria := ReadAcceptanceCriteria(acceptance)
+	if len(criteria) != 2 || criteria[0] != "Valid login" || criteria[1] != "returns a token" {
+		t.Errorf("ReadAcceptanceCriteria = %q", criteria)
+	}
+}
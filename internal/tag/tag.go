@@ -26,7 +26,8 @@ var ValidVerbs = map[string]bool{
 const DefaultVerb = "impl"
 
 // TAG represents a single TAG annotation.
-// TAG format: # @SPEC SPEC-ID [verb]
+// TAG format: # @SPEC SPEC-ID [verb], or the same after any comment marker
+// of the file's language (see CommentSyntaxes).
 type TAG struct {
 	SpecID   string `json:"spec_id"`   // SPEC identifier (e.g., "SPEC-AUTH-001")
 	Verb     string `json:"verb"`      // TAG relationship verb (impl, verify, depends, related)
@@ -93,8 +93,13 @@ func ParseTAGString(comment, filePath string, line int) *TAG {
 		return nil
 	}
 
-	// Remove # and leading whitespace
-	comment = strings.TrimSpace(comment[1:])
+	return parseTAGText(comment[1:], filePath, line)
+}
+
+// parseTAGText parses TAG from the text of a comment, without its marker.
+// Format: @SPEC SPEC-ID [verb]
+func parseTAGText(comment, filePath string, line int) *TAG {
+	comment = strings.TrimSpace(comment)
 
 	// Check for @SPEC prefix
 	if !strings.HasPrefix(comment, "@SPEC") {
@@ -0,0 +1,86 @@
+package tag
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce batches the events of an editor save or a checkout into
+// one update.
+const watchDebounce = 300 * time.Millisecond
+
+// Watch keeps the database live: it watches every directory under root
+// (except IgnoredDirs) and calls UpdateFiles with the paths changed in
+// each burst of events, reporting the result through onUpdate. It blocks
+// until ctx is done. Call Sync first to catch up with changes made while
+// nothing was watching.
+func (lm *LinkageManager) Watch(ctx context.Context, root string, onUpdate func(*IndexStats, error)) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	// fsnotify is not recursive: watch every directory that exists now and
+	// pick up new ones as they are created.
+	addTree := func(dir string) {
+		_ = filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+			if err != nil || !e.IsDir() {
+				return nil
+			}
+			if path != dir && IgnoredDirs[e.Name()] {
+				return filepath.SkipDir
+			}
+			_ = w.Add(path)
+			return nil
+		})
+	}
+	addTree(root)
+
+	pending := map[string]bool{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if IgnoredDirs[filepath.Base(ev.Name)] {
+				continue
+			}
+			if ev.Op&fsnotify.Create != 0 {
+				addTree(ev.Name)
+			}
+			pending[ev.Name] = true
+			if debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			onUpdate(nil, err)
+		case <-debounce:
+			debounce = nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			pending = map[string]bool{}
+			stats, err := lm.UpdateFiles(paths)
+			if err != nil || stats.Changed() {
+				onUpdate(stats, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package team
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Checkpoint is a commit in an agent's workspace recorded as a restore point.
+// Persisted to ~/.jikime/teams/<team>/checkpoints/<agent>/<id>.json.
+type Checkpoint struct {
+	// ID is the unique identifier for this checkpoint (UUID v4).
+	ID string `json:"id"`
+
+	// TeamName is the team the workspace belongs to.
+	TeamName string `json:"team_name"`
+
+	// AgentID owns the workspace that was checkpointed.
+	AgentID string `json:"agent_id"`
+
+	// TaskID is the task the agent was working on, if known.
+	TaskID string `json:"task_id,omitempty"`
+
+	// SHA is the workspace commit the checkpoint points at.
+	SHA string `json:"sha"`
+
+	// Message is the commit message.
+	Message string `json:"message,omitempty"`
+
+	// CreatedAt is when the checkpoint was taken.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CheckpointStore records workspace checkpoints per agent.
+type CheckpointStore struct {
+	dir string
+}
+
+// NewCheckpointStore creates a CheckpointStore rooted at dir.
+func NewCheckpointStore(dir string) (*CheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("team/checkpoint: mkdir %s: %w", dir, err)
+	}
+	return &CheckpointStore{dir: dir}, nil
+}
+
+// Record persists cp, filling in ID and CreatedAt when empty.
+func (s *CheckpointStore) Record(cp *Checkpoint) (*Checkpoint, error) {
+	if cp.AgentID == "" || cp.SHA == "" {
+		return nil, fmt.Errorf("team/checkpoint: agent and sha are required")
+	}
+	if cp.ID == "" {
+		cp.ID = uuid.New().String()
+	}
+	if cp.CreatedAt.IsZero() {
+		cp.CreatedAt = time.Now()
+	}
+	dir := filepath.Join(s.dir, cp.AgentID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("team/checkpoint: mkdir %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("team/checkpoint: marshal: %w", err)
+	}
+	path := filepath.Join(dir, cp.ID+".json")
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return nil, fmt.Errorf("team/checkpoint: write: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return nil, fmt.Errorf("team/checkpoint: rename: %w", err)
+	}
+	return cp, nil
+}
+
+// List returns an agent's checkpoints, oldest first.
+func (s *CheckpointStore) List(agentID string) ([]*Checkpoint, error) {
+	dir := filepath.Join(s.dir, agentID)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("team/checkpoint: readdir: %w", err)
+	}
+	var out []*Checkpoint
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var cp Checkpoint
+		if err := json.Unmarshal(data, &cp); err != nil {
+			continue
+		}
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Latest returns the agent's most recent checkpoint, or nil if there is none.
+func (s *CheckpointStore) Latest(agentID string) (*Checkpoint, error) {
+	cps, err := s.List(agentID)
+	if err != nil || len(cps) == 0 {
+		return nil, err
+	}
+	return cps[len(cps)-1], nil
+}
+
+// Resolve finds an agent's checkpoint by ID, ID prefix, commit SHA prefix,
+// or 1-based position in List ("#3"). An ambiguous prefix is an error.
+func (s *CheckpointStore) Resolve(agentID, ref string) (*Checkpoint, error) {
+	cps, err := s.List(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := strings.CutPrefix(ref, "#"); ok {
+		var i int
+		if _, err := fmt.Sscanf(n, "%d", &i); err != nil || i < 1 || i > len(cps) {
+			return nil, fmt.Errorf("team/checkpoint: %s has no checkpoint %s", agentID, ref)
+		}
+		return cps[i-1], nil
+	}
+	var match *Checkpoint
+	for _, cp := range cps {
+		if cp.ID == ref || cp.SHA == ref {
+			return cp, nil
+		}
+		if len(ref) >= 4 && (strings.HasPrefix(cp.ID, ref) || strings.HasPrefix(cp.SHA, ref)) {
+			if match != nil && match.ID != cp.ID {
+				return nil, fmt.Errorf("team/checkpoint: %q matches more than one checkpoint", ref)
+			}
+			match = cp
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("team/checkpoint: %s has no checkpoint %q", agentID, ref)
+	}
+	return match, nil
+}
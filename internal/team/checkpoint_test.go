@@ -0,0 +1,65 @@
+package team
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckpointStore_Resolve(t *testing.T) {
+	cs, err := NewCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now()
+	for i, sha := range []string{"aaaa1111", "bbbb2222", "aaaa3333"} {
+		if _, err := cs.Record(&Checkpoint{AgentID: "w1", SHA: sha, CreatedAt: base.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if cp, err := cs.Resolve("w1", "#2"); err != nil || cp.SHA != "bbbb2222" {
+		t.Errorf("#2 = %+v, %v", cp, err)
+	}
+	if cp, err := cs.Resolve("w1", "aaaa3"); err != nil || cp.SHA != "aaaa3333" {
+		t.Errorf("sha prefix = %+v, %v", cp, err)
+	}
+	if _, err := cs.Resolve("w1", "aaaa"); err == nil {
+		t.Error("ambiguous prefix should fail")
+	}
+	if latest, _ := cs.Latest("w1"); latest == nil || latest.SHA != "aaaa3333" {
+		t.Errorf("Latest = %+v", latest)
+	}
+	if cps, _ := cs.List("w2"); len(cps) != 0 {
+		t.Errorf("w2 checkpoints = %v", cps)
+	}
+}
+
+func TestStore_ReopenReblocksDependents(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := s.Create("schema", "", "", nil, 0, nil, "")
+	b, _ := s.Create("queries", "", "", []string{a.ID[:8]}, 0, nil, "")
+	if _, err := s.Claim(a.ID, "w1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Complete(a.ID, "w1", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Get(b.ID); got.Status != TaskStatusPending {
+		t.Fatalf("dependent status = %s, want pending", got.Status)
+	}
+
+	reopened, err := s.Reopen(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Status != TaskStatusPending || reopened.AgentID != "" || reopened.Result != "" || reopened.CompletedAt != nil {
+		t.Errorf("reopened = %+v", reopened)
+	}
+	got, _ := s.Get(b.ID)
+	if got.Status != TaskStatusBlocked || len(got.DependsOn) != 1 || len(got.ResolvedDeps) != 0 {
+		t.Errorf("dependent after reopen = %+v", got)
+	}
+}
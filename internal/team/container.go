@@ -0,0 +1,367 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultContainerImage is the image used when ContainerOptions.Image is empty.
+// It must provide the claude CLI on PATH; jikime is optional (on-exit hook).
+const DefaultContainerImage = "jikime-agent:latest"
+
+// ContainerOptions configures the container spawn backend.
+// Persisted in TeamConfig.Container so every agent in a team shares the same sandbox.
+type ContainerOptions struct {
+	// Runtime is the container CLI to use: "docker" or "podman".
+	// Empty means auto-detect (docker first, then podman).
+	Runtime string `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+
+	// Image is the container image to run. Default: DefaultContainerImage.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// CPUs limits the number of CPUs (e.g. "2", "0.5"). Empty means no limit.
+	CPUs string `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+
+	// Memory limits container memory (e.g. "4g", "512m"). Empty means no limit.
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// Network is the container network mode (e.g. "none", "bridge"). Empty uses the runtime default.
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// PassEnv lists host environment variables forwarded into the container
+	// (e.g. "ANTHROPIC_API_KEY"). Nothing else from the host environment is inherited.
+	PassEnv []string `json:"pass_env,omitempty" yaml:"pass_env,omitempty"`
+}
+
+// ContainerMount is a bind mount from the host into the container.
+type ContainerMount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// ContainerSpec is everything a ContainerRuntime needs to start one agent.
+type ContainerSpec struct {
+	// Name is the container name (unique per team/agent).
+	Name string
+
+	// Image is the image to run.
+	Image string
+
+	// WorkDir is the working directory inside the container.
+	WorkDir string
+
+	// Mounts are bind mounts applied to the container.
+	Mounts []ContainerMount
+
+	// Env is the complete container environment.
+	Env map[string]string
+
+	// CPUs and Memory are resource limits passed through to the runtime.
+	CPUs   string
+	Memory string
+
+	// Network is the network mode. Empty uses the runtime default.
+	Network string
+
+	// Command is the entrypoint command and arguments.
+	Command []string
+
+	// Labels are attached to the container for discovery.
+	Labels map[string]string
+}
+
+// ContainerRuntime starts and stops agent containers.
+// CLIContainerRuntime drives docker or podman; FakeContainerRuntime is for tests.
+type ContainerRuntime interface {
+	// Name returns the runtime identifier recorded in the registry (e.g. "docker").
+	Name() string
+
+	// Run starts a detached container and returns its ID.
+	Run(ctx context.Context, spec ContainerSpec) (string, error)
+
+	// Stop stops and removes the container.
+	Stop(ctx context.Context, id string) error
+
+	// IsRunning reports whether the container is still running.
+	IsRunning(ctx context.Context, id string) (bool, error)
+}
+
+// --- CLI runtime (docker / podman) ---
+
+// CLIContainerRuntime runs containers by shelling out to the docker or podman CLI.
+// Both share the same flags for everything the spawner uses.
+type CLIContainerRuntime struct {
+	binary string
+}
+
+// NewCLIContainerRuntime returns a runtime for binary ("docker" or "podman").
+// Pass "" to auto-detect whichever is on PATH, preferring docker.
+func NewCLIContainerRuntime(binary string) (*CLIContainerRuntime, error) {
+	if binary == "" {
+		for _, candidate := range []string{"docker", "podman"} {
+			if _, err := exec.LookPath(candidate); err == nil {
+				binary = candidate
+				break
+			}
+		}
+		if binary == "" {
+			return nil, fmt.Errorf("team/container: neither docker nor podman found in PATH")
+		}
+	}
+	switch binary {
+	case "docker", "podman":
+	default:
+		return nil, fmt.Errorf("team/container: unsupported runtime %q (use docker or podman)", binary)
+	}
+	return &CLIContainerRuntime{binary: binary}, nil
+}
+
+// Name returns the CLI binary name.
+func (r *CLIContainerRuntime) Name() string { return r.binary }
+
+// Run starts the container with `<binary> run -d`.
+func (r *CLIContainerRuntime) Run(ctx context.Context, spec ContainerSpec) (string, error) {
+	out, err := exec.CommandContext(ctx, r.binary, runArgs(spec)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("team/container: %s run %s: %w\n%s", r.binary, spec.Name, err, out)
+	}
+	id := strings.TrimSpace(string(out))
+	// Image pulls may print progress before the ID; the ID is the last line.
+	if i := strings.LastIndex(id, "\n"); i >= 0 {
+		id = strings.TrimSpace(id[i+1:])
+	}
+	return id, nil
+}
+
+// Stop force-removes the container (stopping it first if running).
+func (r *CLIContainerRuntime) Stop(ctx context.Context, id string) error {
+	out, err := exec.CommandContext(ctx, r.binary, "rm", "-f", id).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("team/container: %s rm %s: %w\n%s", r.binary, id, err, out)
+	}
+	return nil
+}
+
+// IsRunning inspects the container state.
+func (r *CLIContainerRuntime) IsRunning(ctx context.Context, id string) (bool, error) {
+	out, err := exec.CommandContext(ctx, r.binary, "inspect", "-f", "{{.State.Running}}", id).Output()
+	if err != nil {
+		// inspect fails when the container no longer exists.
+		return false, nil
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// runArgs builds the `run` argument list for spec. Order is deterministic.
+func runArgs(spec ContainerSpec) []string {
+	args := []string{"run", "-d", "--name", spec.Name}
+	if spec.WorkDir != "" {
+		args = append(args, "-w", spec.WorkDir)
+	}
+	if spec.CPUs != "" {
+		args = append(args, "--cpus", spec.CPUs)
+	}
+	if spec.Memory != "" {
+		args = append(args, "--memory", spec.Memory)
+	}
+	if spec.Network != "" {
+		args = append(args, "--network", spec.Network)
+	}
+	for _, m := range spec.Mounts {
+		v := m.Source + ":" + m.Target
+		if m.ReadOnly {
+			v += ":ro"
+		}
+		args = append(args, "-v", v)
+	}
+	for _, k := range sortedKeys(spec.Env) {
+		args = append(args, "-e", k+"="+spec.Env[k])
+	}
+	for _, k := range sortedKeys(spec.Labels) {
+		args = append(args, "--label", k+"="+spec.Labels[k])
+	}
+	args = append(args, spec.Image)
+	return append(args, spec.Command...)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// --- Fake runtime ---
+
+// FakeContainerRuntime records container specs in memory instead of running them.
+// Use it in tests via NewSpawnerWithRuntime.
+type FakeContainerRuntime struct {
+	mu      sync.Mutex
+	Specs   []ContainerSpec
+	running map[string]bool
+	// RunErr, if set, is returned by Run.
+	RunErr error
+}
+
+// NewFakeContainerRuntime returns an empty fake runtime.
+func NewFakeContainerRuntime() *FakeContainerRuntime {
+	return &FakeContainerRuntime{running: map[string]bool{}}
+}
+
+// Name returns "fake".
+func (f *FakeContainerRuntime) Name() string { return "fake" }
+
+// Run records spec and marks a new container as running.
+func (f *FakeContainerRuntime) Run(_ context.Context, spec ContainerSpec) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.RunErr != nil {
+		return "", f.RunErr
+	}
+	f.Specs = append(f.Specs, spec)
+	id := fmt.Sprintf("fake-%d", len(f.Specs))
+	f.running[id] = true
+	return id, nil
+}
+
+// Stop marks the container as stopped.
+func (f *FakeContainerRuntime) Stop(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.running[id]; !ok {
+		return fmt.Errorf("team/container: no such container %s", id)
+	}
+	f.running[id] = false
+	return nil
+}
+
+// IsRunning reports whether Run was called for id and Stop was not.
+func (f *FakeContainerRuntime) IsRunning(_ context.Context, id string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.running[id], nil
+}
+
+// --- container backend ---
+//
+// Container backend runs claude non-interactively (like subprocess) inside a
+// sandbox. Only the agent worktree and the team data dir are visible to the
+// agent; both are mounted at their host paths so JIKIME_* paths stay valid.
+
+func (s *Spawner) spawnContainer(cfg SpawnConfig) (*SpawnResult, error) {
+	rt, err := s.containerRuntime(cfg.Container.Runtime)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := buildContainerSpec(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	id, err := rt.Run(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("team/spawner: container spawn %s: %w", cfg.AgentID, err)
+	}
+	return &SpawnResult{
+		AgentID:          cfg.AgentID,
+		ContainerID:      id,
+		ContainerRuntime: rt.Name(),
+	}, nil
+}
+
+// containerRuntime returns the injected runtime, or a CLI runtime for name.
+func (s *Spawner) containerRuntime(name string) (ContainerRuntime, error) {
+	if s.containers != nil {
+		return s.containers, nil
+	}
+	return NewCLIContainerRuntime(name)
+}
+
+// buildContainerSpec translates a SpawnConfig into a ContainerSpec.
+func buildContainerSpec(cfg SpawnConfig) (ContainerSpec, error) {
+	dataDir := cfg.DataDir
+	if dataDir == "" {
+		home, _ := os.UserHomeDir()
+		dataDir = filepath.Join(home, ".jikime")
+	}
+	cfg.DataDir = dataDir
+
+	promptFile, err := writePromptFile(cfg)
+	if err != nil {
+		return ContainerSpec{}, fmt.Errorf("team/spawner: write prompt: %w", err)
+	}
+
+	workDir := cfg.WorktreePath
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	teamData := filepath.Join(dataDir, "teams", cfg.TeamName)
+	if err := os.MkdirAll(teamData, 0o755); err != nil {
+		return ContainerSpec{}, fmt.Errorf("team/spawner: mkdir team dir: %w", err)
+	}
+
+	env := buildEnv(cfg)
+	for _, k := range cfg.Container.PassEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			env[k] = v
+		}
+	}
+
+	claudeCmd := "claude -p"
+	if cfg.SkipPermissions {
+		claudeCmd = "claude --dangerously-skip-permissions -p"
+	}
+	if promptFile != "" {
+		claudeCmd += " < " + shellQuote(promptFile)
+	}
+	// Same on-exit hook as the tmux backend; harmless if jikime is absent.
+	script := claudeCmd + "; jikime team lifecycle on-exit 2>/dev/null; true"
+
+	image := cfg.Container.Image
+	if image == "" {
+		image = DefaultContainerImage
+	}
+	return ContainerSpec{
+		Name:    "jikime-" + sanitize(cfg.TeamName) + "-" + sanitize(cfg.AgentID),
+		Image:   image,
+		WorkDir: workDir,
+		Mounts: []ContainerMount{
+			{Source: workDir, Target: workDir},
+			{Source: teamData, Target: teamData},
+		},
+		Env:     env,
+		CPUs:    cfg.Container.CPUs,
+		Memory:  cfg.Container.Memory,
+		Network: cfg.Container.Network,
+		Command: []string{"sh", "-c", script},
+		Labels: map[string]string{
+			"jikime.team":  cfg.TeamName,
+			"jikime.agent": cfg.AgentID,
+			"jikime.role":  cfg.Role,
+		},
+	}, nil
+}
+
+// containerAlive checks a container's state via the named CLI runtime.
+func containerAlive(runtime, id string) bool {
+	rt, err := NewCLIContainerRuntime(runtime)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	alive, _ := rt.IsRunning(ctx, id)
+	return alive
+}
@@ -0,0 +1,91 @@
+package team
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpawner_ContainerBackend(t *testing.T) {
+	dataDir := t.TempDir()
+	worktree := t.TempDir()
+	rt := NewFakeContainerRuntime()
+	s := NewSpawnerWithRuntime(rt)
+
+	res, err := s.Spawn(SpawnConfig{
+		TeamName:        "my-team",
+		AgentID:         "worker-1",
+		Role:            "worker",
+		WorktreePath:    worktree,
+		InitialPrompt:   "do the thing",
+		Backend:         SpawnBackendContainer,
+		DataDir:         dataDir,
+		SkipPermissions: true,
+		ExtraEnv:        map[string]string{"JIKIME_RESUME": "1"},
+		Container:       ContainerOptions{Image: "agent:test", CPUs: "2", Memory: "4g"},
+	})
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	if res.ContainerID == "" || res.ContainerRuntime != "fake" {
+		t.Fatalf("SpawnResult = %+v, want fake container", res)
+	}
+	if len(rt.Specs) != 1 {
+		t.Fatalf("Specs = %d, want 1", len(rt.Specs))
+	}
+
+	spec := rt.Specs[0]
+	if spec.Image != "agent:test" || spec.CPUs != "2" || spec.Memory != "4g" {
+		t.Errorf("image/limits = %q %q %q", spec.Image, spec.CPUs, spec.Memory)
+	}
+	if spec.WorkDir != worktree {
+		t.Errorf("WorkDir = %q, want %q", spec.WorkDir, worktree)
+	}
+	teamData := filepath.Join(dataDir, "teams", "my-team")
+	mounts := map[string]bool{}
+	for _, m := range spec.Mounts {
+		mounts[m.Source] = m.Source == m.Target
+	}
+	if !mounts[worktree] || !mounts[teamData] {
+		t.Errorf("Mounts = %+v, want worktree and team dir mounted at host paths", spec.Mounts)
+	}
+	for k, want := range map[string]string{
+		"JIKIME_AGENT_ID":  "worker-1",
+		"JIKIME_TEAM_NAME": "my-team",
+		"JIKIME_RESUME":    "1",
+	} {
+		if spec.Env[k] != want {
+			t.Errorf("Env[%s] = %q, want %q", k, spec.Env[k], want)
+		}
+	}
+	if cmd := strings.Join(spec.Command, " "); !strings.Contains(cmd, "--dangerously-skip-permissions") {
+		t.Errorf("Command = %q, want skip-permissions flag", cmd)
+	}
+
+	if alive, _ := rt.IsRunning(context.Background(), res.ContainerID); !alive {
+		t.Error("container not running after Spawn")
+	}
+	if err := s.Kill(res); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	if alive, _ := rt.IsRunning(context.Background(), res.ContainerID); alive {
+		t.Error("container still running after Kill")
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	args := strings.Join(runArgs(ContainerSpec{
+		Name:    "jikime-t-w",
+		Image:   "img",
+		WorkDir: "/w",
+		Memory:  "1g",
+		Mounts:  []ContainerMount{{Source: "/w", Target: "/w"}, {Source: "/d", Target: "/d", ReadOnly: true}},
+		Env:     map[string]string{"B": "2", "A": "1"},
+		Command: []string{"sh", "-c", "true"},
+	}), " ")
+	want := "run -d --name jikime-t-w -w /w --memory 1g -v /w:/w -v /d:/d:ro -e A=1 -e B=2 img sh -c true"
+	if args != want {
+		t.Errorf("runArgs =\n  %s\nwant\n  %s", args, want)
+	}
+}
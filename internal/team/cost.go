@@ -19,33 +19,52 @@ type CostStore struct {
 	mu      sync.Mutex
 	costDir string
 	budget  int // 0 = no limit
+	prices  PriceTable
 }
 
 // NewCostStore creates a CostStore rooted at costDir.
+// Events are priced with DefaultPriceTable until SetPrices is called.
 func NewCostStore(costDir string, budget int) (*CostStore, error) {
 	if err := os.MkdirAll(costDir, 0o755); err != nil {
 		return nil, fmt.Errorf("team/cost: mkdir %s: %w", costDir, err)
 	}
-	return &CostStore{costDir: costDir, budget: budget}, nil
+	return &CostStore{costDir: costDir, budget: budget, prices: DefaultPriceTable()}, nil
 }
 
-// Record appends a new cost event to disk.
-func (c *CostStore) Record(agentID, taskID, toolName, model string, input, output int) (*CostEvent, error) {
+// SetPrices overlays overrides on top of DefaultPriceTable.
+func (c *CostStore) SetPrices(overrides PriceTable) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.prices = DefaultPriceTable().Merge(overrides)
+	c.mu.Unlock()
+}
 
-	total := input + output
-	ev := &CostEvent{
-		ID:           uuid.New().String(),
+// Record appends a new cost event to disk.
+func (c *CostStore) Record(agentID, taskID, toolName, model string, input, output int) (*CostEvent, error) {
+	return c.RecordEvent(&CostEvent{
 		AgentID:      agentID,
 		TaskID:       taskID,
 		ToolName:     toolName,
 		Model:        model,
 		InputTokens:  input,
 		OutputTokens: output,
-		TotalTokens:  total,
-		OccurredAt:   time.Now(),
+	})
+}
+
+// RecordEvent persists ev, filling in ID, OccurredAt, TotalTokens and CostUSD.
+// Use it instead of Record when cache token counts are known.
+func (c *CostStore) RecordEvent(ev *CostEvent) (*CostEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ev.ID == "" {
+		ev.ID = uuid.New().String()
 	}
+	if ev.OccurredAt.IsZero() {
+		ev.OccurredAt = time.Now()
+	}
+	ev.TotalTokens = ev.InputTokens + ev.OutputTokens
+	ev.CostUSD = c.prices.Cost(ev)
+	agentID := ev.AgentID
 
 	data, err := json.MarshalIndent(ev, "", "  ")
 	if err != nil {
@@ -78,8 +97,35 @@ func (c *CostStore) Summary(agentID string) (*CostSummary, error) {
 		return nil, err
 	}
 
+	s := c.summarize(events)
+	s.AgentID = agentID
+	return s, nil
+}
+
+// SummaryByTask returns aggregated totals grouped by task ID.
+// Events recorded without a task are grouped under the empty key.
+func (c *CostStore) SummaryByTask() (map[string]*CostSummary, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events, err := c.listEvents("")
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[string][]*CostEvent)
+	for _, ev := range events {
+		grouped[ev.TaskID] = append(grouped[ev.TaskID], ev)
+	}
+	out := make(map[string]*CostSummary, len(grouped))
+	for taskID, evs := range grouped {
+		out[taskID] = c.summarize(evs)
+	}
+	return out, nil
+}
+
+// summarize aggregates events. Caller must hold c.mu.
+func (c *CostStore) summarize(events []*CostEvent) *CostSummary {
 	s := &CostSummary{
-		AgentID:    agentID,
 		Budget:     c.budget,
 		EventCount: len(events),
 	}
@@ -87,11 +133,101 @@ func (c *CostStore) Summary(agentID string) (*CostSummary, error) {
 		s.TotalInputTokens += ev.InputTokens
 		s.TotalOutputTokens += ev.OutputTokens
 		s.TotalTokens += ev.TotalTokens
+		s.TotalCacheCreationTokens += ev.CacheCreationTokens
+		s.TotalCacheReadTokens += ev.CacheReadTokens
+		s.TotalCostUSD += c.eventCost(ev)
 	}
 	if c.budget > 0 {
 		s.BudgetUsedPercent = float64(s.TotalTokens) / float64(c.budget) * 100
 	}
-	return s, nil
+	return s
+}
+
+// eventCost returns the recorded cost of ev, pricing it now if it predates
+// dollar tracking. Caller must hold c.mu.
+func (c *CostStore) eventCost(ev *CostEvent) float64 {
+	if ev.CostUSD > 0 {
+		return ev.CostUSD
+	}
+	return c.prices.Cost(ev)
+}
+
+// CheckBudgets evaluates policy against current spend for the team, agentID
+// and taskID, returning every threshold that has been reached.
+// Pass an empty agentID or taskID to skip that scope.
+func (c *CostStore) CheckBudgets(policy *BudgetPolicy, agentID, taskID string) ([]BudgetAlert, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events, err := c.listEvents("")
+	if err != nil {
+		return nil, err
+	}
+	var teamUSD, agentUSD, taskUSD float64
+	for _, ev := range events {
+		cost := c.eventCost(ev)
+		teamUSD += cost
+		if agentID != "" && ev.AgentID == agentID {
+			agentUSD += cost
+		}
+		if taskID != "" && ev.TaskID == taskID {
+			taskUSD += cost
+		}
+	}
+
+	var alerts []BudgetAlert
+	alerts = appendBudgetAlerts(alerts, BudgetScopeTeam, "", teamUSD, policy.Team)
+	if agentID != "" {
+		limit, ok := policy.Agents[agentID]
+		if !ok {
+			limit = policy.DefaultAgent
+		}
+		alerts = appendBudgetAlerts(alerts, BudgetScopeAgent, agentID, agentUSD, limit)
+	}
+	if taskID != "" {
+		limit, ok := policy.Tasks[taskID]
+		if !ok {
+			limit = policy.DefaultTask
+		}
+		alerts = appendBudgetAlerts(alerts, BudgetScopeTask, taskID, taskUSD, limit)
+	}
+	return alerts, nil
+}
+
+// appendBudgetAlerts adds an alert for the most severe threshold reached.
+func appendBudgetAlerts(alerts []BudgetAlert, scope BudgetScope, key string, spent float64, limit BudgetLimit) []BudgetAlert {
+	switch {
+	case limit.HardUSD > 0 && spent >= limit.HardUSD:
+		return append(alerts, BudgetAlert{Scope: scope, Key: key, Level: BudgetLevelHard, SpentUSD: spent, LimitUSD: limit.HardUSD})
+	case limit.SoftUSD > 0 && spent >= limit.SoftUSD:
+		return append(alerts, BudgetAlert{Scope: scope, Key: key, Level: BudgetLevelSoft, SpentUSD: spent, LimitUSD: limit.SoftUSD})
+	}
+	return alerts
+}
+
+// MarkAlerted records that alert has been raised and reports whether this is
+// the first time. Used to send each soft-limit warning to the leader only once.
+func (c *CostStore) MarkAlerted(alert BudgetAlert) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dir := filepath.Join(c.costDir, "alerts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("team/cost: mkdir alerts: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s-%s", alert.Scope, sanitize(alert.Key), alert.Level)
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("team/cost: mark alert: %w", err)
+	}
+	_ = f.Close()
+	return true, nil
 }
 
 // BudgetExceeded returns true if the team-wide total tokens exceed the budget.
@@ -0,0 +1,122 @@
+package team
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestPriceTable_Lookup(t *testing.T) {
+	p := DefaultPriceTable().Merge(PriceTable{
+		"Sonnet-4": {InputPerMTok: 4, OutputPerMTok: 20},
+		"custom":   {InputPerMTok: 1, OutputPerMTok: 2},
+	})
+	cases := map[string]float64{
+		"custom":            1,   // exact key
+		"claude-opus-4-1":   15,  // substring
+		"claude-sonnet-4-6": 4,   // longest key wins over "sonnet"
+		"claude-sonnet-3-7": 3,   // shorter key still matches
+		"claude-opus-4-5":   5,   // versioned built-in entry beats "opus"
+		"claude-opus-4-6":   5,
+		"claude-haiku-4-5":  1,
+		"claude-3-5-haiku":  0.8, // family entry
+		"":                  3,   // unset model falls back to sonnet
+		"gpt-9":             3,   // unknown model falls back to sonnet
+	}
+	for model, want := range cases {
+		if got := p.Lookup(model).InputPerMTok; got != want {
+			t.Errorf("Lookup(%q).InputPerMTok = %v, want %v", model, got, want)
+		}
+	}
+
+	// Cache tokens are billed at their own rates.
+	cost := DefaultPriceTable().Cost(&CostEvent{Model: "opus", InputTokens: 1_000_000, OutputTokens: 1_000_000,
+		CacheCreationTokens: 1_000_000, CacheReadTokens: 1_000_000})
+	if !approx(cost, 15+75+18.75+1.50) {
+		t.Errorf("opus cost = %v", cost)
+	}
+}
+
+func TestCostStore_CheckBudgetsAndSummaryByTask(t *testing.T) {
+	c, err := NewCostStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// $15 on t1 by w1 (opus), $3 on t2 by w2 (sonnet), $1 without a task.
+	for _, ev := range []*CostEvent{
+		{AgentID: "w1", TaskID: "t1", Model: "claude-opus-4-1", InputTokens: 1_000_000},
+		{AgentID: "w2", TaskID: "t2", Model: "claude-sonnet-4-6", InputTokens: 1_000_000},
+		{AgentID: "w2", Model: "claude-haiku-4-5", InputTokens: 1_000_000},
+	} {
+		if _, err := c.RecordEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byTask, err := c.SummaryByTask()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byTask) != 3 || !approx(byTask["t1"].TotalCostUSD, 15) || !approx(byTask["t2"].TotalCostUSD, 3) ||
+		!approx(byTask[""].TotalCostUSD, 1) || byTask["t1"].EventCount != 1 {
+		t.Errorf("SummaryByTask = t1:%+v t2:%+v none:%+v", byTask["t1"], byTask["t2"], byTask[""])
+	}
+
+	policy := &BudgetPolicy{
+		Team:         BudgetLimit{SoftUSD: 10, HardUSD: 100},
+		DefaultAgent: BudgetLimit{HardUSD: 10},
+		Agents:       map[string]BudgetLimit{"w2": {SoftUSD: 3}},
+		DefaultTask:  BudgetLimit{SoftUSD: 1, HardUSD: 5},
+	}
+	alerts, err := c.CheckBudgets(policy, "w1", "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BudgetAlert{
+		{Scope: BudgetScopeTeam, Level: BudgetLevelSoft, SpentUSD: 19, LimitUSD: 10},
+		{Scope: BudgetScopeAgent, Key: "w1", Level: BudgetLevelHard, SpentUSD: 15, LimitUSD: 10},
+		{Scope: BudgetScopeTask, Key: "t1", Level: BudgetLevelHard, SpentUSD: 15, LimitUSD: 5},
+	}
+	if len(alerts) != len(want) {
+		t.Fatalf("CheckBudgets(w1, t1) = %+v", alerts)
+	}
+	for i, a := range alerts {
+		w := want[i]
+		if a.Scope != w.Scope || a.Key != w.Key || a.Level != w.Level || !approx(a.SpentUSD, w.SpentUSD) || a.LimitUSD != w.LimitUSD {
+			t.Errorf("alert %d = %+v, want %+v", i, a, w)
+		}
+	}
+
+	// Per-agent override replaces the default; an untouched task scope is skipped.
+	alerts, _ = c.CheckBudgets(policy, "w2", "")
+	if len(alerts) != 2 || alerts[1].Scope != BudgetScopeAgent || alerts[1].Level != BudgetLevelSoft || !approx(alerts[1].SpentUSD, 4) {
+		t.Errorf("CheckBudgets(w2) = %+v", alerts)
+	}
+	if alerts, _ := c.CheckBudgets(nil, "w1", "t1"); alerts != nil {
+		t.Errorf("CheckBudgets(nil) = %+v", alerts)
+	}
+}
+
+func TestCostStore_MarkAlerted(t *testing.T) {
+	c, err := NewCostStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soft := BudgetAlert{Scope: BudgetScopeAgent, Key: "w1", Level: BudgetLevelSoft}
+	for i, want := range []bool{true, false} {
+		first, err := c.MarkAlerted(soft)
+		if err != nil || first != want {
+			t.Errorf("MarkAlerted call %d = %v, %v; want %v", i+1, first, err, want)
+		}
+	}
+	// Another level or key is a separate alert.
+	for _, a := range []BudgetAlert{
+		{Scope: BudgetScopeAgent, Key: "w1", Level: BudgetLevelHard},
+		{Scope: BudgetScopeAgent, Key: "w2", Level: BudgetLevelSoft},
+	} {
+		if first, _ := c.MarkAlerted(a); !first {
+			t.Errorf("MarkAlerted(%+v) = false, want true", a)
+		}
+	}
+}
@@ -12,15 +12,29 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultVisibilityTimeout is how long a leased message stays hidden before
+// it is redelivered if the recipient never acknowledges it.
+const DefaultVisibilityTimeout = 5 * time.Minute
+
+// DefaultMaxDeliveries is the number of leases after which an unacknowledged
+// message is moved to the dead-letter queue instead of being redelivered.
+const DefaultMaxDeliveries = 5
+
 // Inbox manages a FIFO message queue for a single agent.
 // Messages are stored as JSON files under:
 //
-//	~/.jikime/teams/<team>/inbox/<agentID>/
+//	~/.jikime/teams/<team>/inbox/<agentID>/            pending
+//	~/.jikime/teams/<team>/inbox/<agentID>/inflight/   leased, awaiting Ack
 //
 // File names are prefixed with an RFC3339Nano timestamp so that
 // os.ReadDir returns them in chronological order.
+//
+// Lease/Ack gives at-least-once delivery: a leased message moves to inflight/
+// and returns to the queue when its visibility timeout expires without an Ack.
 type Inbox struct {
-	dir string // absolute path to this agent's inbox directory
+	dir           string // absolute path to this agent's inbox directory
+	deadDir       string // team dead-letter directory; empty disables dead-lettering
+	maxDeliveries int
 }
 
 // NewInbox returns an Inbox rooted at dir, creating the directory as needed.
@@ -28,7 +42,11 @@ func NewInbox(dir string) (*Inbox, error) {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return nil, fmt.Errorf("team/inbox: mkdir %s: %w", dir, err)
 	}
-	return &Inbox{dir: dir}, nil
+	return &Inbox{
+		dir:           dir,
+		deadDir:       filepath.Join(filepath.Dir(dir), deadLetterDirName),
+		maxDeliveries: DefaultMaxDeliveries,
+	}, nil
 }
 
 // InboxDir returns the inbox directory for agentID inside teamDir.
@@ -36,6 +54,18 @@ func InboxDir(teamDir, agentID string) string {
 	return filepath.Join(teamDir, "inbox", agentID)
 }
 
+// deadLetterDirName is the inbox sub-directory holding undeliverable messages.
+const deadLetterDirName = "_deadletter"
+
+// DeadLetterDir returns the dead-letter directory for a team.
+func DeadLetterDir(teamDir string) string {
+	return filepath.Join(teamDir, "inbox", deadLetterDirName)
+}
+
+func (b *Inbox) inflightDir() string {
+	return filepath.Join(b.dir, "inflight")
+}
+
 // Send writes a message to this inbox atomically (tmp + rename).
 func (b *Inbox) Send(msg *Message) error {
 	if msg.ID == "" {
@@ -68,7 +98,41 @@ func (b *Inbox) Send(msg *Message) error {
 
 // Receive reads up to limit messages from the inbox in FIFO order and
 // removes them from disk. Pass limit ≤ 0 to receive all pending messages.
+// Delivery is at-most-once; use Lease and Ack when losing a message on a
+// crash is not acceptable.
 func (b *Inbox) Receive(limit int) ([]*Message, error) {
+	msgs, err := b.Lease(limit, DefaultVisibilityTimeout)
+	if err != nil {
+		return msgs, err
+	}
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return msgs, b.Ack(ids...)
+}
+
+// Lease reads up to limit messages in FIFO order and hides them from other
+// readers for visibility. Each message must be acknowledged with Ack once
+// processed; otherwise it is redelivered after the timeout expires.
+// Pass limit ≤ 0 to lease all pending messages.
+func (b *Inbox) Lease(limit int, visibility time.Duration) ([]*Message, error) {
+	return b.LeaseMatching(limit, visibility, nil)
+}
+
+// LeaseMatching is Lease restricted to the pending messages for which match
+// returns true; the others stay queued, untouched. A nil match leases any
+// message.
+func (b *Inbox) LeaseMatching(limit int, visibility time.Duration, match func(*Message) bool) ([]*Message, error) {
+	if visibility <= 0 {
+		visibility = DefaultVisibilityTimeout
+	}
+	if err := b.requeueExpired(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(b.inflightDir(), 0o755); err != nil {
+		return nil, fmt.Errorf("team/inbox: mkdir inflight: %w", err)
+	}
 	files, err := b.sortedFiles()
 	if err != nil {
 		return nil, err
@@ -78,17 +142,196 @@ func (b *Inbox) Receive(limit int) ([]*Message, error) {
 		if limit > 0 && len(msgs) >= limit {
 			break
 		}
+		src := filepath.Join(b.dir, f)
+		dst := filepath.Join(b.inflightDir(), f)
+		if match != nil {
+			if msg, err := readMessage(src); err != nil || !match(msg) {
+				continue
+			}
+		}
+		// Rename is the claim: only one reader wins a given file.
+		if err := os.Rename(src, dst); err != nil {
+			continue
+		}
+		msg, err := readMessage(dst)
+		if err != nil {
+			if err := b.discardUnreadable(dst); err != nil {
+				return msgs, err
+			}
+			continue
+		}
+		now := time.Now()
+		until := now.Add(visibility)
+		msg.Read = true
+		if msg.ReadAt == nil {
+			msg.ReadAt = &now
+		}
+		msg.DeliveryCount++
+		msg.LeaseUntil = &until
+		if err := writeMessage(dst, msg); err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// Take removes and returns the pending message with the given ID, or nil if
+// no such message is queued. Used to pick a specific reply out of the queue
+// without disturbing the messages ahead of it.
+func (b *Inbox) Take(id string) (*Message, error) {
+	files, err := b.sortedFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		// File names end with "-<id>.json".
+		if filepath.Ext(f) != ".json" || len(f) < len(id)+5 || f[len(f)-len(id)-5:len(f)-5] != id {
+			continue
+		}
 		path := filepath.Join(b.dir, f)
 		msg, err := readMessage(path)
 		if err != nil {
-			continue // skip corrupt files
+			return nil, err
+		}
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil // another reader won
+			}
+			return nil, fmt.Errorf("team/inbox: take %s: %w", id, err)
 		}
+		now := time.Now()
+		msg.Read = true
+		msg.ReadAt = &now
+		msg.DeliveryCount++
+		return msg, nil
+	}
+	return nil, nil
+}
+
+// Ack permanently removes leased messages by ID. Unknown IDs are ignored so
+// that acknowledging twice is harmless.
+func (b *Inbox) Ack(ids ...string) error {
+	return b.eachInflight(ids, func(path string, _ *Message) error {
 		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-			return msgs, fmt.Errorf("team/inbox: remove %s: %w", f, err)
+			return fmt.Errorf("team/inbox: ack %s: %w", filepath.Base(path), err)
 		}
+		return nil
+	})
+}
+
+// Nack returns leased messages to the queue immediately for redelivery.
+func (b *Inbox) Nack(ids ...string) error {
+	return b.eachInflight(ids, func(path string, msg *Message) error {
+		return b.requeue(path, msg)
+	})
+}
+
+// InFlight returns messages currently leased and awaiting acknowledgement.
+func (b *Inbox) InFlight() ([]*Message, error) {
+	var msgs []*Message
+	err := b.eachInflight(nil, func(_ string, msg *Message) error {
 		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// eachInflight calls fn for every inflight message, or only those in ids when non-empty.
+func (b *Inbox) eachInflight(ids []string, fn func(path string, msg *Message) error) error {
+	entries, err := os.ReadDir(b.inflightDir())
+	if os.IsNotExist(err) {
+		return nil
 	}
-	return msgs, nil
+	if err != nil {
+		return fmt.Errorf("team/inbox: readdir inflight: %w", err)
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(b.inflightDir(), e.Name())
+		msg, err := readMessage(path)
+		if err != nil {
+			if err := b.discardUnreadable(path); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(ids) > 0 && !want[msg.ID] && !matchesShortID(want, msg.ID) {
+			continue
+		}
+		if err := fn(path, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeueExpired moves inflight messages whose lease has expired back to the
+// queue, or to the dead-letter queue once they exceed maxDeliveries.
+func (b *Inbox) requeueExpired() error {
+	now := time.Now()
+	return b.eachInflight(nil, func(path string, msg *Message) error {
+		if msg.LeaseUntil != nil && msg.LeaseUntil.After(now) {
+			return nil
+		}
+		if b.maxDeliveries > 0 && msg.DeliveryCount >= b.maxDeliveries && b.deadDir != "" {
+			msg.DeadLetterReason = fmt.Sprintf("not acknowledged after %d deliveries", msg.DeliveryCount)
+			if err := writeDeadLetter(b.deadDir, msg); err != nil {
+				return err
+			}
+			return os.Remove(path)
+		}
+		return b.requeue(path, msg)
+	})
+}
+
+// discardUnreadable moves an inflight file that cannot be decoded out of the
+// way so it is not stranded in inflight/ forever. It goes to the dead-letter
+// directory under its original name, or back to the queue when dead-lettering
+// is disabled. A file that has already vanished (acked concurrently) is ignored.
+func (b *Inbox) discardUnreadable(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	dst := filepath.Join(b.dir, filepath.Base(path))
+	if b.deadDir != "" {
+		if err := os.MkdirAll(b.deadDir, 0o755); err != nil {
+			return fmt.Errorf("team/inbox: mkdir dead-letter: %w", err)
+		}
+		dst = filepath.Join(b.deadDir, filepath.Base(path))
+	}
+	if err := os.Rename(path, dst); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("team/inbox: discard unreadable %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// requeue moves an inflight file back into the pending queue under its
+// original name, so FIFO order is preserved.
+func (b *Inbox) requeue(path string, msg *Message) error {
+	msg.LeaseUntil = nil
+	if err := writeMessage(path, msg); err != nil {
+		return err
+	}
+	if err := os.Rename(path, filepath.Join(b.dir, filepath.Base(path))); err != nil {
+		return fmt.Errorf("team/inbox: requeue %s: %w", msg.ID, err)
+	}
+	return nil
+}
+
+// matchesShortID reports whether id starts with any 8-char prefix in want.
+func matchesShortID(want map[string]bool, id string) bool {
+	if len(id) < 8 {
+		return false
+	}
+	return want[id[:8]]
 }
 
 // Peek returns up to limit messages without removing them.
@@ -112,18 +355,43 @@ func (b *Inbox) Peek(limit int) ([]*Message, error) {
 	return msgs, nil
 }
 
-// Count returns the number of pending (unread) messages.
+// Count returns the number of pending (unread) messages, including leased
+// messages whose visibility timeout has expired and that the next Lease would
+// redeliver. It only reads the inbox; expired leases are requeued by Lease.
 func (b *Inbox) Count() (int, error) {
 	files, err := b.sortedFiles()
 	if err != nil {
 		return 0, err
 	}
-	return len(files), nil
+	n := len(files)
+	entries, err := os.ReadDir(b.inflightDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return n, nil
+		}
+		return 0, fmt.Errorf("team/inbox: readdir inflight: %w", err)
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		msg, err := readMessage(filepath.Join(b.inflightDir(), e.Name()))
+		if err != nil || (msg.LeaseUntil != nil && msg.LeaseUntil.After(now)) {
+			continue
+		}
+		if b.maxDeliveries > 0 && msg.DeliveryCount >= b.maxDeliveries && b.deadDir != "" {
+			continue // will be dead-lettered, not redelivered
+		}
+		n++
+	}
+	return n, nil
 }
 
 // Watch calls fn for each new message that arrives in the inbox.
 // It blocks until ctx.Done() is closed or an unrecoverable error occurs.
-// Each received message is consumed (removed from disk).
+// Each message is acknowledged after fn returns, so a crash inside fn leads
+// to redelivery rather than loss.
 func (b *Inbox) Watch(done <-chan struct{}, fn func(*Message)) error {
 	w, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -136,11 +404,7 @@ func (b *Inbox) Watch(done <-chan struct{}, fn func(*Message)) error {
 	}
 
 	// Drain any messages already present before the watcher started.
-	if msgs, err := b.Receive(0); err == nil {
-		for _, m := range msgs {
-			fn(m)
-		}
-	}
+	b.deliverLeased(fn)
 
 	for {
 		select {
@@ -158,19 +422,25 @@ func (b *Inbox) Watch(done <-chan struct{}, fn func(*Message)) error {
 			}
 			// Small sleep to let the writer finish the atomic rename.
 			time.Sleep(5 * time.Millisecond)
-			msgs, err := b.Receive(0)
-			if err != nil {
-				continue
-			}
-			for _, m := range msgs {
-				fn(m)
-			}
+			b.deliverLeased(fn)
 		case <-w.Errors:
 			// Non-fatal watcher errors are ignored.
 		}
 	}
 }
 
+// deliverLeased leases all pending messages and acks each after fn handles it.
+func (b *Inbox) deliverLeased(fn func(*Message)) {
+	msgs, err := b.Lease(0, DefaultVisibilityTimeout)
+	if err != nil {
+		return
+	}
+	for _, m := range msgs {
+		fn(m)
+		_ = b.Ack(m.ID)
+	}
+}
+
 // sortedFiles returns JSON file names in chronological order (excluding .tmp).
 func (b *Inbox) sortedFiles() ([]string, error) {
 	entries, err := os.ReadDir(b.dir)
@@ -194,6 +464,32 @@ func (b *Inbox) sortedFiles() ([]string, error) {
 	return names, nil
 }
 
+// writeMessage atomically rewrites a message file in place.
+func writeMessage(path string, msg *Message) error {
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("team/inbox: marshal: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("team/inbox: write tmp: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("team/inbox: rename: %w", err)
+	}
+	return nil
+}
+
+// writeDeadLetter stores msg in the team dead-letter directory.
+func writeDeadLetter(deadDir string, msg *Message) error {
+	if err := os.MkdirAll(deadDir, 0o755); err != nil {
+		return fmt.Errorf("team/inbox: mkdir dead-letter: %w", err)
+	}
+	ts := msg.SentAt.UTC().Format("20060102T150405.000000000Z")
+	return writeMessage(filepath.Join(deadDir, fmt.Sprintf("%s-%s.json", ts, msg.ID)), msg)
+}
+
 func readMessage(path string) (*Message, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -245,6 +541,7 @@ func (ti *TeamInbox) For(agentID string) (*Inbox, error) {
 }
 
 // Send delivers a message to the recipient's inbox and records it in the event log.
+// Messages to agents that have left the team go to the dead-letter queue instead.
 func (ti *TeamInbox) Send(msg *Message) error {
 	if msg.ID == "" {
 		msg.ID = uuid.New().String()
@@ -252,15 +549,159 @@ func (ti *TeamInbox) Send(msg *Message) error {
 	if msg.SentAt.IsZero() {
 		msg.SentAt = time.Now()
 	}
+	if err := ti.deliver(msg); err != nil {
+		return err
+	}
+	appendEventLog(ti.teamDir, msg)
+	return nil
+}
+
+// deliver writes msg to its recipient's inbox, or dead-letters it when the
+// recipient is registered as offline.
+func (ti *TeamInbox) deliver(msg *Message) error {
+	if ti.departed(msg.To) {
+		msg.DeadLetterReason = fmt.Sprintf("recipient %s has left the team", msg.To)
+		if err := writeDeadLetter(DeadLetterDir(ti.teamDir), msg); err != nil {
+			return err
+		}
+		ti.notifyUndeliverable(msg)
+		return nil
+	}
 	ib, err := ti.For(msg.To)
 	if err != nil {
 		return err
 	}
-	if err := ib.Send(msg); err != nil {
-		return err
+	return ib.Send(msg)
+}
+
+// notifyUndeliverable tells the sender that msg was dead-lettered.
+// Best-effort: the notice itself is never dead-lettered.
+func (ti *TeamInbox) notifyUndeliverable(msg *Message) {
+	if msg.From == "" || msg.From == msg.To || msg.Kind == MessageKindSystem || ti.departed(msg.From) {
+		return
 	}
-	appendEventLog(ti.teamDir, msg)
-	return nil
+	ib, err := ti.For(msg.From)
+	if err != nil {
+		return
+	}
+	_ = ib.Send(&Message{
+		ID:       uuid.New().String(),
+		TeamName: msg.TeamName,
+		Kind:     MessageKindSystem,
+		From:     "system",
+		To:       msg.From,
+		Subject:  "undeliverable",
+		Body:     fmt.Sprintf("Message %.8s to %s was moved to the dead-letter queue: %s", msg.ID, msg.To, msg.DeadLetterReason),
+		ReplyTo:  msg.ID,
+		SentAt:   time.Now(),
+	})
+}
+
+// departed reports whether agentID is registered and marked offline.
+// Unregistered agents are not considered departed: they may not have started yet.
+func (ti *TeamInbox) departed(agentID string) bool {
+	data, err := os.ReadFile(filepath.Join(ti.teamDir, "registry", agentID+".json"))
+	if err != nil {
+		return false
+	}
+	var info AgentInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false
+	}
+	return info.Status == AgentStatusOffline
+}
+
+// DeadLetters returns all dead-lettered messages, oldest first.
+func (ti *TeamInbox) DeadLetters() ([]*Message, error) {
+	dead := &Inbox{dir: DeadLetterDir(ti.teamDir)}
+	return dead.Peek(0)
+}
+
+// Redrive re-sends dead-lettered messages by ID (all when ids is empty).
+// If to is non-empty the messages are redirected to that agent.
+// Returns the number of messages redriven.
+func (ti *TeamInbox) Redrive(to string, ids ...string) (int, error) {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	return ti.redrive(to, func(m *Message) bool {
+		return len(ids) == 0 || want[m.ID] || matchesShortID(want, m.ID)
+	})
+}
+
+// RedriveAgent re-delivers dead-lettered messages originally addressed to
+// agentID. Call it when an agent rejoins the team.
+func (ti *TeamInbox) RedriveAgent(agentID string) (int, error) {
+	return ti.redrive("", func(m *Message) bool { return m.To == agentID })
+}
+
+func (ti *TeamInbox) redrive(to string, match func(*Message) bool) (int, error) {
+	dead := &Inbox{dir: DeadLetterDir(ti.teamDir)}
+	files, err := dead.sortedFiles()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range files {
+		path := filepath.Join(dead.dir, f)
+		msg, err := readMessage(path)
+		if err != nil || !match(msg) {
+			continue
+		}
+		if to != "" {
+			msg.To = to
+		}
+		msg.DeadLetterReason = ""
+		msg.DeliveryCount = 0
+		msg.LeaseUntil = nil
+		ib, err := ti.For(msg.To)
+		if err != nil {
+			return n, err
+		}
+		if err := ib.Send(msg); err != nil {
+			return n, err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return n, fmt.Errorf("team/inbox: remove dead letter: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// DeadLetterAgent moves every pending and inflight message for agentID to the
+// dead-letter queue. Call it when an agent leaves the team for good.
+func (ti *TeamInbox) DeadLetterAgent(agentID, reason string) (int, error) {
+	ib, err := ti.For(agentID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	move := func(path string) error {
+		msg, err := readMessage(path)
+		if err != nil {
+			return nil
+		}
+		msg.DeadLetterReason = reason
+		msg.LeaseUntil = nil
+		if err := writeDeadLetter(DeadLetterDir(ti.teamDir), msg); err != nil {
+			return err
+		}
+		n++
+		return os.Remove(path)
+	}
+	files, err := ib.sortedFiles()
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := move(filepath.Join(ib.dir, f)); err != nil {
+			return n, err
+		}
+	}
+	err = ib.eachInflight(nil, func(path string, _ *Message) error { return move(path) })
+	return n, err
 }
 
 // Broadcast sends msg to all agentIDs except msg.From and records in the event log.
@@ -283,11 +724,7 @@ func (ti *TeamInbox) Broadcast(msg *Message, agentIDs []string) error {
 		cp.To = id
 		cp.Kind = MessageKindBroadcast
 		cp.SentAt = time.Now()
-		ib, err := ti.For(id)
-		if err != nil {
-			return err
-		}
-		if err := ib.Send(&cp); err != nil {
+		if err := ti.deliver(&cp); err != nil {
 			return err
 		}
 	}
@@ -346,3 +783,78 @@ func splitLines(data []byte) [][]byte {
 	}
 	return lines
 }
+
+// Thread returns every message in the conversation containing messageID,
+// oldest first. Conversations are reconstructed from the event log by
+// following ReplyTo links up to the root and back down to all replies.
+// messageID may be a full ID or its 8-character prefix.
+func (ti *TeamInbox) Thread(messageID string) ([]*Message, error) {
+	msgs, err := ti.EventLog(0, "")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Message, len(msgs))
+	var start *Message
+	for _, m := range msgs {
+		byID[m.ID] = m
+		if m.ID == messageID || (start == nil && len(messageID) >= 8 && len(m.ID) >= len(messageID) && m.ID[:len(messageID)] == messageID) {
+			start = m
+		}
+	}
+	if start == nil {
+		return nil, fmt.Errorf("team/inbox: message %s not found in event log", messageID)
+	}
+	return collectThread(threadRoot(start, byID), msgs), nil
+}
+
+// Threads groups the event log into conversations keyed by root message ID.
+// Only conversations with at least one reply are returned.
+func (ti *TeamInbox) Threads() (map[string][]*Message, error) {
+	msgs, err := ti.EventLog(0, "")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Message, len(msgs))
+	for _, m := range msgs {
+		byID[m.ID] = m
+	}
+	threads := make(map[string][]*Message)
+	for _, m := range msgs {
+		if m.ReplyTo == "" {
+			continue
+		}
+		root := threadRoot(m, byID)
+		if _, ok := threads[root.ID]; !ok {
+			threads[root.ID] = collectThread(root, msgs)
+		}
+	}
+	return threads, nil
+}
+
+// threadRoot follows ReplyTo links to the first message of a conversation.
+func threadRoot(m *Message, byID map[string]*Message) *Message {
+	seen := map[string]bool{}
+	for m.ReplyTo != "" && !seen[m.ID] {
+		seen[m.ID] = true
+		parent, ok := byID[m.ReplyTo]
+		if !ok {
+			break
+		}
+		m = parent
+	}
+	return m
+}
+
+// collectThread returns root and all transitive replies, in event-log order.
+func collectThread(root *Message, msgs []*Message) []*Message {
+	inThread := map[string]bool{root.ID: true}
+	// Event log is append-only, so a reply always follows its parent.
+	var out []*Message
+	for _, m := range msgs {
+		if m.ID == root.ID || (m.ReplyTo != "" && inThread[m.ReplyTo]) {
+			inThread[m.ID] = true
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInbox_LeaseAckRedelivery(t *testing.T) {
+	td := t.TempDir()
+	ib, err := NewInbox(InboxDir(td, "worker-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ib.Send(&Message{From: "leader", To: "worker-1", Body: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := ib.Lease(0, 20*time.Millisecond)
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("Lease = %d msgs, %v; want 1", len(msgs), err)
+	}
+	if !msgs[0].Read || msgs[0].ReadAt == nil || msgs[0].DeliveryCount != 1 {
+		t.Errorf("leased message = %+v, want read with delivery 1", msgs[0])
+	}
+	if hidden, _ := ib.Lease(0, time.Minute); len(hidden) != 0 {
+		t.Errorf("Lease during visibility timeout = %d msgs, want 0", len(hidden))
+	}
+
+	// Not acked: the lease expires and the message is delivered again.
+	time.Sleep(30 * time.Millisecond)
+	again, _ := ib.Lease(0, time.Minute)
+	if len(again) != 1 || again[0].ID != msgs[0].ID || again[0].DeliveryCount != 2 {
+		t.Fatalf("redelivery = %+v, want same message with delivery 2", again)
+	}
+	if !again[0].ReadAt.Equal(*msgs[0].ReadAt) {
+		t.Error("ReadAt changed on redelivery")
+	}
+
+	if err := ib.Ack(again[0].ID[:8]); err != nil {
+		t.Fatal(err)
+	}
+	if inflight, _ := ib.InFlight(); len(inflight) != 0 {
+		t.Errorf("InFlight after Ack = %d, want 0", len(inflight))
+	}
+	if left, _ := ib.Lease(0, time.Minute); len(left) != 0 {
+		t.Errorf("Lease after Ack = %d msgs, want 0", len(left))
+	}
+}
+
+func TestInbox_MaxDeliveriesDeadLetters(t *testing.T) {
+	td := t.TempDir()
+	ib, _ := NewInbox(InboxDir(td, "worker-1"))
+	ib.maxDeliveries = 2
+	_ = ib.Send(&Message{From: "leader", To: "worker-1", Body: "poison"})
+
+	for i := 0; i < 2; i++ {
+		if msgs, _ := ib.Lease(0, time.Millisecond); len(msgs) != 1 {
+			t.Fatalf("lease %d: got %d msgs", i+1, len(msgs))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if msgs, _ := ib.Lease(0, time.Minute); len(msgs) != 0 {
+		t.Fatalf("lease after max deliveries = %d msgs, want 0", len(msgs))
+	}
+	dead, _ := NewTeamInbox(td).DeadLetters()
+	if len(dead) != 1 || dead[0].DeadLetterReason == "" {
+		t.Fatalf("DeadLetters = %+v, want 1 with reason", dead)
+	}
+}
+
+func TestInbox_CountDoesNotRequeue(t *testing.T) {
+	td := t.TempDir()
+	ib, _ := NewInbox(InboxDir(td, "worker-1"))
+	_ = ib.Send(&Message{From: "leader", To: "worker-1", Body: "one"})
+	_ = ib.Send(&Message{From: "leader", To: "worker-1", Body: "two"})
+
+	if _, err := ib.Lease(1, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n, err := ib.Count(); err != nil || n != 2 {
+		t.Fatalf("Count = %d, %v; want 2 (pending + expired lease)", n, err)
+	}
+	// Count must leave the expired lease where it is.
+	if inflight, _ := ib.InFlight(); len(inflight) != 1 {
+		t.Errorf("InFlight after Count = %d, want 1", len(inflight))
+	}
+}
+
+func TestInbox_UnreadableInflightIsDeadLettered(t *testing.T) {
+	td := t.TempDir()
+	ib, _ := NewInbox(InboxDir(td, "worker-1"))
+	name := "20260101T000000.000000000Z-broken.json"
+	if err := os.WriteFile(filepath.Join(ib.dir, name), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = ib.Send(&Message{From: "leader", To: "worker-1", Body: "ok"})
+
+	msgs, err := ib.Lease(0, time.Minute)
+	if err != nil || len(msgs) != 1 || msgs[0].Body != "ok" {
+		t.Fatalf("Lease = %+v, %v; want only the readable message", msgs, err)
+	}
+	if _, err := os.Stat(filepath.Join(ib.inflightDir(), name)); !os.IsNotExist(err) {
+		t.Errorf("unreadable file left in inflight (stat err %v)", err)
+	}
+	if _, err := os.Stat(filepath.Join(DeadLetterDir(td), name)); err != nil {
+		t.Errorf("unreadable file not moved to dead-letter: %v", err)
+	}
+
+	// A file corrupted while leased is dead-lettered on the next sweep.
+	if err := os.WriteFile(filepath.Join(ib.inflightDir(), "x-late.json"), []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ib.Lease(0, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(DeadLetterDir(td), "x-late.json")); err != nil {
+		t.Errorf("corrupted inflight file not dead-lettered: %v", err)
+	}
+}
+
+func TestTeamInbox_DepartedAgentAndThreads(t *testing.T) {
+	td := t.TempDir()
+	reg, _ := NewRegistry(filepath.Join(td, "registry"))
+	_ = reg.Register(&AgentInfo{ID: "leader", Role: "leader", Status: AgentStatusActive})
+	_ = reg.Register(&AgentInfo{ID: "worker-1", Role: "worker", Status: AgentStatusOffline})
+	ti := NewTeamInbox(td)
+
+	q := &Message{Kind: MessageKindDirect, From: "leader", To: "worker-1", Body: "status?"}
+	if err := ti.Send(q); err != nil {
+		t.Fatal(err)
+	}
+	if dead, _ := ti.DeadLetters(); len(dead) != 1 || dead[0].ID != q.ID {
+		t.Fatalf("DeadLetters = %+v, want the message to worker-1", dead)
+	}
+	leaderInbox, _ := ti.For("leader")
+	if notes, _ := leaderInbox.Peek(0); len(notes) != 1 || notes[0].ReplyTo != q.ID {
+		t.Errorf("leader notices = %+v, want one undeliverable notice", notes)
+	}
+
+	// Worker rejoins: its parked message is redriven.
+	_ = reg.SetStatus("worker-1", AgentStatusActive)
+	if n, err := ti.RedriveAgent("worker-1"); err != nil || n != 1 {
+		t.Fatalf("RedriveAgent = %d, %v; want 1", n, err)
+	}
+
+	a := &Message{Kind: MessageKindDirect, From: "worker-1", To: "leader", Body: "fine", ReplyTo: q.ID}
+	_ = ti.Send(a)
+	b := &Message{Kind: MessageKindDirect, From: "leader", To: "worker-1", Body: "thanks", ReplyTo: a.ID}
+	_ = ti.Send(b)
+	_ = ti.Send(&Message{Kind: MessageKindDirect, From: "leader", To: "worker-1", Body: "unrelated"})
+
+	thread, err := ti.Thread(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread) != 3 || thread[0].ID != q.ID || thread[2].ID != b.ID {
+		t.Errorf("Thread = %d msgs, want q→a→b", len(thread))
+	}
+	threads, _ := ti.Threads()
+	if len(threads) != 1 || len(threads[q.ID]) != 3 {
+		t.Errorf("Threads = %v, want one thread rooted at q", threads)
+	}
+}
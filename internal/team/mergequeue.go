@@ -0,0 +1,372 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultMergeVerifyTimeout bounds one run of the merge queue's verify command.
+const DefaultMergeVerifyTimeout = 15 * time.Minute
+
+// MergeCandidate is an agent workspace branch waiting to be merged.
+type MergeCandidate struct {
+	AgentID string `json:"agent_id"`
+	Branch  string `json:"branch"`
+}
+
+// MergeRejectReason says why the queue dropped a branch.
+type MergeRejectReason string
+
+const (
+	// MergeRejectConflict means the branch did not merge cleanly onto staging.
+	MergeRejectConflict MergeRejectReason = "conflict"
+
+	// MergeRejectVerify means the verify command failed once the branch was merged.
+	MergeRejectVerify MergeRejectReason = "verify"
+)
+
+// MergeRejection is a branch the queue refused, with the git or verify output.
+type MergeRejection struct {
+	MergeCandidate
+	Reason MergeRejectReason `json:"reason"`
+	Output string            `json:"output"`
+}
+
+// MergeQueueResult summarizes one queue run.
+type MergeQueueResult struct {
+	Target     string           `json:"target"`
+	Staging    string           `json:"staging"`
+	StagingSHA string           `json:"staging_sha,omitempty"`
+	Merged     []MergeCandidate `json:"merged"`
+	Rejected   []MergeRejection `json:"rejected,omitempty"`
+	Landed     bool             `json:"landed"`
+}
+
+// MergeQueue merges agent branches onto a staging branch cut from Target,
+// verifies the result and, unless DryRun is set, fast-forwards Target to it.
+//
+// By default all branches are merged first and verified once; when that
+// fails the queue bisects over the merged prefix to find the first branch
+// that breaks verification, drops it and tries again. With VerifyEach the
+// command runs after every merge instead.
+type MergeQueue struct {
+	// Repo is the main repository (not a worktree) holding the branches.
+	Repo string
+
+	// Target is the branch agent work lands on.
+	Target string
+
+	// Staging is the branch the queue builds; it is reset on every run.
+	Staging string
+
+	// Verify is a shell command run in the staging checkout. Empty skips verification.
+	Verify string
+
+	// VerifyEach runs Verify after every merge rather than once per batch.
+	VerifyEach bool
+
+	// VerifyTimeout bounds a single Verify run. Zero uses DefaultMergeVerifyTimeout.
+	VerifyTimeout time.Duration
+
+	// DryRun builds and verifies staging without moving Target.
+	DryRun bool
+
+	// Log receives progress lines. nil discards them.
+	Log io.Writer
+
+	dir string // staging checkout
+}
+
+// OrderMergeCandidates sorts candidates so a branch whose tasks depend on
+// tasks finished by another candidate merges after that candidate. Otherwise
+// (and for dependency cycles) the input order is kept.
+func OrderMergeCandidates(cands []MergeCandidate, tasks []*Task) []MergeCandidate {
+	index := make(map[string]int, len(cands))
+	for i, c := range cands {
+		index[c.AgentID] = i
+	}
+	// Dependencies may be recorded as short (8-char) ID prefixes.
+	owner := func(dep string) string {
+		for _, t := range tasks {
+			if t.ID == dep || (len(dep) >= 8 && strings.HasPrefix(t.ID, dep)) {
+				return t.AgentID
+			}
+		}
+		return ""
+	}
+
+	// after[i] lists candidates that must merge after candidate i.
+	after := make([][]int, len(cands))
+	indegree := make([]int, len(cands))
+	seen := make(map[[2]int]bool)
+	for _, t := range tasks {
+		to, ok := index[t.AgentID]
+		if !ok {
+			continue
+		}
+		deps := append(append([]string(nil), t.DependsOn...), t.ResolvedDeps...)
+		for _, dep := range deps {
+			from, ok := index[owner(dep)]
+			if !ok || from == to || seen[[2]int{from, to}] {
+				continue
+			}
+			seen[[2]int{from, to}] = true
+			after[from] = append(after[from], to)
+			indegree[to]++
+		}
+	}
+
+	out := make([]MergeCandidate, 0, len(cands))
+	done := make([]bool, len(cands))
+	for len(out) < len(cands) {
+		next := -1
+		for i := range cands {
+			if !done[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			// Cycle: take the earliest remaining candidate.
+			for i := range cands {
+				if !done[i] {
+					next = i
+					break
+				}
+			}
+		}
+		done[next] = true
+		out = append(out, cands[next])
+		for _, j := range after[next] {
+			indegree[j]--
+		}
+	}
+	return out
+}
+
+// Run merges cands in order and returns what was merged and rejected.
+func (q *MergeQueue) Run(cands []MergeCandidate) (*MergeQueueResult, error) {
+	if q.Target == "" || q.Staging == "" {
+		return nil, fmt.Errorf("team/mergequeue: target and staging branches are required")
+	}
+	res := &MergeQueueResult{Target: q.Target, Staging: q.Staging}
+
+	// Build staging in a throwaway worktree so the user's checkout is untouched.
+	dir, err := os.MkdirTemp("", "jikime-mergequeue-")
+	if err != nil {
+		return nil, fmt.Errorf("team/mergequeue: %w", err)
+	}
+	_ = os.Remove(dir)
+	if out, err := q.git(q.Repo, "worktree", "add", "--force", "-B", q.Staging, dir, q.Target); err != nil {
+		return nil, fmt.Errorf("team/mergequeue: create staging worktree: %w\n%s", err, out)
+	}
+	q.dir = dir
+	defer func() {
+		_, _ = q.git(q.Repo, "worktree", "remove", "--force", dir)
+		_ = os.RemoveAll(dir)
+	}()
+
+	if q.Verify != "" {
+		if out, err := q.verify(); err != nil {
+			return nil, fmt.Errorf("team/mergequeue: verification already fails on %s, nothing merged: %w\n%s", q.Target, err, tailOutput(out))
+		}
+	}
+
+	for _, c := range cands {
+		q.logf("→ merging %s", c.Branch)
+		if out, err := q.merge(c); errors.Is(err, errMergeConflict) {
+			q.logf("  ✗ conflict")
+			res.Rejected = append(res.Rejected, MergeRejection{MergeCandidate: c, Reason: MergeRejectConflict, Output: out})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if q.Verify != "" && q.VerifyEach {
+			if out, err := q.verify(); err != nil {
+				q.logf("  ✗ verification failed")
+				res.Rejected = append(res.Rejected, MergeRejection{MergeCandidate: c, Reason: MergeRejectVerify, Output: out})
+				if out, err := q.git(q.dir, "reset", "--hard", "HEAD~1"); err != nil {
+					return nil, fmt.Errorf("team/mergequeue: undo merge of %s: %w\n%s", c.Branch, err, out)
+				}
+				continue
+			}
+		}
+		res.Merged = append(res.Merged, c)
+	}
+
+	if q.Verify != "" && !q.VerifyEach {
+		if err := q.verifyBatch(res); err != nil {
+			return nil, err
+		}
+	}
+
+	sha, err := q.git(q.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("team/mergequeue: rev-parse staging: %w", err)
+	}
+	res.StagingSHA = strings.TrimSpace(sha)
+
+	if q.DryRun || len(res.Merged) == 0 {
+		return res, nil
+	}
+	if err := q.land(res.StagingSHA); err != nil {
+		return res, err
+	}
+	res.Landed = true
+	return res, nil
+}
+
+// verifyBatch verifies the staging branch and, while it fails, bisects the
+// merged prefix for the first breaking branch and rebuilds without it.
+func (q *MergeQueue) verifyBatch(res *MergeQueueResult) error {
+	for len(res.Merged) > 0 {
+		out, err := q.verify()
+		if err == nil {
+			return nil
+		}
+		// Prefix 0 (the target) passes and prefix n fails: find the
+		// smallest failing prefix; its last branch is the culprit.
+		lo, hi := 0, len(res.Merged)
+		lastOut := out
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			if err := q.rebuild(res.Merged[:mid]); err != nil {
+				return err
+			}
+			q.logf("  bisect: verifying first %d of %d", mid, len(res.Merged))
+			if out, err := q.verify(); err != nil {
+				hi, lastOut = mid, out
+			} else {
+				lo = mid
+			}
+		}
+		bad := res.Merged[hi-1]
+		q.logf("  ✗ %s breaks verification", bad.Branch)
+		res.Rejected = append(res.Rejected, MergeRejection{MergeCandidate: bad, Reason: MergeRejectVerify, Output: lastOut})
+		res.Merged = append(res.Merged[:hi-1:hi-1], res.Merged[hi:]...)
+		if err := q.rebuild(res.Merged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuild resets staging to the target and re-merges cands. They merged
+// cleanly in this order before, so a conflict here is an error.
+func (q *MergeQueue) rebuild(cands []MergeCandidate) error {
+	if out, err := q.git(q.dir, "reset", "--hard", q.Target); err != nil {
+		return fmt.Errorf("team/mergequeue: reset staging: %w\n%s", err, out)
+	}
+	for _, c := range cands {
+		if _, err := q.merge(c); err != nil {
+			return fmt.Errorf("team/mergequeue: re-merge %s: %w", c.Branch, err)
+		}
+	}
+	return nil
+}
+
+// errMergeConflict marks a merge that stopped on conflicting changes, as
+// opposed to git failing for another reason.
+var errMergeConflict = errors.New("merge conflict")
+
+// merge merges one branch with --no-ff, aborting on conflict. For conflicts
+// the error wraps errMergeConflict and the output lists the conflicting files.
+func (q *MergeQueue) merge(c MergeCandidate) (string, error) {
+	msg := fmt.Sprintf("merge: %s into %s (merge queue)", c.Branch, q.Target)
+	out, err := q.git(q.dir, "merge", "--no-ff", "--no-edit", "-m", msg, c.Branch)
+	if err == nil {
+		return out, nil
+	}
+	files, _ := q.git(q.dir, "diff", "--name-only", "--diff-filter=U")
+	_, _ = q.git(q.dir, "merge", "--abort")
+	_, _ = q.git(q.dir, "reset", "--hard", "HEAD")
+	if strings.TrimSpace(files) == "" {
+		return out, fmt.Errorf("team/mergequeue: merge %s: %w\n%s", c.Branch, err, out)
+	}
+	return out + "\nConflicting files:\n" + files, fmt.Errorf("%w in %s", errMergeConflict, c.Branch)
+}
+
+// verify runs the verify command in the staging checkout.
+func (q *MergeQueue) verify() (string, error) {
+	timeout := q.VerifyTimeout
+	if timeout <= 0 {
+		timeout = DefaultMergeVerifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", q.Verify)
+	cmd.Dir = q.dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("$ %s\n%s\n(%v)", q.Verify, strings.TrimRight(string(out), "\n"), err), err
+	}
+	return string(out), nil
+}
+
+// land moves Target to sha. When Target is checked out in the main repo
+// the working tree is fast-forwarded; otherwise only the ref is updated.
+func (q *MergeQueue) land(sha string) error {
+	head, _ := q.git(q.Repo, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if strings.TrimSpace(head) == q.Target {
+		if out, err := q.git(q.Repo, "merge", "--ff-only", sha); err != nil {
+			return fmt.Errorf("team/mergequeue: fast-forward %s: %w\n%s", q.Target, err, out)
+		}
+		return nil
+	}
+	old, err := q.git(q.Repo, "rev-parse", "refs/heads/"+q.Target)
+	if err != nil {
+		return fmt.Errorf("team/mergequeue: resolve %s: %w", q.Target, err)
+	}
+	if out, err := q.git(q.Repo, "update-ref", "refs/heads/"+q.Target, sha, strings.TrimSpace(old)); err != nil {
+		return fmt.Errorf("team/mergequeue: update %s: %w\n%s", q.Target, err, out)
+	}
+	return nil
+}
+
+func (q *MergeQueue) git(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	return string(out), err
+}
+
+func (q *MergeQueue) logf(format string, args ...interface{}) {
+	if q.Log != nil {
+		fmt.Fprintf(q.Log, format+"\n", args...)
+	}
+}
+
+// tailOutput keeps the end of long command output, where errors usually are.
+func tailOutput(s string) string {
+	const max = 4000
+	if len(s) <= max {
+		return s
+	}
+	return "…" + s[len(s)-max:]
+}
+
+// RejectionMessage is the inbox message telling an agent its branch was not merged.
+func (r *MergeRejection) RejectionMessage(teamName, from, target string) *Message {
+	var what string
+	switch r.Reason {
+	case MergeRejectConflict:
+		what = fmt.Sprintf("conflicts with %s and the branches merged before it", target)
+	default:
+		what = "fails verification once merged"
+	}
+	return &Message{
+		TeamName: teamName,
+		Kind:     MessageKindSystem,
+		From:     from,
+		To:       r.AgentID,
+		Subject:  "merge_rejected",
+		Body: fmt.Sprintf("Your branch %s was not merged: it %s.\nRebase onto %s, fix the problem, checkpoint and ask the leader to re-run the merge queue.\n\n%s",
+			r.Branch, what, target, tailOutput(strings.TrimSpace(r.Output))),
+	}
+}
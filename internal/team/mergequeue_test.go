@@ -0,0 +1,97 @@
+package team
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOrderMergeCandidates(t *testing.T) {
+	cands := []MergeCandidate{{AgentID: "ui"}, {AgentID: "api"}, {AgentID: "db"}}
+	tasks := []*Task{
+		{ID: "t-db", AgentID: "db"},
+		{ID: "t-api", AgentID: "api", ResolvedDeps: []string{"t-db"}},
+		{ID: "t-ui", AgentID: "ui", DependsOn: []string{"t-api", "t-missing"}},
+	}
+	var got []string
+	for _, c := range OrderMergeCandidates(cands, tasks) {
+		got = append(got, c.AgentID)
+	}
+	if strings.Join(got, ",") != "db,api,ui" {
+		t.Errorf("order = %v, want db,api,ui", got)
+	}
+}
+
+func TestMergeQueue_RejectsConflictAndBisectsVerifyFailure(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commitFile := func(branch, name, content string) {
+		git("checkout", "-q", "-B", branch, "main")
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", name)
+		git("commit", "-q", "-m", branch+": "+name)
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "t")
+	git("config", "user.email", "t@t")
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "README")
+	git("commit", "-q", "-m", "init")
+	commitFile("w1", "README", "w1\n")
+	commitFile("w2", "ok.txt", "fine\n")
+	commitFile("w3", "README", "w3\n") // conflicts with w1
+	commitFile("w4", "broken", "x\n")  // fails verification
+	commitFile("w5", "more.txt", "fine\n")
+	git("checkout", "-q", "main")
+
+	q := &MergeQueue{Repo: repo, Target: "main", Staging: "staging", Verify: "test ! -e broken"}
+	var cands []MergeCandidate
+	for _, b := range []string{"w1", "w2", "w3", "w4", "w5"} {
+		cands = append(cands, MergeCandidate{AgentID: b, Branch: b})
+	}
+	res, err := q.Run(cands)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var merged []string
+	for _, c := range res.Merged {
+		merged = append(merged, c.Branch)
+	}
+	if strings.Join(merged, ",") != "w1,w2,w5" {
+		t.Errorf("merged = %v, want w1,w2,w5", merged)
+	}
+	if len(res.Rejected) != 2 ||
+		res.Rejected[0].Branch != "w3" || res.Rejected[0].Reason != MergeRejectConflict || !strings.Contains(res.Rejected[0].Output, "README") ||
+		res.Rejected[1].Branch != "w4" || res.Rejected[1].Reason != MergeRejectVerify {
+		t.Errorf("rejected = %+v", res.Rejected)
+	}
+	if !res.Landed {
+		t.Fatal("expected main to be fast-forwarded")
+	}
+	for name, want := range map[string]bool{"README": true, "ok.txt": true, "more.txt": true, "broken": false} {
+		if _, err := os.Stat(filepath.Join(repo, name)); (err == nil) != want {
+			t.Errorf("%s present = %v, want %v", name, err == nil, want)
+		}
+	}
+
+	msg := res.Rejected[1].RejectionMessage("my-team", "leader", "main")
+	if msg.To != "w4" || msg.Subject != "merge_rejected" || !strings.Contains(msg.Body, "fails verification") {
+		t.Errorf("rejection message = %+v", msg)
+	}
+}
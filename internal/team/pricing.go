@@ -0,0 +1,88 @@
+package team
+
+import (
+	"sort"
+	"strings"
+)
+
+// ModelPrice is the per-million-token price of a model in US dollars.
+// Cache writes and cache reads are billed separately from regular input.
+type ModelPrice struct {
+	// InputPerMTok is the price per million uncached input tokens.
+	InputPerMTok float64 `json:"input_per_mtok" yaml:"input_per_mtok"`
+
+	// OutputPerMTok is the price per million output tokens.
+	OutputPerMTok float64 `json:"output_per_mtok" yaml:"output_per_mtok"`
+
+	// CacheWritePerMTok is the price per million tokens written to the prompt cache.
+	CacheWritePerMTok float64 `json:"cache_write_per_mtok" yaml:"cache_write_per_mtok"`
+
+	// CacheReadPerMTok is the price per million tokens read from the prompt cache.
+	CacheReadPerMTok float64 `json:"cache_read_per_mtok" yaml:"cache_read_per_mtok"`
+}
+
+// PriceTable maps a model name (or a substring of it, e.g. "opus") to its price.
+type PriceTable map[string]ModelPrice
+
+// DefaultModelKey is the price table entry used when a model is unknown or unset.
+const DefaultModelKey = "sonnet"
+
+// DefaultPriceTable returns the built-in price table keyed by model family.
+// Versioned keys cover releases priced differently from their family, such
+// as Opus 4.5 and Haiku 4.5; the family keys price older models.
+// Teams can override or extend entries via TeamConfig.Budgets.Prices.
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		"opus":      {InputPerMTok: 15, OutputPerMTok: 75, CacheWritePerMTok: 18.75, CacheReadPerMTok: 1.50},
+		"opus-4-5":  {InputPerMTok: 5, OutputPerMTok: 25, CacheWritePerMTok: 6.25, CacheReadPerMTok: 0.50},
+		"opus-4-6":  {InputPerMTok: 5, OutputPerMTok: 25, CacheWritePerMTok: 6.25, CacheReadPerMTok: 0.50},
+		"sonnet":    {InputPerMTok: 3, OutputPerMTok: 15, CacheWritePerMTok: 3.75, CacheReadPerMTok: 0.30},
+		"haiku":     {InputPerMTok: 0.80, OutputPerMTok: 4, CacheWritePerMTok: 1, CacheReadPerMTok: 0.08},
+		"haiku-4-5": {InputPerMTok: 1, OutputPerMTok: 5, CacheWritePerMTok: 1.25, CacheReadPerMTok: 0.10},
+	}
+}
+
+// Merge returns a copy of p with every entry in overrides applied on top.
+func (p PriceTable) Merge(overrides PriceTable) PriceTable {
+	out := make(PriceTable, len(p)+len(overrides))
+	for k, v := range p {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// Lookup returns the price for model.
+// An exact key match wins; otherwise the longest key contained in the model
+// name is used (so "claude-opus-4-1" matches "opus"). Unknown models fall
+// back to DefaultModelKey.
+func (p PriceTable) Lookup(model string) ModelPrice {
+	m := strings.ToLower(model)
+	if price, ok := p[m]; ok {
+		return price
+	}
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	// Longest match first so "sonnet-4" beats "sonnet" when both are present.
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	for _, k := range keys {
+		if k != "" && strings.Contains(m, k) {
+			return p[k]
+		}
+	}
+	return p[DefaultModelKey]
+}
+
+// Cost returns the dollar cost of ev under this price table.
+func (p PriceTable) Cost(ev *CostEvent) float64 {
+	price := p.Lookup(ev.Model)
+	const perMTok = 1_000_000.0
+	return float64(ev.InputTokens)*price.InputPerMTok/perMTok +
+		float64(ev.OutputTokens)*price.OutputPerMTok/perMTok +
+		float64(ev.CacheCreationTokens)*price.CacheWritePerMTok/perMTok +
+		float64(ev.CacheReadTokens)*price.CacheReadPerMTok/perMTok
+}
@@ -95,8 +95,10 @@ Your responsibilities as team leader:
    jikime team tasks create %s "Task title" --desc "What to do" --dod "Done when..." --owner worker-1
 3. After creating all tasks, run the blocking wait command that monitors completion:
    jikime team tasks wait %s --timeout 3600
-   (This command polls every 5 seconds, drains your inbox, and recovers tasks from dead agents.
-    It returns automatically when ALL tasks are done or failed.)
+   (This command polls every 5 seconds and recovers tasks from dead agents.
+    It returns automatically when ALL tasks are done or failed.
+    If it returns with "status: request", a worker is blocked on a question:
+    answer it with jikime team inbox answer %s <message-id> "..." and run wait again.)
 4. When wait returns, perform final integration:
    a. Review all files created/modified by workers.
    b. Fix any integration issues (missing imports, broken references, type errors).
@@ -108,7 +110,7 @@ Your responsibilities as team leader:
    jikime team lifecycle shutdown %s
 
 Available workers: %s
-`, goal, team, team, team, team, workers)
+`, goal, team, team, team, team, team, workers)
 
 	case "worker":
 		leaderID := cfg.LeaderID
@@ -180,7 +182,9 @@ func coordinationProtocol(cfg PromptConfig) string {
 			fmt.Sprintf("  jikime team tasks list %s                                          # view all tasks", team),
 			fmt.Sprintf("  jikime team status %s                                              # team overview", team),
 			fmt.Sprintf("  jikime team inbox receive %s                                       # read messages", team),
+			fmt.Sprintf("  jikime team inbox ack %s <message-id>                              # acknowledge a message once handled", team),
 			fmt.Sprintf("  jikime team inbox broadcast %s \"message\"                          # message all agents", team),
+			fmt.Sprintf("  jikime team inbox answer %s <message-id> \"answer\"              # answer a question/review-request/handoff", team),
 			fmt.Sprintf("  jikime team lifecycle shutdown %s                                  # shut down team", team),
 		)
 	case "worker":
@@ -190,21 +194,42 @@ func coordinationProtocol(cfg PromptConfig) string {
 			fmt.Sprintf("  jikime team tasks claim %s <id> --agent %s              # claim a task", team, agent),
 			fmt.Sprintf("  jikime team tasks complete %s <id> --agent %s --result \"summary\"  # mark task done", team, agent),
 			fmt.Sprintf("  jikime team inbox send %s %s \"message\"                # message leader", team, leader),
+			fmt.Sprintf("  jikime team inbox ask %s \"question\" --to %s          # BLOCKING: ask and wait for the answer", team, leader),
+			fmt.Sprintf("  jikime team inbox ask %s \"summary\" --kind review-request --to <reviewer>  # request a review", team),
+			fmt.Sprintf("  jikime team inbox ask %s \"context\" --kind handoff --to <agent>            # hand work to another agent", team),
 			fmt.Sprintf("  jikime team inbox receive %s                                       # check your inbox", team),
+			fmt.Sprintf("  jikime team inbox ack %s <message-id>                              # acknowledge a message once handled", team),
 		)
 	case "reviewer":
 		lines = append(lines,
 			fmt.Sprintf("  jikime team tasks list %s --status done                            # find tasks to review", team),
 			fmt.Sprintf("  jikime team tasks update %s <id> --status pending                 # send back for revision", team),
+			fmt.Sprintf("  jikime team inbox answer %s <message-id> \"verdict\"             # answer a review-request", team),
 			fmt.Sprintf("  jikime team inbox send %s %s \"message\"                # message leader", team, leader),
 			fmt.Sprintf("  jikime team inbox receive %s                                       # check your inbox", team),
+			fmt.Sprintf("  jikime team inbox ack %s <message-id>                              # acknowledge a message once handled", team),
 		)
 	default:
 		lines = append(lines,
 			fmt.Sprintf("  jikime team tasks list %s                                          # view tasks", team),
 			fmt.Sprintf("  jikime team inbox receive %s                                       # check inbox", team),
+			fmt.Sprintf("  jikime team inbox ack %s <message-id>                              # acknowledge a message once handled", team),
 		)
 	}
 
-	return strings.Join(lines, "\n") + "\n"
+	return strings.Join(lines, "\n") + "\n" + messageKinds
 }
+
+// messageKinds documents the typed request/response messages shared by all roles.
+const messageKinds = `
+Message kinds (shown in parentheses by inbox receive/peek):
+  question        — needs information; reply with inbox answer
+  review-request  — needs a review verdict; reply with inbox answer
+  handoff         — transfers work to you; reply with inbox answer to accept or decline
+  answer          — the reply to one of your requests
+The sender of a question, review-request or handoff may be blocked until you answer,
+so answer requests before continuing other work.
+inbox receive leases messages: run inbox ack <message-id> after handling each one
+(inbox answer acknowledges the request it answers). Unacknowledged messages are
+redelivered after 5 minutes.
+`
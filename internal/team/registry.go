@@ -133,18 +133,23 @@ func (r *Registry) IsAlive(agentID string) (bool, error) {
 		return false, nil
 	}
 
-	// 1. Try tmux session check.
+	// 1. Container check.
+	if info.ContainerID != "" {
+		return containerAlive(info.ContainerRuntime, info.ContainerID), nil
+	}
+
+	// 2. Try tmux session check.
 	if info.TmuxSession != "" {
 		alive := tmuxAlive(info.TmuxSession)
 		return alive, nil
 	}
 
-	// 2. Fallback to PID check.
+	// 3. Fallback to PID check.
 	if info.PID > 0 {
 		return pidAlive(info.PID), nil
 	}
 
-	// 3. Heartbeat staleness check (>30s without heartbeat → offline).
+	// 4. Heartbeat staleness check (>30s without heartbeat → offline).
 	if time.Since(info.LastHeartbeat) > 30*time.Second {
 		return false, nil
 	}
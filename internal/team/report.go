@@ -0,0 +1,589 @@
+package team
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportFormat selects how a Report is rendered.
+type ReportFormat string
+
+const (
+	ReportMarkdown ReportFormat = "md"
+	ReportHTML     ReportFormat = "html"
+	ReportJSON     ReportFormat = "json"
+)
+
+// ParseReportFormat accepts "md", "markdown", "html" and "json".
+func ParseReportFormat(s string) (ReportFormat, error) {
+	switch strings.ToLower(s) {
+	case "md", "markdown":
+		return ReportMarkdown, nil
+	case "html", "htm":
+		return ReportHTML, nil
+	case "json":
+		return ReportJSON, nil
+	}
+	return "", fmt.Errorf("team/report: unknown format %q (want md, html or json)", s)
+}
+
+// Report is a self-contained record of one team run, built from the team
+// directory after the run finishes.
+type Report struct {
+	Team        string    `json:"team"`
+	Goal        string    `json:"goal,omitempty"`
+	Template    string    `json:"template,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// StartedAt and FinishedAt bound the timeline (first claim to last completion).
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	TaskSummary map[TaskStatus]int `json:"task_summary"`
+	Tasks       []ReportTask       `json:"tasks"`
+	Agents      []ReportAgent      `json:"agents"`
+	Cost        *CostSummary       `json:"cost,omitempty"`
+	Plans       []ReportPlan       `json:"plans,omitempty"`
+	Failures    []ReportFailure    `json:"failures,omitempty"`
+	Commits     []ReportCommit     `json:"commits,omitempty"`
+	Timeline    []ReportEvent      `json:"timeline"`
+}
+
+// ReportTask is the outcome of one task.
+type ReportTask struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Status      TaskStatus `json:"status"`
+	Agent       string     `json:"agent,omitempty"`
+	DoD         string     `json:"dod,omitempty"`
+	Result      string     `json:"result,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Tokens      int        `json:"tokens,omitempty"`
+	CostUSD     float64    `json:"cost_usd,omitempty"`
+}
+
+// ReportAgent is one agent's share of the work and spend.
+type ReportAgent struct {
+	ID           string  `json:"id"`
+	Role         string  `json:"role,omitempty"`
+	TasksDone    int     `json:"tasks_done"`
+	TasksFailed  int     `json:"tasks_failed"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	TotalTokens  int     `json:"total_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// ReportPlan is a plan and the decision taken on it.
+type ReportPlan struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	SubmittedBy string     `json:"submitted_by"`
+	Status      PlanStatus `json:"status"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// ReportFailure is a failed task with its error message.
+type ReportFailure struct {
+	TaskID string     `json:"task_id"`
+	Title  string     `json:"title"`
+	Agent  string     `json:"agent,omitempty"`
+	Error  string     `json:"error"`
+	At     *time.Time `json:"at,omitempty"`
+}
+
+// ReportCommit is a commit made on an agent's workspace branch.
+// Merged reports whether it is reachable from the merge target.
+type ReportCommit struct {
+	Agent   string    `json:"agent"`
+	Branch  string    `json:"branch"`
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author,omitempty"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Merged  bool      `json:"merged"`
+}
+
+// ReportEvent is one line of the run timeline.
+type ReportEvent struct {
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor,omitempty"`
+	Text  string    `json:"text"`
+}
+
+// ReportInput is everything BuildReport reads. Commits are collected by the
+// caller because finding agent workspaces depends on how the team was launched.
+type ReportInput struct {
+	Config  *TeamConfig
+	Agents  []*AgentInfo
+	Tasks   []*Task
+	Plans   []*Plan
+	Costs   *CostStore
+	Commits []ReportCommit
+}
+
+// BuildReport assembles a Report from a finished (or running) team.
+func BuildReport(in ReportInput) (*Report, error) {
+	if in.Config == nil {
+		return nil, fmt.Errorf("team/report: team config is required")
+	}
+	r := &Report{
+		Team:        in.Config.Name,
+		Goal:        in.Config.Goal,
+		Template:    in.Config.Template,
+		CreatedAt:   in.Config.CreatedAt,
+		GeneratedAt: time.Now(),
+		TaskSummary: make(map[TaskStatus]int),
+		Commits:     in.Commits,
+	}
+	r.addEvent(in.Config.CreatedAt, "", "Team created")
+
+	var byTask map[string]*CostSummary
+	if in.Costs != nil {
+		var err error
+		if byTask, err = in.Costs.SummaryByTask(); err != nil {
+			return nil, fmt.Errorf("team/report: cost by task: %w", err)
+		}
+		if r.Cost, err = in.Costs.Summary(""); err != nil {
+			return nil, fmt.Errorf("team/report: cost summary: %w", err)
+		}
+	}
+
+	agents := make(map[string]*ReportAgent)
+	agent := func(id string) *ReportAgent {
+		if a, ok := agents[id]; ok {
+			return a
+		}
+		a := &ReportAgent{ID: id}
+		agents[id] = a
+		return a
+	}
+	for _, a := range in.Agents {
+		agent(a.ID).Role = a.Role
+		if !a.JoinedAt.IsZero() {
+			r.addEvent(a.JoinedAt, a.ID, "Joined as "+orDefault(a.Role, "agent"))
+		}
+	}
+
+	tasks := append([]*Task(nil), in.Tasks...)
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+	for _, t := range tasks {
+		r.TaskSummary[t.Status]++
+		rt := ReportTask{
+			ID:          t.ID,
+			Title:       t.Title,
+			Status:      t.Status,
+			Agent:       orDefault(t.AgentID, t.Owner),
+			DoD:         t.DoD,
+			Result:      t.Result,
+			Error:       t.ErrorMsg,
+			CreatedAt:   t.CreatedAt,
+			ClaimedAt:   t.ClaimedAt,
+			CompletedAt: t.CompletedAt,
+		}
+		if s := byTask[t.ID]; s != nil {
+			rt.Tokens = s.TotalTokens
+			rt.CostUSD = s.TotalCostUSD
+		}
+		r.Tasks = append(r.Tasks, rt)
+
+		if t.ClaimedAt != nil {
+			r.addEvent(*t.ClaimedAt, t.AgentID, fmt.Sprintf("Claimed %q", t.Title))
+			if r.StartedAt == nil || t.ClaimedAt.Before(*r.StartedAt) {
+				r.StartedAt = t.ClaimedAt
+			}
+		}
+		if t.CompletedAt != nil {
+			if r.FinishedAt == nil || t.CompletedAt.After(*r.FinishedAt) {
+				r.FinishedAt = t.CompletedAt
+			}
+		}
+		switch t.Status {
+		case TaskStatusDone:
+			if t.AgentID != "" {
+				agent(t.AgentID).TasksDone++
+			}
+			if t.CompletedAt != nil {
+				r.addEvent(*t.CompletedAt, t.AgentID, fmt.Sprintf("Completed %q", t.Title))
+			}
+		case TaskStatusFailed:
+			if t.AgentID != "" {
+				agent(t.AgentID).TasksFailed++
+			}
+			r.Failures = append(r.Failures, ReportFailure{
+				TaskID: t.ID, Title: t.Title, Agent: t.AgentID, Error: t.ErrorMsg, At: t.CompletedAt,
+			})
+			if t.CompletedAt != nil {
+				r.addEvent(*t.CompletedAt, t.AgentID, fmt.Sprintf("Failed %q: %s", t.Title, t.ErrorMsg))
+			}
+		}
+	}
+
+	if in.Costs != nil {
+		events, err := in.Costs.Events("")
+		if err != nil {
+			return nil, fmt.Errorf("team/report: cost events: %w", err)
+		}
+		for _, ev := range events {
+			agent(ev.AgentID)
+		}
+		for id, a := range agents {
+			s, err := in.Costs.Summary(id)
+			if err != nil {
+				return nil, fmt.Errorf("team/report: cost for %s: %w", id, err)
+			}
+			a.InputTokens = s.TotalInputTokens
+			a.OutputTokens = s.TotalOutputTokens
+			a.TotalTokens = s.TotalTokens
+			a.CostUSD = s.TotalCostUSD
+		}
+	}
+	for _, a := range agents {
+		r.Agents = append(r.Agents, *a)
+	}
+	sort.Slice(r.Agents, func(i, j int) bool { return r.Agents[i].ID < r.Agents[j].ID })
+
+	for _, p := range in.Plans {
+		r.Plans = append(r.Plans, ReportPlan{
+			ID:          p.ID,
+			Title:       p.Title,
+			SubmittedBy: p.SubmittedBy,
+			Status:      p.Status,
+			ReviewedBy:  p.ReviewedBy,
+			Reason:      p.RejectionReason,
+			SubmittedAt: p.SubmittedAt,
+			ReviewedAt:  p.ReviewedAt,
+		})
+		r.addEvent(p.SubmittedAt, p.SubmittedBy, fmt.Sprintf("Submitted plan %q", p.Title))
+		if p.ReviewedAt != nil {
+			r.addEvent(*p.ReviewedAt, p.ReviewedBy, fmt.Sprintf("Plan %q %s", p.Title, p.Status))
+		}
+	}
+	sort.Slice(r.Plans, func(i, j int) bool { return r.Plans[i].SubmittedAt.Before(r.Plans[j].SubmittedAt) })
+
+	for _, c := range in.Commits {
+		if c.Merged {
+			r.addEvent(c.Date, c.Agent, fmt.Sprintf("Committed %.8s %s", c.SHA, c.Subject))
+		}
+	}
+	sort.SliceStable(r.Timeline, func(i, j int) bool { return r.Timeline[i].At.Before(r.Timeline[j].At) })
+	return r, nil
+}
+
+func (r *Report) addEvent(at time.Time, actor, text string) {
+	if at.IsZero() {
+		return
+	}
+	r.Timeline = append(r.Timeline, ReportEvent{At: at, Actor: actor, Text: text})
+}
+
+// Duration is the time from the first task claim to the last completion.
+func (r *Report) Duration() time.Duration {
+	if r.StartedAt == nil || r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(*r.StartedAt).Round(time.Second)
+}
+
+// MergedCommits returns the commits reachable from the merge target.
+func (r *Report) MergedCommits() []ReportCommit {
+	var out []ReportCommit
+	for _, c := range r.Commits {
+		if c.Merged {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// UnmergedCount is the number of workspace commits not on the merge target.
+func (r *Report) UnmergedCount() int {
+	return len(r.Commits) - len(r.MergedCommits())
+}
+
+// Render writes the report in the given format.
+func (r *Report) Render(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case ReportHTML:
+		return reportHTML.Execute(w, r)
+	case ReportMarkdown, "":
+		return r.writeMarkdown(w)
+	}
+	return fmt.Errorf("team/report: unknown format %q", format)
+}
+
+// reportTimeFormat is used for all timestamps in rendered reports.
+const reportTimeFormat = "2006-01-02 15:04:05 MST"
+
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Team report: %s\n\n", r.Team)
+	if r.Goal != "" {
+		fmt.Fprintf(&b, "**Goal:** %s\n\n", r.Goal)
+	}
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Template | %s |\n", mdCell(orDefault(r.Template, "—")))
+	fmt.Fprintf(&b, "| Created | %s |\n", fmtTime(&r.CreatedAt))
+	fmt.Fprintf(&b, "| Started | %s |\n", fmtTime(r.StartedAt))
+	fmt.Fprintf(&b, "| Finished | %s |\n", fmtTime(r.FinishedAt))
+	if d := r.Duration(); d > 0 {
+		fmt.Fprintf(&b, "| Duration | %s |\n", d)
+	}
+	fmt.Fprintf(&b, "| Tasks | %s |\n", summaryLine(r.TaskSummary, len(r.Tasks)))
+	if r.Cost != nil {
+		fmt.Fprintf(&b, "| Tokens | %d |\n| Cost | $%.2f |\n", r.Cost.TotalTokens, r.Cost.TotalCostUSD)
+	}
+	fmt.Fprintf(&b, "| Generated | %s |\n\n", fmtTime(&r.GeneratedAt))
+
+	b.WriteString("## Tasks\n\n")
+	if len(r.Tasks) == 0 {
+		b.WriteString("_No tasks._\n\n")
+	}
+	for _, t := range r.Tasks {
+		fmt.Fprintf(&b, "### %s %s (`%.8s`)\n\n", statusIcon(t.Status), t.Title, t.ID)
+		fmt.Fprintf(&b, "- **Status:** %s\n", t.Status)
+		if t.Agent != "" {
+			fmt.Fprintf(&b, "- **Agent:** %s\n", t.Agent)
+		}
+		if t.CompletedAt != nil {
+			fmt.Fprintf(&b, "- **Completed:** %s\n", fmtTime(t.CompletedAt))
+		}
+		if t.Tokens > 0 {
+			fmt.Fprintf(&b, "- **Tokens:** %d ($%.2f)\n", t.Tokens, t.CostUSD)
+		}
+		if t.DoD != "" {
+			fmt.Fprintf(&b, "- **Definition of done:** %s\n", oneLine(t.DoD))
+		}
+		if t.Result != "" {
+			fmt.Fprintf(&b, "\n**Result**\n\n%s\n", mdQuote(t.Result))
+		}
+		if t.Error != "" {
+			fmt.Fprintf(&b, "\n**Error**\n\n```\n%s\n```\n", strings.TrimRight(t.Error, "\n"))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Agents\n\n")
+	b.WriteString("| Agent | Role | Done | Failed | Input | Output | Total | Cost |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, a := range r.Agents {
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %d | %d | $%.2f |\n",
+			mdCell(a.ID), mdCell(a.Role), a.TasksDone, a.TasksFailed, a.InputTokens, a.OutputTokens, a.TotalTokens, a.CostUSD)
+	}
+	b.WriteString("\n")
+
+	if len(r.Plans) > 0 {
+		b.WriteString("## Plan decisions\n\n")
+		b.WriteString("| Plan | Submitted by | Decision | Reviewed by | Reason |\n|---|---|---|---|---|\n")
+		for _, p := range r.Plans {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				mdCell(p.Title), mdCell(p.SubmittedBy), p.Status, mdCell(p.ReviewedBy), mdCell(p.Reason))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Failures) > 0 {
+		b.WriteString("## Failures\n\n")
+		for _, f := range r.Failures {
+			fmt.Fprintf(&b, "- **%s** (`%.8s`, %s): %s\n", f.Title, f.TaskID, orDefault(f.Agent, "unassigned"), oneLine(f.Error))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Merged commits\n\n")
+	merged := r.MergedCommits()
+	if len(merged) == 0 {
+		b.WriteString("_No merged commits found in agent workspaces._\n\n")
+	} else {
+		b.WriteString("| Commit | Agent | Date | Subject |\n|---|---|---|---|\n")
+		for _, c := range merged {
+			fmt.Fprintf(&b, "| `%.8s` | %s | %s | %s |\n", c.SHA, mdCell(c.Agent), fmtTime(&c.Date), mdCell(c.Subject))
+		}
+		b.WriteString("\n")
+	}
+	if n := r.UnmergedCount(); n > 0 {
+		fmt.Fprintf(&b, "%d workspace commit(s) were not merged.\n\n", n)
+	}
+
+	b.WriteString("## Timeline\n\n")
+	for _, e := range r.Timeline {
+		actor := ""
+		if e.Actor != "" {
+			actor = " **" + e.Actor + "**"
+		}
+		fmt.Fprintf(&b, "- `%s`%s %s\n", e.At.Format(reportTimeFormat), actor, oneLine(e.Text))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func statusIcon(s TaskStatus) string {
+	switch s {
+	case TaskStatusDone:
+		return "✅"
+	case TaskStatusFailed:
+		return "❌"
+	case TaskStatusInProgress:
+		return "🔄"
+	case TaskStatusBlocked:
+		return "⛔"
+	}
+	return "⏳"
+}
+
+func summaryLine(counts map[TaskStatus]int, total int) string {
+	parts := []string{fmt.Sprintf("%d total", total)}
+	for _, s := range []TaskStatus{TaskStatusDone, TaskStatusFailed, TaskStatusInProgress, TaskStatusPending, TaskStatusBlocked} {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fmtTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "—"
+	}
+	return t.Format(reportTimeFormat)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// mdCell makes s safe inside a Markdown table cell.
+func mdCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
+
+// mdQuote renders multi-line text as a Markdown block quote.
+func mdQuote(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reportHTML renders a single HTML file with inline CSS and no external
+// assets, so it can be attached to deliverables as-is.
+var reportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time": func(v any) string {
+		switch t := v.(type) {
+		case time.Time:
+			return fmtTime(&t)
+		case *time.Time:
+			return fmtTime(t)
+		}
+		return "—"
+	},
+	"icon":     statusIcon,
+	"summary":  summaryLine,
+	"short":    func(s string) string { return fmt.Sprintf("%.8s", s) },
+	"usd":      func(f float64) string { return fmt.Sprintf("$%.2f", f) },
+	"timeline": func(t time.Time) string { return t.Format(reportTimeFormat) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Team report: {{.Team}}</title>
+<style>
+body{font:14px/1.5 -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;color:#1f2328;max-width:960px;margin:2rem auto;padding:0 1rem}
+h1{border-bottom:2px solid #d0d7de;padding-bottom:.3rem}
+h2{margin-top:2rem;border-bottom:1px solid #d0d7de;padding-bottom:.2rem}
+table{border-collapse:collapse;width:100%;margin:.5rem 0}
+th,td{border:1px solid #d0d7de;padding:4px 8px;text-align:left;vertical-align:top}
+th{background:#f6f8fa}
+td.n{text-align:right;font-variant-numeric:tabular-nums}
+code,pre{font-family:ui-monospace,SFMono-Regular,Menlo,monospace;font-size:12px}
+pre{background:#f6f8fa;padding:8px;overflow-x:auto;white-space:pre-wrap}
+.task{border:1px solid #d0d7de;border-radius:6px;padding:.5rem 1rem;margin:.75rem 0}
+.task h3{margin:.25rem 0}
+.failed{border-color:#cf222e}
+.meta{color:#59636e}
+.err{background:#ffebe9}
+ul.timeline{list-style:none;padding:0}
+ul.timeline li{padding:2px 0}
+</style>
+</head>
+<body>
+<h1>Team report: {{.Team}}</h1>
+{{if .Goal}}<p><strong>Goal:</strong> {{.Goal}}</p>{{end}}
+<table>
+<tr><th>Template</th><td>{{or .Template "—"}}</td></tr>
+<tr><th>Created</th><td>{{time .CreatedAt}}</td></tr>
+<tr><th>Started</th><td>{{time .StartedAt}}</td></tr>
+<tr><th>Finished</th><td>{{time .FinishedAt}}</td></tr>
+{{with .Duration}}<tr><th>Duration</th><td>{{.}}</td></tr>{{end}}
+<tr><th>Tasks</th><td>{{summary .TaskSummary (len .Tasks)}}</td></tr>
+{{with .Cost}}<tr><th>Tokens</th><td>{{.TotalTokens}}</td></tr><tr><th>Cost</th><td>{{usd .TotalCostUSD}}</td></tr>{{end}}
+<tr><th>Generated</th><td>{{time .GeneratedAt}}</td></tr>
+</table>
+
+<h2>Tasks</h2>
+{{range .Tasks}}<div class="task{{if eq .Status "failed"}} failed{{end}}">
+<h3>{{icon .Status}} {{.Title}} <code>{{short .ID}}</code></h3>
+<p class="meta">{{.Status}}{{if .Agent}} · {{.Agent}}{{end}}{{if .CompletedAt}} · {{time .CompletedAt}}{{end}}{{if .Tokens}} · {{.Tokens}} tokens ({{usd .CostUSD}}){{end}}</p>
+{{if .DoD}}<p><strong>Definition of done:</strong> {{.DoD}}</p>{{end}}
+{{if .Result}}<p><strong>Result</strong></p><pre>{{.Result}}</pre>{{end}}
+{{if .Error}}<p><strong>Error</strong></p><pre class="err">{{.Error}}</pre>{{end}}
+</div>
+{{else}}<p><em>No tasks.</em></p>
+{{end}}
+<h2>Agents</h2>
+<table>
+<tr><th>Agent</th><th>Role</th><th>Done</th><th>Failed</th><th>Input</th><th>Output</th><th>Total</th><th>Cost</th></tr>
+{{range .Agents}}<tr><td>{{.ID}}</td><td>{{.Role}}</td><td class="n">{{.TasksDone}}</td><td class="n">{{.TasksFailed}}</td><td class="n">{{.InputTokens}}</td><td class="n">{{.OutputTokens}}</td><td class="n">{{.TotalTokens}}</td><td class="n">{{usd .CostUSD}}</td></tr>
+{{end}}</table>
+{{if .Plans}}
+<h2>Plan decisions</h2>
+<table>
+<tr><th>Plan</th><th>Submitted by</th><th>Decision</th><th>Reviewed by</th><th>Reason</th></tr>
+{{range .Plans}}<tr><td>{{.Title}}</td><td>{{.SubmittedBy}}</td><td>{{.Status}}</td><td>{{.ReviewedBy}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Failures}}
+<h2>Failures</h2>
+<table>
+<tr><th>Task</th><th>Agent</th><th>Error</th></tr>
+{{range .Failures}}<tr><td>{{.Title}} <code>{{short .TaskID}}</code></td><td>{{or .Agent "unassigned"}}</td><td><pre class="err">{{.Error}}</pre></td></tr>
+{{end}}</table>
+{{end}}
+<h2>Merged commits</h2>
+{{with .MergedCommits}}<table>
+<tr><th>Commit</th><th>Agent</th><th>Date</th><th>Subject</th></tr>
+{{range .}}<tr><td><code>{{short .SHA}}</code></td><td>{{.Agent}}</td><td>{{time .Date}}</td><td>{{.Subject}}</td></tr>
+{{end}}</table>
+{{else}}<p><em>No merged commits found in agent workspaces.</em></p>
+{{end}}{{with .UnmergedCount}}<p class="meta">{{.}} workspace commit(s) were not merged.</p>{{end}}
+<h2>Timeline</h2>
+<ul class="timeline">
+{{range .Timeline}}<li><code>{{timeline .At}}</code>{{if .Actor}} <strong>{{.Actor}}</strong>{{end}} {{.Text}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
@@ -0,0 +1,81 @@
+package team
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildReport_Render(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	claimed := created.Add(time.Minute)
+	done := created.Add(10 * time.Minute)
+	failed := created.Add(12 * time.Minute)
+
+	costs, err := NewCostStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := costs.Record("worker-1", "t1", "Edit", "", 1000, 200); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := BuildReport(ReportInput{
+		Config: &TeamConfig{Name: "mig", Goal: "port <legacy> app", Template: "leader-worker", CreatedAt: created},
+		Agents: []*AgentInfo{{ID: "worker-1", Role: "worker", JoinedAt: created}},
+		Tasks: []*Task{
+			{ID: "t1", Title: "Port DAO", Status: TaskStatusDone, AgentID: "worker-1", DoD: "tests pass",
+				Result: "ported 12 DAOs\nall green", CreatedAt: created, ClaimedAt: &claimed, CompletedAt: &done},
+			{ID: "t2", Title: "Port | views", Status: TaskStatusFailed, AgentID: "worker-1",
+				ErrorMsg: "JSP tag <c:forEach> unsupported", CreatedAt: created, ClaimedAt: &claimed, CompletedAt: &failed},
+		},
+		Plans: []*Plan{{ID: "p1", Title: "Split by layer", SubmittedBy: "leader", Status: PlanStatusRejected,
+			ReviewedBy: "user", RejectionReason: "too coarse", SubmittedAt: created, ReviewedAt: &claimed}},
+		Costs: costs,
+		Commits: []ReportCommit{
+			{Agent: "worker-1", SHA: "abcdef1234567", Subject: "port dao", Date: done, Merged: true},
+			{Agent: "worker-1", SHA: "0123456789abc", Subject: "wip views", Date: failed},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildReport: %v", err)
+	}
+	if len(r.Agents) != 1 || r.Agents[0].TasksDone != 1 || r.Agents[0].TasksFailed != 1 || r.Agents[0].TotalTokens != 1200 {
+		t.Errorf("agents = %+v", r.Agents)
+	}
+	if r.Tasks[0].Tokens != 1200 || len(r.Failures) != 1 || r.Duration() != 11*time.Minute {
+		t.Errorf("tasks = %+v, failures = %+v, duration = %s", r.Tasks, r.Failures, r.Duration())
+	}
+
+	var md bytes.Buffer
+	if err := r.Render(&md, ReportMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"**Goal:** port <legacy> app", "> ported 12 DAOs\n> all green", "| Split by layer | leader | rejected |", "`abcdef12`", "1 workspace commit(s) were not merged", "too coarse"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := r.Render(&html, ReportHTML); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html.String(), "<c:forEach>") || !strings.Contains(html.String(), "&lt;c:forEach&gt;") {
+		t.Error("html does not escape task output")
+	}
+	if strings.Contains(html.String(), "<link") || strings.Contains(html.String(), "<script") {
+		t.Error("html report must not reference external assets")
+	}
+
+	var js bytes.Buffer
+	if err := r.Render(&js, ReportJSON); err != nil {
+		t.Fatal(err)
+	}
+	var back Report
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil || back.Team != "mig" || len(back.Commits) != 2 {
+		t.Errorf("json round trip = %+v, %v", back, err)
+	}
+}
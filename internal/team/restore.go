@@ -0,0 +1,227 @@
+package team
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// RestoreOptions controls how a Session is rehydrated into a live team.
+type RestoreOptions struct {
+	// DataDir is the root ~/.jikime directory passed to respawned agents.
+	DataDir string
+
+	// Goal is injected into respawned agents' prompts (usually TeamConfig.Goal).
+	Goal string
+
+	// Backend overrides the backend recorded for each agent. Empty keeps the recorded one.
+	Backend SpawnBackend
+
+	// Container configures the container backend for respawned agents.
+	Container ContainerOptions
+
+	// Prune deletes tasks created after the snapshot was taken.
+	Prune bool
+
+	// NoSpawn restores tasks only; in-progress tasks of every agent that is
+	// not currently alive are released.
+	NoSpawn bool
+}
+
+// RestoreResult reports what RestoreSession changed.
+type RestoreResult struct {
+	// TasksRestored is the number of task files rewritten from the snapshot.
+	TasksRestored int
+
+	// TasksPruned lists IDs of tasks deleted because they were not in the snapshot.
+	TasksPruned []string
+
+	// TasksReleased lists IDs of in-progress tasks reset to pending because
+	// their agent is gone and was not respawned.
+	TasksReleased []string
+
+	// Respawned lists agents started again.
+	Respawned []*SpawnResult
+
+	// AlreadyAlive lists agents that were still running and left untouched.
+	AlreadyAlive []string
+
+	// Failed maps agent IDs to the spawn error that prevented their restore.
+	Failed map[string]error
+}
+
+// RestoreSession rewrites the task store to match sess, releases in-progress
+// tasks whose agents are gone, and respawns the agents recorded in sess with
+// their original roles and worktrees.
+//
+// Agents are spawned before orphaned tasks are released, so a task keeps its
+// claim only if its agent is alive again afterwards.
+func RestoreSession(sess *Session, store *Store, reg *Registry, spawner *Spawner, opts RestoreOptions) (*RestoreResult, error) {
+	res := &RestoreResult{Failed: map[string]error{}}
+
+	// 1. Rewrite task files to the snapshot.
+	inSnapshot := make(map[string]bool, len(sess.Tasks))
+	for i := range sess.Tasks {
+		t := sess.Tasks[i]
+		inSnapshot[t.ID] = true
+		t.UpdatedAt = time.Now()
+		if err := store.ForceStatus(&t); err != nil {
+			return res, fmt.Errorf("team/restore: task %s: %w", t.ID, err)
+		}
+		res.TasksRestored++
+	}
+	if opts.Prune {
+		current, err := store.List("", "")
+		if err != nil {
+			return res, err
+		}
+		for _, t := range current {
+			if inSnapshot[t.ID] {
+				continue
+			}
+			if err := store.Delete(t.ID); err != nil {
+				return res, fmt.Errorf("team/restore: prune %s: %w", t.ID, err)
+			}
+			res.TasksPruned = append(res.TasksPruned, t.ID)
+		}
+	}
+
+	// 2. Respawn agents that are no longer running.
+	alive := map[string]bool{}
+	var leaderID string
+	var workers []string
+	for _, a := range sess.Agents {
+		switch a.Role {
+		case "leader":
+			leaderID = a.ID
+		case "worker":
+			workers = append(workers, a.ID)
+		}
+	}
+	for _, a := range sess.Agents {
+		if ok, _ := reg.IsAlive(a.ID); ok {
+			alive[a.ID] = true
+			res.AlreadyAlive = append(res.AlreadyAlive, a.ID)
+			continue
+		}
+		if opts.NoSpawn {
+			continue
+		}
+		spawned, err := respawnAgent(a, sess, reg, spawner, opts, leaderID, workers)
+		if err != nil {
+			res.Failed[a.ID] = err
+			continue
+		}
+		alive[a.ID] = true
+		res.Respawned = append(res.Respawned, spawned)
+	}
+
+	// 3. Release in-progress tasks whose agents did not come back.
+	tasks, err := store.List(TaskStatusInProgress, "")
+	if err != nil {
+		return res, err
+	}
+	for _, t := range tasks {
+		if alive[t.AgentID] {
+			continue
+		}
+		if _, err := store.Release(t.ID); err != nil {
+			return res, fmt.Errorf("team/restore: release %s: %w", t.ID, err)
+		}
+		res.TasksReleased = append(res.TasksReleased, t.ID)
+	}
+	return res, nil
+}
+
+// respawnAgent starts a single agent from its snapshot record and registers it.
+func respawnAgent(a AgentInfo, sess *Session, reg *Registry, spawner *Spawner, opts RestoreOptions, leaderID string, workers []string) (*SpawnResult, error) {
+	backend := opts.Backend
+	if backend == "" {
+		backend = recordedBackend(a)
+	}
+	worktree := a.WorktreePath
+	if worktree != "" {
+		if _, err := os.Stat(worktree); err != nil {
+			return nil, fmt.Errorf("worktree %s: %w", worktree, err)
+		}
+	}
+
+	pcfg := PromptConfig{
+		TeamName:     sess.TeamName,
+		AgentID:      a.ID,
+		Role:         a.Role,
+		LeaderID:     leaderID,
+		Workers:      workers,
+		Goal:         opts.Goal,
+		WorktreePath: worktree,
+	}
+	if a.Role == "leader" {
+		pcfg.LeaderID = ""
+	}
+	prompt := BuildAgentPrompt(pcfg) + resumeNote(sess, a.ID)
+
+	res, err := spawner.Spawn(SpawnConfig{
+		TeamName:        sess.TeamName,
+		AgentID:         a.ID,
+		Role:            a.Role,
+		WorktreePath:    worktree,
+		InitialPrompt:   prompt,
+		Backend:         backend,
+		DataDir:         opts.DataDir,
+		ExtraEnv:        map[string]string{"JIKIME_RESUME": "1"},
+		SkipPermissions: true,
+		Container:       opts.Container,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := a
+	info.Status = AgentStatusActive
+	info.PID = res.PID
+	info.TmuxSession = res.TmuxSession
+	info.ContainerID = res.ContainerID
+	info.ContainerRuntime = res.ContainerRuntime
+	info.Backend = backend
+	if err := reg.Register(&info); err != nil {
+		return res, fmt.Errorf("register: %w", err)
+	}
+	return res, nil
+}
+
+// recordedBackend returns the backend an agent was spawned with. Records
+// saved before AgentInfo.Backend existed leave it empty, so it is inferred
+// from the handle the agent was tracked by, falling back to tmux.
+func recordedBackend(a AgentInfo) SpawnBackend {
+	switch {
+	case a.Backend != "":
+		return a.Backend
+	case a.ContainerID != "":
+		return SpawnBackendContainer
+	case a.TmuxSession != "":
+		return SpawnBackendTmux
+	case a.PID > 0:
+		return SpawnBackendSubprocess
+	}
+	return SpawnBackendTmux
+}
+
+// resumeNote tells a respawned agent which of its tasks were in flight.
+func resumeNote(sess *Session, agentID string) string {
+	var inFlight []string
+	for _, t := range sess.Tasks {
+		if t.Status == TaskStatusInProgress && t.AgentID == agentID {
+			inFlight = append(inFlight, fmt.Sprintf("- %.8s  %s", t.ID, t.Title))
+		}
+	}
+	note := fmt.Sprintf("\n## Resumed Session\n\nThis team was restored from a session saved at %s.\n",
+		sess.SavedAt.Format(time.RFC3339))
+	if len(inFlight) == 0 {
+		return note + "You had no task in progress; continue with the workflow above.\n"
+	}
+	note += "You were working on these tasks when the session was saved. Inspect your workspace for partial work and continue them before claiming new ones:\n"
+	for _, l := range inFlight {
+		note += l + "\n"
+	}
+	return note
+}
@@ -0,0 +1,117 @@
+package team
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreSession(t *testing.T) {
+	dataDir := t.TempDir()
+	td := filepath.Join(dataDir, "teams", "my-team")
+	store, err := NewStore(filepath.Join(td, "tasks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := NewRegistry(filepath.Join(td, "registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := store.Create("task a", "", "", nil, 0, nil, "")
+	b, _ := store.Create("task b", "", "", nil, 0, nil, "")
+	a, _ = store.Claim(a.ID, "worker-1")
+	b, _ = store.Claim(b.ID, "worker-2")
+
+	worktree := t.TempDir()
+	sess := &Session{
+		ID:       "sess-1",
+		TeamName: "my-team",
+		Tasks:    []Task{*a, *b},
+		Agents: []AgentInfo{
+			{ID: "worker-1", Role: "worker", Backend: SpawnBackendContainer, WorktreePath: worktree},
+		},
+	}
+
+	// Work after the snapshot: task a finished, a new task appeared.
+	if _, err := store.Complete(a.ID, "worker-1", "done"); err != nil {
+		t.Fatal(err)
+	}
+	extra, _ := store.Create("late task", "", "", nil, 0, nil, "")
+
+	rt := NewFakeContainerRuntime()
+	res, err := RestoreSession(sess, store, reg, NewSpawnerWithRuntime(rt), RestoreOptions{DataDir: dataDir, Prune: true})
+	if err != nil {
+		t.Fatalf("RestoreSession: %v", err)
+	}
+
+	if res.TasksRestored != 2 {
+		t.Errorf("TasksRestored = %d, want 2", res.TasksRestored)
+	}
+	if len(res.TasksPruned) != 1 || res.TasksPruned[0] != extra.ID {
+		t.Errorf("TasksPruned = %v, want [%s]", res.TasksPruned, extra.ID)
+	}
+	if len(res.Respawned) != 1 || res.Respawned[0].AgentID != "worker-1" {
+		t.Fatalf("Respawned = %+v, want worker-1", res.Respawned)
+	}
+	if rt.Specs[0].WorkDir != worktree || rt.Specs[0].Env["JIKIME_ROLE"] != "worker" {
+		t.Errorf("spawn spec = %+v, want worker in recorded worktree", rt.Specs[0])
+	}
+
+	// worker-1 is back, so its task keeps the claim from the snapshot.
+	if got, _ := store.Get(a.ID); got.Status != TaskStatusInProgress || got.AgentID != "worker-1" {
+		t.Errorf("task a = %s/%s, want in_progress/worker-1", got.Status, got.AgentID)
+	}
+	// worker-2 is not in the session, so its task is released.
+	if got, _ := store.Get(b.ID); got.Status != TaskStatusPending || got.AgentID != "" {
+		t.Errorf("task b = %s/%q, want pending/unclaimed", got.Status, got.AgentID)
+	}
+	if info, _ := reg.Get("worker-1"); info == nil || info.ContainerID == "" {
+		t.Errorf("registry entry = %+v, want container ID recorded", info)
+	}
+}
+
+func TestRestoreSession_LegacyAgentWithoutBackend(t *testing.T) {
+	dataDir := t.TempDir()
+	td := filepath.Join(dataDir, "teams", "old-team")
+	store, err := NewStore(filepath.Join(td, "tasks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := NewRegistry(filepath.Join(td, "registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Saved before AgentInfo.Backend existed: only the container handle is known.
+	sess := &Session{
+		ID:       "sess-old",
+		TeamName: "old-team",
+		Agents:   []AgentInfo{{ID: "worker-1", Role: "worker", ContainerID: "abc123", ContainerRuntime: "docker"}},
+	}
+	rt := NewFakeContainerRuntime()
+	res, err := RestoreSession(sess, store, reg, NewSpawnerWithRuntime(rt), RestoreOptions{DataDir: dataDir})
+	if err != nil {
+		t.Fatalf("RestoreSession: %v", err)
+	}
+	if len(res.Failed) != 0 || len(res.Respawned) != 1 || len(rt.Specs) != 1 {
+		t.Fatalf("Respawned = %+v, Failed = %v, want worker-1 in a container", res.Respawned, res.Failed)
+	}
+	if info, _ := reg.Get("worker-1"); info == nil || info.Backend != SpawnBackendContainer {
+		t.Errorf("registry entry = %+v, want backend recorded as container", info)
+	}
+
+	for _, c := range []struct {
+		agent AgentInfo
+		want  SpawnBackend
+	}{
+		{AgentInfo{Backend: SpawnBackendSubprocess, TmuxSession: "s"}, SpawnBackendSubprocess},
+		{AgentInfo{ContainerID: "abc"}, SpawnBackendContainer},
+		{AgentInfo{TmuxSession: "jikime-t-w1", PID: 42}, SpawnBackendTmux},
+		{AgentInfo{PID: 42}, SpawnBackendSubprocess},
+		{AgentInfo{}, SpawnBackendTmux},
+	} {
+		if got := recordedBackend(c.agent); got != c.want {
+			t.Errorf("recordedBackend(%+v) = %q, want %q", c.agent, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRPCTimeout is returned when no answer arrives before the deadline.
+var ErrRPCTimeout = errors.New("team/rpc: timed out waiting for answer")
+
+// DefaultRPCPollInterval is how often RPC checks the inbox for an answer.
+const DefaultRPCPollInterval = 500 * time.Millisecond
+
+// RPC layers request/response on top of a Transport.
+//
+// A request (question, review-request, handoff) carries a fresh CorrelationID.
+// The responder answers with Reply, which copies the CorrelationID onto an
+// answer message. The requester polls its own inbox and takes only the
+// matching answer, leaving unrelated messages queued.
+type RPC struct {
+	transport Transport
+	teamName  string
+	agentID   string
+
+	// LeaderID is the recipient of AskLeader. Default: "leader".
+	LeaderID string
+
+	// PollInterval is how often the inbox is checked. Default: DefaultRPCPollInterval.
+	PollInterval time.Duration
+}
+
+// NewRPC returns an RPC client sending as agentID within teamName.
+func NewRPC(t Transport, teamName, agentID string) *RPC {
+	return &RPC{
+		transport:    t,
+		teamName:     teamName,
+		agentID:      agentID,
+		LeaderID:     "leader",
+		PollInterval: DefaultRPCPollInterval,
+	}
+}
+
+// Send delivers a request of the given kind to `to` without waiting for the
+// answer. The returned message carries the CorrelationID to pass to Await.
+func (r *RPC) Send(kind MessageKind, to, subject, body string) (*Message, error) {
+	if !kind.IsRequest() {
+		return nil, fmt.Errorf("team/rpc: %q is not a request kind", kind)
+	}
+	req := &Message{
+		ID:            uuid.New().String(),
+		TeamName:      r.teamName,
+		Kind:          kind,
+		From:          r.agentID,
+		To:            to,
+		Subject:       subject,
+		Body:          body,
+		CorrelationID: uuid.New().String(),
+		SentAt:        time.Now(),
+	}
+	if err := r.transport.Send(req); err != nil {
+		return nil, err
+	}
+	if req.DeadLetterReason != "" {
+		return req, fmt.Errorf("team/rpc: %s", req.DeadLetterReason)
+	}
+	return req, nil
+}
+
+// Await blocks until the answer for req arrives in this agent's inbox or ctx
+// is done. Returns ErrRPCTimeout when ctx expires first.
+func (r *RPC) Await(ctx context.Context, req *Message) (*Message, error) {
+	interval := r.PollInterval
+	if interval <= 0 {
+		interval = DefaultRPCPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ans, err := r.findAnswer(req.CorrelationID)
+		if err != nil || ans != nil {
+			return ans, err
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrRPCTimeout
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// findAnswer takes the answer with correlationID from the inbox, if present.
+func (r *RPC) findAnswer(correlationID string) (*Message, error) {
+	pending, err := r.transport.Peek(r.agentID, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range pending {
+		if m.Kind != MessageKindAnswer || m.CorrelationID != correlationID {
+			continue
+		}
+		ans, err := r.transport.Take(r.agentID, m.ID)
+		if err != nil || ans != nil {
+			return ans, err
+		}
+	}
+	return nil, nil
+}
+
+// Request sends a request and waits up to timeout for its answer.
+// timeout ≤ 0 waits until ctx is done.
+func (r *RPC) Request(ctx context.Context, kind MessageKind, to, subject, body string, timeout time.Duration) (*Message, error) {
+	req, err := r.Send(kind, to, subject, body)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return r.Await(ctx, req)
+}
+
+// Ask sends a question to `to` and waits for the answer.
+func (r *RPC) Ask(to, question string, timeout time.Duration) (*Message, error) {
+	return r.Request(context.Background(), MessageKindQuestion, to, "", question, timeout)
+}
+
+// AskLeader sends a question to the team leader and waits for the answer.
+func (r *RPC) AskLeader(question string, timeout time.Duration) (*Message, error) {
+	return r.Ask(r.LeaderID, question, timeout)
+}
+
+// RequestReview asks `to` to review work and waits for the verdict.
+func (r *RPC) RequestReview(to, subject, body string, timeout time.Duration) (*Message, error) {
+	return r.Request(context.Background(), MessageKindReviewRequest, to, subject, body, timeout)
+}
+
+// Handoff passes work to `to` and waits for it to be accepted.
+func (r *RPC) Handoff(to, subject, body string, timeout time.Duration) (*Message, error) {
+	return r.Request(context.Background(), MessageKindHandoff, to, subject, body, timeout)
+}
+
+// Reply answers req. The answer is addressed to the requester and carries
+// req's CorrelationID so the requester's Await picks it up.
+func (r *RPC) Reply(req *Message, body string) (*Message, error) {
+	if !req.Kind.IsRequest() {
+		return nil, fmt.Errorf("team/rpc: message %.8s is a %s, not a request", req.ID, req.Kind)
+	}
+	correlationID := req.CorrelationID
+	if correlationID == "" {
+		correlationID = req.ID
+	}
+	ans := &Message{
+		ID:            uuid.New().String(),
+		TeamName:      r.teamName,
+		Kind:          MessageKindAnswer,
+		From:          r.agentID,
+		To:            req.From,
+		Subject:       req.Subject,
+		Body:          body,
+		ReplyTo:       req.ID,
+		CorrelationID: correlationID,
+		SentAt:        time.Now(),
+	}
+	if err := r.transport.Send(ans); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+// FindRequest looks up a request in the team event log by full ID or
+// 8-character prefix. Used to answer a request that was already consumed
+// from the inbox.
+func (ti *TeamInbox) FindRequest(id string) (*Message, error) {
+	msgs, err := ti.EventLog(0, "")
+	if err != nil {
+		return nil, err
+	}
+	var found *Message
+	for _, m := range msgs {
+		if m.ID == id || (len(id) >= 8 && len(m.ID) >= len(id) && m.ID[:len(id)] == id) {
+			found = m
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("team/rpc: message %s not found in event log", id)
+	}
+	if !found.Kind.IsRequest() {
+		return nil, fmt.Errorf("team/rpc: message %.8s is a %s, not a request", found.ID, found.Kind)
+	}
+	return found, nil
+}
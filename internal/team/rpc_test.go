@@ -0,0 +1,57 @@
+package team
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRPC_AskLeader(t *testing.T) {
+	td := t.TempDir()
+	tr := NewFileTransport(td)
+	worker := NewRPC(tr, "my-team", "worker-1")
+	worker.PollInterval = 5 * time.Millisecond
+	leader := NewRPC(tr, "my-team", "leader")
+
+	// Unrelated message queued ahead of the answer must survive the wait.
+	_ = tr.Send(&Message{Kind: MessageKindDirect, From: "leader", To: "worker-1", Body: "fyi"})
+
+	go func() {
+		for i := 0; i < 200; i++ {
+			pending, _ := tr.Peek("leader", 0)
+			for _, m := range pending {
+				if m.Kind == MessageKindQuestion {
+					_, _ = tr.Take("leader", m.ID)
+					_, _ = leader.Reply(m, "use postgres")
+					return
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	ans, err := worker.AskLeader("which database?", 2*time.Second)
+	if err != nil {
+		t.Fatalf("AskLeader: %v", err)
+	}
+	if ans.Body != "use postgres" || ans.Kind != MessageKindAnswer || ans.From != "leader" {
+		t.Errorf("answer = %+v", ans)
+	}
+	if left, _ := tr.Peek("worker-1", 0); len(left) != 1 || left[0].Body != "fyi" {
+		t.Errorf("worker inbox = %+v, want only the unrelated message", left)
+	}
+
+	// The request is in the event log and can be answered from there.
+	req, err := NewTeamInbox(td).FindRequest(ans.ReplyTo[:8])
+	if err != nil || req.CorrelationID != ans.CorrelationID {
+		t.Errorf("FindRequest = %+v, %v", req, err)
+	}
+}
+
+func TestRPC_Timeout(t *testing.T) {
+	r := NewRPC(NewFileTransport(t.TempDir()), "my-team", "worker-1")
+	r.PollInterval = 5 * time.Millisecond
+	if _, err := r.Ask("leader", "anyone?", 20*time.Millisecond); !errors.Is(err, ErrRPCTimeout) {
+		t.Errorf("Ask err = %v, want ErrRPCTimeout", err)
+	}
+}
@@ -46,6 +46,7 @@ type SpawnBackend string
 const (
 	SpawnBackendTmux       SpawnBackend = "tmux"
 	SpawnBackendSubprocess SpawnBackend = "subprocess"
+	SpawnBackendContainer  SpawnBackend = "container"
 )
 
 // SpawnConfig holds all parameters needed to launch an agent.
@@ -78,21 +79,34 @@ type SpawnConfig struct {
 
 	// SkipPermissions passes --dangerously-skip-permissions to Claude CLI.
 	SkipPermissions bool
+
+	// Container configures the container backend. Ignored by other backends.
+	Container ContainerOptions
 }
 
 // SpawnResult is returned by a successful Spawn call.
 type SpawnResult struct {
-	AgentID     string
-	TmuxSession string // non-empty for tmux backend
-	PID         int    // non-zero for subprocess backend
+	AgentID          string
+	TmuxSession      string // non-empty for tmux backend
+	PID              int    // non-zero for subprocess backend
+	ContainerID      string // non-empty for container backend
+	ContainerRuntime string // runtime that owns ContainerID (e.g. "docker")
 }
 
 // Spawner launches agent processes with team identity baked in.
-type Spawner struct{}
+type Spawner struct {
+	containers ContainerRuntime // nil = docker/podman CLI chosen per spawn
+}
 
 // NewSpawner returns a Spawner.
 func NewSpawner() *Spawner { return &Spawner{} }
 
+// NewSpawnerWithRuntime returns a Spawner whose container backend uses rt
+// (e.g. a FakeContainerRuntime in tests).
+func NewSpawnerWithRuntime(rt ContainerRuntime) *Spawner {
+	return &Spawner{containers: rt}
+}
+
 // Spawn starts a new agent process according to cfg.
 func (s *Spawner) Spawn(cfg SpawnConfig) (*SpawnResult, error) {
 	if cfg.AgentID == "" {
@@ -103,6 +117,8 @@ func (s *Spawner) Spawn(cfg SpawnConfig) (*SpawnResult, error) {
 		return s.spawnTmux(cfg)
 	case SpawnBackendSubprocess:
 		return s.spawnSubprocess(cfg)
+	case SpawnBackendContainer:
+		return s.spawnContainer(cfg)
 	default:
 		return nil, fmt.Errorf("team/spawner: unknown backend %q", cfg.Backend)
 	}
@@ -110,6 +126,15 @@ func (s *Spawner) Spawn(cfg SpawnConfig) (*SpawnResult, error) {
 
 // Kill terminates an agent based on its SpawnResult.
 func (s *Spawner) Kill(res *SpawnResult) error {
+	if res.ContainerID != "" {
+		rt, err := s.containerRuntime(res.ContainerRuntime)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return rt.Stop(ctx, res.ContainerID)
+	}
 	if res.TmuxSession != "" {
 		return exec.Command("tmux", "kill-session", "-t", res.TmuxSession).Run()
 	}
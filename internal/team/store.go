@@ -242,6 +242,72 @@ func (s *Store) Release(taskID string) (*Task, error) {
 	return t, s.save(t)
 }
 
+// Reopen returns a task to pending, clearing its claim and outcome, so it can
+// be worked again (e.g. after rolling its workspace back). Tasks that were
+// unblocked by its completion and have not started are blocked on it again.
+func (s *Store) Reopen(taskID string) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, &ErrTaskNotFound{ID: taskID}
+	}
+	wasDone := t.Status == TaskStatusDone
+
+	t.Status = TaskStatusPending
+	if len(t.DependsOn) > 0 {
+		t.Status = TaskStatusBlocked
+	}
+	t.AgentID = ""
+	t.Result = ""
+	t.ErrorMsg = ""
+	t.ClaimedAt = nil
+	t.CompletedAt = nil
+	t.UpdatedAt = time.Now()
+	if err := s.save(t); err != nil {
+		return nil, err
+	}
+	if wasDone {
+		if err := s.reblock(t.ID); err != nil {
+			return t, fmt.Errorf("team/store: reblock after reopen: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// reblock undoes unblock for pending tasks that depended on reopenedID.
+// Caller must hold s.mu.
+func (s *Store) reblock(reopenedID string) error {
+	tasks, err := s.List(TaskStatusPending, "")
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		kept := t.ResolvedDeps[:0:0]
+		for _, dep := range t.ResolvedDeps {
+			if dep == reopenedID || strings.HasPrefix(reopenedID, dep) {
+				t.DependsOn = append(t.DependsOn, dep)
+			} else {
+				kept = append(kept, dep)
+			}
+		}
+		if len(kept) == len(t.ResolvedDeps) {
+			continue
+		}
+		t.ResolvedDeps = kept
+		t.Status = TaskStatusBlocked
+		t.UpdatedAt = time.Now()
+		if err := s.save(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Update allows updating title, description, dod, or priority of any task.
 func (s *Store) Update(taskID string, title, description, dod string, priority int) (*Task, error) {
 	s.mu.Lock()
@@ -270,6 +336,48 @@ func (s *Store) Update(taskID string, title, description, dod string, priority i
 	return t, s.save(t)
 }
 
+// SetPriority sets a task's priority. Unlike Update, 0 is applied as a value
+// and restores the default priority.
+func (s *Store) SetPriority(taskID string, priority int) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, &ErrTaskNotFound{ID: taskID}
+	}
+	t.Priority = priority
+	t.UpdatedAt = time.Now()
+	return t, s.save(t)
+}
+
+// Assign sets the pre-assigned owner of a task. If the task is in progress
+// under a different agent, the claim is dropped so the new owner can claim it.
+// Pass owner "" to unassign.
+func (s *Store) Assign(taskID, owner string) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, &ErrTaskNotFound{ID: taskID}
+	}
+	t.Owner = owner
+	if t.Status == TaskStatusInProgress && t.AgentID != owner {
+		t.Status = TaskStatusPending
+		t.AgentID = ""
+		t.ClaimedAt = nil
+	}
+	t.UpdatedAt = time.Now()
+	return t, s.save(t)
+}
+
 // Delete removes a task file from the store.
 func (s *Store) Delete(taskID string) error {
 	s.mu.Lock()
@@ -304,6 +412,7 @@ func (s *Store) unblock(completedID string) error {
 			// Support both full UUID and short-prefix (first 8 chars) matching.
 			if dep == completedID || strings.HasPrefix(completedID, dep) {
 				removed = true
+				t.ResolvedDeps = append(t.ResolvedDeps, dep)
 			} else {
 				newDeps = append(newDeps, dep)
 			}
@@ -0,0 +1,223 @@
+package team
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// TerminalKind identifies where an agent's terminal output comes from.
+type TerminalKind string
+
+const (
+	// TerminalTmux reads the agent's tmux pane with capture-pane.
+	TerminalTmux TerminalKind = "tmux"
+
+	// TerminalLog tails the subprocess backend's log file.
+	TerminalLog TerminalKind = "log"
+
+	// TerminalContainer reads the container's stdout via `<runtime> logs`.
+	TerminalContainer TerminalKind = "container"
+)
+
+// AgentTerminal is a read (and, for tmux, write) handle on an agent's console.
+type AgentTerminal struct {
+	AgentID string
+	Kind    TerminalKind
+
+	// Target is the tmux session, log file path or container ID.
+	Target string
+
+	// Runtime is the container CLI for TerminalContainer.
+	Runtime string
+}
+
+// ResolveTerminal picks the terminal source for a registered agent:
+// a tmux session if it has one, else its container, else its log file.
+func ResolveTerminal(dataDir, teamName string, a *AgentInfo) (*AgentTerminal, error) {
+	switch {
+	case a.TmuxSession != "":
+		return &AgentTerminal{AgentID: a.ID, Kind: TerminalTmux, Target: a.TmuxSession}, nil
+	case a.ContainerID != "":
+		return &AgentTerminal{AgentID: a.ID, Kind: TerminalContainer, Target: a.ContainerID, Runtime: a.ContainerRuntime}, nil
+	}
+	path := logPath(dataDir, teamName, a.ID)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("team/terminal: agent %s has no tmux session, container or log file", a.ID)
+	}
+	return &AgentTerminal{AgentID: a.ID, Kind: TerminalLog, Target: path}, nil
+}
+
+// CanSendKeys reports whether input can be typed into this terminal.
+// Only tmux panes accept input; subprocess and container agents run claude -p.
+func (t *AgentTerminal) CanSendKeys() bool {
+	return t.Kind == TerminalTmux
+}
+
+// Snapshot returns the last `lines` lines of terminal output.
+func (t *AgentTerminal) Snapshot(lines int) (string, error) {
+	if lines <= 0 {
+		lines = 200
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch t.Kind {
+	case TerminalTmux:
+		// -J joins wrapped lines; -S starts `lines` rows into the scrollback.
+		out, err := exec.CommandContext(ctx, "tmux", "capture-pane", "-p", "-J",
+			"-S", "-"+strconv.Itoa(lines), "-t", t.Target).Output()
+		if err != nil {
+			return "", fmt.Errorf("team/terminal: capture-pane %s: %w", t.Target, err)
+		}
+		return string(bytes.TrimRight(out, "\n")) + "\n", nil
+
+	case TerminalContainer:
+		rt, err := NewCLIContainerRuntime(t.Runtime)
+		if err != nil {
+			return "", err
+		}
+		out, err := exec.CommandContext(ctx, rt.Name(), "logs", "--tail", strconv.Itoa(lines), t.Target).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("team/terminal: %s logs %s: %w", rt.Name(), t.Target, err)
+		}
+		return string(out), nil
+
+	case TerminalLog:
+		data, _, err := TailFile(t.Target, &LogCursor{}, 256<<10)
+		if err != nil {
+			return "", err
+		}
+		return lastLines(data, lines), nil
+	}
+	return "", fmt.Errorf("team/terminal: unknown terminal kind %q", t.Kind)
+}
+
+// Follow reads a log terminal incrementally. When reset is true, text is the
+// last lines of the log and replaces the view (first call, or the log was
+// truncated or replaced); otherwise text is output appended since the last call.
+func (t *AgentTerminal) Follow(cur *LogCursor, lines int) (text string, reset bool, err error) {
+	if t.Kind != TerminalLog {
+		return "", false, fmt.Errorf("team/terminal: agent %s (%s) has no log to follow", t.AgentID, t.Kind)
+	}
+	data, reset, err := TailFile(t.Target, cur, 256<<10)
+	if err != nil {
+		return "", false, err
+	}
+	if reset {
+		return lastLines(data, lines), true, nil
+	}
+	return data, false, nil
+}
+
+// SendKeys types text into the agent's tmux pane, followed by Enter if enter is set.
+func (t *AgentTerminal) SendKeys(text string, enter bool) error {
+	if !t.CanSendKeys() {
+		return fmt.Errorf("team/terminal: agent %s (%s) does not accept input", t.AgentID, t.Kind)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if text != "" {
+		// -l sends the text literally so key names like "C-c" are not interpreted.
+		if out, err := exec.CommandContext(ctx, "tmux", "send-keys", "-t", t.Target, "-l", text).CombinedOutput(); err != nil {
+			return fmt.Errorf("team/terminal: send-keys %s: %w\n%s", t.Target, err, out)
+		}
+	}
+	if enter {
+		if out, err := exec.CommandContext(ctx, "tmux", "send-keys", "-t", t.Target, "Enter").CombinedOutput(); err != nil {
+			return fmt.Errorf("team/terminal: send-keys %s: %w\n%s", t.Target, err, out)
+		}
+	}
+	return nil
+}
+
+// logTailFingerprint is how many of the last bytes read a LogCursor keeps to
+// recognise a log that was truncated and rewritten past the old offset.
+const logTailFingerprint = 64
+
+// LogCursor records where TailFile stopped reading a log: the offset, the
+// identity of the file read and its last bytes. The zero value starts a new
+// read.
+type LogCursor struct {
+	Offset int64
+	info   os.FileInfo
+	tail   []byte
+}
+
+// TailFile reads path from cur and advances cur past the returned data. At
+// most the last max bytes are returned. reset is true when reading started
+// over from the beginning of the file: on the first read, and when the file
+// was replaced, truncated or rewritten since the previous read, including a
+// truncated log that has already grown past the old offset.
+func TailFile(path string, cur *LogCursor, max int64) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, fmt.Errorf("team/terminal: open %s: %w", path, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", false, err
+	}
+	size := fi.Size()
+	offset := cur.Offset
+	reset := cur.info == nil || !os.SameFile(cur.info, fi) || size < offset || !cur.tailMatches(f)
+	if reset {
+		offset = 0
+		cur.tail = nil
+	}
+	if size-offset > max {
+		offset = size - max
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", false, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, size-offset))
+	if err != nil {
+		return "", false, err
+	}
+	cur.Offset = offset + int64(len(data))
+	cur.info = fi
+	tail := append(cur.tail, data...)
+	if len(tail) > logTailFingerprint {
+		tail = tail[len(tail)-logTailFingerprint:]
+	}
+	cur.tail = append([]byte(nil), tail...)
+	return string(data), reset, nil
+}
+
+// tailMatches reports whether the bytes just before cur.Offset are still the
+// ones read last time.
+func (c *LogCursor) tailMatches(f *os.File) bool {
+	if len(c.tail) == 0 {
+		return true
+	}
+	buf := make([]byte, len(c.tail))
+	if _, err := f.ReadAt(buf, c.Offset-int64(len(c.tail))); err != nil {
+		return false
+	}
+	return bytes.Equal(buf, c.tail)
+}
+
+// lastLines returns the final n lines of s.
+func lastLines(s string, n int) string {
+	end := len(s)
+	if end > 0 && s[end-1] == '\n' {
+		end--
+	}
+	count := 0
+	for i := end - 1; i >= 0; i-- {
+		if s[i] == '\n' {
+			count++
+			if count == n {
+				return s[i+1:]
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveTerminal_LogTail(t *testing.T) {
+	dataDir := t.TempDir()
+	path := logPath(dataDir, "my-team", "worker-1")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	term, err := ResolveTerminal(dataDir, "my-team", &AgentInfo{ID: "worker-1", PID: 42})
+	if err != nil {
+		t.Fatalf("ResolveTerminal: %v", err)
+	}
+	if term.Kind != TerminalLog || term.CanSendKeys() {
+		t.Fatalf("terminal = %+v, want read-only log", term)
+	}
+	if got, _ := term.Snapshot(2); got != "two\nthree\n" {
+		t.Errorf("Snapshot(2) = %q", got)
+	}
+
+	var cur LogCursor
+	if _, reset, _ := TailFile(path, &cur, 1<<20); !reset {
+		t.Error("first TailFile read not reported as reset")
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString("four\n")
+	f.Close()
+	if chunk, reset, _ := TailFile(path, &cur, 1<<20); chunk != "four\n" || reset {
+		t.Errorf("TailFile = %q, reset %v; want appended line", chunk, reset)
+	}
+
+	tmux, _ := ResolveTerminal(dataDir, "my-team", &AgentInfo{ID: "leader", TmuxSession: "jikime-my-team-leader"})
+	if tmux.Kind != TerminalTmux || !tmux.CanSendKeys() {
+		t.Errorf("tmux terminal = %+v, want writable tmux pane", tmux)
+	}
+	if _, err := ResolveTerminal(dataDir, "my-team", &AgentInfo{ID: "ghost"}); err == nil {
+		t.Error("ResolveTerminal(ghost) succeeded, want error")
+	}
+}
+
+func TestTailFile_TruncateThenGrow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.log")
+	if err := os.WriteFile(path, []byte("old line 1\nold line 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	term := &AgentTerminal{AgentID: "worker-1", Kind: TerminalLog, Target: path}
+	var cur LogCursor
+	if text, reset, err := term.Follow(&cur, 10); err != nil || !reset || text != "old line 1\nold line 2\n" {
+		t.Fatalf("first Follow = %q, %v, %v", text, reset, err)
+	}
+
+	// Truncated in place, then grown past the old offset before the next poll.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("new run line 1\nnew run line 2\nnew run line 3\n")
+	f.Close()
+
+	text, reset, err := term.Follow(&cur, 10)
+	if err != nil || !reset || text != "new run line 1\nnew run line 2\nnew run line 3\n" {
+		t.Errorf("Follow after truncate+grow = %q, reset %v, %v; want reset with new content", text, reset, err)
+	}
+
+	// Replaced by a new file (rotation) of the same size.
+	if err := os.WriteFile(path+".new", []byte("new run line 1\nnew run line 2\nnew run line 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(path+".new", path); err != nil {
+		t.Fatal(err)
+	}
+	if _, reset, _ := term.Follow(&cur, 10); !reset {
+		t.Error("Follow after rotation not reported as reset")
+	}
+	f, _ = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString("more\n")
+	f.Close()
+	if text, reset, _ := term.Follow(&cur, 10); reset || text != "more\n" {
+		t.Errorf("Follow after append = %q, reset %v; want appended line", text, reset)
+	}
+}
@@ -1,5 +1,7 @@
 package team
 
+import "time"
+
 // Transport is the pluggable interface for delivering messages between agents.
 // The default implementation uses the file-based Inbox.
 // A ZeroMQ or network implementation can satisfy this interface.
@@ -11,6 +13,17 @@ type Transport interface {
 	// Pass limit ≤ 0 to receive all pending messages.
 	Receive(agentID string, limit int) ([]*Message, error)
 
+	// Lease hides up to limit messages from agentID's queue for visibility
+	// and returns them. Leased messages are redelivered unless acknowledged.
+	Lease(agentID string, limit int, visibility time.Duration) ([]*Message, error)
+
+	// Ack permanently removes leased messages from agentID's queue.
+	Ack(agentID string, ids ...string) error
+
+	// Take consumes the queued message with the given ID.
+	// Returns nil, nil when no such message is queued.
+	Take(agentID, messageID string) (*Message, error)
+
 	// Peek returns up to limit messages without consuming them.
 	Peek(agentID string, limit int) ([]*Message, error)
 
@@ -43,6 +56,30 @@ func (f *FileTransport) Receive(agentID string, limit int) ([]*Message, error) {
 	return ib.Receive(limit)
 }
 
+func (f *FileTransport) Lease(agentID string, limit int, visibility time.Duration) ([]*Message, error) {
+	ib, err := f.ti.For(agentID)
+	if err != nil {
+		return nil, err
+	}
+	return ib.Lease(limit, visibility)
+}
+
+func (f *FileTransport) Ack(agentID string, ids ...string) error {
+	ib, err := f.ti.For(agentID)
+	if err != nil {
+		return err
+	}
+	return ib.Ack(ids...)
+}
+
+func (f *FileTransport) Take(agentID, messageID string) (*Message, error) {
+	ib, err := f.ti.For(agentID)
+	if err != nil {
+		return nil, err
+	}
+	return ib.Take(messageID)
+}
+
 func (f *FileTransport) Peek(agentID string, limit int) ([]*Message, error) {
 	ib, err := f.ti.For(agentID)
 	if err != nil {
@@ -16,6 +16,9 @@ type TeamConfig struct {
 	// Template is the template name used to create this team (e.g. "leader-worker").
 	Template string `json:"template,omitempty"`
 
+	// Goal is the high-level objective injected into agent prompts at launch.
+	Goal string `json:"goal,omitempty"`
+
 	// BaseDir is the absolute path to the team workspace directory.
 	// Default: ~/.jikime/teams/<name>/
 	BaseDir string `json:"base_dir"`
@@ -28,10 +31,22 @@ type TeamConfig struct {
 	// 0 means no limit.
 	Budget int `json:"budget,omitempty"`
 
+	// Budgets holds dollar-denominated limits at team, agent, and task scope.
+	// nil means only the token Budget above is enforced.
+	Budgets *BudgetPolicy `json:"budgets,omitempty"`
+
 	// TimeoutSeconds is the overall team execution timeout in seconds.
 	// 0 means no timeout.
 	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
 
+	// Container holds default sandbox settings for the container spawn backend.
+	Container *ContainerOptions `json:"container,omitempty"`
+
+	// MergeVerify is the shell command the merge queue runs on the staging
+	// branch to verify merged agent work (e.g. "go build ./... && go test ./...").
+	// Empty means merges are not verified.
+	MergeVerify string `json:"merge_verify,omitempty"`
+
 	// CreatedAt is when this team was created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -96,6 +111,10 @@ type Task struct {
 	// DependsOn lists the IDs of tasks that must be done before this task can start.
 	DependsOn []string `json:"depends_on,omitempty"`
 
+	// ResolvedDeps keeps the dependencies removed from DependsOn as they
+	// completed, so the original dependency order stays known.
+	ResolvedDeps []string `json:"resolved_deps,omitempty"`
+
 	// Priority is a numeric priority value (higher = more important).
 	// 0 is the default priority.
 	Priority int `json:"priority,omitempty"`
@@ -136,8 +155,30 @@ const (
 
 	// MessageKindSystem is sent by the orchestrator or CLI for control purposes.
 	MessageKindSystem MessageKind = "system"
+
+	// MessageKindQuestion asks the recipient for information; expects an answer.
+	MessageKindQuestion MessageKind = "question"
+
+	// MessageKindAnswer responds to a request. It carries the request's CorrelationID.
+	MessageKindAnswer MessageKind = "answer"
+
+	// MessageKindReviewRequest asks the recipient to review work; expects an answer.
+	MessageKindReviewRequest MessageKind = "review-request"
+
+	// MessageKindHandoff transfers a piece of work to the recipient; expects an answer
+	// acknowledging (or declining) the handoff.
+	MessageKindHandoff MessageKind = "handoff"
 )
 
+// IsRequest reports whether messages of this kind expect an answer.
+func (k MessageKind) IsRequest() bool {
+	switch k {
+	case MessageKindQuestion, MessageKindReviewRequest, MessageKindHandoff:
+		return true
+	}
+	return false
+}
+
 // Message represents a unit of communication between agents or the orchestrator.
 // Persisted to ~/.jikime/teams/<team-name>/inbox/<recipient>/<id>.json.
 type Message struct {
@@ -147,7 +188,7 @@ type Message struct {
 	// TeamName is the team this message belongs to.
 	TeamName string `json:"team_name"`
 
-	// Kind classifies the message (direct, broadcast, system).
+	// Kind classifies the message (direct, broadcast, system, question, answer, ...).
 	Kind MessageKind `json:"kind"`
 
 	// From is the sender agent ID, or "orchestrator" for system messages.
@@ -165,6 +206,10 @@ type Message struct {
 	// ReplyTo is the ID of a previous message this is in response to.
 	ReplyTo string `json:"reply_to,omitempty"`
 
+	// CorrelationID links a request (question, review-request, handoff) to its answer.
+	// Set on the request and copied verbatim onto the answer.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
 	// Read indicates whether the recipient has read this message.
 	Read bool `json:"read"`
 
@@ -173,6 +218,17 @@ type Message struct {
 
 	// ReadAt is when the recipient marked this message as read.
 	ReadAt *time.Time `json:"read_at,omitempty"`
+
+	// DeliveryCount is how many times this message has been leased to the recipient.
+	// Values above 1 mean the message was redelivered after an unacknowledged lease.
+	DeliveryCount int `json:"delivery_count,omitempty"`
+
+	// LeaseUntil is when the current lease expires. Unacknowledged messages
+	// become visible again after this time.
+	LeaseUntil *time.Time `json:"lease_until,omitempty"`
+
+	// DeadLetterReason explains why the message was moved to the dead-letter queue.
+	DeadLetterReason string `json:"dead_letter_reason,omitempty"`
 }
 
 // --- Agent Registry ---
@@ -216,6 +272,18 @@ type AgentInfo struct {
 	// TmuxSession is the tmux session name if the agent runs in tmux.
 	TmuxSession string `json:"tmux_session,omitempty"`
 
+	// ContainerID is the container ID if the agent runs in a container.
+	ContainerID string `json:"container_id,omitempty"`
+
+	// ContainerRuntime is the runtime that owns ContainerID (e.g. "docker", "podman").
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+
+	// Backend is the spawn backend used to launch this agent.
+	Backend SpawnBackend `json:"backend,omitempty"`
+
+	// WorktreePath is the working directory the agent was spawned in.
+	WorktreePath string `json:"worktree_path,omitempty"`
+
 	// CurrentTaskID is the ID of the task currently being worked on, if any.
 	CurrentTaskID string `json:"current_task_id,omitempty"`
 
@@ -255,12 +323,21 @@ type CostEvent struct {
 	// OutputTokens is the number of output (completion) tokens used.
 	OutputTokens int `json:"output_tokens"`
 
+	// CacheCreationTokens is the number of input tokens written to the prompt cache.
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+
+	// CacheReadTokens is the number of input tokens served from the prompt cache.
+	CacheReadTokens int `json:"cache_read_tokens,omitempty"`
+
 	// TotalTokens is InputTokens + OutputTokens, stored for convenience.
 	TotalTokens int `json:"total_tokens"`
 
 	// Model is the model name used for this tool call (e.g. "claude-sonnet-4-6").
 	Model string `json:"model,omitempty"`
 
+	// CostUSD is the dollar cost of this event, priced at record time.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+
 	// OccurredAt is when this cost event was recorded.
 	OccurredAt time.Time `json:"occurred_at"`
 }
@@ -282,6 +359,15 @@ type CostSummary struct {
 	// TotalTokens is TotalInputTokens + TotalOutputTokens.
 	TotalTokens int `json:"total_tokens"`
 
+	// TotalCacheCreationTokens is the sum of all cache-write tokens.
+	TotalCacheCreationTokens int `json:"total_cache_creation_tokens,omitempty"`
+
+	// TotalCacheReadTokens is the sum of all cache-read tokens.
+	TotalCacheReadTokens int `json:"total_cache_read_tokens,omitempty"`
+
+	// TotalCostUSD is the sum of all event costs in US dollars.
+	TotalCostUSD float64 `json:"total_cost_usd"`
+
 	// EventCount is the number of cost events included in this summary.
 	EventCount int `json:"event_count"`
 
@@ -292,6 +378,81 @@ type CostSummary struct {
 	BudgetUsedPercent float64 `json:"budget_used_percent,omitempty"`
 }
 
+// --- Budget Policy ---
+
+// BudgetLevel classifies how severe a budget breach is.
+type BudgetLevel string
+
+const (
+	// BudgetLevelSoft means the soft limit was crossed; the leader is warned.
+	BudgetLevelSoft BudgetLevel = "soft"
+
+	// BudgetLevelHard means the hard limit was crossed; the agent is stopped.
+	BudgetLevelHard BudgetLevel = "hard"
+)
+
+// BudgetScope identifies what a budget limit applies to.
+type BudgetScope string
+
+const (
+	BudgetScopeTeam  BudgetScope = "team"
+	BudgetScopeAgent BudgetScope = "agent"
+	BudgetScopeTask  BudgetScope = "task"
+)
+
+// BudgetLimit is a pair of dollar thresholds. 0 disables a threshold.
+type BudgetLimit struct {
+	// SoftUSD warns the leader via inbox once spend reaches this amount.
+	SoftUSD float64 `json:"soft_usd,omitempty" yaml:"soft_usd,omitempty"`
+
+	// HardUSD stops the agent once spend reaches this amount.
+	HardUSD float64 `json:"hard_usd,omitempty" yaml:"hard_usd,omitempty"`
+}
+
+// IsZero reports whether neither threshold is set.
+func (l BudgetLimit) IsZero() bool {
+	return l.SoftUSD <= 0 && l.HardUSD <= 0
+}
+
+// BudgetPolicy configures dollar budgets at team, agent, and task granularity.
+type BudgetPolicy struct {
+	// Team applies to the sum of all spend in the team.
+	Team BudgetLimit `json:"team,omitempty" yaml:"team,omitempty"`
+
+	// DefaultAgent applies to every agent without an explicit entry in Agents.
+	DefaultAgent BudgetLimit `json:"default_agent,omitempty" yaml:"default_agent,omitempty"`
+
+	// Agents holds per-agent limits keyed by agent ID.
+	Agents map[string]BudgetLimit `json:"agents,omitempty" yaml:"agents,omitempty"`
+
+	// DefaultTask applies to every task without an explicit entry in Tasks.
+	DefaultTask BudgetLimit `json:"default_task,omitempty" yaml:"default_task,omitempty"`
+
+	// Tasks holds per-task limits keyed by task ID.
+	Tasks map[string]BudgetLimit `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+
+	// Prices overrides or extends DefaultPriceTable, keyed by model or model family.
+	Prices PriceTable `json:"prices,omitempty" yaml:"prices,omitempty"`
+}
+
+// BudgetAlert describes a single threshold crossed by current spend.
+type BudgetAlert struct {
+	// Scope is what the limit applies to (team, agent, task).
+	Scope BudgetScope `json:"scope"`
+
+	// Key is the agent or task ID. Empty for team scope.
+	Key string `json:"key,omitempty"`
+
+	// Level is soft or hard.
+	Level BudgetLevel `json:"level"`
+
+	// SpentUSD is the current spend within the scope.
+	SpentUSD float64 `json:"spent_usd"`
+
+	// LimitUSD is the threshold that was crossed.
+	LimitUSD float64 `json:"limit_usd"`
+}
+
 // --- Session ---
 
 // Session captures a snapshot of team state for persistence and restoration.
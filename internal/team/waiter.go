@@ -8,7 +8,7 @@ import (
 
 // WaitResult summarises the outcome of a Wait call.
 type WaitResult struct {
-	Status    string // "completed" | "timeout" | "cancelled" | "error"
+	Status    string // "completed" | "timeout" | "cancelled" | "request" | "error"
 	Elapsed   time.Duration
 	Total     int
 	Done      int
@@ -28,7 +28,14 @@ type WaiterCallbacks struct {
 	OnAgentDead func(agentID string, taskIDs []string)
 
 	// OnMessage is called for each message drained from the leader inbox.
+	// When OnRequest is also set, requests are left for it and not drained.
 	OnMessage func(m *Message)
+
+	// OnRequest is called for each pending request (question, review-request,
+	// handoff) in the leader inbox. Requests are not consumed. Returning true
+	// stops the wait with Status "request" so the leader can answer; an agent
+	// blocked on `inbox ask` would otherwise wait for the whole run.
+	OnRequest func(m *Message) bool
 }
 
 // Waiter polls the task store and registry until all tasks are done (or timeout).
@@ -66,11 +73,37 @@ func (w *Waiter) Wait(ctx context.Context) (WaitResult, error) {
 	defer ticker.Stop()
 
 	for {
-		// Drain leader inbox messages first.
+		// Drain leader inbox messages first; ack only after the callback ran.
+		// Requests stay queued for OnRequest, which does not consume them.
 		if w.inbox != nil && w.cb.OnMessage != nil {
-			msgs, _ := w.inbox.Receive(50)
+			var match func(*Message) bool
+			if w.cb.OnRequest != nil {
+				match = func(m *Message) bool { return !m.Kind.IsRequest() }
+			}
+			msgs, _ := w.inbox.LeaseMatching(50, DefaultVisibilityTimeout, match)
 			for _, m := range msgs {
 				w.cb.OnMessage(m)
+				_ = w.inbox.Ack(m.ID)
+			}
+		}
+
+		// Surface pending requests; they are answered outside the wait loop.
+		if w.inbox != nil && w.cb.OnRequest != nil {
+			pending, _ := w.inbox.Peek(0)
+			stop := false
+			for _, m := range pending {
+				if m.Kind.IsRequest() && w.cb.OnRequest(m) {
+					stop = true
+				}
+			}
+			if stop {
+				cur, err := w.tally()
+				if err != nil {
+					return WaitResult{Status: "error", Elapsed: time.Since(start)}, err
+				}
+				cur.Status = "request"
+				cur.Elapsed = time.Since(start)
+				return cur, nil
 			}
 		}
 
@@ -0,0 +1,56 @@
+package team
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaiter_MessagesAndRequests(t *testing.T) {
+	td := t.TempDir()
+	store, err := NewStore(filepath.Join(td, "tasks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := NewRegistry(filepath.Join(td, "registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox, err := NewInbox(filepath.Join(td, "inbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*Message{
+		{Kind: MessageKindDirect, From: "worker-1", To: "leader", Body: "fyi"},
+		{Kind: MessageKindQuestion, From: "worker-1", To: "leader", Body: "which database?"},
+	} {
+		if err := inbox.Send(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var messages, requests []*Message
+	w := NewWaiter(store, registry, inbox, "my-team", 5*time.Millisecond, WaiterCallbacks{
+		OnMessage: func(m *Message) { messages = append(messages, m) },
+		OnRequest: func(m *Message) bool {
+			requests = append(requests, m)
+			return true
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	res, err := w.Wait(ctx)
+	if err != nil || res.Status != "request" {
+		t.Fatalf("Wait = %+v, %v; want status request", res, err)
+	}
+	if len(messages) != 1 || messages[0].Body != "fyi" {
+		t.Errorf("OnMessage got %+v, want only the direct message", messages)
+	}
+	if len(requests) != 1 || requests[0].Kind != MessageKindQuestion {
+		t.Errorf("OnRequest got %+v, want the question", requests)
+	}
+	if pending, _ := inbox.Peek(0); len(pending) != 1 || pending[0].Kind != MessageKindQuestion {
+		t.Errorf("pending = %+v, want the question left for the leader", pending)
+	}
+}
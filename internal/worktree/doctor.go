@@ -0,0 +1,254 @@
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Issue kinds reported by Doctor.
+const (
+	IssueMissing        = "missing"         // registered, but neither on disk nor known to git
+	IssuePrunable       = "prunable"        // git still tracks it, but the directory is gone
+	IssueMoved          = "moved"           // the branch is checked out at a different path
+	IssueBranchMismatch = "branch-mismatch" // git has a different branch checked out
+	IssueBranchGone     = "branch-gone"     // the upstream branch was deleted
+	IssueUnregistered   = "unregistered"    // a git worktree under the root the registry doesn't know
+	IssueCorrupt        = "corrupt"         // the registry file cannot be parsed
+)
+
+// GitWorktree is one entry of `git worktree list --porcelain`.
+type GitWorktree struct {
+	Path           string
+	Head           string
+	Branch         string // short name; empty when detached
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	Prunable       bool
+	PrunableReason string
+}
+
+// DoctorIssue is a difference between the registry and git.
+type DoctorIssue struct {
+	Kind   string `json:"kind"`
+	SpecID string `json:"spec_id,omitempty"`
+	Path   string `json:"path"`
+	Branch string `json:"branch,omitempty"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix"`
+	Fixed  bool   `json:"fixed"`
+}
+
+// ListGitWorktrees parses `git worktree list --porcelain` for a repository.
+func (m *Manager) ListGitWorktrees() ([]GitWorktree, error) {
+	out, err := m.gitCmdOutput(m.RepoPath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, &GitOperationError{Operation: "worktree list", Message: err.Error()}
+	}
+	return parseWorktreePorcelain(out), nil
+}
+
+func parseWorktreePorcelain(out string) []GitWorktree {
+	var (
+		list []GitWorktree
+		cur  *GitWorktree
+	)
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		key, val, _ := strings.Cut(sc.Text(), " ")
+		switch key {
+		case "worktree":
+			list = append(list, GitWorktree{Path: val})
+			cur = &list[len(list)-1]
+		case "HEAD":
+			cur.Head = val
+		case "branch":
+			cur.Branch = strings.TrimPrefix(val, "refs/heads/")
+		case "bare":
+			cur.Bare = true
+		case "detached":
+			cur.Detached = true
+		case "locked":
+			cur.Locked = true
+		case "prunable":
+			cur.Prunable, cur.PrunableReason = true, val
+		}
+	}
+	return list
+}
+
+// Doctor compares the registry with `git worktree list --porcelain` and
+// the branches' upstream state. With fix, registry entries are repaired
+// (pruned, re-pointed, or registered) in one locked update; branches whose
+// upstream is gone are only reported, since removing them loses work.
+func (m *Manager) Doctor(fix bool) ([]DoctorIssue, error) {
+	// Refresh remote-tracking refs so deleted upstream branches show as gone.
+	if _, err := m.gitCmd("fetch", "--prune", "origin"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to fetch from origin: %v\n", err)
+	}
+
+	gitList, err := m.ListGitWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	byPath := map[string]*GitWorktree{}
+	byBranch := map[string]*GitWorktree{}
+	for i := range gitList {
+		g := &gitList[i]
+		byPath[cleanPath(g.Path)] = g
+		if g.Branch != "" {
+			byBranch[g.Branch] = g
+		}
+	}
+	gone := m.goneBranches()
+
+	var (
+		issues   []DoctorIssue
+		prune    bool
+		corrupt  bool
+		repaired = map[string]*WorktreeInfo{} // spec ID -> new info, nil to unregister
+	)
+	if err := m.Registry.Check(); err != nil {
+		issues = append(issues, DoctorIssue{Kind: IssueCorrupt, Path: m.Registry.Path(),
+			Detail: err.Error(), Fix: "back up and rebuild from git worktrees"})
+		corrupt = true
+	}
+	registered := map[string]bool{}
+	for _, info := range m.List() {
+		path := cleanPath(info.Path)
+		registered[path] = true
+		g := byPath[path]
+		_, statErr := os.Stat(info.Path)
+
+		switch {
+		case g != nil && g.Prunable:
+			issues = append(issues, DoctorIssue{Kind: IssuePrunable, SpecID: info.SpecID, Path: info.Path, Branch: info.Branch,
+				Detail: "git reports the worktree as prunable: " + g.PrunableReason,
+				Fix:    "git worktree prune and unregister"})
+			prune = true
+			repaired[info.SpecID] = nil
+			continue
+		case g == nil && os.IsNotExist(statErr):
+			if moved := byBranch[info.Branch]; moved != nil && info.Branch != "" && !moved.Prunable {
+				issues = append(issues, DoctorIssue{Kind: IssueMoved, SpecID: info.SpecID, Path: info.Path, Branch: info.Branch,
+					Detail: "branch is checked out at " + moved.Path,
+					Fix:    "update registry path"})
+				updated := *info
+				updated.Path, updated.Status = moved.Path, "active"
+				repaired[info.SpecID] = &updated
+				registered[cleanPath(moved.Path)] = true
+				g = moved
+			} else {
+				issues = append(issues, DoctorIssue{Kind: IssueMissing, SpecID: info.SpecID, Path: info.Path, Branch: info.Branch,
+					Detail: "directory is gone and git has no worktree for it",
+					Fix:    "unregister"})
+				repaired[info.SpecID] = nil
+				continue
+			}
+		}
+
+		if g != nil && g.Branch != "" && g.Branch != info.Branch {
+			issues = append(issues, DoctorIssue{Kind: IssueBranchMismatch, SpecID: info.SpecID, Path: g.Path, Branch: info.Branch,
+				Detail: fmt.Sprintf("registry says %s, git has %s checked out", info.Branch, g.Branch),
+				Fix:    "update registry branch"})
+			updated := *info
+			if r := repaired[info.SpecID]; r != nil {
+				updated = *r
+			}
+			updated.Branch = g.Branch
+			repaired[info.SpecID] = &updated
+		}
+
+		branch := info.Branch
+		if g != nil && g.Branch != "" {
+			branch = g.Branch
+		}
+		if upstream, ok := gone[branch]; ok {
+			issues = append(issues, DoctorIssue{Kind: IssueBranchGone, SpecID: info.SpecID, Path: info.Path, Branch: branch,
+				Detail: "upstream " + upstream + " was deleted (merged or abandoned?)",
+				Fix:    "manual: jikime worktree remove " + info.SpecID})
+		}
+	}
+
+	// Worktrees under our root that git knows but the registry doesn't.
+	projectRoot := cleanPath(filepath.Join(m.WorktreeRoot, m.ProjectName))
+	for _, g := range gitList {
+		path := cleanPath(g.Path)
+		if g.Bare || registered[path] || filepath.Dir(path) != projectRoot {
+			continue
+		}
+		specID := filepath.Base(path)
+		if g.Prunable {
+			issues = append(issues, DoctorIssue{Kind: IssuePrunable, SpecID: specID, Path: g.Path, Branch: g.Branch,
+				Detail: "unregistered and prunable: " + g.PrunableReason,
+				Fix:    "git worktree prune"})
+			prune = true
+			continue
+		}
+		issues = append(issues, DoctorIssue{Kind: IssueUnregistered, SpecID: specID, Path: g.Path, Branch: g.Branch,
+			Detail: "git worktree is not in the registry",
+			Fix:    "register"})
+		now := time.Now()
+		repaired[specID] = &WorktreeInfo{SpecID: specID, Path: g.Path, Branch: g.Branch,
+			CreatedAt: now, LastAccessed: now, Status: "recovered"}
+	}
+
+	if !fix || len(repaired) == 0 && !prune && !corrupt {
+		return issues, nil
+	}
+	if prune {
+		if _, err := m.gitCmd("worktree", "prune"); err != nil {
+			return issues, &GitOperationError{Operation: "worktree prune", Message: err.Error()}
+		}
+	}
+	if err := m.Registry.Apply(m.ProjectName, repaired); err != nil {
+		return issues, err
+	}
+	for i := range issues {
+		if issues[i].Kind != IssueBranchGone {
+			issues[i].Fixed = true
+		}
+	}
+	return issues, nil
+}
+
+// goneBranches maps local branches whose configured upstream no longer
+// exists to that upstream's name.
+func (m *Manager) goneBranches() map[string]string {
+	out, err := m.gitCmdOutput(m.RepoPath, "for-each-ref", "--format=%(refname:short)\t%(upstream:short)\t%(upstream:track)", "refs/heads")
+	if err != nil {
+		return nil
+	}
+	gone := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) == 3 && parts[2] == "[gone]" {
+			gone[parts[0]] = parts[1]
+		}
+	}
+	return gone
+}
+
+// cleanPath normalizes a path for comparison, resolving symlinks (macOS
+// /var vs /private/var) when possible.
+func cleanPath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
+
+// SortIssues orders issues by spec ID, then kind.
+func SortIssues(issues []DoctorIssue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].SpecID != issues[j].SpecID {
+			return issues[i].SpecID < issues[j].SpecID
+		}
+		return issues[i].Kind < issues[j].Kind
+	})
+}
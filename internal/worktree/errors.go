@@ -1,6 +1,9 @@
 package worktree
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // WorktreeExistsError is returned when a worktree already exists.
 type WorktreeExistsError struct {
@@ -31,9 +34,11 @@ func (e *UncommittedChangesError) Error() string {
 }
 
 // MergeConflictError is returned when a merge conflict occurs.
+// Unresolved is set when automatic resolution was attempted.
 type MergeConflictError struct {
 	SpecID          string
 	ConflictedFiles []string
+	Unresolved      []FileResolution
 }
 
 func (e *MergeConflictError) Error() string {
@@ -49,3 +54,15 @@ type GitOperationError struct {
 func (e *GitOperationError) Error() string {
 	return fmt.Sprintf("git %s failed: %s", e.Operation, e.Message)
 }
+
+// StackedWorktreesError is returned when removing a worktree that other
+// worktrees are stacked on.
+type StackedWorktreesError struct {
+	SpecID   string
+	Children []string
+}
+
+func (e *StackedWorktreesError) Error() string {
+	return fmt.Sprintf("worktrees are stacked on %s: %s (finish it with done, or remove with --force to detach them)",
+		e.SpecID, strings.Join(e.Children, ", "))
+}
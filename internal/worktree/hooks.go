@@ -0,0 +1,519 @@
+package worktree
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lifecycle hooks declared in worktree.yaml.
+const (
+	HookPostCreate = "post_create"
+	HookPreRemove  = "pre_remove"
+	HookPostDone   = "post_done"
+)
+
+// Step result statuses.
+const (
+	StepOK     = "ok"
+	StepFailed = "failed"
+)
+
+// Bootstrap health values reported by WorktreeInfo.BootstrapHealth.
+const (
+	HealthNone     = "none"
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthFailed   = "failed"
+)
+
+// HooksConfigFiles are the locations searched for the hooks file,
+// relative to the repository root.
+var HooksConfigFiles = []string{"worktree.yaml", filepath.Join(".jikime", "worktree.yaml")}
+
+const (
+	defaultStepTimeout = 10 * time.Minute
+	defaultPortBase    = 4000
+	defaultEnvFile     = ".env.local"
+)
+
+// HooksConfig is the parsed worktree.yaml.
+type HooksConfig struct {
+	PostCreate []HookStep `yaml:"post_create"`
+	PreRemove  []HookStep `yaml:"pre_remove"`
+	PostDone   []HookStep `yaml:"post_done"`
+}
+
+// HookStep is one lifecycle step. Exactly one of Copy, Symlink, Run or
+// Ports is set.
+type HookStep struct {
+	Name     string    `yaml:"name,omitempty"`
+	Copy     []string  `yaml:"copy,omitempty"`    // paths/globs copied from the main repo
+	Symlink  []string  `yaml:"symlink,omitempty"` // paths/globs linked to the main repo
+	Run      string    `yaml:"run,omitempty"`     // shell command
+	Dir      string    `yaml:"dir,omitempty"`     // working directory for Run, relative to the worktree
+	Timeout  string    `yaml:"timeout,omitempty"` // Go duration, default 10m
+	Optional bool      `yaml:"optional,omitempty"`
+	Ports    *PortSpec `yaml:"ports,omitempty"`
+}
+
+// PortSpec allocates a block of ports unique among registered worktrees.
+type PortSpec struct {
+	Count int      `yaml:"count"`
+	Base  int      `yaml:"base,omitempty"`  // lowest port considered, default 4000
+	Names []string `yaml:"names,omitempty"` // env var per port, default PORT, PORT_1, ...
+	File  string   `yaml:"file,omitempty"`  // env file in the worktree, default .env.local
+}
+
+// PortRange is a block of ports allocated to a worktree.
+type PortRange struct {
+	Start int `json:"start"`
+	Count int `json:"count"`
+}
+
+// End returns the last port in the range.
+func (p *PortRange) End() int { return p.Start + p.Count - 1 }
+
+func (p *PortRange) overlaps(o *PortRange) bool {
+	return p.Start <= o.End() && o.Start <= p.End()
+}
+
+// StepResult records the outcome of one hook step.
+type StepResult struct {
+	Hook       string    `json:"hook"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Optional   bool      `json:"optional,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	At         time.Time `json:"at"`
+}
+
+// HookError is returned when a required hook step fails.
+type HookError struct {
+	Hook string
+	Step string
+	Err  string
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook step %q failed: %s", e.Hook, e.Step, e.Err)
+}
+
+// LoadHooksConfig reads worktree.yaml from the repository. It returns nil
+// when the project declares no hooks.
+func LoadHooksConfig(repoPath string) (*HooksConfig, error) {
+	for _, name := range HooksConfigFiles {
+		content, err := os.ReadFile(filepath.Join(repoPath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var cfg HooksConfig
+		if err := yaml.Unmarshal(content, &cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := cfg.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return &cfg, nil
+	}
+	return nil, nil
+}
+
+func (c *HooksConfig) validate() error {
+	for hook, steps := range map[string][]HookStep{HookPostCreate: c.PostCreate, HookPreRemove: c.PreRemove, HookPostDone: c.PostDone} {
+		for i, s := range steps {
+			kinds := 0
+			for _, set := range []bool{len(s.Copy) > 0, len(s.Symlink) > 0, s.Run != "", s.Ports != nil} {
+				if set {
+					kinds++
+				}
+			}
+			if kinds != 1 {
+				return fmt.Errorf("%s[%d]: exactly one of copy, symlink, run, ports is required", hook, i)
+			}
+			if s.Timeout != "" {
+				if _, err := time.ParseDuration(s.Timeout); err != nil {
+					return fmt.Errorf("%s[%d]: invalid timeout: %w", hook, i, err)
+				}
+			}
+			if s.Ports != nil && s.Ports.Count <= 0 {
+				return fmt.Errorf("%s[%d]: ports.count must be positive", hook, i)
+			}
+			if s.Ports != nil && hook != HookPostCreate {
+				return fmt.Errorf("%s[%d]: ports can only be allocated in post_create", hook, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Steps returns the steps declared for a hook.
+func (c *HooksConfig) Steps(hook string) []HookStep {
+	if c == nil {
+		return nil
+	}
+	switch hook {
+	case HookPostCreate:
+		return c.PostCreate
+	case HookPreRemove:
+		return c.PreRemove
+	case HookPostDone:
+		return c.PostDone
+	}
+	return nil
+}
+
+// label names a step for reports.
+func (s *HookStep) label() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case len(s.Copy) > 0:
+		return "copy " + strings.Join(s.Copy, ", ")
+	case len(s.Symlink) > 0:
+		return "symlink " + strings.Join(s.Symlink, ", ")
+	case s.Ports != nil:
+		return fmt.Sprintf("allocate %d port(s)", s.Ports.Count)
+	}
+	return s.Run
+}
+
+// hookEnv is the context a hook runs in.
+type hookEnv struct {
+	repoPath string
+	dir      string // working directory: the worktree, or the repo for post_done
+	info     *WorktreeInfo
+	extra    []string
+	registry *Registry // records port reservations
+	project  string
+}
+
+// runHook runs a hook's steps in order. It stops at the first required
+// step that fails and returns a *HookError; optional failures are only
+// recorded.
+func runHook(hook string, steps []HookStep, env *hookEnv) ([]StepResult, error) {
+	var results []StepResult
+	for i := range steps {
+		s := &steps[i]
+		start := time.Now()
+		detail, err := env.runStep(s)
+		r := StepResult{Hook: hook, Name: s.label(), Status: StepOK, Optional: s.Optional, Detail: detail,
+			DurationMs: time.Since(start).Milliseconds(), At: start}
+		if err != nil {
+			r.Status, r.Detail = StepFailed, err.Error()
+		}
+		results = append(results, r)
+		if err != nil && !s.Optional {
+			return results, &HookError{Hook: hook, Step: r.Name, Err: err.Error()}
+		}
+	}
+	return results, nil
+}
+
+func (e *hookEnv) runStep(s *HookStep) (string, error) {
+	switch {
+	case len(s.Copy) > 0:
+		return e.linkOrCopy(s.Copy, false)
+	case len(s.Symlink) > 0:
+		return e.linkOrCopy(s.Symlink, true)
+	case s.Ports != nil:
+		return e.allocatePorts(s.Ports)
+	}
+	return e.run(s)
+}
+
+// linkOrCopy mirrors paths from the main repository into the worktree.
+// Matches that resolve outside the repository, through ".." or a
+// symlink, are rejected.
+func (e *hookEnv) linkOrCopy(patterns []string, link bool) (string, error) {
+	root, err := filepath.EvalSymlinks(e.repoPath)
+	if err != nil {
+		return "", err
+	}
+	var done []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(e.repoPath, pattern))
+		if err != nil {
+			return "", err
+		}
+		if len(matches) == 0 {
+			return "", fmt.Errorf("%s not found in %s", pattern, e.repoPath)
+		}
+		for _, src := range matches {
+			rel, _ := filepath.Rel(e.repoPath, src)
+			resolved, err := filepath.EvalSymlinks(src)
+			if err != nil {
+				return "", err
+			}
+			if !inside(resolved, root) || !inside(src, e.repoPath) {
+				return "", fmt.Errorf("%s resolves outside the repository", filepath.ToSlash(rel))
+			}
+			dst := filepath.Join(e.dir, rel)
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return "", err
+			}
+			if link {
+				if _, err := os.Lstat(dst); err == nil {
+					if target, _ := os.Readlink(dst); target == src {
+						done = append(done, rel)
+						continue
+					}
+					return "", fmt.Errorf("%s already exists in the worktree", rel)
+				}
+				if err := os.Symlink(src, dst); err != nil {
+					return "", err
+				}
+			} else if err := copyPath(src, dst); err != nil {
+				return "", err
+			}
+			done = append(done, rel)
+		}
+	}
+	return strings.Join(done, ", "), nil
+}
+
+// inside reports whether path is dir or below it.
+func inside(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// copyPath copies a file or directory tree, preserving file modes.
+func copyPath(src, dst string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm()|0700)
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			return os.Symlink(link, target)
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fi.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+func (e *hookEnv) run(s *HookStep) (string, error) {
+	timeout := defaultStepTimeout
+	if s.Timeout != "" {
+		timeout, _ = time.ParseDuration(s.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Run)
+	cmd.Dir = filepath.Join(e.dir, s.Dir)
+	cmd.Env = append(os.Environ(), e.environ()...)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if tail := lastLine(string(out)); tail != "" {
+			return "", fmt.Errorf("%v: %s", err, tail)
+		}
+		return "", err
+	}
+	return lastLine(string(out)), nil
+}
+
+// environ describes the worktree to hook commands.
+func (e *hookEnv) environ() []string {
+	env := []string{
+		"JIKIME_REPO_PATH=" + e.repoPath,
+		"JIKIME_SPEC_ID=" + e.info.SpecID,
+		"JIKIME_WORKTREE_PATH=" + e.info.Path,
+		"JIKIME_BRANCH=" + e.info.Branch,
+	}
+	if p := e.info.Ports; p != nil {
+		env = append(env, fmt.Sprintf("JIKIME_PORT_START=%d", p.Start), fmt.Sprintf("JIKIME_PORT_END=%d", p.End()))
+	}
+	return append(env, e.extra...)
+}
+
+// allocatePorts reserves the lowest free block not held by another
+// worktree and writes it to the worktree's env file. The block is picked
+// and recorded under the registry lock.
+func (e *hookEnv) allocatePorts(spec *PortSpec) (string, error) {
+	base := spec.Base
+	if base == 0 {
+		base = defaultPortBase
+	}
+	cur := e.info.Ports
+	found, err := e.registry.ReservePorts(e.info, e.project, func(taken []*PortRange) *PortRange {
+		if cur != nil && cur.Count == spec.Count && cur.Start >= base && !rangeTaken(cur, taken) {
+			return cur // re-bootstrapping keeps the worktree's ports
+		}
+		for start := base; start+spec.Count-1 <= 65535; start += spec.Count {
+			r := &PortRange{Start: start, Count: spec.Count}
+			if !rangeTaken(r, taken) && portsFree(r) {
+				return r
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == nil {
+		return "", fmt.Errorf("no free block of %d ports from %d", spec.Count, base)
+	}
+
+	vars := map[string]string{}
+	var keys []string
+	for i := 0; i < spec.Count; i++ {
+		name := fmt.Sprintf("PORT_%d", i)
+		switch {
+		case i < len(spec.Names):
+			name = spec.Names[i]
+		case i == 0:
+			name = "PORT"
+		}
+		keys = append(keys, name)
+		vars[name] = strconv.Itoa(found.Start + i)
+	}
+	file := spec.File
+	if file == "" {
+		file = defaultEnvFile
+	}
+	if err := upsertEnvFile(filepath.Join(e.dir, file), keys, vars); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d → %s", found.Start, found.End(), file), nil
+}
+
+func rangeTaken(r *PortRange, taken []*PortRange) bool {
+	for _, t := range taken {
+		if t != nil && r.overlaps(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// portsFree reports whether every port in the range can be bound locally.
+func portsFree(r *PortRange) bool {
+	for p := r.Start; p <= r.End(); p++ {
+		l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", p))
+		if err != nil {
+			return false
+		}
+		l.Close()
+	}
+	return true
+}
+
+// upsertEnvFile sets keys in a dotenv file, keeping other lines as they are.
+func upsertEnvFile(path string, keys []string, vars map[string]string) error {
+	var lines []string
+	if f, err := os.Open(path); err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		f.Close()
+	}
+	written := map[string]bool{}
+	for i, l := range lines {
+		k, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(l), "export "), "=")
+		if v, set := vars[strings.TrimSpace(k)]; ok && set {
+			lines[i] = strings.TrimSpace(k) + "=" + v
+			written[strings.TrimSpace(k)] = true
+		}
+	}
+	for _, k := range keys {
+		if !written[k] {
+			lines = append(lines, k+"="+vars[k])
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// BootstrapHealth summarizes the post-create steps recorded for a worktree.
+func (w *WorktreeInfo) BootstrapHealth() string {
+	health := HealthNone
+	for _, r := range w.Hooks {
+		if r.Hook != HookPostCreate {
+			continue
+		}
+		switch {
+		case r.Status == StepOK && health == HealthNone:
+			health = HealthHealthy
+		case r.Status == StepFailed && !r.Optional:
+			return HealthFailed
+		case r.Status == StepFailed:
+			health = HealthDegraded
+		}
+	}
+	return health
+}
+
+// setHookResults replaces the recorded results of one hook.
+func (w *WorktreeInfo) setHookResults(hook string, results []StepResult) {
+	kept := w.Hooks[:0:0]
+	for _, r := range w.Hooks {
+		if r.Hook != hook {
+			kept = append(kept, r)
+		}
+	}
+	w.Hooks = append(kept, results...)
+}
+
+// hookEnvFor builds the environment for running a hook against a worktree.
+func (m *Manager) hookEnvFor(info *WorktreeInfo, dir string, extra ...string) *hookEnv {
+	return &hookEnv{repoPath: m.RepoPath, dir: dir, info: info, extra: extra, registry: m.Registry, project: m.ProjectName}
+}
+
+// Bootstrap runs the post_create hook for a worktree and records its
+// results. It is called by Create and can be re-run to repair a worktree.
+func (m *Manager) Bootstrap(specID string) (*WorktreeInfo, error) {
+	info := m.Registry.Get(specID, m.ProjectName)
+	if info == nil {
+		return nil, &WorktreeNotFoundError{SpecID: specID}
+	}
+	cfg, err := LoadHooksConfig(m.RepoPath)
+	if err != nil || cfg == nil {
+		return info, err
+	}
+	results, hookErr := runHook(HookPostCreate, cfg.PostCreate, m.hookEnvFor(info, info.Path))
+	info.setHookResults(HookPostCreate, results)
+	if err := m.Registry.Register(info, m.ProjectName); err != nil {
+		return info, err
+	}
+	return info, hookErr
+}
@@ -0,0 +1,146 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoadHooksConfig(t *testing.T) {
+	repo := t.TempDir()
+	if cfg, err := LoadHooksConfig(repo); cfg != nil || err != nil {
+		t.Fatalf("no file: %v %v", cfg, err)
+	}
+
+	write := func(s string) {
+		if err := os.WriteFile(filepath.Join(repo, "worktree.yaml"), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("post_create:\n  - copy: [.env]\n    run: npm ci\n")
+	if _, err := LoadHooksConfig(repo); err == nil || !strings.Contains(err.Error(), "post_create[0]") {
+		t.Errorf("two kinds in one step: err = %v", err)
+	}
+	write("pre_remove:\n  - ports: {count: 2}\n")
+	if _, err := LoadHooksConfig(repo); err == nil {
+		t.Error("ports outside post_create should fail")
+	}
+	write("post_create:\n  - copy: [.env]\n  - run: npm ci\n    timeout: 2m\npost_done:\n  - run: make clean\n")
+	cfg, err := LoadHooksConfig(repo)
+	if err != nil || len(cfg.PostCreate) != 2 || len(cfg.Steps(HookPostDone)) != 1 {
+		t.Fatalf("cfg = %+v, %v", cfg, err)
+	}
+}
+
+func TestRunHookPostCreate(t *testing.T) {
+	repo, wt := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, ".env"), []byte("SECRET=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, ".env.local"), []byte("# local\nWEB_PORT=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info := &WorktreeInfo{SpecID: "SPEC-001", Path: wt}
+	registry := NewRegistry(t.TempDir())
+	if err := registry.Register(&WorktreeInfo{SpecID: "SPEC-000", Path: "/elsewhere", Ports: &PortRange{Start: 41000, Count: 2}}, "other"); err != nil {
+		t.Fatal(err)
+	}
+	env := &hookEnv{repoPath: repo, dir: wt, info: info, registry: registry, project: "app"}
+	steps := []HookStep{
+		{Copy: []string{".env"}},
+		{Ports: &PortSpec{Count: 2, Base: 41000, Names: []string{"WEB_PORT"}}},
+		{Run: `test "$JIKIME_SPEC_ID" = SPEC-001 && test "$JIKIME_PORT_START" = 41002`},
+		{Run: "exit 1", Optional: true},
+	}
+
+	results, err := runHook(HookPostCreate, steps, env)
+	if err != nil {
+		t.Fatalf("runHook: %v (%+v)", err, results)
+	}
+	if info.Ports == nil || info.Ports.Start != 41002 {
+		t.Errorf("ports = %+v, want start 41002", info.Ports)
+	}
+	if got := registry.Get("SPEC-001", "app"); got == nil || got.Ports == nil || got.Ports.Start != 41002 {
+		t.Errorf("reservation not recorded in the registry: %+v", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt, ".env")); string(data) != "SECRET=1\n" {
+		t.Errorf("copied .env = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt, ".env.local")); string(data) != "# local\nWEB_PORT=41002\nPORT_1=41003\n" {
+		t.Errorf(".env.local = %q", data)
+	}
+	info.Hooks = results
+	if h := info.BootstrapHealth(); h != HealthDegraded {
+		t.Errorf("health = %s, want degraded", h)
+	}
+
+	results, err = runHook(HookPostCreate, []HookStep{{Copy: []string{"missing"}}, {Run: "true"}}, env)
+	if _, ok := err.(*HookError); !ok || len(results) != 1 {
+		t.Fatalf("required failure: err = %v, results = %+v", err, results)
+	}
+	info.setHookResults(HookPostCreate, results)
+	if h := info.BootstrapHealth(); h != HealthFailed {
+		t.Errorf("health = %s, want failed", h)
+	}
+}
+
+func TestReservePorts_Concurrent(t *testing.T) {
+	registry := NewRegistry(t.TempDir())
+	pick := func(taken []*PortRange) *PortRange {
+		for start := 42000; ; start += 2 {
+			r := &PortRange{Start: start, Count: 2}
+			if !rangeTaken(r, taken) {
+				return r
+			}
+		}
+	}
+	var wg sync.WaitGroup
+	infos := make([]*WorktreeInfo, 8)
+	for i := range infos {
+		infos[i] = &WorktreeInfo{SpecID: fmt.Sprintf("SPEC-%03d", i), Path: fmt.Sprintf("/wt/%d", i)}
+		wg.Add(1)
+		go func(info *WorktreeInfo) {
+			defer wg.Done()
+			if _, err := registry.ReservePorts(info, "app", pick); err != nil {
+				t.Error(err)
+			}
+		}(infos[i])
+	}
+	wg.Wait()
+	seen := map[int]bool{}
+	for _, info := range registry.ListAll("app") {
+		if info.Ports == nil || seen[info.Ports.Start] {
+			t.Fatalf("duplicate or missing reservation: %+v", info.Ports)
+		}
+		seen[info.Ports.Start] = true
+	}
+	if len(seen) != len(infos) {
+		t.Errorf("reservations = %d, want %d", len(seen), len(infos))
+	}
+}
+
+func TestLinkOrCopyRejectsOutsidePaths(t *testing.T) {
+	base := t.TempDir()
+	repo, wt := filepath.Join(base, "repo"), t.TempDir()
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "secret.env"), []byte("X=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(base, "secret.env"), filepath.Join(repo, ".env")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	env := &hookEnv{repoPath: repo, dir: wt, info: &WorktreeInfo{SpecID: "SPEC-001", Path: wt}}
+	for _, pattern := range []string{"../*.env", ".env"} {
+		if _, err := env.linkOrCopy([]string{pattern}, false); err == nil || !strings.Contains(err.Error(), "outside the repository") {
+			t.Errorf("%s: err = %v", pattern, err)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(wt, ".env")); !os.IsNotExist(err) {
+		t.Errorf(".env copied into the worktree: %v", err)
+	}
+}
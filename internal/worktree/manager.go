@@ -113,6 +113,17 @@ func (m *Manager) Create(specID, branchName, baseBranch string, force bool, llmC
 		}
 	}
 
+	// Bootstrap the environment; a failed step leaves the worktree in place
+	// with its health recorded, so it can be fixed and re-run.
+	if bootstrapped, err := m.Bootstrap(specID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		if bootstrapped != nil {
+			info = bootstrapped
+		}
+	} else {
+		info = bootstrapped
+	}
+
 	return info, nil
 }
 
@@ -130,6 +141,32 @@ func (m *Manager) Remove(specID string, force bool) error {
 		}
 	}
 
+	// Worktrees stacked on this one would lose their parent
+	if children := m.Children(specID); len(children) > 0 && !force {
+		names := make([]string, len(children))
+		for i, child := range children {
+			names[i] = child.SpecID
+		}
+		return &StackedWorktreesError{SpecID: specID, Children: names}
+	}
+
+	// Run pre_remove steps; a failure blocks removal unless forced
+	cfg, err := LoadHooksConfig(m.RepoPath)
+	if err != nil && !force {
+		return err
+	}
+	if steps := cfg.Steps(HookPreRemove); len(steps) > 0 {
+		results, hookErr := runHook(HookPreRemove, steps, m.hookEnvFor(info, info.Path))
+		if hookErr != nil {
+			if !force {
+				info.setHookResults(HookPreRemove, results)
+				_ = m.Registry.Register(info, m.ProjectName)
+				return hookErr
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", hookErr)
+		}
+	}
+
 	// Remove worktree using git command
 	args := []string{"worktree", "remove", info.Path}
 	if force {
@@ -143,6 +180,11 @@ func (m *Manager) Remove(specID string, force bool) error {
 		}
 	}
 
+	// Forced removal leaves stacked worktrees as plain branches
+	if err := m.detachChildren(specID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to detach stacked worktrees: %v\n", err)
+	}
+
 	// Unregister from registry
 	return m.Registry.Unregister(specID, m.ProjectName)
 }
@@ -154,9 +196,26 @@ func (m *Manager) List() []*WorktreeInfo {
 
 // Sync synchronizes a worktree with the base branch.
 func (m *Manager) Sync(specID, baseBranch string, rebase, ffOnly, squash, autoResolve bool) error {
+	_, err := m.SyncWithReport(specID, baseBranch, rebase, ffOnly, squash, autoResolve)
+	return err
+}
+
+// SyncWithReport synchronizes a worktree with the base branch and reports
+// how conflicts were resolved. With autoResolve, conflicts go through git
+// rerere, a diff3 hunk merge, and language-aware strategies (structural
+// JSON/YAML merge, import unions, lockfile regeneration). If any file is
+// left unresolved the merge is aborted and a *MergeConflictError lists it
+// with per-hunk detail; nothing partially resolved is committed.
+//
+// A stacked worktree syncs from its parent instead of baseBranch: it is
+// restacked onto the parent's branch (see Restack).
+func (m *Manager) SyncWithReport(specID, baseBranch string, rebase, ffOnly, squash, autoResolve bool) (*ResolveReport, error) {
 	info := m.Registry.Get(specID, m.ProjectName)
 	if info == nil {
-		return &WorktreeNotFoundError{SpecID: specID}
+		return nil, &WorktreeNotFoundError{SpecID: specID}
+	}
+	if info.Parent != "" {
+		return m.Restack(specID, autoResolve)
 	}
 
 	// Fetch latest (network issues shouldn't block local operations)
@@ -169,49 +228,97 @@ func (m *Manager) Sync(specID, baseBranch string, rebase, ffOnly, squash, autoRe
 	if _, err := m.gitCmdInDir(info.Path, "rev-parse", targetBranch); err != nil {
 		targetBranch = baseBranch
 		if _, err := m.gitCmdInDir(info.Path, "rev-parse", targetBranch); err != nil {
-			return &GitOperationError{Operation: "rev-parse", Message: fmt.Sprintf("base branch '%s' not found", baseBranch)}
+			return nil, &GitOperationError{Operation: "rev-parse", Message: fmt.Sprintf("base branch '%s' not found", baseBranch)}
 		}
 	}
 
+	report, err := m.integrate(info, targetBranch, "", rebase, ffOnly, squash, autoResolve)
+	if err != nil {
+		return report, err
+	}
+
+	// Update last accessed time
+	info.LastAccessed = time.Now()
+	return report, m.Registry.Register(info, m.ProjectName)
+}
+
+// integrate brings targetBranch into a worktree with the given strategy
+// and works through conflicts as described on SyncWithReport. A non-empty
+// upstream makes a rebase replay only upstream..HEAD (rebase --onto), which
+// is how stacked worktrees move onto a rewritten parent.
+func (m *Manager) integrate(info *WorktreeInfo, targetBranch, upstream string, rebase, ffOnly, squash, autoResolve bool) (*ResolveReport, error) {
+	// With auto-resolve, let rerere replay and record resolutions.
+	git := func(args ...string) (string, error) {
+		if autoResolve {
+			args = append([]string{"-c", "rerere.enabled=true", "-c", "rerere.autoUpdate=true"}, args...)
+		}
+		return m.gitCmdInDir(info.Path, args...)
+	}
+
 	// Perform sync based on strategy
-	var err error
+	var (
+		output string
+		err    error
+	)
 	if ffOnly {
-		_, err = m.gitCmdInDir(info.Path, "merge", targetBranch, "--ff-only")
+		output, err = git("merge", targetBranch, "--ff-only")
+	} else if rebase && upstream != "" {
+		output, err = git("rebase", "--onto", targetBranch, upstream)
 	} else if rebase {
-		_, err = m.gitCmdInDir(info.Path, "rebase", targetBranch)
+		output, err = git("rebase", targetBranch)
 	} else if squash {
 		// Squash merge: merge all changes into a single commit
-		_, err = m.gitCmdInDir(info.Path, "merge", targetBranch, "--squash")
+		output, err = git("merge", targetBranch, "--squash")
 		if err == nil {
 			// Commit the squashed changes
-			_, err = m.gitCmdInDir(info.Path, "commit", "-m", fmt.Sprintf("Squash merge from %s", targetBranch))
+			output, err = git("commit", "-m", fmt.Sprintf("Squash merge from %s", targetBranch))
 		}
 	} else {
-		_, err = m.gitCmdInDir(info.Path, "merge", targetBranch)
+		output, err = git("merge", targetBranch)
 	}
 
-	if err != nil {
-		// Check for conflicts
-		conflicted := m.getConflictedFiles(info.Path)
-		if len(conflicted) > 0 {
-			if autoResolve {
-				if resolveErr := m.autoResolveConflicts(info.Path, specID, conflicted); resolveErr != nil {
-					// Abort and return error
-					m.abortMerge(info.Path, rebase)
-					return &MergeConflictError{SpecID: specID, ConflictedFiles: conflicted}
-				}
-			} else {
+	// During a rebase "ours" is the base branch; during a merge it is the worktree.
+	upstreamSide := "theirs"
+	if rebase {
+		upstreamSide = "ours"
+	}
+	report := &ResolveReport{}
+	for err != nil {
+		conflicted := m.getConflicts(info.Path)
+		rerere := rerereResolutions(output)
+		if len(conflicted) == 0 && len(rerere) == 0 {
+			if len(report.Resolved) > 0 {
+				// Concluding a resolved step failed; don't leave it half done.
 				m.abortMerge(info.Path, rebase)
-				return &MergeConflictError{SpecID: specID, ConflictedFiles: conflicted}
 			}
-		} else {
-			return &GitOperationError{Operation: "sync", Message: err.Error()}
+			return report, &GitOperationError{Operation: "sync", Message: err.Error()}
+		}
+		if !autoResolve {
+			m.abortMerge(info.Path, rebase)
+			return report, &MergeConflictError{SpecID: info.SpecID, ConflictedFiles: conflictPaths(conflicted)}
+		}
+
+		report.Resolved = append(report.Resolved, rerere...)
+		step := m.resolveConflicts(info.Path, conflicted, upstreamSide)
+		report.Resolved = append(report.Resolved, step.Resolved...)
+		report.Unresolved = append(report.Unresolved, step.Unresolved...)
+		if len(step.Unresolved) > 0 {
+			m.abortMerge(info.Path, rebase)
+			return report, &MergeConflictError{SpecID: info.SpecID, ConflictedFiles: conflictPaths(conflicted), Unresolved: step.Unresolved}
+		}
+
+		// Conclude the step; a rebase may stop again on a later commit.
+		switch {
+		case rebase:
+			output, err = git("-c", "core.editor=true", "rebase", "--continue")
+		case squash:
+			output, err = git("commit", "-m", fmt.Sprintf("Squash merge from %s", targetBranch))
+		default:
+			output, err = git("commit", "--no-edit")
 		}
 	}
 
-	// Update last accessed time
-	info.LastAccessed = time.Now()
-	return m.Registry.Register(info, m.ProjectName)
+	return report, nil
 }
 
 // CleanMerged removes worktrees for merged branches.
@@ -286,6 +393,9 @@ func (m *Manager) Done(specID, baseBranch string, push, force bool) (*DoneResult
 		pushed = true
 	}
 
+	// Move worktrees stacked on this one onto the base branch
+	restacked := m.retargetChildren(info, baseBranch)
+
 	// Remove worktree
 	if err := m.Remove(specID, force); err != nil {
 		return nil, err
@@ -296,11 +406,25 @@ func (m *Manager) Done(specID, baseBranch string, push, force bool) (*DoneResult
 		fmt.Fprintf(os.Stderr, "Warning: Failed to delete branch %s: %v\n", mergedBranch, err)
 	}
 
-	return &DoneResult{
+	result := &DoneResult{
 		MergedBranch: mergedBranch,
 		BaseBranch:   baseBranch,
 		Pushed:       pushed,
-	}, nil
+		Restacked:    restacked,
+	}
+
+	// Run post_done steps in the main repository (the worktree is gone)
+	if cfg, err := LoadHooksConfig(m.RepoPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if steps := cfg.Steps(HookPostDone); len(steps) > 0 {
+		var hookErr error
+		result.Hooks, hookErr = runHook(HookPostDone, steps, m.hookEnvFor(info, m.RepoPath, "JIKIME_BASE_BRANCH="+baseBranch))
+		if hookErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", hookErr)
+		}
+	}
+
+	return result, nil
 }
 
 // gitCmd runs a git command in the main repository.
@@ -327,23 +451,36 @@ func (m *Manager) hasUncommittedChanges(path string) (bool, error) {
 
 // getConflictedFiles returns files with merge conflicts.
 func (m *Manager) getConflictedFiles(path string) []string {
+	return conflictPaths(m.getConflicts(path))
+}
+
+// getConflicts returns unmerged paths with their porcelain status.
+func (m *Manager) getConflicts(path string) []conflictEntry {
 	output, err := m.gitCmdInDir(path, "status", "--porcelain")
 	if err != nil {
 		return nil
 	}
 
-	var conflicted []string
+	var conflicted []conflictEntry
 	for _, line := range strings.Split(output, "\n") {
-		if len(line) >= 2 {
-			status := line[:2]
-			if status == "UU" || status == "DD" || status == "AA" || status == "DU" {
-				conflicted = append(conflicted, strings.TrimSpace(line[3:]))
+		if len(line) >= 4 {
+			switch status := line[:2]; status {
+			case "UU", "DD", "AA", "DU", "UD", "AU", "UA":
+				conflicted = append(conflicted, conflictEntry{Path: strings.TrimSpace(line[3:]), Status: status})
 			}
 		}
 	}
 	return conflicted
 }
 
+func conflictPaths(entries []conflictEntry) []string {
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.Path
+	}
+	return paths
+}
+
 // abortMerge aborts an in-progress merge or rebase (best effort cleanup).
 func (m *Manager) abortMerge(path string, isRebase bool) {
 	if isRebase {
@@ -357,56 +494,6 @@ func (m *Manager) abortMerge(path string, isRebase bool) {
 	}
 }
 
-// autoResolveConflicts attempts to automatically resolve merge conflicts.
-func (m *Manager) autoResolveConflicts(path, specID string, conflicted []string) error {
-	for _, file := range conflicted {
-		// Try to accept our changes
-		if _, err := m.gitCmdInDir(path, "checkout", "--ours", file); err == nil {
-			if _, addErr := m.gitCmdInDir(path, "add", file); addErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to stage %s after checkout --ours: %v\n", file, addErr)
-			}
-			continue
-		}
-
-		// Try to accept their changes
-		if _, err := m.gitCmdInDir(path, "checkout", "--theirs", file); err == nil {
-			if _, addErr := m.gitCmdInDir(path, "add", file); addErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to stage %s after checkout --theirs: %v\n", file, addErr)
-			}
-			continue
-		}
-
-		// Try to remove conflict markers
-		filePath := filepath.Join(path, file)
-		if err := m.removeConflictMarkers(filePath); err == nil {
-			if _, addErr := m.gitCmdInDir(path, "add", file); addErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to stage %s after removing conflict markers: %v\n", file, addErr)
-			}
-			continue
-		}
-
-		return fmt.Errorf("failed to resolve conflict in %s", file)
-	}
-
-	// Commit the resolution
-	_, err := m.gitCmdInDir(path, "commit", "-m", fmt.Sprintf("Auto-resolved conflicts during sync of %s", specID))
-	return err
-}
-
-// removeConflictMarkers removes Git conflict markers from a file.
-func (m *Manager) removeConflictMarkers(filePath string) error {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
-	}
-
-	// Remove conflict markers using regex
-	re := regexp.MustCompile(`(?m)^<<<<<<<.*\n|^=======\n|^>>>>>>>.*\n`)
-	cleaned := re.ReplaceAll(content, []byte{})
-
-	return os.WriteFile(filePath, cleaned, 0644)
-}
-
 // copyLLMConfig copies LLM config to the worktree with environment variable substitution.
 func (m *Manager) copyLLMConfig(worktreePath, llmConfigPath string) error {
 	content, err := os.ReadFile(llmConfigPath)
@@ -0,0 +1,440 @@
+package worktree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Structural three-way merges for JSON and YAML documents. Objects are
+// merged key by key; a key changed on only one side takes that side's value.
+// Arrays and scalars changed differently on both sides are conflicts,
+// reported by their dotted path.
+
+// jsonObject is a JSON object that remembers its key order, so merged
+// files (package.json, tsconfig.json, ...) keep their layout.
+type jsonObject struct {
+	keys []string
+	vals map[string]any
+}
+
+// mergeJSON three-way merges JSON documents. base may be nil (both sides
+// added the file). It returns the merged document, indented like ours.
+func mergeJSON(base, ours, theirs []byte) ([]byte, []string, error) {
+	var b any = &jsonObject{vals: map[string]any{}}
+	if len(bytes.TrimSpace(base)) > 0 {
+		v, err := decodeOrderedJSON(base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("base: %w", err)
+		}
+		b = v
+	}
+	o, err := decodeOrderedJSON(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ours: %w", err)
+	}
+	t, err := decodeOrderedJSON(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("theirs: %w", err)
+	}
+
+	var conflicts []string
+	merged := mergeJSONValue("", b, o, t, &conflicts)
+	if len(conflicts) > 0 {
+		return nil, conflicts, nil
+	}
+	indent := detectIndent(ours)
+	if !bytes.Contains(bytes.TrimSpace(ours), []byte("\n")) {
+		indent = "" // keep single-line documents on one line
+	}
+	var buf bytes.Buffer
+	writeOrderedJSON(&buf, merged, indent, "")
+	if bytes.HasSuffix(ours, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil, nil
+}
+
+// missing marks a key absent on one side of a merge.
+type missingValue struct{}
+
+var missing = missingValue{}
+
+func mergeJSONValue(path string, b, o, t any, conflicts *[]string) any {
+	switch {
+	case jsonEqual(o, t):
+		return o
+	case jsonEqual(o, b):
+		return t
+	case jsonEqual(t, b):
+		return o
+	}
+	bo, _ := b.(*jsonObject)
+	oo, ok1 := o.(*jsonObject)
+	to, ok2 := t.(*jsonObject)
+	if !ok1 || !ok2 {
+		*conflicts = append(*conflicts, orRoot(path))
+		return o
+	}
+	if bo == nil {
+		bo = &jsonObject{vals: map[string]any{}}
+	}
+	out := &jsonObject{vals: map[string]any{}}
+	for _, k := range unionKeys(oo.keys, to.keys) {
+		v := mergeJSONValue(joinPath(path, k), lookup(bo, k), lookup(oo, k), lookup(to, k), conflicts)
+		if v == missing {
+			continue
+		}
+		out.keys = append(out.keys, k)
+		out.vals[k] = v
+	}
+	return out
+}
+
+func lookup(obj *jsonObject, k string) any {
+	if v, ok := obj.vals[k]; ok {
+		return v
+	}
+	return missing
+}
+
+func jsonEqual(a, b any) bool {
+	if a == missing || b == missing {
+		return a == b
+	}
+	var ab, bb bytes.Buffer
+	writeOrderedJSON(&ab, canonicalJSON(a), "", "")
+	writeOrderedJSON(&bb, canonicalJSON(b), "", "")
+	return ab.String() == bb.String()
+}
+
+// canonicalJSON sorts object keys so equality ignores key order.
+func canonicalJSON(v any) any {
+	switch x := v.(type) {
+	case *jsonObject:
+		out := &jsonObject{keys: append([]string(nil), x.keys...), vals: map[string]any{}}
+		sort.Strings(out.keys)
+		for k, val := range x.vals {
+			out.vals[k] = canonicalJSON(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, val := range x {
+			out[i] = canonicalJSON(val)
+		}
+		return out
+	}
+	return v
+}
+
+func decodeOrderedJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch d := tok.(type) {
+	case json.Delim:
+		switch d {
+		case '{':
+			obj := &jsonObject{vals: map[string]any{}}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				k, _ := kt.(string)
+				v, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := obj.vals[k]; !dup {
+					obj.keys = append(obj.keys, k)
+				}
+				obj.vals[k] = v
+			}
+			_, err := dec.Token() // '}'
+			return obj, err
+		case '[':
+			arr := []any{}
+			for dec.More() {
+				v, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token() // ']'
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", d)
+	}
+	return tok, nil
+}
+
+func writeOrderedJSON(buf *bytes.Buffer, v any, indent, prefix string) {
+	nl := func(p string) {
+		if indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(p)
+		}
+	}
+	sep := ":"
+	if indent != "" {
+		sep = ": "
+	}
+	switch x := v.(type) {
+	case *jsonObject:
+		if len(x.keys) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteByte('{')
+		for i, k := range x.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			nl(prefix + indent)
+			writeJSONString(buf, k)
+			buf.WriteString(sep)
+			writeOrderedJSON(buf, x.vals[k], indent, prefix+indent)
+		}
+		nl(prefix)
+		buf.WriteByte('}')
+	case []any:
+		if len(x) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			nl(prefix + indent)
+			writeOrderedJSON(buf, e, indent, prefix+indent)
+		}
+		nl(prefix)
+		buf.WriteByte(']')
+	case string:
+		writeJSONString(buf, x)
+	case json.Number:
+		buf.WriteString(x.String())
+	case bool:
+		if x {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case nil:
+		buf.WriteString("null")
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode appends a newline
+}
+
+// detectIndent returns the indentation of the first indented line, or two spaces.
+func detectIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// mergeYAML three-way merges YAML documents, keeping ours' key order and
+// comments. base may be nil. Multi-document streams ("---") are merged
+// document by document; when the sides have a different number of documents
+// the file is reported as a conflict and left for manual resolution.
+func mergeYAML(base, ours, theirs []byte) ([]byte, []string, error) {
+	var bDocs []*yaml.Node
+	if len(bytes.TrimSpace(base)) > 0 {
+		docs, err := decodeYAMLDocs(base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("base: %w", err)
+		}
+		bDocs = docs
+	}
+	oDocs, err := decodeYAMLDocs(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ours: %w", err)
+	}
+	tDocs, err := decodeYAMLDocs(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("theirs: %w", err)
+	}
+	if len(oDocs) != len(tDocs) || (bDocs != nil && len(bDocs) != len(oDocs)) {
+		return nil, []string{fmt.Sprintf("document count (ours %d, theirs %d)", len(oDocs), len(tDocs))}, nil
+	}
+
+	var conflicts []string
+	merged := make([]*yaml.Node, len(oDocs))
+	for i := range oDocs {
+		b := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if bDocs != nil {
+			b = bDocs[i]
+		}
+		path := ""
+		if len(oDocs) > 1 {
+			path = fmt.Sprintf("doc[%d]", i)
+		}
+		merged[i] = mergeYAMLNode(path, b, oDocs[i], tDocs[i], &conflicts)
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts, nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(len(detectIndent(ours)))
+	for _, doc := range merged {
+		if err := enc.Encode(doc); err != nil {
+			return nil, nil, err
+		}
+	}
+	_ = enc.Close()
+	return buf.Bytes(), nil, nil
+}
+
+// decodeYAMLDocs returns the root node of every document in data. Empty
+// input yields a single empty mapping.
+func decodeYAMLDocs(data []byte) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			docs = append(docs, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+			continue
+		}
+		docs = append(docs, doc.Content[0])
+	}
+	if len(docs) == 0 {
+		docs = append(docs, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	return docs, nil
+}
+
+func mergeYAMLNode(path string, b, o, t *yaml.Node, conflicts *[]string) *yaml.Node {
+	switch {
+	case yamlEqual(o, t):
+		return o
+	case yamlEqual(o, b):
+		return t
+	case yamlEqual(t, b):
+		return o
+	}
+	if o == nil || t == nil || o.Kind != yaml.MappingNode || t.Kind != yaml.MappingNode {
+		*conflicts = append(*conflicts, orRoot(path))
+		return o
+	}
+	if b == nil || b.Kind != yaml.MappingNode {
+		b = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	out := &yaml.Node{Kind: yaml.MappingNode, Tag: o.Tag, Style: o.Style,
+		HeadComment: o.HeadComment, LineComment: o.LineComment, FootComment: o.FootComment}
+	for _, k := range unionKeys(yamlKeys(o), yamlKeys(t)) {
+		okey, ov := yamlLookup(o, k)
+		_, bv := yamlLookup(b, k)
+		tkey, tv := yamlLookup(t, k)
+		v := mergeYAMLNode(joinPath(path, k), bv, ov, tv, conflicts)
+		if v == nil {
+			continue
+		}
+		if okey == nil {
+			okey = tkey
+		}
+		out.Content = append(out.Content, okey, v)
+	}
+	return out
+}
+
+func yamlKeys(n *yaml.Node) []string {
+	var keys []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys = append(keys, n.Content[i].Value)
+	}
+	return keys
+}
+
+func yamlLookup(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func yamlEqual(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+	ab, _ := json.Marshal(av)
+	bb, _ := json.Marshal(bv)
+	return string(ab) == string(bb)
+}
+
+// unionKeys returns a's keys followed by keys only in b.
+func unionKeys(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	out := append([]string(nil), a...)
+	for _, k := range a {
+		seen[k] = true
+	}
+	for _, k := range b {
+		if !seen[k] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
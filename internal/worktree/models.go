@@ -3,6 +3,8 @@ package worktree
 
 import (
 	"time"
+
+	"jikime-adk/internal/forge"
 )
 
 // WorktreeInfo represents metadata about a Git worktree.
@@ -13,6 +15,20 @@ type WorktreeInfo struct {
 	CreatedAt    time.Time `json:"created_at"`
 	LastAccessed time.Time `json:"last_accessed"`
 	Status       string    `json:"status"` // active, inactive, recovered
+
+	// Parent is the SPEC ID of the worktree this one is stacked on, if any.
+	Parent string `json:"parent,omitempty"`
+	// ParentHead is the parent commit the branch was last stacked on; a
+	// restack replays only the commits after it.
+	ParentHead string `json:"parent_head,omitempty"`
+
+	// Hooks records the last run of each worktree.yaml lifecycle hook.
+	Hooks []StepResult `json:"hooks,omitempty"`
+	// Ports is the port block allocated by a post_create ports step.
+	Ports *PortRange `json:"ports,omitempty"`
+	// PullRequest is the pull request opened by `done --pr`, with the
+	// review and CI state last fetched from the forge.
+	PullRequest *forge.PullRequest `json:"pull_request,omitempty"`
 }
 
 // ToMap converts WorktreeInfo to a map for JSON serialization.
@@ -24,6 +40,11 @@ func (w *WorktreeInfo) ToMap() map[string]any {
 		"created_at":    w.CreatedAt.Format(time.RFC3339),
 		"last_accessed": w.LastAccessed.Format(time.RFC3339),
 		"status":        w.Status,
+		"parent":        w.Parent,
+		"bootstrap":     w.BootstrapHealth(),
+		"hooks":         w.Hooks,
+		"ports":         w.Ports,
+		"pull_request":  w.PullRequest,
 	}
 }
 
@@ -32,4 +53,11 @@ type DoneResult struct {
 	MergedBranch string `json:"merged_branch"`
 	BaseBranch   string `json:"base_branch"`
 	Pushed       bool   `json:"pushed"`
+	// Hooks holds the post_done step results, if any ran.
+	Hooks []StepResult `json:"hooks,omitempty"`
+	// PullRequest is set by DonePR; PRExisted if it was already open.
+	PullRequest *forge.PullRequest `json:"pull_request,omitempty"`
+	PRExisted   bool               `json:"pr_existed,omitempty"`
+	// Restacked lists the stacked worktrees moved onto the base branch.
+	Restacked []RestackResult `json:"restacked,omitempty"`
 }
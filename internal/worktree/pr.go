@@ -0,0 +1,212 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"jikime-adk/internal/forge"
+	"jikime-adk/internal/tag"
+)
+
+// DonePR completes a worktree through a pull request instead of a local
+// merge: it pushes the branch, opens a pull request into baseBranch (or
+// adopts the one already open), and records it in the registry. The
+// worktree stays until the pull request is merged; see CleanMergedPRs.
+func (m *Manager) DonePR(ctx context.Context, specID, baseBranch string, f forge.Forge, draft bool) (*DoneResult, error) {
+	info := m.Registry.Get(specID, m.ProjectName)
+	if info == nil {
+		return nil, &WorktreeNotFoundError{SpecID: specID}
+	}
+	if hasChanges, _ := m.hasUncommittedChanges(info.Path); hasChanges {
+		return nil, &UncommittedChangesError{SpecID: specID}
+	}
+
+	if _, err := m.gitCmdInDir(info.Path, "push", "-u", "origin", info.Branch); err != nil {
+		return nil, &GitOperationError{Operation: "push", Message: err.Error()}
+	}
+	result := &DoneResult{MergedBranch: info.Branch, BaseBranch: baseBranch, Pushed: true}
+
+	pr, err := f.FindPullRequest(ctx, info.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if pr != nil {
+		result.PRExisted = true
+	} else {
+		title, body := m.BuildPRBody(info, baseBranch)
+		pr, err = f.CreatePullRequest(ctx, forge.CreateOptions{
+			Head:  info.Branch,
+			Base:  baseBranch,
+			Title: title,
+			Body:  body,
+			Draft: draft,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.PullRequest = pr
+
+	info.PullRequest = pr
+	return result, m.Registry.Register(info, m.ProjectName)
+}
+
+// RefreshPullRequests updates the recorded state of every worktree that
+// has a pull request. Worktrees whose lookup fails keep their last known
+// state; the first error is returned after the rest are updated.
+func (m *Manager) RefreshPullRequests(ctx context.Context, f forge.Forge) ([]*WorktreeInfo, error) {
+	worktrees := m.List()
+	changes := map[string]*WorktreeInfo{}
+	var firstErr error
+	for _, info := range worktrees {
+		if info.PullRequest == nil || info.PullRequest.Forge != f.Name() {
+			continue
+		}
+		pr, err := f.GetPullRequest(ctx, info.PullRequest.Number)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", info.SpecID, err)
+			}
+			continue
+		}
+		info.PullRequest = pr
+		changes[info.SpecID] = info
+	}
+	if len(changes) > 0 {
+		if err := m.Registry.Apply(m.ProjectName, changes); err != nil {
+			return worktrees, err
+		}
+	}
+	return worktrees, firstErr
+}
+
+// CleanMergedPRs removes worktrees whose pull requests have been merged
+// and deletes their local branches when git considers them merged.
+// Worktrees with uncommitted changes are kept. Worktrees stacked on a
+// merged one are retargeted onto the pull request's base branch.
+func (m *Manager) CleanMergedPRs(ctx context.Context, f forge.Forge) ([]string, error) {
+	worktrees, refreshErr := m.RefreshPullRequests(ctx, f)
+	var cleaned []string
+	for _, info := range worktrees {
+		if info.PullRequest == nil || info.PullRequest.State != forge.PRStateMerged {
+			continue
+		}
+		// Stacked worktrees move onto the branch the pull request merged into
+		if len(m.Children(info.SpecID)) > 0 {
+			if _, err := m.gitCmd("fetch", "origin"); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to fetch from origin: %v\n", err)
+			}
+			for _, r := range m.retargetChildren(info, "origin/"+info.PullRequest.Base) {
+				if r.Err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Could not move %s onto %s: %v\n  finish with: %s\n", r.SpecID, r.Onto, r.Err, r.Command)
+				}
+			}
+		}
+		if err := m.Remove(info.SpecID, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to remove %s: %v\n", info.SpecID, err)
+			continue
+		}
+		if _, err := m.gitCmd("branch", "-d", info.Branch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Kept branch %s (not merged locally; delete with git branch -D)\n", info.Branch)
+		}
+		cleaned = append(cleaned, info.SpecID)
+	}
+	return cleaned, refreshErr
+}
+
+// maxPRCriteria caps the acceptance checklist in a pull request body.
+const maxPRCriteria = 20
+
+// BuildPRBody renders a pull request title and description from the SPEC
+// documents (.jikime/specs/<SPEC-ID>/spec.md and acceptance.md), the
+// @SPEC tags in the files the branch changed, and its commits.
+func (m *Manager) BuildPRBody(info *WorktreeInfo, baseBranch string) (string, string) {
+	base := baseBranch
+	if _, err := m.gitCmdInDir(info.Path, "rev-parse", "--verify", "origin/"+baseBranch); err == nil {
+		base = "origin/" + baseBranch
+	}
+
+	specTitle, summary := tag.ReadSpecSummary(m.specFile(info, "spec.md"), info.SpecID)
+	title := info.SpecID
+	if specTitle != "" {
+		title = info.SpecID + ": " + specTitle
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", title)
+	if summary != "" {
+		b.WriteString(summary + "\n\n")
+	}
+
+	if criteria := tag.ReadAcceptanceCriteria(m.specFile(info, "acceptance.md")); len(criteria) > 0 {
+		b.WriteString("### Acceptance criteria\n\n")
+		for i, c := range criteria {
+			if i == maxPRCriteria {
+				fmt.Fprintf(&b, "- _%d more in acceptance.md_\n", len(criteria)-i)
+				break
+			}
+			b.WriteString("- [ ] " + c + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	changed, _ := m.gitCmdOutput(info.Path, "diff", "--name-only", base+"...HEAD")
+	byVerb := map[string][]string{}
+	untagged := 0
+	for _, file := range strings.Fields(changed) {
+		verbs := map[string]bool{}
+		for _, t := range tag.ExtractTagsFromFile(filepath.Join(info.Path, file)) {
+			if t.SpecID == info.SpecID {
+				verbs[t.Verb] = true
+			}
+		}
+		if len(verbs) == 0 {
+			untagged++
+		}
+		for v := range verbs {
+			byVerb[v] = append(byVerb[v], file)
+		}
+	}
+	if len(byVerb) > 0 {
+		b.WriteString("### Traceability (@SPEC " + info.SpecID + ")\n\n")
+		for _, section := range []struct{ verb, label string }{
+			{"impl", "Implementation"}, {"verify", "Tests"}, {"depends", "Depends on"}, {"related", "Related"},
+		} {
+			files := byVerb[section.verb]
+			if len(files) == 0 {
+				continue
+			}
+			sort.Strings(files)
+			fmt.Fprintf(&b, "**%s**\n", section.label)
+			for _, f := range files {
+				fmt.Fprintf(&b, "- `%s`\n", f)
+			}
+			b.WriteString("\n")
+		}
+		if untagged > 0 {
+			fmt.Fprintf(&b, "_%d other changed file(s) without @SPEC tags._\n\n", untagged)
+		}
+	}
+
+	if log, _ := m.gitCmdOutput(info.Path, "log", "--format=- %h %s", "--max-count=20", base+"..HEAD"); strings.TrimSpace(log) != "" {
+		b.WriteString("### Commits\n\n" + strings.TrimSpace(log) + "\n\n")
+	}
+	b.WriteString("---\n_Opened by `jikime worktree done --pr`._\n")
+	return title, b.String()
+}
+
+// specFile finds a SPEC document, preferring the worktree's copy (the
+// SPEC may be written on the branch) over the main repository's.
+func (m *Manager) specFile(info *WorktreeInfo, name string) string {
+	for _, root := range []string{info.Path, m.RepoPath} {
+		path := filepath.Join(root, ".jikime", "specs", info.SpecID, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
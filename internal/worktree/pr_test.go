@@ -0,0 +1,29 @@
+package worktree
+
+import (
+	"context"
+	"testing"
+
+	"jikime-adk/internal/forge"
+)
+
+func TestRefreshPullRequests(t *testing.T) {
+	m := NewManager(t.TempDir(), t.TempDir(), "proj")
+	f := forge.NewFake()
+	pr, _ := f.CreatePullRequest(context.Background(), forge.CreateOptions{Head: "feature/SPEC-001", Base: "main"})
+	m.Registry.Register(&WorktreeInfo{SpecID: "SPEC-001", Branch: "feature/SPEC-001", PullRequest: pr}, "proj")
+	m.Registry.Register(&WorktreeInfo{SpecID: "SPEC-002", Branch: "feature/SPEC-002"}, "proj")
+
+	f.SetState(pr.Number, forge.PRStateMerged)
+	f.SetCI(pr.Number, forge.CIStateSuccess)
+	if _, err := m.RefreshPullRequests(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	got := NewRegistry(m.WorktreeRoot).Get("SPEC-001", "proj").PullRequest
+	if got.State != forge.PRStateMerged || got.CI != forge.CIStateSuccess {
+		t.Errorf("recorded PR = %+v", got)
+	}
+	if NewRegistry(m.WorktreeRoot).Get("SPEC-002", "proj").PullRequest != nil {
+		t.Error("worktree without a PR gained one")
+	}
+}
@@ -6,9 +6,15 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"jikime-adk/internal/tag"
 )
 
-// Registry manages worktree metadata persistence.
+// Registry manages worktree metadata persistence. Access is serialized
+// within the process by mu and across processes (jikime-wt and
+// jikime worktree in different shells) by an flock on a sidecar lock file;
+// writes go through a temp file and rename so readers never see a partial
+// registry.
 type Registry struct {
 	root     string
 	filePath string
@@ -28,10 +34,48 @@ func NewRegistry(worktreeRoot string) *Registry {
 	}
 }
 
+// lock takes the in-process and cross-process locks and returns the
+// function that releases both.
+func (r *Registry) lock(exclusive bool) (func(), error) {
+	if exclusive {
+		r.mu.Lock()
+	} else {
+		r.mu.RLock()
+	}
+	unlockMu := func() {
+		if exclusive {
+			r.mu.Unlock()
+		} else {
+			r.mu.RUnlock()
+		}
+	}
+	if err := os.MkdirAll(r.root, 0755); err != nil {
+		unlockMu()
+		return nil, err
+	}
+	f, err := os.OpenFile(r.filePath+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		unlockMu()
+		return nil, err
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		unlockMu()
+		return nil, err
+	}
+	return func() {
+		f.Close() // releases the flock
+		unlockMu()
+	}, nil
+}
+
 // Register adds or updates a worktree in the registry.
 func (r *Registry) Register(info *WorktreeInfo, projectName string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -49,8 +93,11 @@ func (r *Registry) Register(info *WorktreeInfo, projectName string) error {
 
 // Unregister removes a worktree from the registry.
 func (r *Registry) Unregister(specID, projectName string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -64,10 +111,91 @@ func (r *Registry) Unregister(specID, projectName string) error {
 	return r.save(data)
 }
 
+// Apply replaces, adds or (for nil values) removes several entries of a
+// project in one locked read-modify-write.
+func (r *Registry) Apply(projectName string, changes map[string]*WorktreeInfo) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := r.load()
+	if err != nil {
+		// Unreadable registry: keep a copy and rebuild from the changes.
+		if renameErr := os.Rename(r.filePath, r.filePath+".corrupt-"+time.Now().Format("20060102-150405")); renameErr != nil {
+			return err
+		}
+		data = &registryData{Worktrees: make(map[string]map[string]*WorktreeInfo)}
+	}
+	if data.Worktrees[projectName] == nil {
+		data.Worktrees[projectName] = make(map[string]*WorktreeInfo)
+	}
+	for specID, info := range changes {
+		if info == nil {
+			delete(data.Worktrees[projectName], specID)
+		} else {
+			data.Worktrees[projectName][specID] = info
+		}
+	}
+
+	return r.save(data)
+}
+
+// ReservePorts picks a port block for info and records it in one locked
+// read-modify-write, so concurrent creates cannot pick the same block.
+// pick receives the ranges currently held by every other worktree sharing
+// this root and returns nil when none is free.
+func (r *Registry) ReservePorts(info *WorktreeInfo, projectName string, pick func(taken []*PortRange) *PortRange) (*PortRange, error) {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	var taken []*PortRange
+	for _, worktrees := range data.Worktrees {
+		for _, other := range worktrees {
+			if other.Path != info.Path && other.Ports != nil {
+				taken = append(taken, other.Ports)
+			}
+		}
+	}
+	found := pick(taken)
+	if found == nil {
+		return nil, nil
+	}
+	info.Ports = found
+	if data.Worktrees[projectName] == nil {
+		data.Worktrees[projectName] = make(map[string]*WorktreeInfo)
+	}
+	data.Worktrees[projectName][info.SpecID] = info
+	return found, r.save(data)
+}
+
+// Check reports whether the registry file can be read.
+func (r *Registry) Check() error {
+	unlock, err := r.lock(false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = r.load()
+	return err
+}
+
 // Get retrieves a worktree info by spec ID.
 func (r *Registry) Get(specID, projectName string) *WorktreeInfo {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	unlock, err := r.lock(false)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -83,8 +211,11 @@ func (r *Registry) Get(specID, projectName string) *WorktreeInfo {
 
 // ListAll returns all worktrees for a project.
 func (r *Registry) ListAll(projectName string) []*WorktreeInfo {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	unlock, err := r.lock(false)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -103,10 +234,35 @@ func (r *Registry) ListAll(projectName string) []*WorktreeInfo {
 	return result
 }
 
+// ListEveryProject returns the worktrees of all projects sharing this root.
+func (r *Registry) ListEveryProject() []*WorktreeInfo {
+	unlock, err := r.lock(false)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
+
+	data, err := r.load()
+	if err != nil {
+		return nil
+	}
+
+	var result []*WorktreeInfo
+	for _, worktrees := range data.Worktrees {
+		for _, info := range worktrees {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
 // SyncWithGit synchronizes the registry with actual Git worktrees.
 func (r *Registry) SyncWithGit(repoPath string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -129,8 +285,11 @@ func (r *Registry) SyncWithGit(repoPath string) error {
 
 // RecoverFromDisk scans the worktree root directory and recovers worktrees.
 func (r *Registry) RecoverFromDisk() (int, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
 
 	data, err := r.load()
 	if err != nil {
@@ -269,19 +428,9 @@ func (r *Registry) load() (*registryData, error) {
 	return &data, nil
 }
 
-// save writes the registry file.
+// save writes the registry file atomically.
 func (r *Registry) save(data *registryData) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(r.root, 0755); err != nil {
-		return err
-	}
-
-	content, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(r.filePath, content, 0644)
+	return tag.AtomicWriteJSON(r.filePath, data)
 }
 
 // Path returns the registry file path.
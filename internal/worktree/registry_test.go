@@ -0,0 +1,44 @@
+package worktree
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRegistryConcurrentWriters(t *testing.T) {
+	root := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// A separate Registry per writer, like separate processes.
+			r := NewRegistry(root)
+			if err := r.Register(&WorktreeInfo{SpecID: fmt.Sprintf("SPEC-%03d", i)}, "proj"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(NewRegistry(root).ListAll("proj")); got != 20 {
+		t.Errorf("registered %d worktrees, want 20", got)
+	}
+}
+
+func TestParseWorktreePorcelain(t *testing.T) {
+	out := "worktree /repo\nHEAD abc\nbranch refs/heads/main\n\n" +
+		"worktree /wt/SPEC-001\nHEAD def\nbranch refs/heads/feature/SPEC-001\nprunable gitdir file points to non-existent location\n\n" +
+		"worktree /wt/SPEC-002\nHEAD 123\ndetached\nlocked\n"
+	got := parseWorktreePorcelain(out)
+	if len(got) != 3 {
+		t.Fatalf("got %d entries: %+v", len(got), got)
+	}
+	if got[1].Branch != "feature/SPEC-001" || !got[1].Prunable || got[1].PrunableReason == "" {
+		t.Errorf("entry 1 = %+v", got[1])
+	}
+	if !got[2].Detached || !got[2].Locked || got[2].Branch != "" {
+		t.Errorf("entry 2 = %+v", got[2])
+	}
+}
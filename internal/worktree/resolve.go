@@ -0,0 +1,486 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resolution strategies reported in FileResolution.Strategy.
+const (
+	StrategyRerere   = "rerere"
+	StrategyDiff3    = "diff3"
+	StrategyImports  = "imports"
+	StrategyJSON     = "json"
+	StrategyYAML     = "yaml"
+	StrategyLockfile = "lockfile"
+	StrategyDeleted  = "deleted"
+)
+
+// ConflictHunk is one conflicted region of a file, as written by
+// `git checkout --conflict=diff3`.
+type ConflictHunk struct {
+	StartLine int      `json:"start_line"` // line of the <<<<<<< marker
+	EndLine   int      `json:"end_line"`   // line of the >>>>>>> marker
+	Ours      []string `json:"ours"`
+	Base      []string `json:"base,omitempty"`
+	Theirs    []string `json:"theirs"`
+}
+
+// FileResolution describes how a conflicted file was resolved, or why it
+// could not be.
+type FileResolution struct {
+	Path     string         `json:"path"`
+	Strategy string         `json:"strategy,omitempty"`
+	Detail   string         `json:"detail,omitempty"`
+	Hunks    []ConflictHunk `json:"hunks,omitempty"` // unresolved hunks
+}
+
+// ResolveReport is the outcome of automatic conflict resolution.
+type ResolveReport struct {
+	Resolved   []FileResolution `json:"resolved,omitempty"`
+	Unresolved []FileResolution `json:"unresolved,omitempty"`
+}
+
+// conflictEntry is an unmerged path with its two-letter status.
+type conflictEntry struct {
+	Path   string
+	Status string
+}
+
+// lockfileCommands regenerate a lockfile from its (already merged) manifest.
+// Yarn and Poetry entries are for Yarn 1 and Poetry 1; lockfileCommand
+// switches to the newer tools' flags. Yarn 1 has no lockfile-only mode, so
+// it does a full install (without scripts) into node_modules.
+var lockfileCommands = map[string][]string{
+	"package-lock.json":   {"npm", "install", "--package-lock-only", "--ignore-scripts"},
+	"npm-shrinkwrap.json": {"npm", "install", "--package-lock-only", "--ignore-scripts"},
+	"yarn.lock":           {"yarn", "install", "--ignore-scripts"},
+	"pnpm-lock.yaml":      {"pnpm", "install", "--lockfile-only", "--ignore-scripts"},
+	"Cargo.lock":          {"cargo", "update", "--workspace"},
+	"composer.lock":       {"composer", "update", "--lock", "--no-install", "--no-scripts"},
+	"poetry.lock":         {"poetry", "lock", "--no-update"},
+	"Gemfile.lock":        {"bundle", "lock"},
+}
+
+// lockfileCommand returns the command regenerating lockfile name, run in
+// dir. Yarn 2+ (Berry) only updates the lockfile with --mode
+// update-lockfile; Poetry 2 dropped --no-update, as plain "poetry lock"
+// keeps locked versions.
+func lockfileCommand(name, dir string) []string {
+	switch name {
+	case "yarn.lock":
+		if toolMajorVersion(dir, "yarn") >= 2 {
+			return []string{"yarn", "install", "--mode", "update-lockfile"}
+		}
+	case "poetry.lock":
+		if toolMajorVersion(dir, "poetry") >= 2 {
+			return []string{"poetry", "lock"}
+		}
+	}
+	return lockfileCommands[name]
+}
+
+var versionNumber = regexp.MustCompile(`(\d+)\.\d+`)
+
+// toolMajorVersion runs "tool --version" in dir, where a project can pin
+// its own version (Yarn's packageManager field), and returns the major
+// version, or 0 when it cannot be determined.
+func toolMajorVersion(dir, tool string) int {
+	cmd := exec.Command(tool, "--version")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return 0
+	}
+	m := versionNumber.FindStringSubmatch(string(out))
+	if m == nil {
+		return 0
+	}
+	major, _ := strconv.Atoi(m[1])
+	return major
+}
+
+// importLine matches a single import statement per language, keyed by
+// file extension.
+var importLine = map[string]*regexp.Regexp{
+	".go":   regexp.MustCompile(`^\s*(import\s+)?([\w.]+\s+)?"[^"]+"\s*(//.*)?$`),
+	".js":   regexp.MustCompile(`^\s*(import\s.+\sfrom\s+['"][^'"]+['"]|import\s+['"][^'"]+['"]|(const|let|var)\s+.+=\s*require\(['"][^'"]+['"]\))\s*;?\s*$`),
+	".py":   regexp.MustCompile(`^\s*(from\s+[\w.]+\s+)?import\s+[\w., ]+(\s+as\s+\w+)?\s*$`),
+	".java": regexp.MustCompile(`^\s*import\s+(static\s+)?[\w.]+(\.\*)?\s*;?\s*$`),
+	".cs":   regexp.MustCompile(`^\s*(global\s+)?using\s+(static\s+)?[\w.]+(\s*=\s*[\w.<>]+)?\s*;\s*$`),
+	".php":  regexp.MustCompile(`^\s*use\s+(function\s+|const\s+)?[\w\\]+(\s+as\s+\w+)?\s*;\s*$`),
+	".rs":   regexp.MustCompile(`^\s*(pub\s+)?use\s+[\w:{}, *]+\s*;\s*$`),
+	".c":    regexp.MustCompile(`^\s*#\s*include\s+[<"][^>"]+[>"]\s*$`),
+}
+
+func init() {
+	for _, ext := range []string{".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".vue", ".svelte"} {
+		importLine[ext] = importLine[".js"]
+	}
+	for _, ext := range []string{".kt", ".kts", ".scala", ".groovy"} {
+		importLine[ext] = importLine[".java"]
+	}
+	for _, ext := range []string{".h", ".cc", ".cpp", ".hpp", ".m"} {
+		importLine[ext] = importLine[".c"]
+	}
+}
+
+// freeformWhitespace lists extensions whose syntax does not depend on
+// indentation or line breaks. Only these get whitespace-insensitive hunk
+// matching; elsewhere (Python, YAML, Makefiles, ...) a whitespace-only change
+// is a real change and must not be dropped.
+var freeformWhitespace = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true, ".groovy": true,
+	".c": true, ".h": true, ".cc": true, ".cpp": true, ".hpp": true, ".m": true,
+	".cs": true, ".php": true, ".rs": true, ".swift": true, ".css": true, ".scss": true, ".less": true,
+}
+
+var rerereResolved = regexp.MustCompile(`(?m)^(?:Resolved|Staged) '(.+)' using previous resolution\.$`)
+
+// rerereResolutions returns the paths git rerere resolved, from the output
+// of the merge or rebase that conflicted.
+func rerereResolutions(output string) []FileResolution {
+	var out []FileResolution
+	for _, m := range rerereResolved.FindAllStringSubmatch(output, -1) {
+		out = append(out, FileResolution{Path: m[1], Strategy: StrategyRerere, Detail: "previous resolution"})
+	}
+	return out
+}
+
+// resolveConflicts resolves and stages what it can. upstream names the
+// side holding the base branch: "theirs" while merging, "ours" while
+// rebasing. Lockfiles are handled last so they regenerate against merged
+// manifests.
+func (m *Manager) resolveConflicts(dir string, entries []conflictEntry, upstream string) *ResolveReport {
+	report := &ResolveReport{}
+	record := func(r FileResolution) {
+		if r.Strategy != "" {
+			if _, err := m.gitCmdInDir(dir, "add", "--", r.Path); err != nil {
+				r = FileResolution{Path: r.Path, Detail: fmt.Sprintf("git add failed: %v", err)}
+			}
+		}
+		if r.Strategy != "" {
+			report.Resolved = append(report.Resolved, r)
+		} else {
+			report.Unresolved = append(report.Unresolved, r)
+		}
+	}
+
+	var lockfiles []conflictEntry
+	for _, e := range entries {
+		if _, ok := lockfileCommands[filepath.Base(e.Path)]; ok && e.Status == "UU" {
+			lockfiles = append(lockfiles, e)
+			continue
+		}
+		switch e.Status {
+		case "UU", "AA":
+			record(m.resolveFile(dir, e.Path))
+		case "DD":
+			if _, err := m.gitCmdInDir(dir, "rm", "--cached", "--quiet", "--", e.Path); err != nil {
+				record(FileResolution{Path: e.Path, Detail: fmt.Sprintf("git rm failed: %v", err)})
+			} else {
+				report.Resolved = append(report.Resolved, FileResolution{Path: e.Path, Strategy: StrategyDeleted, Detail: "deleted on both sides"})
+			}
+		default:
+			record(FileResolution{Path: e.Path, Detail: describeStatus(e.Status)})
+		}
+	}
+	for _, e := range lockfiles {
+		record(m.resolveLockfile(dir, e.Path, upstream))
+	}
+	return report
+}
+
+func describeStatus(status string) string {
+	switch status {
+	case "DU":
+		return "modified by them, deleted by us"
+	case "UD":
+		return "modified by us, deleted by them"
+	case "AU":
+		return "added by us only"
+	case "UA":
+		return "added by them only"
+	}
+	return "unmerged (" + status + ")"
+}
+
+// resolveFile tries a structural merge for JSON/YAML, then a hunk-by-hunk
+// diff3 merge. The file is only written when every hunk resolves.
+func (m *Manager) resolveFile(dir, path string) FileResolution {
+	if filepath.Base(path) == "go.sum" {
+		ours, err1 := m.gitCmdOutput(dir, "show", ":2:"+path)
+		theirs, err2 := m.gitCmdOutput(dir, "show", ":3:"+path)
+		if err1 != nil || err2 != nil {
+			return FileResolution{Path: path, Detail: "cannot read go.sum stages"}
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(mergeGoSum(ours, theirs)), 0644); err != nil {
+			return FileResolution{Path: path, Detail: err.Error()}
+		}
+		return FileResolution{Path: path, Strategy: StrategyLockfile, Detail: "union of both sides"}
+	}
+
+	var structural []string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".yaml", ".yml":
+		base, _ := m.gitCmdOutput(dir, "show", ":1:"+path)
+		ours, err1 := m.gitCmdOutput(dir, "show", ":2:"+path)
+		theirs, err2 := m.gitCmdOutput(dir, "show", ":3:"+path)
+		if err1 == nil && err2 == nil {
+			merge, strategy := mergeJSON, StrategyJSON
+			if ext != ".json" {
+				merge, strategy = mergeYAML, StrategyYAML
+			}
+			merged, conflicts, err := merge([]byte(base), []byte(ours), []byte(theirs))
+			if err == nil && len(conflicts) == 0 {
+				if err := os.WriteFile(filepath.Join(dir, path), merged, 0644); err != nil {
+					return FileResolution{Path: path, Detail: err.Error()}
+				}
+				return FileResolution{Path: path, Strategy: strategy}
+			}
+			structural = conflicts
+		}
+	}
+
+	res := FileResolution{Path: path}
+	if len(structural) > 0 {
+		res.Detail = "conflicting keys: " + strings.Join(structural, ", ")
+	}
+	if _, err := m.gitCmdInDir(dir, "checkout", "--conflict=diff3", "--", path); err != nil {
+		res.Detail = strings.TrimSpace(res.Detail + "; checkout --conflict=diff3 failed: " + err.Error())
+		return res
+	}
+	full := filepath.Join(dir, path)
+	content, err := os.ReadFile(full)
+	if err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	merged, strategy, hunks := resolveText(string(content), strings.ToLower(filepath.Ext(path)))
+	if len(hunks) > 0 {
+		res.Hunks = hunks
+		if res.Detail == "" {
+			res.Detail = fmt.Sprintf("%d unresolved hunk(s)", len(hunks))
+		}
+		return res
+	}
+	if err := os.WriteFile(full, []byte(merged), 0644); err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	res.Strategy, res.Detail = strategy, ""
+	return res
+}
+
+// resolveLockfile takes the upstream lockfile and regenerates it with the
+// package manager, so dependencies added on either side end up locked.
+// go.sum needs no tool: the union of both sides is valid.
+func (m *Manager) resolveLockfile(dir, path, upstream string) FileResolution {
+	res := FileResolution{Path: path}
+	cmdArgs := lockfileCommands[filepath.Base(path)]
+	if _, err := exec.LookPath(cmdArgs[0]); err != nil {
+		res.Detail = fmt.Sprintf("cannot regenerate: %s not found in PATH", cmdArgs[0])
+		return res
+	}
+	if _, err := m.gitCmdInDir(dir, "checkout", "--"+upstream, "--", path); err != nil {
+		res.Detail = fmt.Sprintf("checkout --%s failed: %v", upstream, err)
+		return res
+	}
+	cmdDir := filepath.Join(dir, filepath.Dir(path))
+	cmdArgs = lockfileCommand(filepath.Base(path), cmdDir)
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd.Dir = cmdDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		res.Detail = fmt.Sprintf("%s failed: %v: %s", strings.Join(cmdArgs, " "), err, lastLine(string(out)))
+		return res
+	}
+	res.Strategy = StrategyLockfile
+	res.Detail = "regenerated with " + strings.Join(cmdArgs, " ")
+	return res
+}
+
+// mergeGoSum unions both sides of a go.sum conflict.
+func mergeGoSum(ours, theirs string) string {
+	seen := map[string]bool{}
+	var lines []string
+	for _, l := range strings.Split(ours+"\n"+theirs, "\n") {
+		if l = strings.TrimSpace(l); l != "" && !seen[l] {
+			seen[l] = true
+			lines = append(lines, l)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// resolveText resolves each diff3 hunk in content, a file with extension
+// ext. It returns the merged text and the strategy used, or the hunks it
+// could not resolve.
+func resolveText(content, ext string) (string, string, []ConflictHunk) {
+	imports := importLine[ext]
+	lines := strings.Split(content, "\n")
+	var (
+		out        []string
+		unresolved []ConflictHunk
+		strategy   = StrategyDiff3
+	)
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "<<<<<<<") {
+			out = append(out, lines[i])
+			continue
+		}
+		h, hasBase, end := parseHunk(lines, i)
+		if end < 0 {
+			// Unterminated marker: leave the rest untouched.
+			out = append(out, lines[i:]...)
+			break
+		}
+		i = end
+		if merged, ok := resolveHunk(h, hasBase, freeformWhitespace[ext]); ok {
+			out = append(out, merged...)
+			continue
+		}
+		if imports != nil && hasBase && allMatch(imports, h.Ours, h.Base, h.Theirs) {
+			out = append(out, unionLines(h.Base, h.Ours, h.Theirs)...)
+			strategy = StrategyImports
+			continue
+		}
+		unresolved = append(unresolved, h)
+	}
+	if len(unresolved) > 0 {
+		return "", "", unresolved
+	}
+	return strings.Join(out, "\n"), strategy, nil
+}
+
+// parseHunk reads the conflict starting at lines[start]. It returns the
+// hunk, whether it had a base section, and the index of the closing marker
+// (-1 if there is none).
+func parseHunk(lines []string, start int) (ConflictHunk, bool, int) {
+	h := ConflictHunk{StartLine: start + 1}
+	section, hasBase := &h.Ours, false
+	for i := start + 1; i < len(lines); i++ {
+		switch l := lines[i]; {
+		case strings.HasPrefix(l, "|||||||"):
+			section, hasBase = &h.Base, true
+		case l == "=======" || strings.HasPrefix(l, "======= "):
+			section = &h.Theirs
+		case strings.HasPrefix(l, ">>>>>>>"):
+			h.EndLine = i + 1
+			return h, hasBase, i
+		default:
+			*section = append(*section, l)
+		}
+	}
+	return h, hasBase, -1
+}
+
+// resolveHunk applies the diff3 rules: identical sides, or one side
+// unchanged from base, first exactly and then, if ignoreSpace is set,
+// ignoring indentation and trailing whitespace.
+func resolveHunk(h ConflictHunk, hasBase, ignoreSpace bool) ([]string, bool) {
+	eqs := []func(a, b []string) bool{linesEqual}
+	if ignoreSpace {
+		eqs = append(eqs, linesEqualTrimmed)
+	}
+	for _, eq := range eqs {
+		switch {
+		case eq(h.Ours, h.Theirs):
+			return h.Ours, true
+		case hasBase && eq(h.Ours, h.Base):
+			return h.Theirs, true
+		case hasBase && eq(h.Theirs, h.Base):
+			return h.Ours, true
+		}
+	}
+	return nil, false
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// linesEqualTrimmed compares line by line, ignoring only leading and
+// trailing whitespace: spaces inside a line (a string literal) and line
+// breaks still count.
+func linesEqualTrimmed(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimSpace(a[i]) != strings.TrimSpace(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// allMatch reports whether every non-blank line is an import.
+func allMatch(re *regexp.Regexp, groups ...[]string) bool {
+	for _, g := range groups {
+		for _, l := range g {
+			if strings.TrimSpace(l) != "" && !re.MatchString(l) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unionLines keeps ours' lines plus theirs' additions, dropping lines
+// either side removed from base.
+func unionLines(base, ours, theirs []string) []string {
+	key := strings.TrimSpace
+	inBase, inOurs, inTheirs := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, l := range base {
+		inBase[key(l)] = true
+	}
+	for _, l := range ours {
+		inOurs[key(l)] = true
+	}
+	for _, l := range theirs {
+		inTheirs[key(l)] = true
+	}
+	var out []string
+	seen := map[string]bool{}
+	for _, l := range append(append([]string(nil), ours...), theirs...) {
+		k := key(l)
+		if k == "" || seen[k] {
+			continue
+		}
+		if inBase[k] && (!inOurs[k] || !inTheirs[k]) {
+			continue
+		}
+		seen[k] = true
+		out = append(out, l)
+	}
+	return out
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+// gitCmdOutput runs a git command and returns stdout only.
+func (m *Manager) gitCmdOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,164 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveText(t *testing.T) {
+	conflicted := strings.Join([]string{
+		"package main",
+		"",
+		"import (",
+		"<<<<<<< HEAD",
+		`	"fmt"`,
+		`	"os"`,
+		"||||||| base",
+		`	"fmt"`,
+		"=======",
+		`	"fmt"`,
+		`	"strings"`,
+		">>>>>>> main",
+		")",
+		"<<<<<<< HEAD",
+		"var x = 1",
+		"||||||| base",
+		"var x = 1",
+		"=======",
+		"var x = 2",
+		">>>>>>> main",
+		"",
+	}, "\n")
+
+	merged, strategy, hunks := resolveText(conflicted, ".go")
+	if len(hunks) != 0 {
+		t.Fatalf("unexpected unresolved hunks: %+v", hunks)
+	}
+	if strategy != StrategyImports {
+		t.Errorf("strategy = %q, want %q", strategy, StrategyImports)
+	}
+	want := "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n)\nvar x = 2\n"
+	if merged != want {
+		t.Errorf("merged =\n%s\nwant\n%s", merged, want)
+	}
+
+	// Both sides changed the same line: reported, not concatenated.
+	both := "<<<<<<< HEAD\nvar x = 1\n||||||| base\nvar x = 0\n=======\nvar x = 2\n>>>>>>> main\n"
+	_, _, hunks = resolveText(both, ".go")
+	if len(hunks) != 1 || hunks[0].StartLine != 1 || hunks[0].EndLine != 7 {
+		t.Fatalf("hunks = %+v", hunks)
+	}
+
+	// Theirs only re-indents, ours changes the value. In Go the indentation
+	// change is insignificant; in Python it moves the line out of the block.
+	indent := "<<<<<<< HEAD\n    x = 2\n||||||| base\n    x = 1\n=======\nx = 1\n>>>>>>> main\n"
+	if merged, _, hunks := resolveText(indent, ".go"); len(hunks) != 0 || merged != "    x = 2\n" {
+		t.Errorf(".go: merged = %q, hunks = %+v; want ours", merged, hunks)
+	}
+	if _, _, hunks := resolveText(indent, ".py"); len(hunks) != 1 {
+		t.Errorf(".py: hunks = %+v, want the indentation change left unresolved", hunks)
+	}
+
+	// Spacing inside a line is a real change: ours is kept, not dropped in
+	// favor of theirs' re-indentation.
+	literal := "<<<<<<< HEAD\ns := \"a  b\"\n||||||| base\ns := \"a b\"\n=======\n\ts := \"a b\"\n>>>>>>> main\n"
+	if merged, _, hunks := resolveText(literal, ".go"); len(hunks) != 0 || merged != "s := \"a  b\"\n" {
+		t.Errorf("literal: merged = %q, hunks = %+v; want ours", merged, hunks)
+	}
+	// Joining two lines is not whitespace-only.
+	joined := "<<<<<<< HEAD\nx := f(a, b)\n||||||| base\nx := f(a,\n\tb)\n=======\nx := g(a,\n\tb)\n>>>>>>> main\n"
+	if _, _, hunks := resolveText(joined, ".go"); len(hunks) != 1 {
+		t.Errorf("joined: hunks = %+v, want unresolved", hunks)
+	}
+}
+
+func TestLockfileCommand(t *testing.T) {
+	bin := t.TempDir()
+	for tool, version := range map[string]string{"yarn": "4.1.0", "poetry": "Poetry (version 2.1.1)"} {
+		script := "#!/bin/sh\necho '" + version + "'\n"
+		if err := os.WriteFile(filepath.Join(bin, tool), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	dir := t.TempDir()
+	if got := strings.Join(lockfileCommand("yarn.lock", dir), " "); got != "yarn install --mode update-lockfile" {
+		t.Errorf("yarn 4: %s", got)
+	}
+	if got := strings.Join(lockfileCommand("poetry.lock", dir), " "); got != "poetry lock" {
+		t.Errorf("poetry 2: %s", got)
+	}
+	t.Setenv("PATH", t.TempDir())
+	if got := strings.Join(lockfileCommand("poetry.lock", dir), " "); got != "poetry lock --no-update" {
+		t.Errorf("unknown poetry version: %s", got)
+	}
+}
+
+func TestMergeJSON(t *testing.T) {
+	base := []byte(`{"name": "app", "dependencies": {"a": "1.0.0"}}`)
+	ours := []byte("{\n    \"name\": \"app\",\n    \"dependencies\": {\"a\": \"1.0.0\", \"b\": \"2.0.0\"}\n}\n")
+	theirs := []byte(`{"name": "app", "dependencies": {"a": "1.1.0"}, "private": true}`)
+
+	merged, conflicts, err := mergeJSON(base, ours, theirs)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("mergeJSON: %v %v", err, conflicts)
+	}
+	want := "{\n    \"name\": \"app\",\n    \"dependencies\": {\n        \"a\": \"1.1.0\",\n        \"b\": \"2.0.0\"\n    },\n    \"private\": true\n}\n"
+	if string(merged) != want {
+		t.Errorf("merged =\n%s\nwant\n%s", merged, want)
+	}
+
+	_, conflicts, _ = mergeJSON(base, []byte(`{"name": "x", "dependencies": {"a": "1.0.0"}}`), []byte(`{"name": "y", "dependencies": {"a": "1.0.0"}}`))
+	if len(conflicts) != 1 || conflicts[0] != "name" {
+		t.Errorf("conflicts = %v, want [name]", conflicts)
+	}
+}
+
+func TestMergeYAML(t *testing.T) {
+	base := []byte("services:\n  web:\n    image: app:1\n")
+	ours := []byte("services:\n  web:\n    image: app:1\n    ports: [\"80\"]\n")
+	theirs := []byte("services:\n  web:\n    image: app:2\n  db:\n    image: pg\n")
+
+	merged, conflicts, err := mergeYAML(base, ours, theirs)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("mergeYAML: %v %v", err, conflicts)
+	}
+	for _, s := range []string{"image: app:2", "ports:", "db:"} {
+		if !strings.Contains(string(merged), s) {
+			t.Errorf("merged missing %q:\n%s", s, merged)
+		}
+	}
+}
+
+func TestMergeYAML_MultiDocument(t *testing.T) {
+	base := []byte("kind: Service\nmetadata:\n  name: web\n---\nkind: Deployment\nspec:\n  replicas: 1\n")
+	ours := []byte("kind: Service\nmetadata:\n  name: web\n  labels: {app: web}\n---\nkind: Deployment\nspec:\n  replicas: 1\n")
+	theirs := []byte("kind: Service\nmetadata:\n  name: web\n---\nkind: Deployment\nspec:\n  replicas: 3\n")
+
+	merged, conflicts, err := mergeYAML(base, ours, theirs)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("mergeYAML: %v %v", err, conflicts)
+	}
+	for _, s := range []string{"labels:", "kind: Deployment", "replicas: 3", "\n---\n"} {
+		if !strings.Contains(string(merged), s) {
+			t.Errorf("merged missing %q:\n%s", s, merged)
+		}
+	}
+
+	_, conflicts, _ = mergeYAML(base, ours, []byte("kind: Service\nmetadata:\n  name: api\n---\nkind: Deployment\nspec:\n  replicas: 1\n"))
+	if len(conflicts) != 0 {
+		t.Errorf("independent edits conflicted: %v", conflicts)
+	}
+	_, conflicts, _ = mergeYAML(base, []byte("kind: Service\nspec: {replicas: 2}\n---\nkind: Deployment\nspec:\n  replicas: 2\n"), theirs)
+	if len(conflicts) != 1 || conflicts[0] != "doc[1].spec.replicas" {
+		t.Errorf("conflicts = %v, want [doc[1].spec.replicas]", conflicts)
+	}
+
+	// A document added on one side cannot be matched up: leave it unresolved.
+	_, conflicts, _ = mergeYAML(base, ours, append(append([]byte{}, theirs...), "---\nkind: ConfigMap\n"...))
+	if len(conflicts) != 1 {
+		t.Errorf("conflicts = %v, want document count conflict", conflicts)
+	}
+}
@@ -0,0 +1,227 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RestackResult reports how a stacked worktree was moved onto a new base.
+type RestackResult struct {
+	SpecID string         `json:"spec_id"`
+	Onto   string         `json:"onto"`
+	Report *ResolveReport `json:"report,omitempty"`
+	Err    error          `json:"-"`
+	// Command finishes the move by hand when Err is set.
+	Command string `json:"command,omitempty"`
+}
+
+// CreateStacked creates a worktree whose branch starts from the branch of
+// another worktree (its parent) instead of a base branch. Sync restacks it
+// when the parent changes, and Done on the parent retargets it.
+func (m *Manager) CreateStacked(specID, branchName, parentSpecID string, force bool, llmConfigPath string) (*WorktreeInfo, error) {
+	if parentSpecID == specID {
+		return nil, fmt.Errorf("worktree %s cannot be its own parent", specID)
+	}
+	parent := m.Registry.Get(parentSpecID, m.ProjectName)
+	if parent == nil {
+		return nil, &WorktreeNotFoundError{SpecID: parentSpecID}
+	}
+
+	info, err := m.Create(specID, branchName, parent.Branch, force, llmConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	info.Parent = parentSpecID
+	info.ParentHead = m.mergeBase(info.Branch, parent.Branch)
+	return info, m.Registry.Register(info, m.ProjectName)
+}
+
+// Children returns the worktrees stacked directly on specID, by SPEC ID.
+func (m *Manager) Children(specID string) []*WorktreeInfo {
+	var children []*WorktreeInfo
+	for _, info := range m.List() {
+		if info.Parent == specID {
+			children = append(children, info)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].SpecID < children[j].SpecID })
+	return children
+}
+
+// StackForest arranges worktrees into stacks: the roots (worktrees without
+// a registered parent) and each worktree's children, both by SPEC ID.
+func StackForest(worktrees []*WorktreeInfo) ([]*WorktreeInfo, map[string][]*WorktreeInfo) {
+	worktrees = append([]*WorktreeInfo(nil), worktrees...)
+	sort.Slice(worktrees, func(i, j int) bool { return worktrees[i].SpecID < worktrees[j].SpecID })
+	known := make(map[string]bool, len(worktrees))
+	for _, info := range worktrees {
+		known[info.SpecID] = true
+	}
+	var roots []*WorktreeInfo
+	children := map[string][]*WorktreeInfo{}
+	for _, info := range worktrees {
+		if info.Parent != "" && known[info.Parent] {
+			children[info.Parent] = append(children[info.Parent], info)
+		} else {
+			roots = append(roots, info)
+		}
+	}
+	return roots, children
+}
+
+// StackOrder lists worktrees parents first, each followed by its stack.
+func StackOrder(worktrees []*WorktreeInfo) []*WorktreeInfo {
+	roots, children := StackForest(worktrees)
+	ordered := make([]*WorktreeInfo, 0, len(worktrees))
+	var walk func(*WorktreeInfo)
+	walk = func(info *WorktreeInfo) {
+		ordered = append(ordered, info)
+		for _, child := range children[info.SpecID] {
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return ordered
+}
+
+// Restack rebases a stacked worktree onto the current head of its parent's
+// branch. Only the worktree's own commits (since ParentHead, the parent
+// commit it was last stacked on) are replayed, so a rebased or amended
+// parent does not duplicate its old commits into the child.
+func (m *Manager) Restack(specID string, autoResolve bool) (*ResolveReport, error) {
+	info := m.Registry.Get(specID, m.ProjectName)
+	if info == nil {
+		return nil, &WorktreeNotFoundError{SpecID: specID}
+	}
+	if info.Parent == "" {
+		return nil, fmt.Errorf("worktree %s is not stacked on another worktree", specID)
+	}
+	parent := m.Registry.Get(info.Parent, m.ProjectName)
+	if parent == nil {
+		return nil, &WorktreeNotFoundError{SpecID: info.Parent}
+	}
+	return m.moveOnto(info, parent.Branch, autoResolve)
+}
+
+// RestackDescendants restacks every worktree stacked on specID, parents
+// before children. A worktree that fails to restack keeps its own stack
+// as it was.
+func (m *Manager) RestackDescendants(specID string, autoResolve bool) []RestackResult {
+	var results []RestackResult
+	for _, child := range m.Children(specID) {
+		parent := m.Registry.Get(specID, m.ProjectName)
+		result := RestackResult{SpecID: child.SpecID, Onto: parent.Branch}
+		result.Report, result.Err = m.Restack(child.SpecID, autoResolve)
+		results = append(results, result)
+		if result.Err == nil {
+			results = append(results, m.RestackDescendants(child.SpecID, autoResolve)...)
+		}
+	}
+	return results
+}
+
+// moveOnto replays a worktree's own commits onto target and records the
+// commit it now sits on.
+func (m *Manager) moveOnto(info *WorktreeInfo, target string, autoResolve bool) (*ResolveReport, error) {
+	if hasChanges, _ := m.hasUncommittedChanges(info.Path); hasChanges {
+		return nil, &UncommittedChangesError{SpecID: info.SpecID}
+	}
+	upstream := m.stackUpstream(info, target)
+	head := m.revParse(target)
+	if head == "" {
+		return nil, &GitOperationError{Operation: "rev-parse", Message: fmt.Sprintf("branch '%s' not found", target)}
+	}
+
+	report := &ResolveReport{}
+	if upstream != head {
+		var err error
+		if report, err = m.integrate(info, target, upstream, true, false, false, autoResolve); err != nil {
+			return report, err
+		}
+	}
+	info.ParentHead = head
+	info.LastAccessed = time.Now()
+	return report, m.Registry.Register(info, m.ProjectName)
+}
+
+// stackUpstream is the commit below a worktree's own commits: the recorded
+// ParentHead while it is still in the branch's history, else the merge
+// base with target.
+func (m *Manager) stackUpstream(info *WorktreeInfo, target string) string {
+	if info.ParentHead != "" {
+		if _, err := m.gitCmd("merge-base", "--is-ancestor", info.ParentHead, info.Branch); err == nil {
+			return info.ParentHead
+		}
+	}
+	return m.mergeBase(info.Branch, target)
+}
+
+// retargetChildren moves the worktrees stacked on a finished parent onto
+// onto (the branch the parent was merged into) and hands them to the
+// parent's own parent, if any. Failures are reported, not returned: the
+// children are re-parented either way, with the command to finish by hand.
+func (m *Manager) retargetChildren(parent *WorktreeInfo, onto string) []RestackResult {
+	var results []RestackResult
+	for _, child := range m.Children(parent.SpecID) {
+		target := onto
+		child.Parent = ""
+		if grand := m.Registry.Get(parent.Parent, m.ProjectName); parent.Parent != "" && grand != nil {
+			target = grand.Branch
+			child.Parent = grand.SpecID
+		}
+		// Pin the child's base to the parent commit it sits on before the
+		// parent branch goes away.
+		child.ParentHead = m.stackUpstream(child, parent.Branch)
+
+		result := RestackResult{SpecID: child.SpecID, Onto: target}
+		result.Report, result.Err = m.moveOnto(child, target, false)
+		if result.Err != nil {
+			result.Command = fmt.Sprintf("git -C %s rebase --onto %s %s", child.Path, target, child.ParentHead)
+		} else if child.Parent == "" {
+			child.ParentHead = ""
+		}
+		if err := m.Registry.Register(child, m.ProjectName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to re-parent %s: %v\n", child.SpecID, err)
+		}
+		results = append(results, result)
+		if result.Err == nil {
+			results = append(results, m.RestackDescendants(child.SpecID, false)...)
+		}
+	}
+	return results
+}
+
+// detachChildren drops the parent link of worktrees stacked on specID,
+// leaving their branches as they are.
+func (m *Manager) detachChildren(specID string) error {
+	changes := map[string]*WorktreeInfo{}
+	for _, child := range m.Children(specID) {
+		child.Parent, child.ParentHead = "", ""
+		changes[child.SpecID] = child
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return m.Registry.Apply(m.ProjectName, changes)
+}
+
+func (m *Manager) revParse(ref string) string {
+	out, err := m.gitCmdOutput(m.RepoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+func (m *Manager) mergeBase(a, b string) string {
+	out, err := m.gitCmdOutput(m.RepoPath, "merge-base", a, b)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,79 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitFile(t *testing.T, dir, name, content, msg string, extra ...string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", "-A")
+	git(t, dir, append([]string{"commit", "-q", "-m", msg}, extra...)...)
+}
+
+func TestStackedWorktrees(t *testing.T) {
+	for _, k := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(k+"_NAME", "t")
+		t.Setenv(k+"_EMAIL", "t@t")
+	}
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	commitFile(t, repo, "a.txt", "a\n", "base")
+
+	m := NewManager(repo, t.TempDir(), "proj")
+	parent, err := m.Create("SPEC-A-001", "", "main", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, parent.Path, "p.txt", "p1\n", "parent")
+	child, err := m.CreateStacked("SPEC-A-002", "", "SPEC-A-001", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, child.Path, "c.txt", "c\n", "child")
+
+	if err := m.Remove("SPEC-A-001", false); err == nil {
+		t.Fatal("removing a parent with stacked worktrees should fail")
+	}
+
+	// Rewrite the parent; the restack must not bring back its old commit.
+	commitFile(t, parent.Path, "p.txt", "p2\n", "parent amended", "--amend")
+	results := m.RestackDescendants("SPEC-A-001", false)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("restack: %+v", results)
+	}
+	if log := git(t, child.Path, "log", "--format=%s"); log != "child\nparent amended\nbase" {
+		t.Errorf("child history after restack:\n%s", log)
+	}
+
+	// Finishing the parent moves the child onto main as a plain worktree.
+	result, err := m.Done("SPEC-A-001", "main", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restacked) != 1 || result.Restacked[0].Err != nil || result.Restacked[0].Onto != "main" {
+		t.Fatalf("restacked = %+v", result.Restacked)
+	}
+	moved := m.Registry.Get("SPEC-A-002", "proj")
+	if moved.Parent != "" || moved.ParentHead != "" {
+		t.Errorf("child still stacked: %+v", moved)
+	}
+	git(t, child.Path, "merge-base", "--is-ancestor", "main", "HEAD")
+}